@@ -0,0 +1,83 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qinzj/superpowers-demo/internal/service/declarative"
+)
+
+var (
+	applyFiles  []string
+	applyDryRun bool
+	applyPrune  bool
+	applyEmpty  bool
+)
+
+func init() {
+	applyCmd.Flags().StringArrayVarP(&applyFiles, "filename", "f", nil, "YAML file or directory of files to apply (repeatable)")
+	_ = applyCmd.MarkFlagRequired("filename")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "print the plan without changing anything")
+	applyCmd.Flags().BoolVar(&applyPrune, "prune", false, "delete clients and connectors the files do not declare")
+	applyCmd.Flags().BoolVar(&applyEmpty, "allow-empty", false, "with --prune, also apply files that declare nothing, deleting everything")
+	rootCmd.AddCommand(applyCmd)
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f FILE|DIR...",
+	Short: "Sync OAuth2 clients and connectors with YAML files",
+	Long: `Make the OAuth2 clients and upstream connectors in the database match YAML files, for keeping
+them in a repository. The files have the clients and connectors sections of a bootstrap file (see
+configs/bootstrap.example.yaml); secrets are file or env references and are compared with the
+stored ones, so a changed secret is rotated.
+
+The plan is printed first: + creates, ~ updates, - deletes. Entries the files do not declare are
+left alone unless --prune is given. Files that declare nothing at all are refused with --prune,
+as that would delete everything, unless --allow-empty is also given.
+
+  superpowers-demo apply -f deploy/sso/ --dry-run
+  superpowers-demo apply -f deploy/sso/ --prune`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := declarative.Load(applyFiles...)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		sync := declarative.NewService(svc.clients, svc.connectors)
+		plan, err := sync.Plan(ctx, st, declarative.PlanOptions{Prune: applyPrune, AllowEmpty: applyEmpty})
+		if errors.Is(err, declarative.ErrNothingDeclared) {
+			return fmt.Errorf("%w (pass --allow-empty to allow)", err)
+		}
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		for _, c := range plan.Changes {
+			fmt.Fprintln(out, c)
+		}
+		if len(plan.Undeclared) > 0 {
+			fmt.Fprintf(out, "Not declared, kept without --prune: %s\n", strings.Join(plan.Undeclared, ", "))
+		}
+		fmt.Fprintf(out, "Plan: %s.\n", plan.Summary())
+		if applyDryRun || len(plan.Changes) == 0 {
+			return nil
+		}
+		done, err := sync.Apply(ctx, plan)
+		fmt.Fprintf(out, "Applied %d of %d changes.\n", len(done), len(plan.Changes))
+		return err
+	},
+}
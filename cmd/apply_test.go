@@ -0,0 +1,73 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/service/declarative"
+)
+
+// runApply runs the apply command against the configuration in cfg, resetting the flags left
+// set by earlier runs.
+func runApply(t *testing.T, cfg string, args ...string) (string, error) {
+	t.Helper()
+	applyDryRun, applyPrune, applyEmpty = false, false, false
+	require.NoError(t, applyCmd.Flags().Lookup("filename").Value.(interface{ Replace([]string) error }).Replace(nil))
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs(append([]string{"--config", cfg, "apply"}, args...))
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestApply_RefusesToPruneEverything(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "settings.yaml")
+	require.NoError(t, os.WriteFile(cfg, []byte(fmt.Sprintf(`
+database:
+  driver: sqlite3
+  dsn: file:%s?_fk=1
+  auto_migrate: true
+`, filepath.Join(dir, "sso.db"))), 0o600))
+	files := filepath.Join(dir, "sso")
+	require.NoError(t, os.Mkdir(files, 0o700))
+	t.Setenv("APPLY_TEST_PORTAL_SECRET", "portal-secret-0123456789")
+	require.NoError(t, os.WriteFile(filepath.Join(files, "portal.yaml"), []byte(`
+clients:
+  - client_id: portal
+    redirect_uris: [https://portal.example.com/cb]
+    secret:
+      env: APPLY_TEST_PORTAL_SECRET
+`), 0o600))
+
+	out, err := runApply(t, cfg, "-f", files)
+	require.NoError(t, err)
+	require.Contains(t, out, "+ client portal")
+
+	// An emptied directory is refused with --prune, and pruned with --allow-empty.
+	empty := t.TempDir()
+	_, err = runApply(t, cfg, "-f", empty, "--prune")
+	require.ErrorIs(t, err, declarative.ErrNothingDeclared)
+	require.ErrorContains(t, err, "--allow-empty")
+
+	out, err = runApply(t, cfg, "-f", empty, "--prune", "--dry-run")
+	require.ErrorIs(t, err, declarative.ErrNothingDeclared)
+	require.NotContains(t, out, "- client portal")
+
+	out, err = runApply(t, cfg, "-f", empty, "--prune", "--allow-empty")
+	require.NoError(t, err)
+	require.Contains(t, out, "- client portal")
+	require.Contains(t, out, "Applied 1 of 1 changes.")
+}
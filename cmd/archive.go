@@ -0,0 +1,183 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/internal/config"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+	"github.com/qinzj/superpowers-demo/internal/storage/archive"
+)
+
+var (
+	exportOutput       string
+	exportFormat       string
+	importSkipExisting bool
+)
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "file to write, or - for stdout")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "json or ndjson (default: json for a .json file, otherwise ndjson)")
+	importCmd.Flags().BoolVar(&importSkipExisting, "skip-existing", false, "keep entries that already exist instead of overwriting them")
+	rootCmd.AddCommand(exportCmd, importCmd, backupCmd)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [-o FILE]",
+	Short: "Export users, groups, roles, clients and connectors to an archive",
+	Long: `Write the users (with their group and role memberships), groups, roles, OAuth2 clients and
+upstream connectors of the configured database to a versioned archive that any database driver
+can import.
+
+Credentials are exported as stored: password and client secret hashes, and connector secrets
+encrypted with secrets.master_key (secrets stored before encryption was enabled are encrypted on
+the way out). The importing instance needs the same master key, or that key in
+secrets.previous_keys. Sessions, tokens, audit events and webhooks are not exported.
+
+  superpowers-demo export -o sso-export.ndjson
+  superpowers-demo export --format json | jq '.users[].username'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := archive.Format(exportFormat)
+		if format == "" {
+			format = archive.FormatNDJSON
+			if filepath.Ext(exportOutput) == ".json" {
+				format = archive.FormatJSON
+			}
+		}
+		if format != archive.FormatJSON && format != archive.FormatNDJSON {
+			return fmt.Errorf("--format must be json or ndjson, got %q", exportFormat)
+		}
+		return withArchiveDatabase(cmd.Context(), func(cfg *config.Config, client *ent.Client, sealer storage.Sealer) error {
+			a, plaintext, err := archive.Export(cmd.Context(), client, sealer)
+			if err != nil {
+				return err
+			}
+			a.Source = cfg.Database.Driver
+			if plaintext > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: %d connector secrets are exported unencrypted because secrets.master_key is not set\n", plaintext)
+			}
+			var w io.Writer = cmd.OutOrStdout()
+			if exportOutput != "-" {
+				f, err := os.OpenFile(exportOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			if err := archive.Write(w, a, format); err != nil {
+				return err
+			}
+			if exportOutput != "-" {
+				fmt.Fprintf(cmd.OutOrStdout(), "exported %d users, %d groups, %d roles, %d clients and %d connectors to %s\n",
+					len(a.Users), len(a.Groups), len(a.Roles), len(a.Clients), len(a.Connectors), exportOutput)
+			}
+			return nil
+		})
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import an archive written by export",
+	Long: `Import an archive written by export (JSON or NDJSON; - reads stdin) into the configured
+database, in one transaction. Entries are matched by username, client ID, issuer and client ID,
+and group or role name: existing ones are overwritten with the archive's values (or kept with
+--skip-existing) and the rest are created, so importing the same archive again is harmless.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = cmd.InOrStdin()
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		a, err := archive.Read(r)
+		if err != nil {
+			return err
+		}
+		return withArchiveDatabase(cmd.Context(), func(_ *config.Config, client *ent.Client, sealer storage.Sealer) error {
+			counts, err := archive.Import(cmd.Context(), client, a, archive.ImportOptions{SkipExisting: importSkipExisting, Sealer: sealer})
+			if err != nil {
+				return err
+			}
+			w := newTable(cmd.OutOrStdout())
+			fmt.Fprintln(w, "KIND\tCREATED\tUPDATED\tSKIPPED")
+			for _, kind := range []string{"connectors", "clients", "roles", "groups", "users", "identities"} {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", kind, counts.Created[kind], counts.Updated[kind], counts.Skipped[kind])
+			}
+			return w.Flush()
+		})
+	},
+}
+
+var backupCmd = &cobra.Command{
+	Use:   "backup FILE",
+	Short: "Copy the SQLite database to a new file while the server keeps running",
+	Long: `Write a consistent copy of the configured SQLite database to FILE using SQLite's online
+backup API; the server does not need to be stopped. To restore, stop the server and put the copy
+in place of the database file. For PostgreSQL and MySQL use their own tools (pg_dump,
+mysqldump), or export.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		if cfg.Database.Driver != config.DriverSQLite {
+			return fmt.Errorf("backup supports sqlite3 only, the configured driver is %s", cfg.Database.Driver)
+		}
+		drv, err := openDriver(cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer drv.Close()
+		pages, err := storage.BackupSQLite(cmd.Context(), drv.DB(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "backed up %d pages to %s\n", pages, args[0])
+		return nil
+	},
+}
+
+// withArchiveDatabase runs fn with the configured, migrated database opened without secret
+// encryption, and the keyring as a sealer when a master key is set.
+func withArchiveDatabase(ctx context.Context, fn func(*config.Config, *ent.Client, storage.Sealer) error) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return err
+	}
+	var sealer storage.Sealer
+	if cfg.Secrets.Enabled() {
+		keyring, err := cfg.Secrets.Keyring()
+		if err != nil {
+			return err
+		}
+		sealer = keyring
+	}
+	client, drv, err := openRawDatabase(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := migrateOnBoot(ctx, cfg, drv, zap.NewNop()); err != nil {
+		return err
+	}
+	return fn(cfg, client, sealer)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var clientRedirectURIs []string
+
+func init() {
+	clientCreateCmd.Flags().StringArrayVar(&clientRedirectURIs, "redirect-uri", nil, "allowed redirect URI (repeatable)")
+	_ = clientCreateCmd.MarkFlagRequired("redirect-uri")
+	clientCmd.AddCommand(clientCreateCmd, clientListCmd, clientRotateSecretCmd, clientDeleteCmd)
+	rootCmd.AddCommand(clientCmd)
+}
+
+var clientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Manage OAuth2 clients",
+}
+
+var clientCreateCmd = &cobra.Command{
+	Use:   "create CLIENT_ID",
+	Short: "Register a client and print its secret",
+	Long: `Register an OAuth2 client. The generated secret is printed once; only its hash is stored.
+
+  superpowers-demo client create web-app --redirect-uri https://app.example.com/callback`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		c, secret, err := svc.clients.Create(ctx, args[0], clientRedirectURIs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "client_id: %s\nclient_secret: %s\n", c.ClientID, secret)
+		return nil
+	},
+}
+
+var clientListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List clients",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		clients, err := svc.clients.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := newTable(cmd.OutOrStdout())
+		fmt.Fprintln(w, "CLIENT_ID\tREDIRECT_URIS")
+		for _, c := range clients {
+			fmt.Fprintf(w, "%s\t%s\n", c.ClientID, strings.Join(c.RedirectURIs, ","))
+		}
+		return w.Flush()
+	},
+}
+
+var clientRotateSecretCmd = &cobra.Command{
+	Use:   "rotate-secret CLIENT_ID",
+	Short: "Replace a client's secret and print the new one",
+	Long:  `Replace a client's secret. The previous secret stops working immediately.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		secret, err := svc.clients.RotateSecret(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "client_id: %s\nclient_secret: %s\n", args[0], secret)
+		return nil
+	},
+}
+
+var clientDeleteCmd = &cobra.Command{
+	Use:   "delete CLIENT_ID",
+	Short: "Delete a client",
+	Long: `Delete a client registration. Tokens already issued to it by a running server expire on
+their own; delete it from the admin console to revoke them at once.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		if err := svc.clients.Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "deleted client %s\n", args[0])
+		return nil
+	},
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+	_ "github.com/mattn/go-sqlite3"    // sqlite3 driver for ent
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/internal/config"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+	"github.com/qinzj/superpowers-demo/internal/storage/migrations"
+)
+
+// openDatabase connects to the configured database and, when a master key is configured,
+// enables encryption of sensitive fields. It does not touch the schema; see migrateOnBoot. The
+// returned driver exposes the connection pool for metrics and migrations.
+func openDatabase(ctx context.Context, cfg *config.Config) (*ent.Client, *entsql.Driver, error) {
+	client, drv, err := openRawDatabase(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Secrets.Enabled() {
+		keyring, err := cfg.Secrets.Keyring()
+		if err != nil {
+			client.Close()
+			return nil, nil, err
+		}
+		storage.EncryptSecrets(client, keyring)
+	}
+	return client, drv, nil
+}
+
+// openRawDatabase is openDatabase without the encryption of stored secrets: sealed values are
+// read and written as stored.
+func openRawDatabase(ctx context.Context, cfg *config.Config) (*ent.Client, *entsql.Driver, error) {
+	drv, err := openDriver(cfg.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := drv.DB().PingContext(ctx); err != nil {
+		drv.Close()
+		return nil, nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return ent.NewClient(ent.Driver(tracing.WrapDriver(drv))), drv, nil
+}
+
+// openDriver opens the database/sql pool for the configured driver. ent calls the PostgreSQL
+// dialect "postgres", but it is served by pgx, which registers itself as "pgx".
+func openDriver(c config.DatabaseConfig) (*entsql.Driver, error) {
+	name, dsn := c.Driver, c.DSN
+	switch c.Driver {
+	case config.DriverSQLite:
+		if dir := dataDirFromDSN(dsn); dir != "" {
+			if err := os.MkdirAll(dir, 0o750); err != nil {
+				return nil, fmt.Errorf("create data dir: %w", err)
+			}
+		}
+	case config.DriverPostgres:
+		name = "pgx"
+	case config.DriverMySQL:
+		// ent scans time columns into time.Time, which the MySQL driver only does with parseTime.
+		mc, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("parse database.dsn: %w", err)
+		}
+		mc.ParseTime = true
+		dsn = mc.FormatDSN()
+	}
+	db, err := sql.Open(name, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return entsql.OpenDB(c.Driver, db), nil
+}
+
+// migrateOnBoot applies pending migrations when database.auto_migrate is set, and otherwise
+// refuses to start on an out-of-date schema.
+func migrateOnBoot(ctx context.Context, cfg *config.Config, drv *entsql.Driver, logger *zap.Logger) error {
+	m, err := migrations.New(drv.DB(), cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+	if !cfg.Database.AutoMigrate {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("check migrations: %w", err)
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("database schema is %d migrations behind (next: %s_%s); run `superpowers-demo migrate up` or set database.auto_migrate",
+				len(pending), pending[0].Version, pending[0].Description)
+		}
+		return nil
+	}
+	applied, err := m.Up(ctx, 0)
+	for _, mg := range applied {
+		logger.Info("applied migration", zap.String("version", mg.Version), zap.String("name", mg.Description))
+	}
+	if err != nil {
+		return fmt.Errorf("migrate schema: %w", err)
+	}
+	return nil
+}
+
+// dataDirFromDSN returns the directory of a sqlite file DSN (file:./data/sso.db?params), or ""
+// for in-memory databases and other DSN forms.
+func dataDirFromDSN(dsn string) string {
+	const filePrefix = "file:"
+	if !strings.HasPrefix(dsn, filePrefix) {
+		return ""
+	}
+	pathPart := strings.SplitN(dsn[len(filePrefix):], "?", 2)[0]
+	if pathPart == "" || strings.HasPrefix(pathPart, ":memory:") || strings.Contains(dsn, "mode=memory") {
+		return ""
+	}
+	return filepath.Dir(pathPart)
+}
@@ -25,21 +25,25 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/qinzj/superpowers-demo/internal/config"
 )
 
+// cfgFile is the configuration file given with --config.
+var cfgFile string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "superpowers-demo",
-	Short: "A brief description of your application",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+	Short: "SSO server with OpenID Connect login",
+	Long: `An OpenID Connect provider with local and federated login, an account portal and
+admin and SCIM APIs.
+
+Settings are read from the file given with --config. Any key can be overridden with an
+environment variable named SSO_ followed by the upper-cased key path with dots replaced by
+underscores, e.g. SSO_SERVER_PORT=9000 or SSO_DATABASE_DSN=...`,
+	// Errors from a command are about its configuration or runtime, not its usage.
+	SilenceUsage: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -52,13 +56,5 @@ func Execute() {
 }
 
 func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.superpowers-demo.yaml)")
-
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", config.DefaultPath, "config file")
 }
@@ -0,0 +1,78 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qinzj/superpowers-demo/internal/config"
+	"github.com/qinzj/superpowers-demo/internal/infra/envelope"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func init() {
+	secretsCmd.AddCommand(secretsGenKeyCmd, secretsRekeyCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage the master keys that encrypt secrets stored in the database",
+}
+
+var secretsGenKeyCmd = &cobra.Command{
+	Use:   "genkey",
+	Short: "Print a new random master key",
+	Long: `Print a new random master key for secrets.master_key (or SSO_SECRETS_MASTER_KEY, or the
+file named by secrets.master_key_file).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := envelope.GenerateKey()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), key)
+		return nil
+	},
+}
+
+var secretsRekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Re-encrypt stored secrets with the current master key",
+	Long: `Re-encrypt every stored secret with secrets.master_key. Values sealed with a key listed
+in secrets.previous_keys, and values stored before encryption was enabled, are rewritten.
+
+To rotate the master key: move the current key to previous_keys, set a new master_key, run
+this command, then remove the old key from previous_keys.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		if !cfg.Secrets.Enabled() {
+			return errors.New("secrets.master_key is not set")
+		}
+		ctx := cmd.Context()
+		client, _, err := openDatabase(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		n, err := storage.RekeySecrets(ctx, client)
+		if err != nil {
+			return err
+		}
+		keyring, err := cfg.Secrets.Keyring()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "re-encrypted %d secrets with master key %s\n", n, keyring.PrimaryID())
+		return nil
+	},
+}
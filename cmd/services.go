@@ -0,0 +1,124 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/internal/config"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// managementServices are the services behind the user, client and apply commands, wired as in svr so
+// that changes made from the command line are audited and raise the same webhook events. Events
+// are queued in the database and delivered by the running server.
+type managementServices struct {
+	db         *ent.Client
+	users      *user.UserService
+	auth       *auth.AuthService
+	rbac       *rbac.Service
+	clients    *oauthclient.Service
+	connectors *federation.FederationService
+}
+
+// openManagementServices loads the configuration and connects to its database, which must be
+// migrated.
+func openManagementServices(ctx context.Context) (*managementServices, error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+	hasher, err := password.NewHasher(&cfg.Password)
+	if err != nil {
+		return nil, fmt.Errorf("init password hasher: %w", err)
+	}
+	client, drv, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateOnBoot(ctx, cfg, drv, zap.NewNop()); err != nil {
+		client.Close()
+		return nil, err
+	}
+	userRepo := storage.NewUserRepository(client)
+	auditSvc := audit.NewService(storage.NewAuditRepository(client))
+	webhookSvc := webhook.NewService(storage.NewWebhookRepository(client), webhook.WithConfig(cfg.Webhooks))
+	authSvc := auth.NewAuthService(userRepo, storage.NewSessionRepository(client),
+		auth.WithPasswordHasher(hasher),
+		auth.WithConsentRepository(storage.NewConsentRepository(client)),
+		auth.WithSessionConfig(cfg.Session),
+		auth.WithAuditor(auditSvc),
+		auth.WithEventPublisher(webhookSvc),
+	)
+	rbacSvc := rbac.NewService(storage.NewRBACRepository(client), userRepo)
+	return &managementServices{
+		db: client,
+		users: user.NewUserService(userRepo,
+			user.WithPasswordHasher(hasher),
+			user.WithDeletionGracePeriod(cfg.Account.DeletionGracePeriod),
+			user.WithAuditor(auditSvc),
+			user.WithEventPublisher(webhookSvc),
+		),
+		auth:    authSvc,
+		rbac:    rbacSvc,
+		clients: oauthclient.NewService(storage.NewOAuth2ClientRepository(client), oauthclient.WithPasswordHasher(hasher)),
+		connectors: federation.NewFederationService(storage.NewIdPConnectorRepository(client), federation.NewOIDCClientAdapter(), userRepo, authSvc,
+			federation.WithGroupSync(rbacSvc),
+			federation.WithAuditor(auditSvc),
+			federation.WithEventPublisher(webhookSvc),
+		),
+	}, nil
+}
+
+func (s *managementServices) Close() error {
+	return s.db.Close()
+}
+
+// generatedSecretBytes is the entropy of passwords generated by the user commands.
+const generatedSecretBytes = 18
+
+// readOrGenerateSecret returns the first line of in when fromStdin is set, and otherwise a random
+// secret; generated reports which one it is, so the caller prints it only then.
+func readOrGenerateSecret(in io.Reader, fromStdin bool) (secret string, generated bool, err error) {
+	if !fromStdin {
+		b := make([]byte, generatedSecretBytes)
+		if _, err := rand.Read(b); err != nil {
+			return "", false, err
+		}
+		return base64.RawURLEncoding.EncodeToString(b), true, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(in, 4096))
+	if err != nil {
+		return "", false, fmt.Errorf("read stdin: %w", err)
+	}
+	line, _, _ := strings.Cut(string(b), "\n")
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return "", false, fmt.Errorf("no password on stdin")
+	}
+	return line, false, nil
+}
+
+// newTable returns a writer that aligns tab-separated columns.
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
@@ -8,34 +8,38 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
-	_ "github.com/mattn/go-sqlite3" // sqlite3 driver for ent
-	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/internal/config"
+	"github.com/qinzj/superpowers-demo/internal/infra/metrics"
+	"github.com/qinzj/superpowers-demo/internal/infra/notify"
 	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
 	"github.com/qinzj/superpowers-demo/internal/router"
+	"github.com/qinzj/superpowers-demo/internal/server"
 	"github.com/qinzj/superpowers-demo/internal/server/http/handler"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/bootstrap"
+	"github.com/qinzj/superpowers-demo/internal/service/cleanup"
+	"github.com/qinzj/superpowers-demo/internal/service/declarative"
 	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
 	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/scim"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
 	"github.com/qinzj/superpowers-demo/internal/storage"
 	"github.com/qinzj/superpowers-demo/pkg/log"
 )
 
-// config keys
-const (
-	keyServerPort     = "server.port"
-	keyDatabaseDriver = "database.driver"
-	keyDatabaseDSN    = "database.dsn"
-	keyOIDCIssuer     = "oidc.issuer"
-)
-
 func init() {
 	rootCmd.AddCommand(svrCmd)
 }
@@ -48,83 +52,183 @@ var svrCmd = &cobra.Command{
 }
 
 func runSvr(cmd *cobra.Command, args []string) error {
-	v := viper.New()
-	v.SetConfigFile("configs/settings.yaml")
-	if err := v.ReadInConfig(); err != nil {
-		return fmt.Errorf("read config: %w", err)
+	loader := config.NewLoader(cfgFile)
+	cfg, err := loader.Load()
+	if err != nil {
+		return err
 	}
 
-	var logCfg log.Config
-	if err := v.UnmarshalKey("log", &logCfg); err != nil {
-		return fmt.Errorf("unmarshal log config: %w", err)
-	}
-	if logCfg.File != "" {
-		if err := os.MkdirAll(filepath.Dir(logCfg.File), 0o750); err != nil {
+	if cfg.Log.File != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.Log.File), 0o750); err != nil {
 			return fmt.Errorf("create log dir: %w", err)
 		}
 	}
-	logger, err := log.New(&logCfg)
+	logger, logLevel, err := log.NewWithLevel(&cfg.Log)
 	if err != nil {
 		return fmt.Errorf("init logger: %w", err)
 	}
 	defer logger.Sync()
 
-	port := v.GetInt(keyServerPort)
-	if port == 0 {
-		port = 8888
+	srvCfg := cfg.Server
+	port := srvCfg.Port
+	scheme := "http"
+	if srvCfg.TLS.Enabled() {
+		scheme = "https"
 	}
-	driver := v.GetString(keyDatabaseDriver)
-	if driver == "" {
-		driver = "sqlite3"
+	driver, dsn := cfg.Database.Driver, cfg.Database.DSN
+
+	hasher, err := password.NewHasher(&cfg.Password)
+	if err != nil {
+		return fmt.Errorf("init password hasher: %w", err)
 	}
-	dsn := v.GetString(keyDatabaseDSN)
-	if dsn == "" {
-		return fmt.Errorf("database.dsn is required")
+
+	cookiePolicy := handler.NewCookiePolicySource(cfg.Cookie)
+	csrfKey := cfg.CSRF.Key
+	if csrfKey == "" {
+		logger.Warn("csrf.key not set; using a random key (form tokens reset on restart)")
 	}
 
-	logger.Info("starting server", zap.Int(keyServerPort, port), zap.String(keyDatabaseDriver, driver), zap.String(keyDatabaseDSN, dsn))
-	issuer := v.GetString(keyOIDCIssuer)
+	logger.Info("starting server", zap.String("config", cfgFile), zap.Int("server.port", port), zap.String("database.driver", driver), zap.String("database.dsn", config.RedactDSN(dsn)))
+	issuer := cfg.OIDC.Issuer
 	if issuer == "" {
-		issuer = fmt.Sprintf("http://localhost:%d", port)
+		issuer = fmt.Sprintf("%s://localhost:%d", scheme, port)
 	}
 
-	// Ensure data dir exists for sqlite (dsn format: file:./data/sso.db?params)
-	if dir := dataDirFromDSN(dsn); dir != "" {
-		if err := os.MkdirAll(dir, 0o750); err != nil {
-			return fmt.Errorf("create data dir: %w", err)
-		}
-	}
+	// ctx is cancelled on SIGINT or SIGTERM, which starts a graceful shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	client, err := ent.Open(driver, dsn)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
 	if err != nil {
-		return fmt.Errorf("open database: %w", err)
+		return fmt.Errorf("init tracing: %w", err)
 	}
-	defer client.Close()
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Warn("flush traces", zap.Error(err))
+		}
+	}()
 
-	ctx := context.Background()
-	if err := client.Schema.Create(ctx); err != nil {
-		return fmt.Errorf("migrate schema: %w", err)
+	if !cfg.Secrets.Enabled() {
+		logger.Warn("secrets.master_key not set; connector and webhook secrets are stored unencrypted")
 	}
-
-	if err := seedOAuth2Client(ctx, client); err != nil {
-		return fmt.Errorf("seed OAuth2 client: %w", err)
+	client, drv, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := migrateOnBoot(ctx, cfg, drv, logger); err != nil {
+		return err
 	}
 
 	oidcCfg, err := oidc.DefaultOIDCConfig(issuer)
 	if err != nil {
 		return fmt.Errorf("init OIDC config: %w", err)
 	}
+	oidcCfg.SecretsHasher = oidc.NewSecretsHasher(hasher)
+	if cfg.OIDC.GlobalSecret != "" {
+		oidcCfg.GlobalSecret = []byte(cfg.OIDC.GlobalSecret)
+	} else {
+		logger.Warn("oidc.global_secret not set; using a random key (authorization codes and tokens are invalidated on restart)")
+	}
+	if key, err := cfg.OIDC.PrivateKey(); err != nil {
+		return err
+	} else if key != nil {
+		oidcCfg.PrivateKey = key
+	} else {
+		logger.Warn("oidc.private_key_file not set; using a random signing key (clients must refetch the JWKS after a restart)")
+	}
+	oidcCfg.SetLifespans(cfg.OIDC.Lifespans())
 
 	oidcStorage := oidc.NewFositeStorage(client)
+	oidcStorage.SetTokenExchangePolicies(cfg.OIDC.TokenExchange)
+	oidcStorage.SetDeviceClients(cfg.OIDC.DeviceClients)
 	provider := oidc.NewOAuth2Provider(oidcCfg, oidcStorage)
 
 	userRepo := storage.NewUserRepository(client)
 	sessionRepo := storage.NewSessionRepository(client)
+	// Metrics are always recorded; metrics.enabled only controls whether they are served.
+	m := metrics.New()
+	m.WatchSessions(sessionRepo)
+	m.WatchTokenStore(oidcStorage)
+	m.WatchDB(drv.DB(), driver)
 	idpConnRepo := storage.NewIdPConnectorRepository(client)
-	userSvc := user.NewUserService(userRepo)
-	authSvc := auth.NewAuthService(userRepo, sessionRepo)
+	auditSvc := audit.NewService(storage.NewAuditRepository(client), audit.WithLogger(logger))
+	webhookSvc := webhook.NewService(storage.NewWebhookRepository(client),
+		webhook.WithConfig(cfg.Webhooks),
+		webhook.WithLogger(logger),
+	)
+	userSvc := user.NewUserService(userRepo,
+		user.WithPasswordHasher(hasher),
+		user.WithNotifier(notify.NewLogNotifier(logger, issuer)),
+		user.WithDeletionGracePeriod(cfg.Account.DeletionGracePeriod),
+		user.WithAuditor(auditSvc),
+		user.WithEventPublisher(webhookSvc),
+	)
+	authSvc := auth.NewAuthService(userRepo, sessionRepo,
+		auth.WithPasswordHasher(hasher),
+		auth.WithTokenStore(oidcStorage),
+		auth.WithConsentRepository(storage.NewConsentRepository(client)),
+		auth.WithSessionConfig(cfg.Session),
+		auth.WithAuditor(auditSvc),
+		auth.WithEventPublisher(webhookSvc),
+		auth.WithMetrics(m),
+	)
+	rbacSvc := rbac.NewService(storage.NewRBACRepository(client), userRepo)
+	if len(cfg.Admin.BootstrapUsers) > 0 {
+		logger.Warn("admin.bootstrap_users is deprecated; list the users under admins in bootstrap.file instead")
+		missing, err := rbacSvc.BootstrapAdmins(ctx, cfg.Admin.BootstrapUsers)
+		if err != nil {
+			return fmt.Errorf("bootstrap admins: %w", err)
+		}
+		if len(missing) > 0 {
+			logger.Warn("admin.bootstrap_users not found", zap.Strings("usernames", missing))
+		}
+	}
+	clientSvc := oauthclient.NewService(storage.NewOAuth2ClientRepository(client),
+		oauthclient.WithPasswordHasher(hasher),
+		oauthclient.WithTokenRevoker(oidcStorage),
+	)
 	oidcAdapter := federation.NewOIDCClientAdapter()
-	fedSvc := federation.NewFederationService(idpConnRepo, oidcAdapter, userRepo, authSvc)
+	fedSvc := federation.NewFederationService(idpConnRepo, oidcAdapter, userRepo, authSvc,
+		federation.WithIdentityRepository(storage.NewLinkedIdentityRepository(client)),
+		federation.WithGroupSync(rbacSvc),
+		federation.WithAuditor(auditSvc),
+		federation.WithEventPublisher(webhookSvc),
+		federation.WithMetrics(m),
+	)
+	if path := cfg.Bootstrap.File; path != "" {
+		f, err := bootstrap.Load(path)
+		if err != nil {
+			return err
+		}
+		res, err := bootstrap.NewService(userSvc, clientSvc, fedSvc, rbacSvc).Apply(ctx, f)
+		if res != nil && len(res.Created) > 0 {
+			logger.Info("bootstrap file applied", zap.String("file", path), zap.Strings("created", res.Created))
+		}
+		if err != nil {
+			return fmt.Errorf("apply bootstrap file: %w", err)
+		}
+	}
+
+	var syncer *declarative.Syncer
+	if cfg.Declarative.Dir != "" {
+		syncer = declarative.NewSyncer(declarative.NewService(clientSvc, fedSvc), cfg.Declarative, logger)
+		if err := syncer.SyncOnce(ctx); err != nil {
+			return fmt.Errorf("apply declarative dir %s: %w", cfg.Declarative.Dir, err)
+		}
+	}
+
+	var metricsCfg *handler.MetricsRouteConfig
+	if cfg.Metrics.Enabled {
+		metricsCfg = &handler.MetricsRouteConfig{Metrics: m, Path: cfg.Metrics.Path}
+	}
+
+	reaper := cleanup.NewReaper(cfg.Cleanup, logger)
+	reaper.Register("sessions", sessionRepo)
+	reaper.Register("oauth2", oidcStorage)
+	reaper.Register("deleted_users", userSvc)
 
 	fedCfg := handler.FederationRouteConfig{
 		Service: fedSvc,
@@ -132,14 +236,19 @@ func runSvr(cmd *cobra.Command, args []string) error {
 	}
 
 	engine := handler.NewEngine(logger)
-	router.Setup(engine, &router.Config{
+	if err := router.Setup(engine, &router.Config{
 		Health: &handler.HealthRouteConfig{
 			Client: client,
 		},
+		Metrics: metricsCfg,
 		OIDC: &handler.OIDCRouteConfig{
 			Provider: provider,
 			Issuer:   issuer,
 			Auth:     authSvc,
+			RBAC:     rbacSvc,
+			Audit:    auditSvc,
+			Metrics:  m,
+			Device:   oidc.NewDeviceFlow(oidcCfg, oidcStorage),
 		},
 		Login: &handler.LoginRouteConfig{
 			Auth:       authSvc,
@@ -151,42 +260,86 @@ func runSvr(cmd *cobra.Command, args []string) error {
 		Account: &handler.AccountRouteConfig{
 			UserService: userSvc,
 			Auth:        authSvc,
+			RBAC:        rbacSvc,
+			Audit:       auditSvc,
+			Federation:  fedSvc,
 		},
 		Federation: &fedCfg,
-	})
-
-	addr := fmt.Sprintf(":%d", port)
-	cmd.Printf("Starting server on http://localhost%s\n", addr)
-	return engine.Run(addr)
-}
-
-func dataDirFromDSN(dsn string) string {
-	const filePrefix = "file:"
-	if !strings.HasPrefix(dsn, filePrefix) {
-		return ""
+		Admin: &handler.AdminRouteConfig{
+			Provider:   provider,
+			Auth:       authSvc,
+			RBAC:       rbacSvc,
+			Users:      userSvc,
+			Clients:    clientSvc,
+			Federation: fedSvc,
+			Audit:      auditSvc,
+			Webhooks:   webhookSvc,
+			APIClients: cfg.Admin.APIClients,
+		},
+		SCIM: &handler.SCIMRouteConfig{
+			Service: scim.NewService(userRepo, authSvc, rbacSvc,
+				scim.WithPasswordHasher(hasher), scim.WithEventPublisher(webhookSvc)),
+			Tokens:  cfg.SCIM.BearerTokens,
+			BaseURL: issuer,
+		},
+		CookieSource: cookiePolicy,
+		CSRFKey:      []byte(csrfKey),
+	}); err != nil {
+		return fmt.Errorf("setup routes: %w", err)
 	}
-	pathPart := strings.SplitN(dsn[len(filePrefix):], "?", 2)[0]
-	return filepath.Dir(pathPart)
-}
 
-// seedOAuth2Client inserts a development OAuth2 client if none exist.
-// Client ID: sso-demo, secret: secret, redirect_uri: http://localhost:3000/callback
-func seedOAuth2Client(ctx context.Context, client *ent.Client) error {
-	count, err := client.OAuth2Client.Query().Count(ctx)
+	srv, err := server.New(srvCfg, engine, server.WithLogger(logger))
 	if err != nil {
-		return err
+		return fmt.Errorf("init server: %w", err)
 	}
-	if count > 0 {
-		return nil
+	// Workers stop in reverse order: the declarative sync and webhook deliveries first, then cleanup.
+	srv.AddWorker("cleanup", reaper.Run)
+	srv.AddWorker("webhooks", webhookSvc.Run)
+	if syncer != nil {
+		srv.AddWorker("declarative", syncer.Run)
 	}
-	secretHash, err := password.Hash("secret")
-	if err != nil {
-		return err
+	if srv.TLS() {
+		srv.AddWorker("tls_sighup", reloadCertificatesOnHUP(srv, logger))
+	}
+
+	live := config.Live{LogLevel: logLevel, OIDC: oidcCfg, Cookie: cookiePolicy}
+	loader.Watch(func(next *config.Config, err error) {
+		if err != nil {
+			logger.Error("reload config; keeping the current settings", zap.Error(err))
+			return
+		}
+		if err := live.Apply(next); err != nil {
+			logger.Error("reload config; keeping the current settings", zap.Error(err))
+			return
+		}
+		logger.Info("reloaded config", zap.String("log.level", next.Log.Level))
+		if config.RestartRequired(cfg, next) {
+			logger.Warn("config changes other than log level, token lifespans and cookie policy take effect on restart")
+		}
+	})
+
+	cmd.Printf("Starting server on %s://localhost%s\n", scheme, srvCfg.Addr())
+	return srv.Run(ctx)
+}
+
+// reloadCertificatesOnHUP returns a worker that reloads the TLS files on SIGHUP, for renewals
+// that should take effect before the next reload_interval check.
+func reloadCertificatesOnHUP(srv *server.Server, logger log.Logger) server.Worker {
+	return func(ctx context.Context) {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := srv.ReloadCertificates(); err != nil {
+					logger.Error("reload tls certificate", zap.Error(err))
+				} else {
+					logger.Info("reloaded tls certificate")
+				}
+			}
+		}
 	}
-	_, err = client.OAuth2Client.Create().
-		SetClientID("sso-demo").
-		SetClientSecret(secretHash).
-		SetRedirectUris([]string{"http://localhost:3000/callback"}).
-		Save(ctx)
-	return err
 }
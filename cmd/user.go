@@ -0,0 +1,286 @@
+/*
+Copyright © 2026 qinzj
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+)
+
+var (
+	userEmail         string
+	userAdmin         bool
+	userPasswordStdin bool
+	userQuery         string
+	userLimit         int
+	userImportFormat  string
+	userSaltFirst     bool
+	userImportDryRun  bool
+)
+
+func init() {
+	userCreateCmd.Flags().StringVar(&userEmail, "email", "", "email address, trusted as verified")
+	_ = userCreateCmd.MarkFlagRequired("email")
+	userCreateCmd.Flags().BoolVar(&userAdmin, "admin", false, "grant the admin role")
+	for _, c := range []*cobra.Command{userCreateCmd, userPasswdCmd} {
+		c.Flags().BoolVar(&userPasswordStdin, "password-stdin", false, "read the password from the first line of stdin instead of generating one")
+	}
+	userListCmd.Flags().StringVarP(&userQuery, "query", "q", "", "only users whose username, email or display name contains this")
+	userListCmd.Flags().IntVar(&userLimit, "limit", 100, "maximum number of users to list")
+	userImportCmd.Flags().StringVar(&userImportFormat, "format", "", "csv or json (default: from the file extension)")
+	userImportCmd.Flags().BoolVar(&userSaltFirst, "salt-first", false, "raw salted digests are sha(salt + password) instead of sha(password + salt)")
+	userImportCmd.Flags().BoolVar(&userImportDryRun, "dry-run", false, "check the file and show what would be imported without changing anything")
+	userCmd.AddCommand(userCreateCmd, userListCmd, userPasswdCmd, userDisableCmd, userEnableCmd, userDeleteCmd, userImportCmd)
+	rootCmd.AddCommand(userCmd)
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage local users",
+	Long: `Manage local users in the configured database. Changes are audited and raise the same
+webhook events as the admin console.`,
+}
+
+var userCreateCmd = &cobra.Command{
+	Use:   "create USERNAME",
+	Short: "Create a user (use --admin for the first administrator)",
+	Long: `Create a local user. Without --password-stdin a random password is generated and printed
+once.
+
+  superpowers-demo user create alice --email alice@example.com --admin
+  printf '%s\n' "$PASSWORD" | superpowers-demo user create bob --email bob@example.com --password-stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pwd, generated, err := readOrGenerateSecret(cmd.InOrStdin(), userPasswordStdin)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		u, err := svc.users.CreateByAdmin(ctx, args[0], userEmail, pwd)
+		if err != nil {
+			return err
+		}
+		if userAdmin {
+			if _, err := svc.rbac.BootstrapAdmins(ctx, []string{u.Username}); err != nil {
+				return err
+			}
+		}
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "created user %s (id %s)\n", u.Username, u.ID)
+		if generated {
+			fmt.Fprintf(out, "password: %s\n", pwd)
+		}
+		return nil
+	},
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List users",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		page, err := svc.users.Search(ctx, userQuery, 1, userLimit)
+		if err != nil {
+			return err
+		}
+		w := newTable(cmd.OutOrStdout())
+		fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tSTATUS\tCREATED")
+		for _, u := range page.Users {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.ID, u.Username, u.Email, u.Status, u.CreatedAt.Local().Format(time.DateTime))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if page.Total > len(page.Users) {
+			fmt.Fprintf(cmd.OutOrStdout(), "showing %d of %d users\n", len(page.Users), page.Total)
+		}
+		return nil
+	},
+}
+
+var userPasswdCmd = &cobra.Command{
+	Use:   "passwd USERNAME",
+	Short: "Reset a user's password",
+	Long: `Set a new password without knowing the current one. Without --password-stdin a random
+password is generated and printed once.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pwd, generated, err := readOrGenerateSecret(cmd.InOrStdin(), userPasswordStdin)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		u, err := svc.users.GetByUsername(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if err := svc.users.ResetPassword(ctx, u.ID, pwd); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "reset password of %s\n", u.Username)
+		if generated {
+			fmt.Fprintf(cmd.OutOrStdout(), "password: %s\n", pwd)
+		}
+		return nil
+	},
+}
+
+var userDisableCmd = &cobra.Command{
+	Use:   "disable USERNAME",
+	Short: "Suspend a user and sign them out everywhere",
+	Long: `Suspend a user: they can no longer sign in, their sessions are revoked and refresh tokens
+are refused. Access tokens already issued expire on their own.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setUserStatus(cmd, args[0], domain.UserStatusSuspended)
+	},
+}
+
+var userEnableCmd = &cobra.Command{
+	Use:   "enable USERNAME",
+	Short: "Reactivate a suspended or locked user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setUserStatus(cmd, args[0], domain.UserStatusActive)
+	},
+}
+
+var userDeleteCmd = &cobra.Command{
+	Use:   "delete USERNAME",
+	Short: "Delete a user and their sessions immediately",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		u, err := svc.users.GetByUsername(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if err := svc.users.Delete(ctx, u.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "deleted user %s\n", u.Username)
+		return nil
+	},
+}
+
+var userImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import users with their password hashes from another system",
+	Long: `Create users from a CSV file with a header row, or a JSON array, keeping the password hashes
+exported by the previous system so that users sign in with their existing passwords. Columns (or
+fields) are username, email, display_name, email_verified, password_hash, and for raw salted SHA
+digests password_salt and password_algorithm (sha1, sha256 or sha512).
+
+password_hash may be bcrypt, argon2id, PBKDF2 (Django, passlib), scrypt ($scrypt$ln=...) or LDAP
+{SSHA}, {SSHA256} and {SSHA512}. It is stored with a marker of its algorithm and replaced by a
+hash with the configured algorithm on the user's first successful sign-in. Existing usernames are
+skipped; invalid records are reported and the rest imported.
+
+  superpowers-demo user import users.csv --dry-run
+  superpowers-demo user import users.json --salt-first`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := userImportFormat
+		if format == "" {
+			format = "csv"
+			if filepath.Ext(args[0]) == ".json" {
+				format = "json"
+			}
+		}
+		var r io.Reader = cmd.InOrStdin()
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		var records []user.ImportRecord
+		var err error
+		switch format {
+		case "csv":
+			records, err = user.ReadImportCSV(r)
+		case "json":
+			records, err = user.ReadImportJSON(r)
+		default:
+			return fmt.Errorf("--format must be csv or json, got %q", format)
+		}
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		svc, err := openManagementServices(ctx)
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+		res, err := svc.users.Import(ctx, records, user.ImportOptions{SaltFirst: userSaltFirst, DryRun: userImportDryRun})
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		for _, e := range res.Errors {
+			fmt.Fprintf(cmd.ErrOrStderr(), "error: %v\n", e)
+		}
+		verb := "imported"
+		if userImportDryRun {
+			verb = "would import"
+		}
+		fmt.Fprintf(out, "%s %d users, skipped %d existing, %d errors\n", verb, len(res.Created), len(res.Skipped), len(res.Errors))
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("%d records were not imported", len(res.Errors))
+		}
+		return nil
+	},
+}
+
+func setUserStatus(cmd *cobra.Command, username string, status domain.UserStatus) error {
+	ctx := cmd.Context()
+	svc, err := openManagementServices(ctx)
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+	u, err := svc.users.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if err := svc.auth.SetUserStatus(ctx, u.ID, status); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is now %s\n", u.Username, status)
+	return nil
+}
@@ -0,0 +1,373 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/qinzj/superpowers-demo/ent/auditevent"
+)
+
+// AuditEventCreate is the builder for creating a AuditEvent entity.
+type AuditEventCreate struct {
+	config
+	mutation *AuditEventMutation
+	hooks    []Hook
+}
+
+// SetType sets the "type" field.
+func (aec *AuditEventCreate) SetType(s string) *AuditEventCreate {
+	aec.mutation.SetType(s)
+	return aec
+}
+
+// SetUserID sets the "user_id" field.
+func (aec *AuditEventCreate) SetUserID(s string) *AuditEventCreate {
+	aec.mutation.SetUserID(s)
+	return aec
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableUserID(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetUserID(*s)
+	}
+	return aec
+}
+
+// SetClientID sets the "client_id" field.
+func (aec *AuditEventCreate) SetClientID(s string) *AuditEventCreate {
+	aec.mutation.SetClientID(s)
+	return aec
+}
+
+// SetNillableClientID sets the "client_id" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableClientID(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetClientID(*s)
+	}
+	return aec
+}
+
+// SetIP sets the "ip" field.
+func (aec *AuditEventCreate) SetIP(s string) *AuditEventCreate {
+	aec.mutation.SetIP(s)
+	return aec
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableIP(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetIP(*s)
+	}
+	return aec
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (aec *AuditEventCreate) SetUserAgent(s string) *AuditEventCreate {
+	aec.mutation.SetUserAgent(s)
+	return aec
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableUserAgent(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetUserAgent(*s)
+	}
+	return aec
+}
+
+// SetDetails sets the "details" field.
+func (aec *AuditEventCreate) SetDetails(m map[string]string) *AuditEventCreate {
+	aec.mutation.SetDetails(m)
+	return aec
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (aec *AuditEventCreate) SetCreatedAt(t time.Time) *AuditEventCreate {
+	aec.mutation.SetCreatedAt(t)
+	return aec
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillableCreatedAt(t *time.Time) *AuditEventCreate {
+	if t != nil {
+		aec.SetCreatedAt(*t)
+	}
+	return aec
+}
+
+// SetPrevHash sets the "prev_hash" field.
+func (aec *AuditEventCreate) SetPrevHash(s string) *AuditEventCreate {
+	aec.mutation.SetPrevHash(s)
+	return aec
+}
+
+// SetNillablePrevHash sets the "prev_hash" field if the given value is not nil.
+func (aec *AuditEventCreate) SetNillablePrevHash(s *string) *AuditEventCreate {
+	if s != nil {
+		aec.SetPrevHash(*s)
+	}
+	return aec
+}
+
+// SetHash sets the "hash" field.
+func (aec *AuditEventCreate) SetHash(s string) *AuditEventCreate {
+	aec.mutation.SetHash(s)
+	return aec
+}
+
+// Mutation returns the AuditEventMutation object of the builder.
+func (aec *AuditEventCreate) Mutation() *AuditEventMutation {
+	return aec.mutation
+}
+
+// Save creates the AuditEvent in the database.
+func (aec *AuditEventCreate) Save(ctx context.Context) (*AuditEvent, error) {
+	aec.defaults()
+	return withHooks(ctx, aec.sqlSave, aec.mutation, aec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (aec *AuditEventCreate) SaveX(ctx context.Context) *AuditEvent {
+	v, err := aec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (aec *AuditEventCreate) Exec(ctx context.Context) error {
+	_, err := aec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aec *AuditEventCreate) ExecX(ctx context.Context) {
+	if err := aec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (aec *AuditEventCreate) defaults() {
+	if _, ok := aec.mutation.UserID(); !ok {
+		v := auditevent.DefaultUserID
+		aec.mutation.SetUserID(v)
+	}
+	if _, ok := aec.mutation.ClientID(); !ok {
+		v := auditevent.DefaultClientID
+		aec.mutation.SetClientID(v)
+	}
+	if _, ok := aec.mutation.IP(); !ok {
+		v := auditevent.DefaultIP
+		aec.mutation.SetIP(v)
+	}
+	if _, ok := aec.mutation.UserAgent(); !ok {
+		v := auditevent.DefaultUserAgent
+		aec.mutation.SetUserAgent(v)
+	}
+	if _, ok := aec.mutation.CreatedAt(); !ok {
+		v := auditevent.DefaultCreatedAt()
+		aec.mutation.SetCreatedAt(v)
+	}
+	if _, ok := aec.mutation.PrevHash(); !ok {
+		v := auditevent.DefaultPrevHash
+		aec.mutation.SetPrevHash(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (aec *AuditEventCreate) check() error {
+	if _, ok := aec.mutation.GetType(); !ok {
+		return &ValidationError{Name: "type", err: errors.New(`ent: missing required field "AuditEvent.type"`)}
+	}
+	if v, ok := aec.mutation.GetType(); ok {
+		if err := auditevent.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "AuditEvent.type": %w`, err)}
+		}
+	}
+	if _, ok := aec.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "AuditEvent.user_id"`)}
+	}
+	if _, ok := aec.mutation.ClientID(); !ok {
+		return &ValidationError{Name: "client_id", err: errors.New(`ent: missing required field "AuditEvent.client_id"`)}
+	}
+	if _, ok := aec.mutation.IP(); !ok {
+		return &ValidationError{Name: "ip", err: errors.New(`ent: missing required field "AuditEvent.ip"`)}
+	}
+	if _, ok := aec.mutation.UserAgent(); !ok {
+		return &ValidationError{Name: "user_agent", err: errors.New(`ent: missing required field "AuditEvent.user_agent"`)}
+	}
+	if _, ok := aec.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "AuditEvent.created_at"`)}
+	}
+	if _, ok := aec.mutation.PrevHash(); !ok {
+		return &ValidationError{Name: "prev_hash", err: errors.New(`ent: missing required field "AuditEvent.prev_hash"`)}
+	}
+	if _, ok := aec.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`ent: missing required field "AuditEvent.hash"`)}
+	}
+	if v, ok := aec.mutation.Hash(); ok {
+		if err := auditevent.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`ent: validator failed for field "AuditEvent.hash": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (aec *AuditEventCreate) sqlSave(ctx context.Context) (*AuditEvent, error) {
+	if err := aec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := aec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, aec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	aec.mutation.id = &_node.ID
+	aec.mutation.done = true
+	return _node, nil
+}
+
+func (aec *AuditEventCreate) createSpec() (*AuditEvent, *sqlgraph.CreateSpec) {
+	var (
+		_node = &AuditEvent{config: aec.config}
+		_spec = sqlgraph.NewCreateSpec(auditevent.Table, sqlgraph.NewFieldSpec(auditevent.FieldID, field.TypeInt))
+	)
+	if value, ok := aec.mutation.GetType(); ok {
+		_spec.SetField(auditevent.FieldType, field.TypeString, value)
+		_node.Type = value
+	}
+	if value, ok := aec.mutation.UserID(); ok {
+		_spec.SetField(auditevent.FieldUserID, field.TypeString, value)
+		_node.UserID = value
+	}
+	if value, ok := aec.mutation.ClientID(); ok {
+		_spec.SetField(auditevent.FieldClientID, field.TypeString, value)
+		_node.ClientID = value
+	}
+	if value, ok := aec.mutation.IP(); ok {
+		_spec.SetField(auditevent.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := aec.mutation.UserAgent(); ok {
+		_spec.SetField(auditevent.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if value, ok := aec.mutation.Details(); ok {
+		_spec.SetField(auditevent.FieldDetails, field.TypeJSON, value)
+		_node.Details = value
+	}
+	if value, ok := aec.mutation.CreatedAt(); ok {
+		_spec.SetField(auditevent.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := aec.mutation.PrevHash(); ok {
+		_spec.SetField(auditevent.FieldPrevHash, field.TypeString, value)
+		_node.PrevHash = value
+	}
+	if value, ok := aec.mutation.Hash(); ok {
+		_spec.SetField(auditevent.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	return _node, _spec
+}
+
+// AuditEventCreateBulk is the builder for creating many AuditEvent entities in bulk.
+type AuditEventCreateBulk struct {
+	config
+	err      error
+	builders []*AuditEventCreate
+}
+
+// Save creates the AuditEvent entities in the database.
+func (aecb *AuditEventCreateBulk) Save(ctx context.Context) ([]*AuditEvent, error) {
+	if aecb.err != nil {
+		return nil, aecb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(aecb.builders))
+	nodes := make([]*AuditEvent, len(aecb.builders))
+	mutators := make([]Mutator, len(aecb.builders))
+	for i := range aecb.builders {
+		func(i int, root context.Context) {
+			builder := aecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*AuditEventMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, aecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, aecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, aecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (aecb *AuditEventCreateBulk) SaveX(ctx context.Context) []*AuditEvent {
+	v, err := aecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (aecb *AuditEventCreateBulk) Exec(ctx context.Context) error {
+	_, err := aecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aecb *AuditEventCreateBulk) ExecX(ctx context.Context) {
+	if err := aecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,412 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/qinzj/superpowers-demo/ent/consent"
+	"github.com/qinzj/superpowers-demo/ent/predicate"
+	"github.com/qinzj/superpowers-demo/ent/user"
+)
+
+// ConsentUpdate is the builder for updating Consent entities.
+type ConsentUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ConsentMutation
+}
+
+// Where appends a list predicates to the ConsentUpdate builder.
+func (cu *ConsentUpdate) Where(ps ...predicate.Consent) *ConsentUpdate {
+	cu.mutation.Where(ps...)
+	return cu
+}
+
+// SetClientID sets the "client_id" field.
+func (cu *ConsentUpdate) SetClientID(s string) *ConsentUpdate {
+	cu.mutation.SetClientID(s)
+	return cu
+}
+
+// SetNillableClientID sets the "client_id" field if the given value is not nil.
+func (cu *ConsentUpdate) SetNillableClientID(s *string) *ConsentUpdate {
+	if s != nil {
+		cu.SetClientID(*s)
+	}
+	return cu
+}
+
+// SetScopes sets the "scopes" field.
+func (cu *ConsentUpdate) SetScopes(s []string) *ConsentUpdate {
+	cu.mutation.SetScopes(s)
+	return cu
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (cu *ConsentUpdate) AppendScopes(s []string) *ConsentUpdate {
+	cu.mutation.AppendScopes(s)
+	return cu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (cu *ConsentUpdate) SetUpdatedAt(t time.Time) *ConsentUpdate {
+	cu.mutation.SetUpdatedAt(t)
+	return cu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (cu *ConsentUpdate) SetUserID(id int) *ConsentUpdate {
+	cu.mutation.SetUserID(id)
+	return cu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (cu *ConsentUpdate) SetUser(u *User) *ConsentUpdate {
+	return cu.SetUserID(u.ID)
+}
+
+// Mutation returns the ConsentMutation object of the builder.
+func (cu *ConsentUpdate) Mutation() *ConsentMutation {
+	return cu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (cu *ConsentUpdate) ClearUser() *ConsentUpdate {
+	cu.mutation.ClearUser()
+	return cu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (cu *ConsentUpdate) Save(ctx context.Context) (int, error) {
+	cu.defaults()
+	return withHooks(ctx, cu.sqlSave, cu.mutation, cu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (cu *ConsentUpdate) SaveX(ctx context.Context) int {
+	affected, err := cu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (cu *ConsentUpdate) Exec(ctx context.Context) error {
+	_, err := cu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cu *ConsentUpdate) ExecX(ctx context.Context) {
+	if err := cu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (cu *ConsentUpdate) defaults() {
+	if _, ok := cu.mutation.UpdatedAt(); !ok {
+		v := consent.UpdateDefaultUpdatedAt()
+		cu.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cu *ConsentUpdate) check() error {
+	if v, ok := cu.mutation.ClientID(); ok {
+		if err := consent.ClientIDValidator(v); err != nil {
+			return &ValidationError{Name: "client_id", err: fmt.Errorf(`ent: validator failed for field "Consent.client_id": %w`, err)}
+		}
+	}
+	if _, ok := cu.mutation.UserID(); cu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Consent.user"`)
+	}
+	return nil
+}
+
+func (cu *ConsentUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := cu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(consent.Table, consent.Columns, sqlgraph.NewFieldSpec(consent.FieldID, field.TypeInt))
+	if ps := cu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := cu.mutation.ClientID(); ok {
+		_spec.SetField(consent.FieldClientID, field.TypeString, value)
+	}
+	if value, ok := cu.mutation.Scopes(); ok {
+		_spec.SetField(consent.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := cu.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, consent.FieldScopes, value)
+		})
+	}
+	if value, ok := cu.mutation.UpdatedAt(); ok {
+		_spec.SetField(consent.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if cu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   consent.UserTable,
+			Columns: []string{consent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := cu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   consent.UserTable,
+			Columns: []string{consent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, cu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{consent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	cu.mutation.done = true
+	return n, nil
+}
+
+// ConsentUpdateOne is the builder for updating a single Consent entity.
+type ConsentUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ConsentMutation
+}
+
+// SetClientID sets the "client_id" field.
+func (cuo *ConsentUpdateOne) SetClientID(s string) *ConsentUpdateOne {
+	cuo.mutation.SetClientID(s)
+	return cuo
+}
+
+// SetNillableClientID sets the "client_id" field if the given value is not nil.
+func (cuo *ConsentUpdateOne) SetNillableClientID(s *string) *ConsentUpdateOne {
+	if s != nil {
+		cuo.SetClientID(*s)
+	}
+	return cuo
+}
+
+// SetScopes sets the "scopes" field.
+func (cuo *ConsentUpdateOne) SetScopes(s []string) *ConsentUpdateOne {
+	cuo.mutation.SetScopes(s)
+	return cuo
+}
+
+// AppendScopes appends s to the "scopes" field.
+func (cuo *ConsentUpdateOne) AppendScopes(s []string) *ConsentUpdateOne {
+	cuo.mutation.AppendScopes(s)
+	return cuo
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (cuo *ConsentUpdateOne) SetUpdatedAt(t time.Time) *ConsentUpdateOne {
+	cuo.mutation.SetUpdatedAt(t)
+	return cuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (cuo *ConsentUpdateOne) SetUserID(id int) *ConsentUpdateOne {
+	cuo.mutation.SetUserID(id)
+	return cuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (cuo *ConsentUpdateOne) SetUser(u *User) *ConsentUpdateOne {
+	return cuo.SetUserID(u.ID)
+}
+
+// Mutation returns the ConsentMutation object of the builder.
+func (cuo *ConsentUpdateOne) Mutation() *ConsentMutation {
+	return cuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (cuo *ConsentUpdateOne) ClearUser() *ConsentUpdateOne {
+	cuo.mutation.ClearUser()
+	return cuo
+}
+
+// Where appends a list predicates to the ConsentUpdate builder.
+func (cuo *ConsentUpdateOne) Where(ps ...predicate.Consent) *ConsentUpdateOne {
+	cuo.mutation.Where(ps...)
+	return cuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (cuo *ConsentUpdateOne) Select(field string, fields ...string) *ConsentUpdateOne {
+	cuo.fields = append([]string{field}, fields...)
+	return cuo
+}
+
+// Save executes the query and returns the updated Consent entity.
+func (cuo *ConsentUpdateOne) Save(ctx context.Context) (*Consent, error) {
+	cuo.defaults()
+	return withHooks(ctx, cuo.sqlSave, cuo.mutation, cuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (cuo *ConsentUpdateOne) SaveX(ctx context.Context) *Consent {
+	node, err := cuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (cuo *ConsentUpdateOne) Exec(ctx context.Context) error {
+	_, err := cuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (cuo *ConsentUpdateOne) ExecX(ctx context.Context) {
+	if err := cuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (cuo *ConsentUpdateOne) defaults() {
+	if _, ok := cuo.mutation.UpdatedAt(); !ok {
+		v := consent.UpdateDefaultUpdatedAt()
+		cuo.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (cuo *ConsentUpdateOne) check() error {
+	if v, ok := cuo.mutation.ClientID(); ok {
+		if err := consent.ClientIDValidator(v); err != nil {
+			return &ValidationError{Name: "client_id", err: fmt.Errorf(`ent: validator failed for field "Consent.client_id": %w`, err)}
+		}
+	}
+	if _, ok := cuo.mutation.UserID(); cuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "Consent.user"`)
+	}
+	return nil
+}
+
+func (cuo *ConsentUpdateOne) sqlSave(ctx context.Context) (_node *Consent, err error) {
+	if err := cuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(consent.Table, consent.Columns, sqlgraph.NewFieldSpec(consent.FieldID, field.TypeInt))
+	id, ok := cuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "Consent.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := cuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, consent.FieldID)
+		for _, f := range fields {
+			if !consent.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != consent.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := cuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := cuo.mutation.ClientID(); ok {
+		_spec.SetField(consent.FieldClientID, field.TypeString, value)
+	}
+	if value, ok := cuo.mutation.Scopes(); ok {
+		_spec.SetField(consent.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := cuo.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, consent.FieldScopes, value)
+		})
+	}
+	if value, ok := cuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(consent.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if cuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   consent.UserTable,
+			Columns: []string{consent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := cuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   consent.UserTable,
+			Columns: []string{consent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &Consent{config: cuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, cuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{consent.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	cuo.mutation.done = true
+	return _node, nil
+}
@@ -12,10 +12,17 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/qinzj/superpowers-demo/ent/auditevent"
+	"github.com/qinzj/superpowers-demo/ent/consent"
+	"github.com/qinzj/superpowers-demo/ent/group"
 	"github.com/qinzj/superpowers-demo/ent/idpconnector"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
 	"github.com/qinzj/superpowers-demo/ent/oauth2client"
+	"github.com/qinzj/superpowers-demo/ent/role"
 	"github.com/qinzj/superpowers-demo/ent/session"
 	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/ent/webhookdelivery"
+	"github.com/qinzj/superpowers-demo/ent/webhooksubscription"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -76,10 +83,17 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			idpconnector.Table: idpconnector.ValidColumn,
-			oauth2client.Table: oauth2client.ValidColumn,
-			session.Table:      session.ValidColumn,
-			user.Table:         user.ValidColumn,
+			auditevent.Table:          auditevent.ValidColumn,
+			consent.Table:             consent.ValidColumn,
+			group.Table:               group.ValidColumn,
+			idpconnector.Table:        idpconnector.ValidColumn,
+			linkedidentity.Table:      linkedidentity.ValidColumn,
+			oauth2client.Table:        oauth2client.ValidColumn,
+			role.Table:                role.ValidColumn,
+			session.Table:             session.ValidColumn,
+			user.Table:                user.ValidColumn,
+			webhookdelivery.Table:     webhookdelivery.ValidColumn,
+			webhooksubscription.Table: webhooksubscription.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
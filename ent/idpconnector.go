@@ -22,6 +22,8 @@ type IdPConnector struct {
 	ClientID string `json:"client_id,omitempty"`
 	// ClientSecret holds the value of the "client_secret" field.
 	ClientSecret string `json:"client_secret,omitempty"`
+	// GroupsClaim holds the value of the "groups_claim" field.
+	GroupsClaim  string `json:"groups_claim,omitempty"`
 	selectValues sql.SelectValues
 }
 
@@ -32,7 +34,7 @@ func (*IdPConnector) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case idpconnector.FieldID:
 			values[i] = new(sql.NullInt64)
-		case idpconnector.FieldIssuer, idpconnector.FieldClientID, idpconnector.FieldClientSecret:
+		case idpconnector.FieldIssuer, idpconnector.FieldClientID, idpconnector.FieldClientSecret, idpconnector.FieldGroupsClaim:
 			values[i] = new(sql.NullString)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -73,6 +75,12 @@ func (ip *IdPConnector) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				ip.ClientSecret = value.String
 			}
+		case idpconnector.FieldGroupsClaim:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field groups_claim", values[i])
+			} else if value.Valid {
+				ip.GroupsClaim = value.String
+			}
 		default:
 			ip.selectValues.Set(columns[i], values[i])
 		}
@@ -117,6 +125,9 @@ func (ip *IdPConnector) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("client_secret=")
 	builder.WriteString(ip.ClientSecret)
+	builder.WriteString(", ")
+	builder.WriteString("groups_claim=")
+	builder.WriteString(ip.GroupsClaim)
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -17,6 +17,8 @@ const (
 	FieldClientID = "client_id"
 	// FieldClientSecret holds the string denoting the client_secret field in the database.
 	FieldClientSecret = "client_secret"
+	// FieldGroupsClaim holds the string denoting the groups_claim field in the database.
+	FieldGroupsClaim = "groups_claim"
 	// Table holds the table name of the idpconnector in the database.
 	Table = "id_pconnectors"
 )
@@ -27,6 +29,7 @@ var Columns = []string{
 	FieldIssuer,
 	FieldClientID,
 	FieldClientSecret,
+	FieldGroupsClaim,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -46,6 +49,8 @@ var (
 	ClientIDValidator func(string) error
 	// ClientSecretValidator is a validator for the "client_secret" field. It is called by the builders before save.
 	ClientSecretValidator func(string) error
+	// DefaultGroupsClaim holds the default value on creation for the "groups_claim" field.
+	DefaultGroupsClaim string
 )
 
 // OrderOption defines the ordering options for the IdPConnector queries.
@@ -70,3 +75,8 @@ func ByClientID(opts ...sql.OrderTermOption) OrderOption {
 func ByClientSecret(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldClientSecret, opts...).ToFunc()
 }
+
+// ByGroupsClaim orders the results by the groups_claim field.
+func ByGroupsClaim(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldGroupsClaim, opts...).ToFunc()
+}
@@ -67,6 +67,11 @@ func ClientSecret(v string) predicate.IdPConnector {
 	return predicate.IdPConnector(sql.FieldEQ(FieldClientSecret, v))
 }
 
+// GroupsClaim applies equality check predicate on the "groups_claim" field. It's identical to GroupsClaimEQ.
+func GroupsClaim(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldEQ(FieldGroupsClaim, v))
+}
+
 // IssuerEQ applies the EQ predicate on the "issuer" field.
 func IssuerEQ(v string) predicate.IdPConnector {
 	return predicate.IdPConnector(sql.FieldEQ(FieldIssuer, v))
@@ -262,6 +267,71 @@ func ClientSecretContainsFold(v string) predicate.IdPConnector {
 	return predicate.IdPConnector(sql.FieldContainsFold(FieldClientSecret, v))
 }
 
+// GroupsClaimEQ applies the EQ predicate on the "groups_claim" field.
+func GroupsClaimEQ(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldEQ(FieldGroupsClaim, v))
+}
+
+// GroupsClaimNEQ applies the NEQ predicate on the "groups_claim" field.
+func GroupsClaimNEQ(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldNEQ(FieldGroupsClaim, v))
+}
+
+// GroupsClaimIn applies the In predicate on the "groups_claim" field.
+func GroupsClaimIn(vs ...string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldIn(FieldGroupsClaim, vs...))
+}
+
+// GroupsClaimNotIn applies the NotIn predicate on the "groups_claim" field.
+func GroupsClaimNotIn(vs ...string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldNotIn(FieldGroupsClaim, vs...))
+}
+
+// GroupsClaimGT applies the GT predicate on the "groups_claim" field.
+func GroupsClaimGT(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldGT(FieldGroupsClaim, v))
+}
+
+// GroupsClaimGTE applies the GTE predicate on the "groups_claim" field.
+func GroupsClaimGTE(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldGTE(FieldGroupsClaim, v))
+}
+
+// GroupsClaimLT applies the LT predicate on the "groups_claim" field.
+func GroupsClaimLT(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldLT(FieldGroupsClaim, v))
+}
+
+// GroupsClaimLTE applies the LTE predicate on the "groups_claim" field.
+func GroupsClaimLTE(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldLTE(FieldGroupsClaim, v))
+}
+
+// GroupsClaimContains applies the Contains predicate on the "groups_claim" field.
+func GroupsClaimContains(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldContains(FieldGroupsClaim, v))
+}
+
+// GroupsClaimHasPrefix applies the HasPrefix predicate on the "groups_claim" field.
+func GroupsClaimHasPrefix(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldHasPrefix(FieldGroupsClaim, v))
+}
+
+// GroupsClaimHasSuffix applies the HasSuffix predicate on the "groups_claim" field.
+func GroupsClaimHasSuffix(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldHasSuffix(FieldGroupsClaim, v))
+}
+
+// GroupsClaimEqualFold applies the EqualFold predicate on the "groups_claim" field.
+func GroupsClaimEqualFold(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldEqualFold(FieldGroupsClaim, v))
+}
+
+// GroupsClaimContainsFold applies the ContainsFold predicate on the "groups_claim" field.
+func GroupsClaimContainsFold(v string) predicate.IdPConnector {
+	return predicate.IdPConnector(sql.FieldContainsFold(FieldGroupsClaim, v))
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.IdPConnector) predicate.IdPConnector {
 	return predicate.IdPConnector(sql.AndPredicates(predicates...))
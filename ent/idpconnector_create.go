@@ -37,6 +37,20 @@ func (ipc *IdPConnectorCreate) SetClientSecret(s string) *IdPConnectorCreate {
 	return ipc
 }
 
+// SetGroupsClaim sets the "groups_claim" field.
+func (ipc *IdPConnectorCreate) SetGroupsClaim(s string) *IdPConnectorCreate {
+	ipc.mutation.SetGroupsClaim(s)
+	return ipc
+}
+
+// SetNillableGroupsClaim sets the "groups_claim" field if the given value is not nil.
+func (ipc *IdPConnectorCreate) SetNillableGroupsClaim(s *string) *IdPConnectorCreate {
+	if s != nil {
+		ipc.SetGroupsClaim(*s)
+	}
+	return ipc
+}
+
 // Mutation returns the IdPConnectorMutation object of the builder.
 func (ipc *IdPConnectorCreate) Mutation() *IdPConnectorMutation {
 	return ipc.mutation
@@ -44,6 +58,7 @@ func (ipc *IdPConnectorCreate) Mutation() *IdPConnectorMutation {
 
 // Save creates the IdPConnector in the database.
 func (ipc *IdPConnectorCreate) Save(ctx context.Context) (*IdPConnector, error) {
+	ipc.defaults()
 	return withHooks(ctx, ipc.sqlSave, ipc.mutation, ipc.hooks)
 }
 
@@ -69,6 +84,14 @@ func (ipc *IdPConnectorCreate) ExecX(ctx context.Context) {
 	}
 }
 
+// defaults sets the default values of the builder before save.
+func (ipc *IdPConnectorCreate) defaults() {
+	if _, ok := ipc.mutation.GroupsClaim(); !ok {
+		v := idpconnector.DefaultGroupsClaim
+		ipc.mutation.SetGroupsClaim(v)
+	}
+}
+
 // check runs all checks and user-defined validators on the builder.
 func (ipc *IdPConnectorCreate) check() error {
 	if _, ok := ipc.mutation.Issuer(); !ok {
@@ -95,6 +118,9 @@ func (ipc *IdPConnectorCreate) check() error {
 			return &ValidationError{Name: "client_secret", err: fmt.Errorf(`ent: validator failed for field "IdPConnector.client_secret": %w`, err)}
 		}
 	}
+	if _, ok := ipc.mutation.GroupsClaim(); !ok {
+		return &ValidationError{Name: "groups_claim", err: errors.New(`ent: missing required field "IdPConnector.groups_claim"`)}
+	}
 	return nil
 }
 
@@ -133,6 +159,10 @@ func (ipc *IdPConnectorCreate) createSpec() (*IdPConnector, *sqlgraph.CreateSpec
 		_spec.SetField(idpconnector.FieldClientSecret, field.TypeString, value)
 		_node.ClientSecret = value
 	}
+	if value, ok := ipc.mutation.GroupsClaim(); ok {
+		_spec.SetField(idpconnector.FieldGroupsClaim, field.TypeString, value)
+		_node.GroupsClaim = value
+	}
 	return _node, _spec
 }
 
@@ -154,6 +184,7 @@ func (ipcb *IdPConnectorCreateBulk) Save(ctx context.Context) ([]*IdPConnector,
 	for i := range ipcb.builders {
 		func(i int, root context.Context) {
 			builder := ipcb.builders[i]
+			builder.defaults()
 			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
 				mutation, ok := m.(*IdPConnectorMutation)
 				if !ok {
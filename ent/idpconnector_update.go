@@ -69,6 +69,20 @@ func (ipu *IdPConnectorUpdate) SetNillableClientSecret(s *string) *IdPConnectorU
 	return ipu
 }
 
+// SetGroupsClaim sets the "groups_claim" field.
+func (ipu *IdPConnectorUpdate) SetGroupsClaim(s string) *IdPConnectorUpdate {
+	ipu.mutation.SetGroupsClaim(s)
+	return ipu
+}
+
+// SetNillableGroupsClaim sets the "groups_claim" field if the given value is not nil.
+func (ipu *IdPConnectorUpdate) SetNillableGroupsClaim(s *string) *IdPConnectorUpdate {
+	if s != nil {
+		ipu.SetGroupsClaim(*s)
+	}
+	return ipu
+}
+
 // Mutation returns the IdPConnectorMutation object of the builder.
 func (ipu *IdPConnectorUpdate) Mutation() *IdPConnectorMutation {
 	return ipu.mutation
@@ -142,6 +156,9 @@ func (ipu *IdPConnectorUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := ipu.mutation.ClientSecret(); ok {
 		_spec.SetField(idpconnector.FieldClientSecret, field.TypeString, value)
 	}
+	if value, ok := ipu.mutation.GroupsClaim(); ok {
+		_spec.SetField(idpconnector.FieldGroupsClaim, field.TypeString, value)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, ipu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{idpconnector.Label}
@@ -204,6 +221,20 @@ func (ipuo *IdPConnectorUpdateOne) SetNillableClientSecret(s *string) *IdPConnec
 	return ipuo
 }
 
+// SetGroupsClaim sets the "groups_claim" field.
+func (ipuo *IdPConnectorUpdateOne) SetGroupsClaim(s string) *IdPConnectorUpdateOne {
+	ipuo.mutation.SetGroupsClaim(s)
+	return ipuo
+}
+
+// SetNillableGroupsClaim sets the "groups_claim" field if the given value is not nil.
+func (ipuo *IdPConnectorUpdateOne) SetNillableGroupsClaim(s *string) *IdPConnectorUpdateOne {
+	if s != nil {
+		ipuo.SetGroupsClaim(*s)
+	}
+	return ipuo
+}
+
 // Mutation returns the IdPConnectorMutation object of the builder.
 func (ipuo *IdPConnectorUpdateOne) Mutation() *IdPConnectorMutation {
 	return ipuo.mutation
@@ -307,6 +338,9 @@ func (ipuo *IdPConnectorUpdateOne) sqlSave(ctx context.Context) (_node *IdPConne
 	if value, ok := ipuo.mutation.ClientSecret(); ok {
 		_spec.SetField(idpconnector.FieldClientSecret, field.TypeString, value)
 	}
+	if value, ok := ipuo.mutation.GroupsClaim(); ok {
+		_spec.SetField(idpconnector.FieldGroupsClaim, field.TypeString, value)
+	}
 	_node = &IdPConnector{config: ipuo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -0,0 +1,180 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/user"
+)
+
+// LinkedIdentity is the model entity for the LinkedIdentity schema.
+type LinkedIdentity struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// ConnectorID holds the value of the "connector_id" field.
+	ConnectorID string `json:"connector_id,omitempty"`
+	// Subject holds the value of the "subject" field.
+	Subject string `json:"subject,omitempty"`
+	// Email holds the value of the "email" field.
+	Email string `json:"email,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the LinkedIdentityQuery when eager-loading is set.
+	Edges           LinkedIdentityEdges `json:"edges"`
+	user_identities *int
+	selectValues    sql.SelectValues
+}
+
+// LinkedIdentityEdges holds the relations/edges for other nodes in the graph.
+type LinkedIdentityEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e LinkedIdentityEdges) UserOrErr() (*User, error) {
+	if e.loadedTypes[0] {
+		if e.User == nil {
+			// Edge was loaded but was not found.
+			return nil, &NotFoundError{label: user.Label}
+		}
+		return e.User, nil
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*LinkedIdentity) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case linkedidentity.FieldID:
+			values[i] = new(sql.NullInt64)
+		case linkedidentity.FieldConnectorID, linkedidentity.FieldSubject, linkedidentity.FieldEmail:
+			values[i] = new(sql.NullString)
+		case linkedidentity.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		case linkedidentity.ForeignKeys[0]: // user_identities
+			values[i] = new(sql.NullInt64)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the LinkedIdentity fields.
+func (li *LinkedIdentity) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case linkedidentity.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			li.ID = int(value.Int64)
+		case linkedidentity.FieldConnectorID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field connector_id", values[i])
+			} else if value.Valid {
+				li.ConnectorID = value.String
+			}
+		case linkedidentity.FieldSubject:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field subject", values[i])
+			} else if value.Valid {
+				li.Subject = value.String
+			}
+		case linkedidentity.FieldEmail:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field email", values[i])
+			} else if value.Valid {
+				li.Email = value.String
+			}
+		case linkedidentity.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				li.CreatedAt = value.Time
+			}
+		case linkedidentity.ForeignKeys[0]:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for edge-field user_identities", value)
+			} else if value.Valid {
+				li.user_identities = new(int)
+				*li.user_identities = int(value.Int64)
+			}
+		default:
+			li.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the LinkedIdentity.
+// This includes values selected through modifiers, order, etc.
+func (li *LinkedIdentity) Value(name string) (ent.Value, error) {
+	return li.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the LinkedIdentity entity.
+func (li *LinkedIdentity) QueryUser() *UserQuery {
+	return NewLinkedIdentityClient(li.config).QueryUser(li)
+}
+
+// Update returns a builder for updating this LinkedIdentity.
+// Note that you need to call LinkedIdentity.Unwrap() before calling this method if this LinkedIdentity
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (li *LinkedIdentity) Update() *LinkedIdentityUpdateOne {
+	return NewLinkedIdentityClient(li.config).UpdateOne(li)
+}
+
+// Unwrap unwraps the LinkedIdentity entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (li *LinkedIdentity) Unwrap() *LinkedIdentity {
+	_tx, ok := li.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: LinkedIdentity is not a transactional entity")
+	}
+	li.config.driver = _tx.drv
+	return li
+}
+
+// String implements the fmt.Stringer.
+func (li *LinkedIdentity) String() string {
+	var builder strings.Builder
+	builder.WriteString("LinkedIdentity(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", li.ID))
+	builder.WriteString("connector_id=")
+	builder.WriteString(li.ConnectorID)
+	builder.WriteString(", ")
+	builder.WriteString("subject=")
+	builder.WriteString(li.Subject)
+	builder.WriteString(", ")
+	builder.WriteString("email=")
+	builder.WriteString(li.Email)
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(li.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// LinkedIdentities is a parsable slice of LinkedIdentity.
+type LinkedIdentities []*LinkedIdentity
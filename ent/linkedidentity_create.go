@@ -0,0 +1,295 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/user"
+)
+
+// LinkedIdentityCreate is the builder for creating a LinkedIdentity entity.
+type LinkedIdentityCreate struct {
+	config
+	mutation *LinkedIdentityMutation
+	hooks    []Hook
+}
+
+// SetConnectorID sets the "connector_id" field.
+func (lic *LinkedIdentityCreate) SetConnectorID(s string) *LinkedIdentityCreate {
+	lic.mutation.SetConnectorID(s)
+	return lic
+}
+
+// SetSubject sets the "subject" field.
+func (lic *LinkedIdentityCreate) SetSubject(s string) *LinkedIdentityCreate {
+	lic.mutation.SetSubject(s)
+	return lic
+}
+
+// SetEmail sets the "email" field.
+func (lic *LinkedIdentityCreate) SetEmail(s string) *LinkedIdentityCreate {
+	lic.mutation.SetEmail(s)
+	return lic
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (lic *LinkedIdentityCreate) SetNillableEmail(s *string) *LinkedIdentityCreate {
+	if s != nil {
+		lic.SetEmail(*s)
+	}
+	return lic
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (lic *LinkedIdentityCreate) SetCreatedAt(t time.Time) *LinkedIdentityCreate {
+	lic.mutation.SetCreatedAt(t)
+	return lic
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (lic *LinkedIdentityCreate) SetNillableCreatedAt(t *time.Time) *LinkedIdentityCreate {
+	if t != nil {
+		lic.SetCreatedAt(*t)
+	}
+	return lic
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (lic *LinkedIdentityCreate) SetUserID(id int) *LinkedIdentityCreate {
+	lic.mutation.SetUserID(id)
+	return lic
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (lic *LinkedIdentityCreate) SetUser(u *User) *LinkedIdentityCreate {
+	return lic.SetUserID(u.ID)
+}
+
+// Mutation returns the LinkedIdentityMutation object of the builder.
+func (lic *LinkedIdentityCreate) Mutation() *LinkedIdentityMutation {
+	return lic.mutation
+}
+
+// Save creates the LinkedIdentity in the database.
+func (lic *LinkedIdentityCreate) Save(ctx context.Context) (*LinkedIdentity, error) {
+	lic.defaults()
+	return withHooks(ctx, lic.sqlSave, lic.mutation, lic.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (lic *LinkedIdentityCreate) SaveX(ctx context.Context) *LinkedIdentity {
+	v, err := lic.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lic *LinkedIdentityCreate) Exec(ctx context.Context) error {
+	_, err := lic.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lic *LinkedIdentityCreate) ExecX(ctx context.Context) {
+	if err := lic.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (lic *LinkedIdentityCreate) defaults() {
+	if _, ok := lic.mutation.Email(); !ok {
+		v := linkedidentity.DefaultEmail
+		lic.mutation.SetEmail(v)
+	}
+	if _, ok := lic.mutation.CreatedAt(); !ok {
+		v := linkedidentity.DefaultCreatedAt()
+		lic.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (lic *LinkedIdentityCreate) check() error {
+	if _, ok := lic.mutation.ConnectorID(); !ok {
+		return &ValidationError{Name: "connector_id", err: errors.New(`ent: missing required field "LinkedIdentity.connector_id"`)}
+	}
+	if v, ok := lic.mutation.ConnectorID(); ok {
+		if err := linkedidentity.ConnectorIDValidator(v); err != nil {
+			return &ValidationError{Name: "connector_id", err: fmt.Errorf(`ent: validator failed for field "LinkedIdentity.connector_id": %w`, err)}
+		}
+	}
+	if _, ok := lic.mutation.Subject(); !ok {
+		return &ValidationError{Name: "subject", err: errors.New(`ent: missing required field "LinkedIdentity.subject"`)}
+	}
+	if v, ok := lic.mutation.Subject(); ok {
+		if err := linkedidentity.SubjectValidator(v); err != nil {
+			return &ValidationError{Name: "subject", err: fmt.Errorf(`ent: validator failed for field "LinkedIdentity.subject": %w`, err)}
+		}
+	}
+	if _, ok := lic.mutation.Email(); !ok {
+		return &ValidationError{Name: "email", err: errors.New(`ent: missing required field "LinkedIdentity.email"`)}
+	}
+	if _, ok := lic.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "LinkedIdentity.created_at"`)}
+	}
+	if _, ok := lic.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "LinkedIdentity.user"`)}
+	}
+	return nil
+}
+
+func (lic *LinkedIdentityCreate) sqlSave(ctx context.Context) (*LinkedIdentity, error) {
+	if err := lic.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := lic.createSpec()
+	if err := sqlgraph.CreateNode(ctx, lic.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	lic.mutation.id = &_node.ID
+	lic.mutation.done = true
+	return _node, nil
+}
+
+func (lic *LinkedIdentityCreate) createSpec() (*LinkedIdentity, *sqlgraph.CreateSpec) {
+	var (
+		_node = &LinkedIdentity{config: lic.config}
+		_spec = sqlgraph.NewCreateSpec(linkedidentity.Table, sqlgraph.NewFieldSpec(linkedidentity.FieldID, field.TypeInt))
+	)
+	if value, ok := lic.mutation.ConnectorID(); ok {
+		_spec.SetField(linkedidentity.FieldConnectorID, field.TypeString, value)
+		_node.ConnectorID = value
+	}
+	if value, ok := lic.mutation.Subject(); ok {
+		_spec.SetField(linkedidentity.FieldSubject, field.TypeString, value)
+		_node.Subject = value
+	}
+	if value, ok := lic.mutation.Email(); ok {
+		_spec.SetField(linkedidentity.FieldEmail, field.TypeString, value)
+		_node.Email = value
+	}
+	if value, ok := lic.mutation.CreatedAt(); ok {
+		_spec.SetField(linkedidentity.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := lic.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   linkedidentity.UserTable,
+			Columns: []string{linkedidentity.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.user_identities = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// LinkedIdentityCreateBulk is the builder for creating many LinkedIdentity entities in bulk.
+type LinkedIdentityCreateBulk struct {
+	config
+	err      error
+	builders []*LinkedIdentityCreate
+}
+
+// Save creates the LinkedIdentity entities in the database.
+func (licb *LinkedIdentityCreateBulk) Save(ctx context.Context) ([]*LinkedIdentity, error) {
+	if licb.err != nil {
+		return nil, licb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(licb.builders))
+	nodes := make([]*LinkedIdentity, len(licb.builders))
+	mutators := make([]Mutator, len(licb.builders))
+	for i := range licb.builders {
+		func(i int, root context.Context) {
+			builder := licb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LinkedIdentityMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, licb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, licb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, licb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (licb *LinkedIdentityCreateBulk) SaveX(ctx context.Context) []*LinkedIdentity {
+	v, err := licb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (licb *LinkedIdentityCreateBulk) Exec(ctx context.Context) error {
+	_, err := licb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (licb *LinkedIdentityCreateBulk) ExecX(ctx context.Context) {
+	if err := licb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,613 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/predicate"
+	"github.com/qinzj/superpowers-demo/ent/user"
+)
+
+// LinkedIdentityQuery is the builder for querying LinkedIdentity entities.
+type LinkedIdentityQuery struct {
+	config
+	ctx        *QueryContext
+	order      []linkedidentity.OrderOption
+	inters     []Interceptor
+	predicates []predicate.LinkedIdentity
+	withUser   *UserQuery
+	withFKs    bool
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the LinkedIdentityQuery builder.
+func (liq *LinkedIdentityQuery) Where(ps ...predicate.LinkedIdentity) *LinkedIdentityQuery {
+	liq.predicates = append(liq.predicates, ps...)
+	return liq
+}
+
+// Limit the number of records to be returned by this query.
+func (liq *LinkedIdentityQuery) Limit(limit int) *LinkedIdentityQuery {
+	liq.ctx.Limit = &limit
+	return liq
+}
+
+// Offset to start from.
+func (liq *LinkedIdentityQuery) Offset(offset int) *LinkedIdentityQuery {
+	liq.ctx.Offset = &offset
+	return liq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (liq *LinkedIdentityQuery) Unique(unique bool) *LinkedIdentityQuery {
+	liq.ctx.Unique = &unique
+	return liq
+}
+
+// Order specifies how the records should be ordered.
+func (liq *LinkedIdentityQuery) Order(o ...linkedidentity.OrderOption) *LinkedIdentityQuery {
+	liq.order = append(liq.order, o...)
+	return liq
+}
+
+// QueryUser chains the current query on the "user" edge.
+func (liq *LinkedIdentityQuery) QueryUser() *UserQuery {
+	query := (&UserClient{config: liq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := liq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := liq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(linkedidentity.Table, linkedidentity.FieldID, selector),
+			sqlgraph.To(user.Table, user.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, linkedidentity.UserTable, linkedidentity.UserColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(liq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
+// First returns the first LinkedIdentity entity from the query.
+// Returns a *NotFoundError when no LinkedIdentity was found.
+func (liq *LinkedIdentityQuery) First(ctx context.Context) (*LinkedIdentity, error) {
+	nodes, err := liq.Limit(1).All(setContextOp(ctx, liq.ctx, "First"))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{linkedidentity.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) FirstX(ctx context.Context) *LinkedIdentity {
+	node, err := liq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first LinkedIdentity ID from the query.
+// Returns a *NotFoundError when no LinkedIdentity ID was found.
+func (liq *LinkedIdentityQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = liq.Limit(1).IDs(setContextOp(ctx, liq.ctx, "FirstID")); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{linkedidentity.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) FirstIDX(ctx context.Context) int {
+	id, err := liq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single LinkedIdentity entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one LinkedIdentity entity is found.
+// Returns a *NotFoundError when no LinkedIdentity entities are found.
+func (liq *LinkedIdentityQuery) Only(ctx context.Context) (*LinkedIdentity, error) {
+	nodes, err := liq.Limit(2).All(setContextOp(ctx, liq.ctx, "Only"))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{linkedidentity.Label}
+	default:
+		return nil, &NotSingularError{linkedidentity.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) OnlyX(ctx context.Context) *LinkedIdentity {
+	node, err := liq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only LinkedIdentity ID in the query.
+// Returns a *NotSingularError when more than one LinkedIdentity ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (liq *LinkedIdentityQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = liq.Limit(2).IDs(setContextOp(ctx, liq.ctx, "OnlyID")); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{linkedidentity.Label}
+	default:
+		err = &NotSingularError{linkedidentity.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) OnlyIDX(ctx context.Context) int {
+	id, err := liq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of LinkedIdentities.
+func (liq *LinkedIdentityQuery) All(ctx context.Context) ([]*LinkedIdentity, error) {
+	ctx = setContextOp(ctx, liq.ctx, "All")
+	if err := liq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*LinkedIdentity, *LinkedIdentityQuery]()
+	return withInterceptors[[]*LinkedIdentity](ctx, liq, qr, liq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) AllX(ctx context.Context) []*LinkedIdentity {
+	nodes, err := liq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of LinkedIdentity IDs.
+func (liq *LinkedIdentityQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if liq.ctx.Unique == nil && liq.path != nil {
+		liq.Unique(true)
+	}
+	ctx = setContextOp(ctx, liq.ctx, "IDs")
+	if err = liq.Select(linkedidentity.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) IDsX(ctx context.Context) []int {
+	ids, err := liq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (liq *LinkedIdentityQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, liq.ctx, "Count")
+	if err := liq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, liq, querierCount[*LinkedIdentityQuery](), liq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) CountX(ctx context.Context) int {
+	count, err := liq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (liq *LinkedIdentityQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, liq.ctx, "Exist")
+	switch _, err := liq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (liq *LinkedIdentityQuery) ExistX(ctx context.Context) bool {
+	exist, err := liq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the LinkedIdentityQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (liq *LinkedIdentityQuery) Clone() *LinkedIdentityQuery {
+	if liq == nil {
+		return nil
+	}
+	return &LinkedIdentityQuery{
+		config:     liq.config,
+		ctx:        liq.ctx.Clone(),
+		order:      append([]linkedidentity.OrderOption{}, liq.order...),
+		inters:     append([]Interceptor{}, liq.inters...),
+		predicates: append([]predicate.LinkedIdentity{}, liq.predicates...),
+		withUser:   liq.withUser.Clone(),
+		// clone intermediate query.
+		sql:  liq.sql.Clone(),
+		path: liq.path,
+	}
+}
+
+// WithUser tells the query-builder to eager-load the nodes that are connected to
+// the "user" edge. The optional arguments are used to configure the query builder of the edge.
+func (liq *LinkedIdentityQuery) WithUser(opts ...func(*UserQuery)) *LinkedIdentityQuery {
+	query := (&UserClient{config: liq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	liq.withUser = query
+	return liq
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		ConnectorID string `json:"connector_id,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.LinkedIdentity.Query().
+//		GroupBy(linkedidentity.FieldConnectorID).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (liq *LinkedIdentityQuery) GroupBy(field string, fields ...string) *LinkedIdentityGroupBy {
+	liq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &LinkedIdentityGroupBy{build: liq}
+	grbuild.flds = &liq.ctx.Fields
+	grbuild.label = linkedidentity.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		ConnectorID string `json:"connector_id,omitempty"`
+//	}
+//
+//	client.LinkedIdentity.Query().
+//		Select(linkedidentity.FieldConnectorID).
+//		Scan(ctx, &v)
+func (liq *LinkedIdentityQuery) Select(fields ...string) *LinkedIdentitySelect {
+	liq.ctx.Fields = append(liq.ctx.Fields, fields...)
+	sbuild := &LinkedIdentitySelect{LinkedIdentityQuery: liq}
+	sbuild.label = linkedidentity.Label
+	sbuild.flds, sbuild.scan = &liq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a LinkedIdentitySelect configured with the given aggregations.
+func (liq *LinkedIdentityQuery) Aggregate(fns ...AggregateFunc) *LinkedIdentitySelect {
+	return liq.Select().Aggregate(fns...)
+}
+
+func (liq *LinkedIdentityQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range liq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, liq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range liq.ctx.Fields {
+		if !linkedidentity.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if liq.path != nil {
+		prev, err := liq.path(ctx)
+		if err != nil {
+			return err
+		}
+		liq.sql = prev
+	}
+	return nil
+}
+
+func (liq *LinkedIdentityQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*LinkedIdentity, error) {
+	var (
+		nodes       = []*LinkedIdentity{}
+		withFKs     = liq.withFKs
+		_spec       = liq.querySpec()
+		loadedTypes = [1]bool{
+			liq.withUser != nil,
+		}
+	)
+	if liq.withUser != nil {
+		withFKs = true
+	}
+	if withFKs {
+		_spec.Node.Columns = append(_spec.Node.Columns, linkedidentity.ForeignKeys...)
+	}
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*LinkedIdentity).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &LinkedIdentity{config: liq.config}
+		nodes = append(nodes, node)
+		node.Edges.loadedTypes = loadedTypes
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, liq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	if query := liq.withUser; query != nil {
+		if err := liq.loadUser(ctx, query, nodes, nil,
+			func(n *LinkedIdentity, e *User) { n.Edges.User = e }); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (liq *LinkedIdentityQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*LinkedIdentity, init func(*LinkedIdentity), assign func(*LinkedIdentity, *User)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*LinkedIdentity)
+	for i := range nodes {
+		if nodes[i].user_identities == nil {
+			continue
+		}
+		fk := *nodes[i].user_identities
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(user.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "user_identities" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
+
+func (liq *LinkedIdentityQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := liq.querySpec()
+	_spec.Node.Columns = liq.ctx.Fields
+	if len(liq.ctx.Fields) > 0 {
+		_spec.Unique = liq.ctx.Unique != nil && *liq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, liq.driver, _spec)
+}
+
+func (liq *LinkedIdentityQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(linkedidentity.Table, linkedidentity.Columns, sqlgraph.NewFieldSpec(linkedidentity.FieldID, field.TypeInt))
+	_spec.From = liq.sql
+	if unique := liq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if liq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := liq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, linkedidentity.FieldID)
+		for i := range fields {
+			if fields[i] != linkedidentity.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := liq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := liq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := liq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := liq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (liq *LinkedIdentityQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(liq.driver.Dialect())
+	t1 := builder.Table(linkedidentity.Table)
+	columns := liq.ctx.Fields
+	if len(columns) == 0 {
+		columns = linkedidentity.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if liq.sql != nil {
+		selector = liq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if liq.ctx.Unique != nil && *liq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range liq.predicates {
+		p(selector)
+	}
+	for _, p := range liq.order {
+		p(selector)
+	}
+	if offset := liq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := liq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// LinkedIdentityGroupBy is the group-by builder for LinkedIdentity entities.
+type LinkedIdentityGroupBy struct {
+	selector
+	build *LinkedIdentityQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ligb *LinkedIdentityGroupBy) Aggregate(fns ...AggregateFunc) *LinkedIdentityGroupBy {
+	ligb.fns = append(ligb.fns, fns...)
+	return ligb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ligb *LinkedIdentityGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ligb.build.ctx, "GroupBy")
+	if err := ligb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*LinkedIdentityQuery, *LinkedIdentityGroupBy](ctx, ligb.build, ligb, ligb.build.inters, v)
+}
+
+func (ligb *LinkedIdentityGroupBy) sqlScan(ctx context.Context, root *LinkedIdentityQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ligb.fns))
+	for _, fn := range ligb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ligb.flds)+len(ligb.fns))
+		for _, f := range *ligb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ligb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ligb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// LinkedIdentitySelect is the builder for selecting fields of LinkedIdentity entities.
+type LinkedIdentitySelect struct {
+	*LinkedIdentityQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (lis *LinkedIdentitySelect) Aggregate(fns ...AggregateFunc) *LinkedIdentitySelect {
+	lis.fns = append(lis.fns, fns...)
+	return lis
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (lis *LinkedIdentitySelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, lis.ctx, "Select")
+	if err := lis.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*LinkedIdentityQuery, *LinkedIdentitySelect](ctx, lis.LinkedIdentityQuery, lis, lis.inters, v)
+}
+
+func (lis *LinkedIdentitySelect) sqlScan(ctx context.Context, root *LinkedIdentityQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(lis.fns))
+	for _, fn := range lis.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*lis.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := lis.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
@@ -0,0 +1,412 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/predicate"
+	"github.com/qinzj/superpowers-demo/ent/user"
+)
+
+// LinkedIdentityUpdate is the builder for updating LinkedIdentity entities.
+type LinkedIdentityUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LinkedIdentityMutation
+}
+
+// Where appends a list predicates to the LinkedIdentityUpdate builder.
+func (liu *LinkedIdentityUpdate) Where(ps ...predicate.LinkedIdentity) *LinkedIdentityUpdate {
+	liu.mutation.Where(ps...)
+	return liu
+}
+
+// SetConnectorID sets the "connector_id" field.
+func (liu *LinkedIdentityUpdate) SetConnectorID(s string) *LinkedIdentityUpdate {
+	liu.mutation.SetConnectorID(s)
+	return liu
+}
+
+// SetNillableConnectorID sets the "connector_id" field if the given value is not nil.
+func (liu *LinkedIdentityUpdate) SetNillableConnectorID(s *string) *LinkedIdentityUpdate {
+	if s != nil {
+		liu.SetConnectorID(*s)
+	}
+	return liu
+}
+
+// SetSubject sets the "subject" field.
+func (liu *LinkedIdentityUpdate) SetSubject(s string) *LinkedIdentityUpdate {
+	liu.mutation.SetSubject(s)
+	return liu
+}
+
+// SetNillableSubject sets the "subject" field if the given value is not nil.
+func (liu *LinkedIdentityUpdate) SetNillableSubject(s *string) *LinkedIdentityUpdate {
+	if s != nil {
+		liu.SetSubject(*s)
+	}
+	return liu
+}
+
+// SetEmail sets the "email" field.
+func (liu *LinkedIdentityUpdate) SetEmail(s string) *LinkedIdentityUpdate {
+	liu.mutation.SetEmail(s)
+	return liu
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (liu *LinkedIdentityUpdate) SetNillableEmail(s *string) *LinkedIdentityUpdate {
+	if s != nil {
+		liu.SetEmail(*s)
+	}
+	return liu
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (liu *LinkedIdentityUpdate) SetUserID(id int) *LinkedIdentityUpdate {
+	liu.mutation.SetUserID(id)
+	return liu
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (liu *LinkedIdentityUpdate) SetUser(u *User) *LinkedIdentityUpdate {
+	return liu.SetUserID(u.ID)
+}
+
+// Mutation returns the LinkedIdentityMutation object of the builder.
+func (liu *LinkedIdentityUpdate) Mutation() *LinkedIdentityMutation {
+	return liu.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (liu *LinkedIdentityUpdate) ClearUser() *LinkedIdentityUpdate {
+	liu.mutation.ClearUser()
+	return liu
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (liu *LinkedIdentityUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, liu.sqlSave, liu.mutation, liu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (liu *LinkedIdentityUpdate) SaveX(ctx context.Context) int {
+	affected, err := liu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (liu *LinkedIdentityUpdate) Exec(ctx context.Context) error {
+	_, err := liu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (liu *LinkedIdentityUpdate) ExecX(ctx context.Context) {
+	if err := liu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (liu *LinkedIdentityUpdate) check() error {
+	if v, ok := liu.mutation.ConnectorID(); ok {
+		if err := linkedidentity.ConnectorIDValidator(v); err != nil {
+			return &ValidationError{Name: "connector_id", err: fmt.Errorf(`ent: validator failed for field "LinkedIdentity.connector_id": %w`, err)}
+		}
+	}
+	if v, ok := liu.mutation.Subject(); ok {
+		if err := linkedidentity.SubjectValidator(v); err != nil {
+			return &ValidationError{Name: "subject", err: fmt.Errorf(`ent: validator failed for field "LinkedIdentity.subject": %w`, err)}
+		}
+	}
+	if _, ok := liu.mutation.UserID(); liu.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "LinkedIdentity.user"`)
+	}
+	return nil
+}
+
+func (liu *LinkedIdentityUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := liu.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(linkedidentity.Table, linkedidentity.Columns, sqlgraph.NewFieldSpec(linkedidentity.FieldID, field.TypeInt))
+	if ps := liu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := liu.mutation.ConnectorID(); ok {
+		_spec.SetField(linkedidentity.FieldConnectorID, field.TypeString, value)
+	}
+	if value, ok := liu.mutation.Subject(); ok {
+		_spec.SetField(linkedidentity.FieldSubject, field.TypeString, value)
+	}
+	if value, ok := liu.mutation.Email(); ok {
+		_spec.SetField(linkedidentity.FieldEmail, field.TypeString, value)
+	}
+	if liu.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   linkedidentity.UserTable,
+			Columns: []string{linkedidentity.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := liu.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   linkedidentity.UserTable,
+			Columns: []string{linkedidentity.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, liu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{linkedidentity.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	liu.mutation.done = true
+	return n, nil
+}
+
+// LinkedIdentityUpdateOne is the builder for updating a single LinkedIdentity entity.
+type LinkedIdentityUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LinkedIdentityMutation
+}
+
+// SetConnectorID sets the "connector_id" field.
+func (liuo *LinkedIdentityUpdateOne) SetConnectorID(s string) *LinkedIdentityUpdateOne {
+	liuo.mutation.SetConnectorID(s)
+	return liuo
+}
+
+// SetNillableConnectorID sets the "connector_id" field if the given value is not nil.
+func (liuo *LinkedIdentityUpdateOne) SetNillableConnectorID(s *string) *LinkedIdentityUpdateOne {
+	if s != nil {
+		liuo.SetConnectorID(*s)
+	}
+	return liuo
+}
+
+// SetSubject sets the "subject" field.
+func (liuo *LinkedIdentityUpdateOne) SetSubject(s string) *LinkedIdentityUpdateOne {
+	liuo.mutation.SetSubject(s)
+	return liuo
+}
+
+// SetNillableSubject sets the "subject" field if the given value is not nil.
+func (liuo *LinkedIdentityUpdateOne) SetNillableSubject(s *string) *LinkedIdentityUpdateOne {
+	if s != nil {
+		liuo.SetSubject(*s)
+	}
+	return liuo
+}
+
+// SetEmail sets the "email" field.
+func (liuo *LinkedIdentityUpdateOne) SetEmail(s string) *LinkedIdentityUpdateOne {
+	liuo.mutation.SetEmail(s)
+	return liuo
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (liuo *LinkedIdentityUpdateOne) SetNillableEmail(s *string) *LinkedIdentityUpdateOne {
+	if s != nil {
+		liuo.SetEmail(*s)
+	}
+	return liuo
+}
+
+// SetUserID sets the "user" edge to the User entity by ID.
+func (liuo *LinkedIdentityUpdateOne) SetUserID(id int) *LinkedIdentityUpdateOne {
+	liuo.mutation.SetUserID(id)
+	return liuo
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (liuo *LinkedIdentityUpdateOne) SetUser(u *User) *LinkedIdentityUpdateOne {
+	return liuo.SetUserID(u.ID)
+}
+
+// Mutation returns the LinkedIdentityMutation object of the builder.
+func (liuo *LinkedIdentityUpdateOne) Mutation() *LinkedIdentityMutation {
+	return liuo.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (liuo *LinkedIdentityUpdateOne) ClearUser() *LinkedIdentityUpdateOne {
+	liuo.mutation.ClearUser()
+	return liuo
+}
+
+// Where appends a list predicates to the LinkedIdentityUpdate builder.
+func (liuo *LinkedIdentityUpdateOne) Where(ps ...predicate.LinkedIdentity) *LinkedIdentityUpdateOne {
+	liuo.mutation.Where(ps...)
+	return liuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (liuo *LinkedIdentityUpdateOne) Select(field string, fields ...string) *LinkedIdentityUpdateOne {
+	liuo.fields = append([]string{field}, fields...)
+	return liuo
+}
+
+// Save executes the query and returns the updated LinkedIdentity entity.
+func (liuo *LinkedIdentityUpdateOne) Save(ctx context.Context) (*LinkedIdentity, error) {
+	return withHooks(ctx, liuo.sqlSave, liuo.mutation, liuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (liuo *LinkedIdentityUpdateOne) SaveX(ctx context.Context) *LinkedIdentity {
+	node, err := liuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (liuo *LinkedIdentityUpdateOne) Exec(ctx context.Context) error {
+	_, err := liuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (liuo *LinkedIdentityUpdateOne) ExecX(ctx context.Context) {
+	if err := liuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (liuo *LinkedIdentityUpdateOne) check() error {
+	if v, ok := liuo.mutation.ConnectorID(); ok {
+		if err := linkedidentity.ConnectorIDValidator(v); err != nil {
+			return &ValidationError{Name: "connector_id", err: fmt.Errorf(`ent: validator failed for field "LinkedIdentity.connector_id": %w`, err)}
+		}
+	}
+	if v, ok := liuo.mutation.Subject(); ok {
+		if err := linkedidentity.SubjectValidator(v); err != nil {
+			return &ValidationError{Name: "subject", err: fmt.Errorf(`ent: validator failed for field "LinkedIdentity.subject": %w`, err)}
+		}
+	}
+	if _, ok := liuo.mutation.UserID(); liuo.mutation.UserCleared() && !ok {
+		return errors.New(`ent: clearing a required unique edge "LinkedIdentity.user"`)
+	}
+	return nil
+}
+
+func (liuo *LinkedIdentityUpdateOne) sqlSave(ctx context.Context) (_node *LinkedIdentity, err error) {
+	if err := liuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(linkedidentity.Table, linkedidentity.Columns, sqlgraph.NewFieldSpec(linkedidentity.FieldID, field.TypeInt))
+	id, ok := liuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "LinkedIdentity.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := liuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, linkedidentity.FieldID)
+		for _, f := range fields {
+			if !linkedidentity.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != linkedidentity.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := liuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := liuo.mutation.ConnectorID(); ok {
+		_spec.SetField(linkedidentity.FieldConnectorID, field.TypeString, value)
+	}
+	if value, ok := liuo.mutation.Subject(); ok {
+		_spec.SetField(linkedidentity.FieldSubject, field.TypeString, value)
+	}
+	if value, ok := liuo.mutation.Email(); ok {
+		_spec.SetField(linkedidentity.FieldEmail, field.TypeString, value)
+	}
+	if liuo.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   linkedidentity.UserTable,
+			Columns: []string{linkedidentity.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := liuo.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   linkedidentity.UserTable,
+			Columns: []string{linkedidentity.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &LinkedIdentity{config: liuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, liuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{linkedidentity.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	liuo.mutation.done = true
+	return _node, nil
+}
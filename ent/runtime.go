@@ -5,17 +5,92 @@ package ent
 import (
 	"time"
 
+	"github.com/qinzj/superpowers-demo/ent/auditevent"
+	"github.com/qinzj/superpowers-demo/ent/consent"
+	"github.com/qinzj/superpowers-demo/ent/group"
 	"github.com/qinzj/superpowers-demo/ent/idpconnector"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
 	"github.com/qinzj/superpowers-demo/ent/oauth2client"
+	"github.com/qinzj/superpowers-demo/ent/role"
 	"github.com/qinzj/superpowers-demo/ent/schema"
 	"github.com/qinzj/superpowers-demo/ent/session"
 	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/ent/webhookdelivery"
+	"github.com/qinzj/superpowers-demo/ent/webhooksubscription"
 )
 
 // The init function reads all schema descriptors with runtime code
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	auditeventFields := schema.AuditEvent{}.Fields()
+	_ = auditeventFields
+	// auditeventDescType is the schema descriptor for type field.
+	auditeventDescType := auditeventFields[0].Descriptor()
+	// auditevent.TypeValidator is a validator for the "type" field. It is called by the builders before save.
+	auditevent.TypeValidator = auditeventDescType.Validators[0].(func(string) error)
+	// auditeventDescUserID is the schema descriptor for user_id field.
+	auditeventDescUserID := auditeventFields[1].Descriptor()
+	// auditevent.DefaultUserID holds the default value on creation for the user_id field.
+	auditevent.DefaultUserID = auditeventDescUserID.Default.(string)
+	// auditeventDescClientID is the schema descriptor for client_id field.
+	auditeventDescClientID := auditeventFields[2].Descriptor()
+	// auditevent.DefaultClientID holds the default value on creation for the client_id field.
+	auditevent.DefaultClientID = auditeventDescClientID.Default.(string)
+	// auditeventDescIP is the schema descriptor for ip field.
+	auditeventDescIP := auditeventFields[3].Descriptor()
+	// auditevent.DefaultIP holds the default value on creation for the ip field.
+	auditevent.DefaultIP = auditeventDescIP.Default.(string)
+	// auditeventDescUserAgent is the schema descriptor for user_agent field.
+	auditeventDescUserAgent := auditeventFields[4].Descriptor()
+	// auditevent.DefaultUserAgent holds the default value on creation for the user_agent field.
+	auditevent.DefaultUserAgent = auditeventDescUserAgent.Default.(string)
+	// auditeventDescCreatedAt is the schema descriptor for created_at field.
+	auditeventDescCreatedAt := auditeventFields[6].Descriptor()
+	// auditevent.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditevent.DefaultCreatedAt = auditeventDescCreatedAt.Default.(func() time.Time)
+	// auditeventDescPrevHash is the schema descriptor for prev_hash field.
+	auditeventDescPrevHash := auditeventFields[7].Descriptor()
+	// auditevent.DefaultPrevHash holds the default value on creation for the prev_hash field.
+	auditevent.DefaultPrevHash = auditeventDescPrevHash.Default.(string)
+	// auditeventDescHash is the schema descriptor for hash field.
+	auditeventDescHash := auditeventFields[8].Descriptor()
+	// auditevent.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	auditevent.HashValidator = auditeventDescHash.Validators[0].(func(string) error)
+	consentFields := schema.Consent{}.Fields()
+	_ = consentFields
+	// consentDescClientID is the schema descriptor for client_id field.
+	consentDescClientID := consentFields[0].Descriptor()
+	// consent.ClientIDValidator is a validator for the "client_id" field. It is called by the builders before save.
+	consent.ClientIDValidator = consentDescClientID.Validators[0].(func(string) error)
+	// consentDescCreatedAt is the schema descriptor for created_at field.
+	consentDescCreatedAt := consentFields[2].Descriptor()
+	// consent.DefaultCreatedAt holds the default value on creation for the created_at field.
+	consent.DefaultCreatedAt = consentDescCreatedAt.Default.(func() time.Time)
+	// consentDescUpdatedAt is the schema descriptor for updated_at field.
+	consentDescUpdatedAt := consentFields[3].Descriptor()
+	// consent.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	consent.DefaultUpdatedAt = consentDescUpdatedAt.Default.(func() time.Time)
+	// consent.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	consent.UpdateDefaultUpdatedAt = consentDescUpdatedAt.UpdateDefault.(func() time.Time)
+	groupFields := schema.Group{}.Fields()
+	_ = groupFields
+	// groupDescName is the schema descriptor for name field.
+	groupDescName := groupFields[0].Descriptor()
+	// group.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	group.NameValidator = groupDescName.Validators[0].(func(string) error)
+	// groupDescDescription is the schema descriptor for description field.
+	groupDescDescription := groupFields[1].Descriptor()
+	// group.DefaultDescription holds the default value on creation for the description field.
+	group.DefaultDescription = groupDescDescription.Default.(string)
+	// groupDescConnectorID is the schema descriptor for connector_id field.
+	groupDescConnectorID := groupFields[2].Descriptor()
+	// group.DefaultConnectorID holds the default value on creation for the connector_id field.
+	group.DefaultConnectorID = groupDescConnectorID.Default.(string)
+	// groupDescCreatedAt is the schema descriptor for created_at field.
+	groupDescCreatedAt := groupFields[3].Descriptor()
+	// group.DefaultCreatedAt holds the default value on creation for the created_at field.
+	group.DefaultCreatedAt = groupDescCreatedAt.Default.(func() time.Time)
 	idpconnectorFields := schema.IdPConnector{}.Fields()
 	_ = idpconnectorFields
 	// idpconnectorDescIssuer is the schema descriptor for issuer field.
@@ -30,6 +105,28 @@ func init() {
 	idpconnectorDescClientSecret := idpconnectorFields[2].Descriptor()
 	// idpconnector.ClientSecretValidator is a validator for the "client_secret" field. It is called by the builders before save.
 	idpconnector.ClientSecretValidator = idpconnectorDescClientSecret.Validators[0].(func(string) error)
+	// idpconnectorDescGroupsClaim is the schema descriptor for groups_claim field.
+	idpconnectorDescGroupsClaim := idpconnectorFields[3].Descriptor()
+	// idpconnector.DefaultGroupsClaim holds the default value on creation for the groups_claim field.
+	idpconnector.DefaultGroupsClaim = idpconnectorDescGroupsClaim.Default.(string)
+	linkedidentityFields := schema.LinkedIdentity{}.Fields()
+	_ = linkedidentityFields
+	// linkedidentityDescConnectorID is the schema descriptor for connector_id field.
+	linkedidentityDescConnectorID := linkedidentityFields[0].Descriptor()
+	// linkedidentity.ConnectorIDValidator is a validator for the "connector_id" field. It is called by the builders before save.
+	linkedidentity.ConnectorIDValidator = linkedidentityDescConnectorID.Validators[0].(func(string) error)
+	// linkedidentityDescSubject is the schema descriptor for subject field.
+	linkedidentityDescSubject := linkedidentityFields[1].Descriptor()
+	// linkedidentity.SubjectValidator is a validator for the "subject" field. It is called by the builders before save.
+	linkedidentity.SubjectValidator = linkedidentityDescSubject.Validators[0].(func(string) error)
+	// linkedidentityDescEmail is the schema descriptor for email field.
+	linkedidentityDescEmail := linkedidentityFields[2].Descriptor()
+	// linkedidentity.DefaultEmail holds the default value on creation for the email field.
+	linkedidentity.DefaultEmail = linkedidentityDescEmail.Default.(string)
+	// linkedidentityDescCreatedAt is the schema descriptor for created_at field.
+	linkedidentityDescCreatedAt := linkedidentityFields[3].Descriptor()
+	// linkedidentity.DefaultCreatedAt holds the default value on creation for the created_at field.
+	linkedidentity.DefaultCreatedAt = linkedidentityDescCreatedAt.Default.(func() time.Time)
 	oauth2clientFields := schema.OAuth2Client{}.Fields()
 	_ = oauth2clientFields
 	// oauth2clientDescClientID is the schema descriptor for client_id field.
@@ -40,12 +137,46 @@ func init() {
 	oauth2clientDescClientSecret := oauth2clientFields[1].Descriptor()
 	// oauth2client.ClientSecretValidator is a validator for the "client_secret" field. It is called by the builders before save.
 	oauth2client.ClientSecretValidator = oauth2clientDescClientSecret.Validators[0].(func(string) error)
+	roleFields := schema.Role{}.Fields()
+	_ = roleFields
+	// roleDescName is the schema descriptor for name field.
+	roleDescName := roleFields[0].Descriptor()
+	// role.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	role.NameValidator = roleDescName.Validators[0].(func(string) error)
+	// roleDescDescription is the schema descriptor for description field.
+	roleDescDescription := roleFields[1].Descriptor()
+	// role.DefaultDescription holds the default value on creation for the description field.
+	role.DefaultDescription = roleDescDescription.Default.(string)
+	// roleDescCreatedAt is the schema descriptor for created_at field.
+	roleDescCreatedAt := roleFields[2].Descriptor()
+	// role.DefaultCreatedAt holds the default value on creation for the created_at field.
+	role.DefaultCreatedAt = roleDescCreatedAt.Default.(func() time.Time)
 	sessionFields := schema.Session{}.Fields()
 	_ = sessionFields
-	// sessionDescToken is the schema descriptor for token field.
-	sessionDescToken := sessionFields[0].Descriptor()
-	// session.TokenValidator is a validator for the "token" field. It is called by the builders before save.
-	session.TokenValidator = sessionDescToken.Validators[0].(func(string) error)
+	// sessionDescTokenHash is the schema descriptor for token_hash field.
+	sessionDescTokenHash := sessionFields[0].Descriptor()
+	// session.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	session.TokenHashValidator = sessionDescTokenHash.Validators[0].(func(string) error)
+	// sessionDescRemember is the schema descriptor for remember field.
+	sessionDescRemember := sessionFields[3].Descriptor()
+	// session.DefaultRemember holds the default value on creation for the remember field.
+	session.DefaultRemember = sessionDescRemember.Default.(bool)
+	// sessionDescCreatedAt is the schema descriptor for created_at field.
+	sessionDescCreatedAt := sessionFields[4].Descriptor()
+	// session.DefaultCreatedAt holds the default value on creation for the created_at field.
+	session.DefaultCreatedAt = sessionDescCreatedAt.Default.(func() time.Time)
+	// sessionDescLastSeenAt is the schema descriptor for last_seen_at field.
+	sessionDescLastSeenAt := sessionFields[5].Descriptor()
+	// session.DefaultLastSeenAt holds the default value on creation for the last_seen_at field.
+	session.DefaultLastSeenAt = sessionDescLastSeenAt.Default.(func() time.Time)
+	// sessionDescIP is the schema descriptor for ip field.
+	sessionDescIP := sessionFields[6].Descriptor()
+	// session.DefaultIP holds the default value on creation for the ip field.
+	session.DefaultIP = sessionDescIP.Default.(string)
+	// sessionDescUserAgent is the schema descriptor for user_agent field.
+	sessionDescUserAgent := sessionFields[7].Descriptor()
+	// session.DefaultUserAgent holds the default value on creation for the user_agent field.
+	session.DefaultUserAgent = sessionDescUserAgent.Default.(string)
 	userFields := schema.User{}.Fields()
 	_ = userFields
 	// userDescUsername is the schema descriptor for username field.
@@ -60,8 +191,76 @@ func init() {
 	userDescPasswordHash := userFields[2].Descriptor()
 	// user.PasswordHashValidator is a validator for the "password_hash" field. It is called by the builders before save.
 	user.PasswordHashValidator = userDescPasswordHash.Validators[0].(func(string) error)
+	// userDescDisplayName is the schema descriptor for display_name field.
+	userDescDisplayName := userFields[3].Descriptor()
+	// user.DefaultDisplayName holds the default value on creation for the display_name field.
+	user.DefaultDisplayName = userDescDisplayName.Default.(string)
+	// userDescEmailVerified is the schema descriptor for email_verified field.
+	userDescEmailVerified := userFields[4].Descriptor()
+	// user.DefaultEmailVerified holds the default value on creation for the email_verified field.
+	user.DefaultEmailVerified = userDescEmailVerified.Default.(bool)
+	// userDescPendingEmail is the schema descriptor for pending_email field.
+	userDescPendingEmail := userFields[5].Descriptor()
+	// user.DefaultPendingEmail holds the default value on creation for the pending_email field.
+	user.DefaultPendingEmail = userDescPendingEmail.Default.(string)
+	// userDescEmailVerifyTokenHash is the schema descriptor for email_verify_token_hash field.
+	userDescEmailVerifyTokenHash := userFields[6].Descriptor()
+	// user.DefaultEmailVerifyTokenHash holds the default value on creation for the email_verify_token_hash field.
+	user.DefaultEmailVerifyTokenHash = userDescEmailVerifyTokenHash.Default.(string)
+	// userDescExternalID is the schema descriptor for external_id field.
+	userDescExternalID := userFields[8].Descriptor()
+	// user.DefaultExternalID holds the default value on creation for the external_id field.
+	user.DefaultExternalID = userDescExternalID.Default.(string)
 	// userDescCreatedAt is the schema descriptor for created_at field.
-	userDescCreatedAt := userFields[3].Descriptor()
+	userDescCreatedAt := userFields[11].Descriptor()
 	// user.DefaultCreatedAt holds the default value on creation for the created_at field.
 	user.DefaultCreatedAt = userDescCreatedAt.Default.(func() time.Time)
+	webhookdeliveryFields := schema.WebhookDelivery{}.Fields()
+	_ = webhookdeliveryFields
+	// webhookdeliveryDescEventID is the schema descriptor for event_id field.
+	webhookdeliveryDescEventID := webhookdeliveryFields[0].Descriptor()
+	// webhookdelivery.EventIDValidator is a validator for the "event_id" field. It is called by the builders before save.
+	webhookdelivery.EventIDValidator = webhookdeliveryDescEventID.Validators[0].(func(string) error)
+	// webhookdeliveryDescEventType is the schema descriptor for event_type field.
+	webhookdeliveryDescEventType := webhookdeliveryFields[1].Descriptor()
+	// webhookdelivery.EventTypeValidator is a validator for the "event_type" field. It is called by the builders before save.
+	webhookdelivery.EventTypeValidator = webhookdeliveryDescEventType.Validators[0].(func(string) error)
+	// webhookdeliveryDescAttempts is the schema descriptor for attempts field.
+	webhookdeliveryDescAttempts := webhookdeliveryFields[4].Descriptor()
+	// webhookdelivery.DefaultAttempts holds the default value on creation for the attempts field.
+	webhookdelivery.DefaultAttempts = webhookdeliveryDescAttempts.Default.(int)
+	// webhookdeliveryDescResponseStatus is the schema descriptor for response_status field.
+	webhookdeliveryDescResponseStatus := webhookdeliveryFields[7].Descriptor()
+	// webhookdelivery.DefaultResponseStatus holds the default value on creation for the response_status field.
+	webhookdelivery.DefaultResponseStatus = webhookdeliveryDescResponseStatus.Default.(int)
+	// webhookdeliveryDescLastError is the schema descriptor for last_error field.
+	webhookdeliveryDescLastError := webhookdeliveryFields[8].Descriptor()
+	// webhookdelivery.DefaultLastError holds the default value on creation for the last_error field.
+	webhookdelivery.DefaultLastError = webhookdeliveryDescLastError.Default.(string)
+	// webhookdeliveryDescCreatedAt is the schema descriptor for created_at field.
+	webhookdeliveryDescCreatedAt := webhookdeliveryFields[9].Descriptor()
+	// webhookdelivery.DefaultCreatedAt holds the default value on creation for the created_at field.
+	webhookdelivery.DefaultCreatedAt = webhookdeliveryDescCreatedAt.Default.(func() time.Time)
+	webhooksubscriptionFields := schema.WebhookSubscription{}.Fields()
+	_ = webhooksubscriptionFields
+	// webhooksubscriptionDescURL is the schema descriptor for url field.
+	webhooksubscriptionDescURL := webhooksubscriptionFields[0].Descriptor()
+	// webhooksubscription.URLValidator is a validator for the "url" field. It is called by the builders before save.
+	webhooksubscription.URLValidator = webhooksubscriptionDescURL.Validators[0].(func(string) error)
+	// webhooksubscriptionDescSecret is the schema descriptor for secret field.
+	webhooksubscriptionDescSecret := webhooksubscriptionFields[2].Descriptor()
+	// webhooksubscription.SecretValidator is a validator for the "secret" field. It is called by the builders before save.
+	webhooksubscription.SecretValidator = webhooksubscriptionDescSecret.Validators[0].(func(string) error)
+	// webhooksubscriptionDescDescription is the schema descriptor for description field.
+	webhooksubscriptionDescDescription := webhooksubscriptionFields[3].Descriptor()
+	// webhooksubscription.DefaultDescription holds the default value on creation for the description field.
+	webhooksubscription.DefaultDescription = webhooksubscriptionDescDescription.Default.(string)
+	// webhooksubscriptionDescEnabled is the schema descriptor for enabled field.
+	webhooksubscriptionDescEnabled := webhooksubscriptionFields[4].Descriptor()
+	// webhooksubscription.DefaultEnabled holds the default value on creation for the enabled field.
+	webhooksubscription.DefaultEnabled = webhooksubscriptionDescEnabled.Default.(bool)
+	// webhooksubscriptionDescCreatedAt is the schema descriptor for created_at field.
+	webhooksubscriptionDescCreatedAt := webhooksubscriptionFields[5].Descriptor()
+	// webhooksubscription.DefaultCreatedAt holds the default value on creation for the created_at field.
+	webhooksubscription.DefaultCreatedAt = webhooksubscriptionDescCreatedAt.Default.(func() time.Time)
 }
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuditEvent holds the schema definition for the AuditEvent entity.
+// Events are append-only; each row's hash covers its content and the previous row's hash.
+type AuditEvent struct {
+	ent.Schema
+}
+
+// Fields of the AuditEvent.
+func (AuditEvent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("type").
+			NotEmpty().
+			Immutable(),
+		// user_id and client_id are plain strings rather than edges so events outlive the
+		// users and clients they mention.
+		field.String("user_id").
+			Default("").
+			Immutable(),
+		field.String("client_id").
+			Default("").
+			Immutable(),
+		field.String("ip").
+			Default("").
+			Immutable(),
+		// Text rather than a varchar: user agents have no length limit.
+		field.Text("user_agent").
+			Default("").
+			Immutable(),
+		field.JSON("details", map[string]string{}).
+			Optional().
+			Immutable(),
+		// created_at is hashed at microsecond precision, which MySQL keeps only in timestamp(6).
+		field.Time("created_at").
+			Default(time.Now).
+			SchemaType(map[string]string{dialect.MySQL: "timestamp(6)"}).
+			Immutable(),
+		field.String("prev_hash").
+			Default("").
+			Immutable(),
+		field.String("hash").
+			NotEmpty().
+			Immutable(),
+	}
+}
+
+// Indexes of the AuditEvent.
+func (AuditEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("hash").
+			Unique(),
+		// A unique prev_hash keeps the chain linear: two writers racing to extend the same
+		// event cannot both succeed.
+		index.Fields("prev_hash").
+			Unique(),
+		index.Fields("user_id"),
+		index.Fields("client_id"),
+		index.Fields("type"),
+		index.Fields("created_at"),
+	}
+}
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Consent holds the schema definition for the Consent entity: the scopes a user has allowed an
+// OAuth2 client, so that the consent screen is only shown again for scopes not allowed before.
+type Consent struct {
+	ent.Schema
+}
+
+// Fields of the Consent.
+func (Consent) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("client_id").
+			NotEmpty(),
+		field.JSON("scopes", []string{}),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the Consent.
+func (Consent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("consents").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the Consent.
+func (Consent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("client_id").
+			Edges("user").
+			Unique(),
+	}
+}
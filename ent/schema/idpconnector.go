@@ -17,7 +17,11 @@ func (IdPConnector) Fields() []ent.Field {
 			NotEmpty(),
 		field.String("client_id").
 			NotEmpty(),
-		field.String("client_secret").
+		// Text rather than a varchar: the secret is stored sealed (see storage.EncryptSecrets).
+		field.Text("client_secret").
 			NotEmpty(),
+		// groups_claim names the upstream claim holding group names; empty disables group sync.
+		field.String("groups_claim").
+			Default(""),
 	}
 }
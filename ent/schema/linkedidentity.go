@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// LinkedIdentity holds the schema definition for the LinkedIdentity entity: an account at an
+// upstream IdP that signs in as a local user.
+type LinkedIdentity struct {
+	ent.Schema
+}
+
+// Fields of the LinkedIdentity.
+func (LinkedIdentity) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("connector_id").
+			NotEmpty(),
+		// subject is the upstream "sub" claim, unique per connector.
+		field.String("subject").
+			NotEmpty(),
+		// email is the upstream address when the identity was linked.
+		field.String("email").
+			Default(""),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the LinkedIdentity.
+func (LinkedIdentity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("identities").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the LinkedIdentity.
+func (LinkedIdentity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("connector_id", "subject").
+			Unique(),
+	}
+}
@@ -1,9 +1,12 @@
 package schema
 
 import (
+	"time"
+
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 )
 
 // Session holds the schema definition for the Session entity.
@@ -14,9 +17,28 @@ type Session struct {
 // Fields of the Session.
 func (Session) Fields() []ent.Field {
 	return []ent.Field{
-		field.String("token").
-			NotEmpty(),
+		// token_hash is the hex SHA-256 digest of the sso_session cookie value; the raw token
+		// is never stored. The column keeps its original name so existing databases migrate.
+		field.String("token_hash").
+			StorageKey("token").
+			NotEmpty().
+			Sensitive(),
+		// expires_at is the idle deadline; it slides forward on activity up to absolute_expires_at.
 		field.Time("expires_at"),
+		field.Time("absolute_expires_at").
+			Optional(),
+		field.Bool("remember").
+			Default(false),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("last_seen_at").
+			Default(time.Now),
+		field.String("ip").
+			Default(""),
+		// Text rather than a varchar: user agents have no length limit.
+		field.Text("user_agent").
+			Default(""),
 	}
 }
 
@@ -29,3 +51,12 @@ func (Session) Edges() []ent.Edge {
 			Required(),
 	}
 }
+
+// Indexes of the Session.
+func (Session) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("token_hash").
+			Unique(),
+		index.Fields("expires_at"),
+	}
+}
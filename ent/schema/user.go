@@ -23,6 +23,31 @@ func (User) Fields() []ent.Field {
 			NotEmpty(),
 		field.String("password_hash").
 			NotEmpty(),
+		field.String("display_name").
+			Default(""),
+		field.Bool("email_verified").
+			Default(false),
+		field.String("pending_email").
+			Default(""),
+		field.String("email_verify_token_hash").
+			Default("").
+			Sensitive(),
+		field.Time("email_verify_expires_at").
+			Optional().
+			Nillable(),
+		// external_id is the identifier assigned by a SCIM provisioning client.
+		field.String("external_id").
+			Default(""),
+		// status gates every way of authenticating; only active users may sign in or hold
+		// sessions and tokens.
+		field.Enum("status").
+			Values("active", "suspended", "locked", "pending_verification").
+			Default("active"),
+		// deletion_scheduled_at is when a requested account deletion becomes final; signing in
+		// before then clears it.
+		field.Time("deletion_scheduled_at").
+			Optional().
+			Nillable(),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
@@ -33,5 +58,11 @@ func (User) Fields() []ent.Field {
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("sessions", Session.Type),
+		edge.To("consents", Consent.Type),
+		edge.To("identities", LinkedIdentity.Type),
+		edge.From("groups", Group.Type).
+			Ref("users"),
+		edge.From("roles", Role.Type).
+			Ref("users"),
 	}
 }
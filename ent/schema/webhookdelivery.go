@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// WebhookDelivery holds the schema definition for the WebhookDelivery entity. Rows are the
+// outbox of pending deliveries and, once settled, the delivery log.
+type WebhookDelivery struct {
+	ent.Schema
+}
+
+// Fields of the WebhookDelivery.
+func (WebhookDelivery) Fields() []ent.Field {
+	return []ent.Field{
+		// event_id identifies the event; redeliveries of the same event share it.
+		field.String("event_id").
+			NotEmpty().
+			Immutable(),
+		field.String("event_type").
+			NotEmpty().
+			Immutable(),
+		// payload is the exact JSON body sent, so signatures can be reproduced.
+		field.Text("payload").
+			Immutable(),
+		field.Enum("status").
+			Values("pending", "succeeded", "failed").
+			Default("pending"),
+		field.Int("attempts").
+			Default(0),
+		field.Time("next_attempt_at"),
+		field.Time("last_attempt_at").
+			Optional().
+			Nillable(),
+		field.Int("response_status").
+			Default(0),
+		// Text rather than a varchar: transport errors quote URLs and can be long.
+		field.Text("last_error").
+			Default(""),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the WebhookDelivery.
+func (WebhookDelivery) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("subscription", WebhookSubscription.Type).
+			Ref("deliveries").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the WebhookDelivery.
+func (WebhookDelivery) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("status", "next_attempt_at"),
+		index.Fields("event_id"),
+	}
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// WebhookSubscription holds the schema definition for the WebhookSubscription entity.
+type WebhookSubscription struct {
+	ent.Schema
+}
+
+// Fields of the WebhookSubscription.
+func (WebhookSubscription) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("url").
+			NotEmpty(),
+		// events lists the event types delivered to the endpoint; "*" matches every type.
+		field.JSON("events", []string{}),
+		// secret keys the HMAC-SHA256 signature of each delivery. It must be readable to sign,
+		// so it is stored as issued.
+		// Text rather than a varchar: the secret is stored sealed (see storage.EncryptSecrets).
+		field.Text("secret").
+			NotEmpty().
+			Sensitive(),
+		field.String("description").
+			Default(""),
+		field.Bool("enabled").
+			Default(true),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the WebhookSubscription.
+func (WebhookSubscription) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("deliveries", WebhookDelivery.Type),
+	}
+}
@@ -21,9 +21,9 @@ type SessionCreate struct {
 	hooks    []Hook
 }
 
-// SetToken sets the "token" field.
-func (sc *SessionCreate) SetToken(s string) *SessionCreate {
-	sc.mutation.SetToken(s)
+// SetTokenHash sets the "token_hash" field.
+func (sc *SessionCreate) SetTokenHash(s string) *SessionCreate {
+	sc.mutation.SetTokenHash(s)
 	return sc
 }
 
@@ -33,6 +33,90 @@ func (sc *SessionCreate) SetExpiresAt(t time.Time) *SessionCreate {
 	return sc
 }
 
+// SetAbsoluteExpiresAt sets the "absolute_expires_at" field.
+func (sc *SessionCreate) SetAbsoluteExpiresAt(t time.Time) *SessionCreate {
+	sc.mutation.SetAbsoluteExpiresAt(t)
+	return sc
+}
+
+// SetNillableAbsoluteExpiresAt sets the "absolute_expires_at" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableAbsoluteExpiresAt(t *time.Time) *SessionCreate {
+	if t != nil {
+		sc.SetAbsoluteExpiresAt(*t)
+	}
+	return sc
+}
+
+// SetRemember sets the "remember" field.
+func (sc *SessionCreate) SetRemember(b bool) *SessionCreate {
+	sc.mutation.SetRemember(b)
+	return sc
+}
+
+// SetNillableRemember sets the "remember" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableRemember(b *bool) *SessionCreate {
+	if b != nil {
+		sc.SetRemember(*b)
+	}
+	return sc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (sc *SessionCreate) SetCreatedAt(t time.Time) *SessionCreate {
+	sc.mutation.SetCreatedAt(t)
+	return sc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableCreatedAt(t *time.Time) *SessionCreate {
+	if t != nil {
+		sc.SetCreatedAt(*t)
+	}
+	return sc
+}
+
+// SetLastSeenAt sets the "last_seen_at" field.
+func (sc *SessionCreate) SetLastSeenAt(t time.Time) *SessionCreate {
+	sc.mutation.SetLastSeenAt(t)
+	return sc
+}
+
+// SetNillableLastSeenAt sets the "last_seen_at" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableLastSeenAt(t *time.Time) *SessionCreate {
+	if t != nil {
+		sc.SetLastSeenAt(*t)
+	}
+	return sc
+}
+
+// SetIP sets the "ip" field.
+func (sc *SessionCreate) SetIP(s string) *SessionCreate {
+	sc.mutation.SetIP(s)
+	return sc
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableIP(s *string) *SessionCreate {
+	if s != nil {
+		sc.SetIP(*s)
+	}
+	return sc
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (sc *SessionCreate) SetUserAgent(s string) *SessionCreate {
+	sc.mutation.SetUserAgent(s)
+	return sc
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableUserAgent(s *string) *SessionCreate {
+	if s != nil {
+		sc.SetUserAgent(*s)
+	}
+	return sc
+}
+
 // SetUserID sets the "user" edge to the User entity by ID.
 func (sc *SessionCreate) SetUserID(id int) *SessionCreate {
 	sc.mutation.SetUserID(id)
@@ -51,6 +135,7 @@ func (sc *SessionCreate) Mutation() *SessionMutation {
 
 // Save creates the Session in the database.
 func (sc *SessionCreate) Save(ctx context.Context) (*Session, error) {
+	sc.defaults()
 	return withHooks(ctx, sc.sqlSave, sc.mutation, sc.hooks)
 }
 
@@ -76,19 +161,58 @@ func (sc *SessionCreate) ExecX(ctx context.Context) {
 	}
 }
 
+// defaults sets the default values of the builder before save.
+func (sc *SessionCreate) defaults() {
+	if _, ok := sc.mutation.Remember(); !ok {
+		v := session.DefaultRemember
+		sc.mutation.SetRemember(v)
+	}
+	if _, ok := sc.mutation.CreatedAt(); !ok {
+		v := session.DefaultCreatedAt()
+		sc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := sc.mutation.LastSeenAt(); !ok {
+		v := session.DefaultLastSeenAt()
+		sc.mutation.SetLastSeenAt(v)
+	}
+	if _, ok := sc.mutation.IP(); !ok {
+		v := session.DefaultIP
+		sc.mutation.SetIP(v)
+	}
+	if _, ok := sc.mutation.UserAgent(); !ok {
+		v := session.DefaultUserAgent
+		sc.mutation.SetUserAgent(v)
+	}
+}
+
 // check runs all checks and user-defined validators on the builder.
 func (sc *SessionCreate) check() error {
-	if _, ok := sc.mutation.Token(); !ok {
-		return &ValidationError{Name: "token", err: errors.New(`ent: missing required field "Session.token"`)}
+	if _, ok := sc.mutation.TokenHash(); !ok {
+		return &ValidationError{Name: "token_hash", err: errors.New(`ent: missing required field "Session.token_hash"`)}
 	}
-	if v, ok := sc.mutation.Token(); ok {
-		if err := session.TokenValidator(v); err != nil {
-			return &ValidationError{Name: "token", err: fmt.Errorf(`ent: validator failed for field "Session.token": %w`, err)}
+	if v, ok := sc.mutation.TokenHash(); ok {
+		if err := session.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Session.token_hash": %w`, err)}
 		}
 	}
 	if _, ok := sc.mutation.ExpiresAt(); !ok {
 		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "Session.expires_at"`)}
 	}
+	if _, ok := sc.mutation.Remember(); !ok {
+		return &ValidationError{Name: "remember", err: errors.New(`ent: missing required field "Session.remember"`)}
+	}
+	if _, ok := sc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "Session.created_at"`)}
+	}
+	if _, ok := sc.mutation.LastSeenAt(); !ok {
+		return &ValidationError{Name: "last_seen_at", err: errors.New(`ent: missing required field "Session.last_seen_at"`)}
+	}
+	if _, ok := sc.mutation.IP(); !ok {
+		return &ValidationError{Name: "ip", err: errors.New(`ent: missing required field "Session.ip"`)}
+	}
+	if _, ok := sc.mutation.UserAgent(); !ok {
+		return &ValidationError{Name: "user_agent", err: errors.New(`ent: missing required field "Session.user_agent"`)}
+	}
 	if _, ok := sc.mutation.UserID(); !ok {
 		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "Session.user"`)}
 	}
@@ -118,14 +242,38 @@ func (sc *SessionCreate) createSpec() (*Session, *sqlgraph.CreateSpec) {
 		_node = &Session{config: sc.config}
 		_spec = sqlgraph.NewCreateSpec(session.Table, sqlgraph.NewFieldSpec(session.FieldID, field.TypeInt))
 	)
-	if value, ok := sc.mutation.Token(); ok {
-		_spec.SetField(session.FieldToken, field.TypeString, value)
-		_node.Token = value
+	if value, ok := sc.mutation.TokenHash(); ok {
+		_spec.SetField(session.FieldTokenHash, field.TypeString, value)
+		_node.TokenHash = value
 	}
 	if value, ok := sc.mutation.ExpiresAt(); ok {
 		_spec.SetField(session.FieldExpiresAt, field.TypeTime, value)
 		_node.ExpiresAt = value
 	}
+	if value, ok := sc.mutation.AbsoluteExpiresAt(); ok {
+		_spec.SetField(session.FieldAbsoluteExpiresAt, field.TypeTime, value)
+		_node.AbsoluteExpiresAt = value
+	}
+	if value, ok := sc.mutation.Remember(); ok {
+		_spec.SetField(session.FieldRemember, field.TypeBool, value)
+		_node.Remember = value
+	}
+	if value, ok := sc.mutation.CreatedAt(); ok {
+		_spec.SetField(session.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := sc.mutation.LastSeenAt(); ok {
+		_spec.SetField(session.FieldLastSeenAt, field.TypeTime, value)
+		_node.LastSeenAt = value
+	}
+	if value, ok := sc.mutation.IP(); ok {
+		_spec.SetField(session.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := sc.mutation.UserAgent(); ok {
+		_spec.SetField(session.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
 	if nodes := sc.mutation.UserIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -164,6 +312,7 @@ func (scb *SessionCreateBulk) Save(ctx context.Context) ([]*Session, error) {
 	for i := range scb.builders {
 		func(i int, root context.Context) {
 			builder := scb.builders[i]
+			builder.defaults()
 			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
 				mutation, ok := m.(*SessionMutation)
 				if !ok {
@@ -298,12 +298,12 @@ func (sq *SessionQuery) WithUser(opts ...func(*UserQuery)) *SessionQuery {
 // Example:
 //
 //	var v []struct {
-//		Token string `json:"token,omitempty"`
+//		TokenHash string `json:"token_hash,omitempty"`
 //		Count int `json:"count,omitempty"`
 //	}
 //
 //	client.Session.Query().
-//		GroupBy(session.FieldToken).
+//		GroupBy(session.FieldTokenHash).
 //		Aggregate(ent.Count()).
 //		Scan(ctx, &v)
 func (sq *SessionQuery) GroupBy(field string, fields ...string) *SessionGroupBy {
@@ -321,11 +321,11 @@ func (sq *SessionQuery) GroupBy(field string, fields ...string) *SessionGroupBy
 // Example:
 //
 //	var v []struct {
-//		Token string `json:"token,omitempty"`
+//		TokenHash string `json:"token_hash,omitempty"`
 //	}
 //
 //	client.Session.Query().
-//		Select(session.FieldToken).
+//		Select(session.FieldTokenHash).
 //		Scan(ctx, &v)
 func (sq *SessionQuery) Select(fields ...string) *SessionSelect {
 	sq.ctx.Fields = append(sq.ctx.Fields, fields...)
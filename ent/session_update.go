@@ -29,16 +29,16 @@ func (su *SessionUpdate) Where(ps ...predicate.Session) *SessionUpdate {
 	return su
 }
 
-// SetToken sets the "token" field.
-func (su *SessionUpdate) SetToken(s string) *SessionUpdate {
-	su.mutation.SetToken(s)
+// SetTokenHash sets the "token_hash" field.
+func (su *SessionUpdate) SetTokenHash(s string) *SessionUpdate {
+	su.mutation.SetTokenHash(s)
 	return su
 }
 
-// SetNillableToken sets the "token" field if the given value is not nil.
-func (su *SessionUpdate) SetNillableToken(s *string) *SessionUpdate {
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableTokenHash(s *string) *SessionUpdate {
 	if s != nil {
-		su.SetToken(*s)
+		su.SetTokenHash(*s)
 	}
 	return su
 }
@@ -57,6 +57,82 @@ func (su *SessionUpdate) SetNillableExpiresAt(t *time.Time) *SessionUpdate {
 	return su
 }
 
+// SetAbsoluteExpiresAt sets the "absolute_expires_at" field.
+func (su *SessionUpdate) SetAbsoluteExpiresAt(t time.Time) *SessionUpdate {
+	su.mutation.SetAbsoluteExpiresAt(t)
+	return su
+}
+
+// SetNillableAbsoluteExpiresAt sets the "absolute_expires_at" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableAbsoluteExpiresAt(t *time.Time) *SessionUpdate {
+	if t != nil {
+		su.SetAbsoluteExpiresAt(*t)
+	}
+	return su
+}
+
+// ClearAbsoluteExpiresAt clears the value of the "absolute_expires_at" field.
+func (su *SessionUpdate) ClearAbsoluteExpiresAt() *SessionUpdate {
+	su.mutation.ClearAbsoluteExpiresAt()
+	return su
+}
+
+// SetRemember sets the "remember" field.
+func (su *SessionUpdate) SetRemember(b bool) *SessionUpdate {
+	su.mutation.SetRemember(b)
+	return su
+}
+
+// SetNillableRemember sets the "remember" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableRemember(b *bool) *SessionUpdate {
+	if b != nil {
+		su.SetRemember(*b)
+	}
+	return su
+}
+
+// SetLastSeenAt sets the "last_seen_at" field.
+func (su *SessionUpdate) SetLastSeenAt(t time.Time) *SessionUpdate {
+	su.mutation.SetLastSeenAt(t)
+	return su
+}
+
+// SetNillableLastSeenAt sets the "last_seen_at" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableLastSeenAt(t *time.Time) *SessionUpdate {
+	if t != nil {
+		su.SetLastSeenAt(*t)
+	}
+	return su
+}
+
+// SetIP sets the "ip" field.
+func (su *SessionUpdate) SetIP(s string) *SessionUpdate {
+	su.mutation.SetIP(s)
+	return su
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableIP(s *string) *SessionUpdate {
+	if s != nil {
+		su.SetIP(*s)
+	}
+	return su
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (su *SessionUpdate) SetUserAgent(s string) *SessionUpdate {
+	su.mutation.SetUserAgent(s)
+	return su
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableUserAgent(s *string) *SessionUpdate {
+	if s != nil {
+		su.SetUserAgent(*s)
+	}
+	return su
+}
+
 // SetUserID sets the "user" edge to the User entity by ID.
 func (su *SessionUpdate) SetUserID(id int) *SessionUpdate {
 	su.mutation.SetUserID(id)
@@ -108,9 +184,9 @@ func (su *SessionUpdate) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (su *SessionUpdate) check() error {
-	if v, ok := su.mutation.Token(); ok {
-		if err := session.TokenValidator(v); err != nil {
-			return &ValidationError{Name: "token", err: fmt.Errorf(`ent: validator failed for field "Session.token": %w`, err)}
+	if v, ok := su.mutation.TokenHash(); ok {
+		if err := session.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Session.token_hash": %w`, err)}
 		}
 	}
 	if _, ok := su.mutation.UserID(); su.mutation.UserCleared() && !ok {
@@ -131,12 +207,30 @@ func (su *SessionUpdate) sqlSave(ctx context.Context) (n int, err error) {
 			}
 		}
 	}
-	if value, ok := su.mutation.Token(); ok {
-		_spec.SetField(session.FieldToken, field.TypeString, value)
+	if value, ok := su.mutation.TokenHash(); ok {
+		_spec.SetField(session.FieldTokenHash, field.TypeString, value)
 	}
 	if value, ok := su.mutation.ExpiresAt(); ok {
 		_spec.SetField(session.FieldExpiresAt, field.TypeTime, value)
 	}
+	if value, ok := su.mutation.AbsoluteExpiresAt(); ok {
+		_spec.SetField(session.FieldAbsoluteExpiresAt, field.TypeTime, value)
+	}
+	if su.mutation.AbsoluteExpiresAtCleared() {
+		_spec.ClearField(session.FieldAbsoluteExpiresAt, field.TypeTime)
+	}
+	if value, ok := su.mutation.Remember(); ok {
+		_spec.SetField(session.FieldRemember, field.TypeBool, value)
+	}
+	if value, ok := su.mutation.LastSeenAt(); ok {
+		_spec.SetField(session.FieldLastSeenAt, field.TypeTime, value)
+	}
+	if value, ok := su.mutation.IP(); ok {
+		_spec.SetField(session.FieldIP, field.TypeString, value)
+	}
+	if value, ok := su.mutation.UserAgent(); ok {
+		_spec.SetField(session.FieldUserAgent, field.TypeString, value)
+	}
 	if su.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -186,16 +280,16 @@ type SessionUpdateOne struct {
 	mutation *SessionMutation
 }
 
-// SetToken sets the "token" field.
-func (suo *SessionUpdateOne) SetToken(s string) *SessionUpdateOne {
-	suo.mutation.SetToken(s)
+// SetTokenHash sets the "token_hash" field.
+func (suo *SessionUpdateOne) SetTokenHash(s string) *SessionUpdateOne {
+	suo.mutation.SetTokenHash(s)
 	return suo
 }
 
-// SetNillableToken sets the "token" field if the given value is not nil.
-func (suo *SessionUpdateOne) SetNillableToken(s *string) *SessionUpdateOne {
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableTokenHash(s *string) *SessionUpdateOne {
 	if s != nil {
-		suo.SetToken(*s)
+		suo.SetTokenHash(*s)
 	}
 	return suo
 }
@@ -214,6 +308,82 @@ func (suo *SessionUpdateOne) SetNillableExpiresAt(t *time.Time) *SessionUpdateOn
 	return suo
 }
 
+// SetAbsoluteExpiresAt sets the "absolute_expires_at" field.
+func (suo *SessionUpdateOne) SetAbsoluteExpiresAt(t time.Time) *SessionUpdateOne {
+	suo.mutation.SetAbsoluteExpiresAt(t)
+	return suo
+}
+
+// SetNillableAbsoluteExpiresAt sets the "absolute_expires_at" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableAbsoluteExpiresAt(t *time.Time) *SessionUpdateOne {
+	if t != nil {
+		suo.SetAbsoluteExpiresAt(*t)
+	}
+	return suo
+}
+
+// ClearAbsoluteExpiresAt clears the value of the "absolute_expires_at" field.
+func (suo *SessionUpdateOne) ClearAbsoluteExpiresAt() *SessionUpdateOne {
+	suo.mutation.ClearAbsoluteExpiresAt()
+	return suo
+}
+
+// SetRemember sets the "remember" field.
+func (suo *SessionUpdateOne) SetRemember(b bool) *SessionUpdateOne {
+	suo.mutation.SetRemember(b)
+	return suo
+}
+
+// SetNillableRemember sets the "remember" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableRemember(b *bool) *SessionUpdateOne {
+	if b != nil {
+		suo.SetRemember(*b)
+	}
+	return suo
+}
+
+// SetLastSeenAt sets the "last_seen_at" field.
+func (suo *SessionUpdateOne) SetLastSeenAt(t time.Time) *SessionUpdateOne {
+	suo.mutation.SetLastSeenAt(t)
+	return suo
+}
+
+// SetNillableLastSeenAt sets the "last_seen_at" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableLastSeenAt(t *time.Time) *SessionUpdateOne {
+	if t != nil {
+		suo.SetLastSeenAt(*t)
+	}
+	return suo
+}
+
+// SetIP sets the "ip" field.
+func (suo *SessionUpdateOne) SetIP(s string) *SessionUpdateOne {
+	suo.mutation.SetIP(s)
+	return suo
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableIP(s *string) *SessionUpdateOne {
+	if s != nil {
+		suo.SetIP(*s)
+	}
+	return suo
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (suo *SessionUpdateOne) SetUserAgent(s string) *SessionUpdateOne {
+	suo.mutation.SetUserAgent(s)
+	return suo
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableUserAgent(s *string) *SessionUpdateOne {
+	if s != nil {
+		suo.SetUserAgent(*s)
+	}
+	return suo
+}
+
 // SetUserID sets the "user" edge to the User entity by ID.
 func (suo *SessionUpdateOne) SetUserID(id int) *SessionUpdateOne {
 	suo.mutation.SetUserID(id)
@@ -278,9 +448,9 @@ func (suo *SessionUpdateOne) ExecX(ctx context.Context) {
 
 // check runs all checks and user-defined validators on the builder.
 func (suo *SessionUpdateOne) check() error {
-	if v, ok := suo.mutation.Token(); ok {
-		if err := session.TokenValidator(v); err != nil {
-			return &ValidationError{Name: "token", err: fmt.Errorf(`ent: validator failed for field "Session.token": %w`, err)}
+	if v, ok := suo.mutation.TokenHash(); ok {
+		if err := session.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "Session.token_hash": %w`, err)}
 		}
 	}
 	if _, ok := suo.mutation.UserID(); suo.mutation.UserCleared() && !ok {
@@ -318,12 +488,30 @@ func (suo *SessionUpdateOne) sqlSave(ctx context.Context) (_node *Session, err e
 			}
 		}
 	}
-	if value, ok := suo.mutation.Token(); ok {
-		_spec.SetField(session.FieldToken, field.TypeString, value)
+	if value, ok := suo.mutation.TokenHash(); ok {
+		_spec.SetField(session.FieldTokenHash, field.TypeString, value)
 	}
 	if value, ok := suo.mutation.ExpiresAt(); ok {
 		_spec.SetField(session.FieldExpiresAt, field.TypeTime, value)
 	}
+	if value, ok := suo.mutation.AbsoluteExpiresAt(); ok {
+		_spec.SetField(session.FieldAbsoluteExpiresAt, field.TypeTime, value)
+	}
+	if suo.mutation.AbsoluteExpiresAtCleared() {
+		_spec.ClearField(session.FieldAbsoluteExpiresAt, field.TypeTime)
+	}
+	if value, ok := suo.mutation.Remember(); ok {
+		_spec.SetField(session.FieldRemember, field.TypeBool, value)
+	}
+	if value, ok := suo.mutation.LastSeenAt(); ok {
+		_spec.SetField(session.FieldLastSeenAt, field.TypeTime, value)
+	}
+	if value, ok := suo.mutation.IP(); ok {
+		_spec.SetField(session.FieldIP, field.TypeString, value)
+	}
+	if value, ok := suo.mutation.UserAgent(); ok {
+		_spec.SetField(session.FieldUserAgent, field.TypeString, value)
+	}
 	if suo.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
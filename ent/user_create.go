@@ -10,6 +10,10 @@ import (
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/qinzj/superpowers-demo/ent/consent"
+	"github.com/qinzj/superpowers-demo/ent/group"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/role"
 	"github.com/qinzj/superpowers-demo/ent/session"
 	"github.com/qinzj/superpowers-demo/ent/user"
 )
@@ -39,6 +43,118 @@ func (uc *UserCreate) SetPasswordHash(s string) *UserCreate {
 	return uc
 }
 
+// SetDisplayName sets the "display_name" field.
+func (uc *UserCreate) SetDisplayName(s string) *UserCreate {
+	uc.mutation.SetDisplayName(s)
+	return uc
+}
+
+// SetNillableDisplayName sets the "display_name" field if the given value is not nil.
+func (uc *UserCreate) SetNillableDisplayName(s *string) *UserCreate {
+	if s != nil {
+		uc.SetDisplayName(*s)
+	}
+	return uc
+}
+
+// SetEmailVerified sets the "email_verified" field.
+func (uc *UserCreate) SetEmailVerified(b bool) *UserCreate {
+	uc.mutation.SetEmailVerified(b)
+	return uc
+}
+
+// SetNillableEmailVerified sets the "email_verified" field if the given value is not nil.
+func (uc *UserCreate) SetNillableEmailVerified(b *bool) *UserCreate {
+	if b != nil {
+		uc.SetEmailVerified(*b)
+	}
+	return uc
+}
+
+// SetPendingEmail sets the "pending_email" field.
+func (uc *UserCreate) SetPendingEmail(s string) *UserCreate {
+	uc.mutation.SetPendingEmail(s)
+	return uc
+}
+
+// SetNillablePendingEmail sets the "pending_email" field if the given value is not nil.
+func (uc *UserCreate) SetNillablePendingEmail(s *string) *UserCreate {
+	if s != nil {
+		uc.SetPendingEmail(*s)
+	}
+	return uc
+}
+
+// SetEmailVerifyTokenHash sets the "email_verify_token_hash" field.
+func (uc *UserCreate) SetEmailVerifyTokenHash(s string) *UserCreate {
+	uc.mutation.SetEmailVerifyTokenHash(s)
+	return uc
+}
+
+// SetNillableEmailVerifyTokenHash sets the "email_verify_token_hash" field if the given value is not nil.
+func (uc *UserCreate) SetNillableEmailVerifyTokenHash(s *string) *UserCreate {
+	if s != nil {
+		uc.SetEmailVerifyTokenHash(*s)
+	}
+	return uc
+}
+
+// SetEmailVerifyExpiresAt sets the "email_verify_expires_at" field.
+func (uc *UserCreate) SetEmailVerifyExpiresAt(t time.Time) *UserCreate {
+	uc.mutation.SetEmailVerifyExpiresAt(t)
+	return uc
+}
+
+// SetNillableEmailVerifyExpiresAt sets the "email_verify_expires_at" field if the given value is not nil.
+func (uc *UserCreate) SetNillableEmailVerifyExpiresAt(t *time.Time) *UserCreate {
+	if t != nil {
+		uc.SetEmailVerifyExpiresAt(*t)
+	}
+	return uc
+}
+
+// SetExternalID sets the "external_id" field.
+func (uc *UserCreate) SetExternalID(s string) *UserCreate {
+	uc.mutation.SetExternalID(s)
+	return uc
+}
+
+// SetNillableExternalID sets the "external_id" field if the given value is not nil.
+func (uc *UserCreate) SetNillableExternalID(s *string) *UserCreate {
+	if s != nil {
+		uc.SetExternalID(*s)
+	}
+	return uc
+}
+
+// SetStatus sets the "status" field.
+func (uc *UserCreate) SetStatus(u user.Status) *UserCreate {
+	uc.mutation.SetStatus(u)
+	return uc
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (uc *UserCreate) SetNillableStatus(u *user.Status) *UserCreate {
+	if u != nil {
+		uc.SetStatus(*u)
+	}
+	return uc
+}
+
+// SetDeletionScheduledAt sets the "deletion_scheduled_at" field.
+func (uc *UserCreate) SetDeletionScheduledAt(t time.Time) *UserCreate {
+	uc.mutation.SetDeletionScheduledAt(t)
+	return uc
+}
+
+// SetNillableDeletionScheduledAt sets the "deletion_scheduled_at" field if the given value is not nil.
+func (uc *UserCreate) SetNillableDeletionScheduledAt(t *time.Time) *UserCreate {
+	if t != nil {
+		uc.SetDeletionScheduledAt(*t)
+	}
+	return uc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (uc *UserCreate) SetCreatedAt(t time.Time) *UserCreate {
 	uc.mutation.SetCreatedAt(t)
@@ -68,6 +184,66 @@ func (uc *UserCreate) AddSessions(s ...*Session) *UserCreate {
 	return uc.AddSessionIDs(ids...)
 }
 
+// AddConsentIDs adds the "consents" edge to the Consent entity by IDs.
+func (uc *UserCreate) AddConsentIDs(ids ...int) *UserCreate {
+	uc.mutation.AddConsentIDs(ids...)
+	return uc
+}
+
+// AddConsents adds the "consents" edges to the Consent entity.
+func (uc *UserCreate) AddConsents(c ...*Consent) *UserCreate {
+	ids := make([]int, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return uc.AddConsentIDs(ids...)
+}
+
+// AddIdentityIDs adds the "identities" edge to the LinkedIdentity entity by IDs.
+func (uc *UserCreate) AddIdentityIDs(ids ...int) *UserCreate {
+	uc.mutation.AddIdentityIDs(ids...)
+	return uc
+}
+
+// AddIdentities adds the "identities" edges to the LinkedIdentity entity.
+func (uc *UserCreate) AddIdentities(l ...*LinkedIdentity) *UserCreate {
+	ids := make([]int, len(l))
+	for i := range l {
+		ids[i] = l[i].ID
+	}
+	return uc.AddIdentityIDs(ids...)
+}
+
+// AddGroupIDs adds the "groups" edge to the Group entity by IDs.
+func (uc *UserCreate) AddGroupIDs(ids ...int) *UserCreate {
+	uc.mutation.AddGroupIDs(ids...)
+	return uc
+}
+
+// AddGroups adds the "groups" edges to the Group entity.
+func (uc *UserCreate) AddGroups(g ...*Group) *UserCreate {
+	ids := make([]int, len(g))
+	for i := range g {
+		ids[i] = g[i].ID
+	}
+	return uc.AddGroupIDs(ids...)
+}
+
+// AddRoleIDs adds the "roles" edge to the Role entity by IDs.
+func (uc *UserCreate) AddRoleIDs(ids ...int) *UserCreate {
+	uc.mutation.AddRoleIDs(ids...)
+	return uc
+}
+
+// AddRoles adds the "roles" edges to the Role entity.
+func (uc *UserCreate) AddRoles(r ...*Role) *UserCreate {
+	ids := make([]int, len(r))
+	for i := range r {
+		ids[i] = r[i].ID
+	}
+	return uc.AddRoleIDs(ids...)
+}
+
 // Mutation returns the UserMutation object of the builder.
 func (uc *UserCreate) Mutation() *UserMutation {
 	return uc.mutation
@@ -103,6 +279,30 @@ func (uc *UserCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (uc *UserCreate) defaults() {
+	if _, ok := uc.mutation.DisplayName(); !ok {
+		v := user.DefaultDisplayName
+		uc.mutation.SetDisplayName(v)
+	}
+	if _, ok := uc.mutation.EmailVerified(); !ok {
+		v := user.DefaultEmailVerified
+		uc.mutation.SetEmailVerified(v)
+	}
+	if _, ok := uc.mutation.PendingEmail(); !ok {
+		v := user.DefaultPendingEmail
+		uc.mutation.SetPendingEmail(v)
+	}
+	if _, ok := uc.mutation.EmailVerifyTokenHash(); !ok {
+		v := user.DefaultEmailVerifyTokenHash
+		uc.mutation.SetEmailVerifyTokenHash(v)
+	}
+	if _, ok := uc.mutation.ExternalID(); !ok {
+		v := user.DefaultExternalID
+		uc.mutation.SetExternalID(v)
+	}
+	if _, ok := uc.mutation.Status(); !ok {
+		v := user.DefaultStatus
+		uc.mutation.SetStatus(v)
+	}
 	if _, ok := uc.mutation.CreatedAt(); !ok {
 		v := user.DefaultCreatedAt()
 		uc.mutation.SetCreatedAt(v)
@@ -135,6 +335,29 @@ func (uc *UserCreate) check() error {
 			return &ValidationError{Name: "password_hash", err: fmt.Errorf(`ent: validator failed for field "User.password_hash": %w`, err)}
 		}
 	}
+	if _, ok := uc.mutation.DisplayName(); !ok {
+		return &ValidationError{Name: "display_name", err: errors.New(`ent: missing required field "User.display_name"`)}
+	}
+	if _, ok := uc.mutation.EmailVerified(); !ok {
+		return &ValidationError{Name: "email_verified", err: errors.New(`ent: missing required field "User.email_verified"`)}
+	}
+	if _, ok := uc.mutation.PendingEmail(); !ok {
+		return &ValidationError{Name: "pending_email", err: errors.New(`ent: missing required field "User.pending_email"`)}
+	}
+	if _, ok := uc.mutation.EmailVerifyTokenHash(); !ok {
+		return &ValidationError{Name: "email_verify_token_hash", err: errors.New(`ent: missing required field "User.email_verify_token_hash"`)}
+	}
+	if _, ok := uc.mutation.ExternalID(); !ok {
+		return &ValidationError{Name: "external_id", err: errors.New(`ent: missing required field "User.external_id"`)}
+	}
+	if _, ok := uc.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "User.status"`)}
+	}
+	if v, ok := uc.mutation.Status(); ok {
+		if err := user.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "User.status": %w`, err)}
+		}
+	}
 	if _, ok := uc.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "User.created_at"`)}
 	}
@@ -176,6 +399,38 @@ func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		_spec.SetField(user.FieldPasswordHash, field.TypeString, value)
 		_node.PasswordHash = value
 	}
+	if value, ok := uc.mutation.DisplayName(); ok {
+		_spec.SetField(user.FieldDisplayName, field.TypeString, value)
+		_node.DisplayName = value
+	}
+	if value, ok := uc.mutation.EmailVerified(); ok {
+		_spec.SetField(user.FieldEmailVerified, field.TypeBool, value)
+		_node.EmailVerified = value
+	}
+	if value, ok := uc.mutation.PendingEmail(); ok {
+		_spec.SetField(user.FieldPendingEmail, field.TypeString, value)
+		_node.PendingEmail = value
+	}
+	if value, ok := uc.mutation.EmailVerifyTokenHash(); ok {
+		_spec.SetField(user.FieldEmailVerifyTokenHash, field.TypeString, value)
+		_node.EmailVerifyTokenHash = value
+	}
+	if value, ok := uc.mutation.EmailVerifyExpiresAt(); ok {
+		_spec.SetField(user.FieldEmailVerifyExpiresAt, field.TypeTime, value)
+		_node.EmailVerifyExpiresAt = &value
+	}
+	if value, ok := uc.mutation.ExternalID(); ok {
+		_spec.SetField(user.FieldExternalID, field.TypeString, value)
+		_node.ExternalID = value
+	}
+	if value, ok := uc.mutation.Status(); ok {
+		_spec.SetField(user.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := uc.mutation.DeletionScheduledAt(); ok {
+		_spec.SetField(user.FieldDeletionScheduledAt, field.TypeTime, value)
+		_node.DeletionScheduledAt = &value
+	}
 	if value, ok := uc.mutation.CreatedAt(); ok {
 		_spec.SetField(user.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -196,6 +451,70 @@ func (uc *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		}
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := uc.mutation.ConsentsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.ConsentsTable,
+			Columns: []string{user.ConsentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(consent.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.IdentitiesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.IdentitiesTable,
+			Columns: []string{user.IdentitiesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(linkedidentity.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.GroupsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   user.GroupsTable,
+			Columns: user.GroupsPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(group.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := uc.mutation.RolesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2M,
+			Inverse: true,
+			Table:   user.RolesTable,
+			Columns: user.RolesPrimaryKey,
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(role.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
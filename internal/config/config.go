@@ -0,0 +1,273 @@
+// Package config defines the server configuration, loads it from a YAML file with SSO_-prefixed
+// environment overrides, validates it, and reloads the settings that can change while the server
+// runs.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/infra/envelope"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
+	"github.com/qinzj/superpowers-demo/internal/server"
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/cleanup"
+	"github.com/qinzj/superpowers-demo/internal/service/declarative"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// Config mirrors configs/settings.yaml. Each section uses the settings type of the package it
+// configures.
+type Config struct {
+	Server      server.Config        `mapstructure:"server"`
+	Database    DatabaseConfig       `mapstructure:"database"`
+	Log         log.Config           `mapstructure:"log"`
+	OIDC        OIDCConfig           `mapstructure:"oidc"`
+	Password    password.Config      `mapstructure:"password"`
+	Session     auth.SessionConfig   `mapstructure:"session"`
+	Cookie      handler.CookiePolicy `mapstructure:"cookie"`
+	CSRF        CSRFConfig           `mapstructure:"csrf"`
+	Admin       AdminConfig          `mapstructure:"admin"`
+	Account     AccountConfig        `mapstructure:"account"`
+	SCIM        SCIMConfig           `mapstructure:"scim"`
+	Cleanup     cleanup.Config       `mapstructure:"cleanup"`
+	Tracing     tracing.Config       `mapstructure:"tracing"`
+	Metrics     MetricsConfig        `mapstructure:"metrics"`
+	Webhooks    webhook.Config       `mapstructure:"webhooks"`
+	Secrets     SecretsConfig        `mapstructure:"secrets"`
+	Bootstrap   BootstrapConfig      `mapstructure:"bootstrap"`
+	Declarative declarative.Config   `mapstructure:"declarative"`
+}
+
+// Database drivers accepted in DatabaseConfig.Driver.
+const (
+	DriverSQLite   = "sqlite3"
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+)
+
+// DatabaseConfig holds the "database" section.
+type DatabaseConfig struct {
+	Driver      string `mapstructure:"driver"` // sqlite3, mysql or postgres
+	DSN         string `mapstructure:"dsn"`
+	DSNFile     string `mapstructure:"dsn_file"`     // read DSN from this file instead
+	AutoMigrate bool   `mapstructure:"auto_migrate"` // apply pending migrations at startup
+}
+
+// Validate reports an unknown driver or a missing DSN.
+func (c DatabaseConfig) Validate() error {
+	switch c.Driver {
+	case DriverSQLite, DriverMySQL, DriverPostgres:
+	default:
+		return fmt.Errorf("database.driver must be %s, %s or %s, got %q", DriverSQLite, DriverMySQL, DriverPostgres, c.Driver)
+	}
+	if c.DSN == "" {
+		return errors.New("database.dsn is required")
+	}
+	return nil
+}
+
+// OIDCConfig holds the "oidc" section.
+type OIDCConfig struct {
+	Issuer                string        `mapstructure:"issuer"`                  // defaults to the server's own URL
+	GlobalSecret          string        `mapstructure:"global_secret"`           // HMAC key for codes and tokens; random per process when empty
+	GlobalSecretFile      string        `mapstructure:"global_secret_file"`      // read global_secret from this file instead
+	PrivateKeyFile        string        `mapstructure:"private_key_file"`        // PEM RSA key signing ID tokens; random per process when empty
+	AccessTokenLifespan   time.Duration `mapstructure:"access_token_lifespan"`   // reloadable
+	RefreshTokenLifespan  time.Duration `mapstructure:"refresh_token_lifespan"`  // reloadable
+	IDTokenLifespan       time.Duration `mapstructure:"id_token_lifespan"`       // reloadable
+	AuthorizeCodeLifespan time.Duration `mapstructure:"authorize_code_lifespan"` // reloadable
+	// TokenExchange lists the clients that may use the token exchange grant (RFC 8693).
+	TokenExchange []oidc.TokenExchangePolicy `mapstructure:"token_exchange"`
+	// DeviceClients lists the clients that may use the device authorization grant (RFC 8628).
+	DeviceClients []string `mapstructure:"device_clients"`
+}
+
+// Validate reports non-positive lifespans and a global secret too short for HMAC-SHA512/256.
+func (c OIDCConfig) Validate() error {
+	if c.GlobalSecret != "" && len(c.GlobalSecret) < minGlobalSecret {
+		return fmt.Errorf("oidc.global_secret must be at least %d bytes", minGlobalSecret)
+	}
+	for _, l := range []struct {
+		key string
+		d   time.Duration
+	}{
+		{"access_token_lifespan", c.AccessTokenLifespan},
+		{"refresh_token_lifespan", c.RefreshTokenLifespan},
+		{"id_token_lifespan", c.IDTokenLifespan},
+		{"authorize_code_lifespan", c.AuthorizeCodeLifespan},
+	} {
+		if l.d <= 0 {
+			return fmt.Errorf("oidc.%s must be positive, got %s", l.key, l.d)
+		}
+	}
+	seen := make(map[string]bool)
+	for i, p := range c.TokenExchange {
+		switch {
+		case p.ClientID == "":
+			return fmt.Errorf("oidc.token_exchange[%d].client_id is required", i)
+		case seen[p.ClientID]:
+			return fmt.Errorf("oidc.token_exchange: client %q is listed twice", p.ClientID)
+		case len(p.Audiences) == 0:
+			return fmt.Errorf("oidc.token_exchange[%d].audiences must not be empty", i)
+		case !p.Impersonation && !p.Delegation:
+			return fmt.Errorf("oidc.token_exchange[%d] must allow impersonation, delegation or both", i)
+		}
+		seen[p.ClientID] = true
+	}
+	for i, id := range c.DeviceClients {
+		if id == "" {
+			return fmt.Errorf("oidc.device_clients[%d] is empty", i)
+		}
+	}
+	return nil
+}
+
+// Lifespans returns the token lifetimes for oidc.OIDCConfig.
+func (c OIDCConfig) Lifespans() oidc.Lifespans {
+	return oidc.Lifespans{
+		AccessToken:   c.AccessTokenLifespan,
+		RefreshToken:  c.RefreshTokenLifespan,
+		IDToken:       c.IDTokenLifespan,
+		AuthorizeCode: c.AuthorizeCodeLifespan,
+	}
+}
+
+// minGlobalSecret is the shortest HMAC key fosite accepts.
+const minGlobalSecret = 32
+
+// CSRFConfig holds the "csrf" section.
+type CSRFConfig struct {
+	Key     string `mapstructure:"key"`      // HMAC key for form tokens; random per process when empty
+	KeyFile string `mapstructure:"key_file"` // read key from this file instead
+}
+
+// AdminConfig holds the "admin" section.
+type AdminConfig struct {
+	// Deprecated: list the users under admins in the bootstrap file (BootstrapConfig.File).
+	BootstrapUsers []string `mapstructure:"bootstrap_users"` // usernames granted the admin role at startup
+	APIClients     []string `mapstructure:"api_clients"`     // client IDs whose tokens may call /admin/api
+}
+
+// AccountConfig holds the "account" section.
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration `mapstructure:"deletion_grace_period"`
+}
+
+// SCIMConfig holds the "scim" section.
+type SCIMConfig struct {
+	BearerTokens     []string `mapstructure:"bearer_tokens"`      // empty disables SCIM
+	BearerTokensFile string   `mapstructure:"bearer_tokens_file"` // one token per line, added to bearer_tokens
+}
+
+// SecretsConfig holds the "secrets" section: the master keys that encrypt sensitive database
+// fields. Keys are 32 random bytes in standard base64.
+type SecretsConfig struct {
+	MasterKey        string   `mapstructure:"master_key"`         // seals new values; empty stores them in plaintext
+	MasterKeyFile    string   `mapstructure:"master_key_file"`    // read master_key from this file instead
+	PreviousKeys     []string `mapstructure:"previous_keys"`      // retired keys still accepted for reading
+	PreviousKeysFile string   `mapstructure:"previous_keys_file"` // one key per line, added to previous_keys
+}
+
+// Enabled reports whether sensitive fields are encrypted.
+func (c SecretsConfig) Enabled() bool {
+	return c.MasterKey != ""
+}
+
+// Keyring builds the keyring for the configured keys.
+func (c SecretsConfig) Keyring() (*envelope.Keyring, error) {
+	primary, err := envelope.ParseKey(c.MasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("secrets.master_key: %w", err)
+	}
+	previous := make([][]byte, 0, len(c.PreviousKeys))
+	for i, s := range c.PreviousKeys {
+		key, err := envelope.ParseKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("secrets.previous_keys[%d]: %w", i, err)
+		}
+		previous = append(previous, key)
+	}
+	return envelope.NewKeyring(primary, previous...)
+}
+
+// Validate reports keys that are not valid master keys.
+func (c SecretsConfig) Validate() error {
+	if !c.Enabled() {
+		if len(c.PreviousKeys) > 0 {
+			return errors.New("secrets.master_key is required when previous_keys is set")
+		}
+		return nil
+	}
+	_, err := c.Keyring()
+	return err
+}
+
+// BootstrapConfig holds the "bootstrap" section.
+type BootstrapConfig struct {
+	File string `mapstructure:"file"` // YAML file of initial clients, connectors and admin users; empty disables
+}
+
+// MetricsConfig holds the "metrics" section.
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // serves the unauthenticated metrics endpoint; off by default
+	Path    string `mapstructure:"path"`
+}
+
+// Validate reports a path that gin cannot route.
+func (c MetricsConfig) Validate() error {
+	if c.Enabled && !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("metrics.path must start with /, got %q", c.Path)
+	}
+	return nil
+}
+
+// Default returns the settings used for keys missing from both the file and the environment.
+func Default() Config {
+	return Config{
+		Server:   server.DefaultConfig(),
+		Database: DatabaseConfig{Driver: DriverSQLite},
+		Log:      log.Config{Level: "info", Output: "stdout"},
+		OIDC: OIDCConfig{
+			AccessTokenLifespan:   30 * time.Minute,
+			RefreshTokenLifespan:  24 * time.Hour,
+			IDTokenLifespan:       time.Hour,
+			AuthorizeCodeLifespan: 15 * time.Minute,
+		},
+		Password: *password.DefaultConfig(),
+		Session:  auth.DefaultSessionConfig(),
+		Cookie:   handler.CookiePolicy{SameSite: "lax"},
+		Account:  AccountConfig{DeletionGracePeriod: user.DefaultDeletionGracePeriod},
+		Metrics:  MetricsConfig{Path: "/metrics"},
+	}
+}
+
+// Validate checks every section and reports all problems at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	check(c.Server.Validate())
+	check(c.Database.Validate())
+	check(c.Log.Validate())
+	check(c.OIDC.Validate())
+	if _, err := password.NewHasher(&c.Password); err != nil {
+		check(err)
+	}
+	check(c.Session.Validate())
+	check(c.Cookie.Validate())
+	check(c.Tracing.Validate())
+	check(c.Metrics.Validate())
+	check(c.Secrets.Validate())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+)
+
+const minimalYAML = `
+database:
+  dsn: file:test.db
+`
+
+func writeConfig(t *testing.T, path, body string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+}
+
+func TestLoad_ShippedSettings(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", DefaultPath))
+	require.NoError(t, err)
+	require.Equal(t, 8888, cfg.Server.Port)
+	require.Equal(t, DriverSQLite, cfg.Database.Driver)
+	require.Equal(t, 30*time.Minute, cfg.OIDC.AccessTokenLifespan)
+	require.False(t, cfg.Metrics.Enabled, "metrics are unauthenticated and off unless enabled")
+}
+
+func TestLoad_DefaultsAndEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeConfig(t, path, minimalYAML+`
+server:
+  port: 9000
+`)
+	t.Setenv("SSO_SERVER_PORT", "9100")
+	// Keys absent from the file are overridable too.
+	t.Setenv("SSO_OIDC_ACCESS_TOKEN_LIFESPAN", "5m")
+	t.Setenv("SSO_SERVER_TLS_MIN_VERSION", "1.3")
+	t.Setenv("SSO_ADMIN_BOOTSTRAP_USERS", "alice,bob")
+	t.Setenv("SSO_METRICS_ENABLED", "true")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 9100, cfg.Server.Port)
+	require.Equal(t, 5*time.Minute, cfg.OIDC.AccessTokenLifespan)
+	require.Equal(t, 24*time.Hour, cfg.OIDC.RefreshTokenLifespan, "default kept")
+	require.Equal(t, "1.3", cfg.Server.TLS.MinVersion)
+	require.Equal(t, []string{"alice", "bob"}, cfg.Admin.BootstrapUsers)
+	require.True(t, cfg.Metrics.Enabled)
+	require.Equal(t, "file:test.db", cfg.Database.DSN)
+}
+
+func TestLoad_ReportsEveryInvalidSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeConfig(t, path, `
+database:
+  driver: oracle
+log:
+  level: loud
+oidc:
+  id_token_lifespan: -1s
+cookie:
+  same_site: none
+`)
+	_, err := Load(path)
+	require.Error(t, err)
+	for _, want := range []string{
+		"database.driver",
+		"unknown level",
+		"oidc.id_token_lifespan",
+		"same_site=none requires secure=true",
+	} {
+		require.ErrorContains(t, err, want)
+	}
+}
+
+func TestLoad_TokenExchangePolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeConfig(t, path, minimalYAML+`
+oidc:
+  token_exchange:
+    - client_id: api-gateway
+      audiences: [orders-api]
+      scopes: [openid]
+      delegation: true
+`)
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, []oidc.TokenExchangePolicy{{
+		ClientID:   "api-gateway",
+		Audiences:  []string{"orders-api"},
+		Scopes:     []string{"openid"},
+		Delegation: true,
+	}}, cfg.OIDC.TokenExchange)
+
+	writeConfig(t, path, minimalYAML+`
+oidc:
+  token_exchange:
+    - client_id: api-gateway
+      audiences: [orders-api]
+`)
+	_, err = Load(path)
+	require.ErrorContains(t, err, "must allow impersonation, delegation or both")
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "read config")
+}
+
+func TestRestartRequired(t *testing.T) {
+	old := Default()
+	cur := Default()
+	cur.Log.Level = "debug"
+	cur.Cookie.SameSite = "strict"
+	cur.OIDC.AccessTokenLifespan = time.Minute
+	require.False(t, RestartRequired(&old, &cur))
+
+	cur.Server.Port = 9000
+	require.True(t, RestartRequired(&old, &cur))
+}
+
+func TestWatch_AppliesReloadableSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeConfig(t, path, minimalYAML)
+	loader := NewLoader(path)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	oidcCfg := &oidc.OIDCConfig{}
+	oidcCfg.SetLifespans(cfg.OIDC.Lifespans())
+	live := Live{
+		LogLevel: zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		OIDC:     oidcCfg,
+		Cookie:   handler.NewCookiePolicySource(cfg.Cookie),
+	}
+	results := make(chan error, 16)
+	loader.Watch(func(next *Config, err error) {
+		if err == nil {
+			err = live.Apply(next)
+		}
+		results <- err
+	})
+
+	// The watcher reads the file after a change event, so an invalid version is rejected and the
+	// live settings stay as they were.
+	writeConfig(t, path, minimalYAML+`
+log:
+  level: loud
+`)
+	require.Error(t, waitResult(t, results))
+	require.Equal(t, zapcore.InfoLevel, live.LogLevel.Level())
+
+	writeConfig(t, path, minimalYAML+`
+log:
+  level: debug
+oidc:
+  access_token_lifespan: 2m
+cookie:
+  same_site: strict
+`)
+	require.Eventually(t, func() bool {
+		for {
+			select {
+			case <-results:
+			default:
+				return live.LogLevel.Level() == zapcore.DebugLevel
+			}
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 2*time.Minute, oidcCfg.Lifespans().AccessToken)
+	require.Equal(t, "strict", live.Cookie.Load().SameSite)
+}
+
+func waitResult(t *testing.T, results <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-results:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change not observed")
+		return nil
+	}
+}
+
+func TestLoad_SecretsFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.yaml")
+	masterKey := "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	writeConfig(t, filepath.Join(dir, "dsn"), "file:secret.db\n")
+	writeConfig(t, filepath.Join(dir, "master"), masterKey+"\n")
+	writeConfig(t, filepath.Join(dir, "scim"), "# rotated 2026-10\ntoken-a\n\ntoken-b\n")
+	writeConfig(t, path, `
+database:
+  dsn_file: `+filepath.Join(dir, "dsn")+`
+scim:
+  bearer_tokens: [inline]
+  bearer_tokens_file: `+filepath.Join(dir, "scim")+`
+`)
+	t.Setenv("SSO_SECRETS_MASTER_KEY_FILE", filepath.Join(dir, "master"))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "file:secret.db", cfg.Database.DSN)
+	require.Equal(t, masterKey, cfg.Secrets.MasterKey)
+	require.True(t, cfg.Secrets.Enabled())
+	require.Equal(t, []string{"inline", "token-a", "token-b"}, cfg.SCIM.BearerTokens)
+}
+
+func TestLoad_SecretFileErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	writeConfig(t, path, minimalYAML+`
+  dsn_file: /nonexistent/dsn
+csrf:
+  key_file: /nonexistent/csrf
+oidc:
+  private_key_file: /nonexistent/key.pem
+secrets:
+  master_key: c2hvcnQ=
+`)
+	_, err := Load(path)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "database.dsn and database.dsn_file are mutually exclusive")
+	require.ErrorContains(t, err, "csrf.key_file")
+	require.ErrorContains(t, err, "oidc.private_key_file")
+
+	writeConfig(t, path, minimalYAML+`
+secrets:
+  master_key: c2hvcnQ=
+`)
+	_, err = Load(path)
+	require.ErrorContains(t, err, "secrets.master_key")
+}
+
+func TestRedactDSN(t *testing.T) {
+	for dsn, want := range map[string]string{
+		"file:./data/sso.db?cache=shared&_fk=1":                 "file:./data/sso.db?cache=shared&_fk=1",
+		"postgres://sso:hunter2@db:5432/sso?sslmode=require":    "postgres://sso:xxxxx@db:5432/sso?sslmode=require",
+		"postgres://sso@db/sso":                                 "postgres://sso@db/sso",
+		"host=db user=sso password=hunter2 dbname=sso":          "host=db user=sso password=xxxxx dbname=sso",
+		"sso:p@ss:w0rd@tcp(db:3306)/sso?parseTime=true":         "sso:xxxxx@tcp(db:3306)/sso?parseTime=true",
+		"file:sso.db?_auth&_auth_user=admin&_auth_pass=hunter2": "file:sso.db?_auth&_auth_user=admin&_auth_pass=xxxxx",
+	} {
+		require.Equal(t, want, RedactDSN(dsn), dsn)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// DefaultPath is the configuration file read when --config is not given, relative to the
+// working directory.
+const DefaultPath = "configs/settings.yaml"
+
+// EnvPrefix prefixes environment overrides. A key's variable is the prefix, an underscore and
+// the upper-cased key with dots replaced by underscores: SSO_SERVER_PORT sets server.port and
+// SSO_OIDC_ACCESS_TOKEN_LIFESPAN sets oidc.access_token_lifespan. Lists are comma-separated.
+const EnvPrefix = "SSO"
+
+// Loader reads the configuration file and the environment.
+type Loader struct {
+	v    *viper.Viper
+	path string
+}
+
+// NewLoader returns a loader for the YAML file at path.
+func NewLoader(path string) *Loader {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	// AutomaticEnv only applies to keys viper already knows; binding every key lets the
+	// environment set values that are absent from the file.
+	bindEnv(v, reflect.TypeOf(Config{}), "")
+	return &Loader{v: v, path: path}
+}
+
+// Load is NewLoader(path).Load().
+func Load(path string) (*Config, error) {
+	return NewLoader(path).Load()
+}
+
+// Load reads the file and returns the validated configuration.
+func (l *Loader) Load() (*Config, error) {
+	if err := l.v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return l.decode()
+}
+
+// Watch calls onChange each time the file changes, with the re-read configuration or the reason
+// it could not be decoded or validated. Call it after Load.
+func (l *Loader) Watch(onChange func(*Config, error)) {
+	l.v.OnConfigChange(func(fsnotify.Event) {
+		onChange(l.decode())
+	})
+	l.v.WatchConfig()
+}
+
+func (l *Loader) decode() (*Config, error) {
+	cfg := Default()
+	if err := l.v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("decode config %s: %w", l.path, err)
+	}
+	if err := cfg.resolveFiles(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", l.path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", l.path, err)
+	}
+	return &cfg, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// bindEnv binds an environment variable for every mapstructure key below t.
+func bindEnv(v *viper.Viper, t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if !f.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		key := prefix + tag
+		if f.Type.Kind() == reflect.Struct && f.Type != durationType {
+			bindEnv(v, f.Type, key+".")
+			continue
+		}
+		_ = v.BindEnv(key)
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// Live holds the running components that reloadable settings are applied to: the log level,
+// the OAuth2 token lifespans and the cookie policy. Every other setting takes effect on restart.
+type Live struct {
+	LogLevel zap.AtomicLevel
+	OIDC     *oidc.OIDCConfig
+	Cookie   *handler.CookiePolicySource
+}
+
+// Apply updates the live components from cfg, which should already be validated.
+func (l Live) Apply(cfg *Config) error {
+	level, err := log.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return err
+	}
+	if err := l.Cookie.Store(cfg.Cookie); err != nil {
+		return fmt.Errorf("apply cookie policy: %w", err)
+	}
+	l.LogLevel.SetLevel(level)
+	l.OIDC.SetLifespans(cfg.OIDC.Lifespans())
+	return nil
+}
+
+// RestartRequired reports whether cur differs from old in a setting that Apply does not reload.
+func RestartRequired(old, cur *Config) bool {
+	return !reflect.DeepEqual(old.static(), cur.static())
+}
+
+// static returns c with the reloadable settings cleared.
+func (c *Config) static() Config {
+	s := *c
+	s.Log.Level = ""
+	s.Cookie = handler.CookiePolicy{}
+	issuer := s.OIDC.Issuer
+	s.OIDC = OIDCConfig{Issuer: issuer, TokenExchange: c.OIDC.TokenExchange, DeviceClients: c.OIDC.DeviceClients}
+	return s
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Consent records the scopes a user has allowed an OAuth2 client to be granted without asking
+// again.
+type Consent struct {
+	UserID    string
+	ClientID  string
+	Scopes    []string
+	GrantedAt time.Time
+	// UpdatedAt is when scopes were last added.
+	UpdatedAt time.Time
+}
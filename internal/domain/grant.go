@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Grant describes an OAuth2 client the user has authorized: one currently holding tokens on
+// their behalf, or allowed by consent.
+type Grant struct {
+	ClientID  string
+	Scopes    []string
+	GrantedAt time.Time
+}
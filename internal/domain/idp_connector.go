@@ -6,4 +6,6 @@ type IdPConnector struct {
 	Issuer       string
 	ClientID     string
 	ClientSecret string
+	// GroupsClaim names the upstream claim carrying group names; empty disables group sync.
+	GroupsClaim string
 }
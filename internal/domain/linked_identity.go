@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// LinkedIdentity is an account at an upstream IdP that signs in as a local user.
+type LinkedIdentity struct {
+	UserID      string
+	ConnectorID string
+	// Subject is the upstream "sub" claim.
+	Subject string
+	// Email is the upstream address when the identity was linked.
+	Email    string
+	LinkedAt time.Time
+}
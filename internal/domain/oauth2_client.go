@@ -1,9 +1,10 @@
 package domain
 
-// OAuth2Client represents a registered OAuth2/OIDC client (RP).
+// OAuth2Client is a relying party registered with the provider.
 type OAuth2Client struct {
-	ID           string
-	ClientID     string
-	ClientSecret string
+	ID       string
+	ClientID string
+	// SecretHash is the PHC-encoded hash of the client secret; the secret itself is never stored.
+	SecretHash   string
 	RedirectURIs []string
 }
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Group is a named set of users. Roles granted to a group apply to all its members.
+type Group struct {
+	ID          string
+	Name        string
+	Description string
+	// ConnectorID is set when the group is synced from an upstream IdP; empty for local groups.
+	ConnectorID string
+	CreatedAt   time.Time
+}
+
+// Role is a named permission set assigned to users directly or through groups.
+type Role struct {
+	ID          string
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
@@ -4,8 +4,20 @@ import "time"
 
 // Session represents an authenticated user session.
 type Session struct {
-	ID        string
-	UserID    string
-	Token     string
-	ExpiresAt time.Time
+	ID     string
+	UserID string
+	// Token is the raw sso_session cookie value. It is only set on a newly created session;
+	// sessions loaded from storage carry TokenHash alone.
+	Token string
+	// TokenHash is the hex SHA-256 digest of Token, the only form persisted.
+	TokenHash string
+	// ExpiresAt is the idle deadline; it slides forward on activity up to AbsoluteExpiresAt.
+	ExpiresAt         time.Time
+	AbsoluteExpiresAt time.Time
+	// Remember marks a "remember me" session with the longer lifetime and a persistent cookie.
+	Remember   bool
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	IP         string
+	UserAgent  string
 }
@@ -4,9 +4,58 @@ import "time"
 
 // User represents a local user identity.
 type User struct {
-	ID           string
-	Username     string
-	Email        string
-	PasswordHash string
-	CreatedAt    time.Time
+	ID            string
+	Username      string
+	Email         string
+	DisplayName   string
+	EmailVerified bool
+	// PendingEmail is the requested new address; it replaces Email once verified.
+	PendingEmail string
+	// EmailVerifyTokenHash is the SHA-256 hex digest of the outstanding verification token.
+	EmailVerifyTokenHash string
+	EmailVerifyExpiresAt time.Time
+	PasswordHash         string
+	// ExternalID is the identifier assigned by a provisioning client (SCIM externalId).
+	ExternalID string
+	// Status controls whether the user may authenticate; see UserStatus.
+	Status UserStatus
+	// DeletionScheduledAt is when a requested account deletion takes effect; zero if none.
+	DeletionScheduledAt time.Time
+	CreatedAt           time.Time
+}
+
+// Active reports whether the user may sign in and use existing sessions and tokens.
+func (u *User) Active() bool {
+	return u.Status == UserStatusActive
+}
+
+// UserStatus is the lifecycle state of a user. Only active users can authenticate.
+type UserStatus string
+
+const (
+	UserStatusActive UserStatus = "active"
+	// UserStatusSuspended is set by an administrator or deprovisioning; reversible.
+	UserStatusSuspended UserStatus = "suspended"
+	// UserStatusLocked blocks an account for security reasons until an administrator unlocks it.
+	UserStatusLocked UserStatus = "locked"
+	// UserStatusPendingVerification is a provisioned account that has not been confirmed yet.
+	UserStatusPendingVerification UserStatus = "pending_verification"
+)
+
+// UserStatuses lists every valid UserStatus.
+var UserStatuses = []UserStatus{
+	UserStatusActive,
+	UserStatusSuspended,
+	UserStatusLocked,
+	UserStatusPendingVerification,
+}
+
+// Valid reports whether s is one of UserStatuses.
+func (s UserStatus) Valid() bool {
+	for _, v := range UserStatuses {
+		if s == v {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// WebhookEventType names an identity lifecycle event delivered to webhook subscribers.
+type WebhookEventType string
+
+// Webhook event types.
+const (
+	WebhookUserCreated    WebhookEventType = "user.created"
+	WebhookUserDeleted    WebhookEventType = "user.deleted"
+	WebhookUserLogin      WebhookEventType = "user.login"
+	WebhookIdentityLinked WebhookEventType = "identity.linked"
+)
+
+// WebhookEventTypes lists every event type a subscription can select.
+var WebhookEventTypes = []WebhookEventType{
+	WebhookUserCreated,
+	WebhookUserDeleted,
+	WebhookUserLogin,
+	WebhookIdentityLinked,
+}
+
+// WebhookAllEvents in a subscription's event list matches every event type.
+const WebhookAllEvents = "*"
+
+// Valid reports whether t is one of WebhookEventTypes.
+func (t WebhookEventType) Valid() bool {
+	return slices.Contains(WebhookEventTypes, t)
+}
+
+// WebhookSubscription is an endpoint that receives signed event deliveries.
+type WebhookSubscription struct {
+	ID  string
+	URL string
+	// Events lists the event types delivered; WebhookAllEvents matches all.
+	Events []string
+	// Secret keys the HMAC-SHA256 signature sent with every delivery.
+	Secret      string
+	Description string
+	Enabled     bool
+	CreatedAt   time.Time
+}
+
+// Wants reports whether the subscription is enabled and selects event type t.
+func (s *WebhookSubscription) Wants(t WebhookEventType) bool {
+	return s.Enabled && (slices.Contains(s.Events, WebhookAllEvents) || slices.Contains(s.Events, string(t)))
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event queued for, or delivered to, one subscription.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	// EventID identifies the event; redeliveries share it so receivers can deduplicate.
+	EventID   string
+	EventType WebhookEventType
+	// Payload is the JSON request body.
+	Payload       string
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	// LastAttemptAt is zero until the first attempt.
+	LastAttemptAt time.Time
+	// ResponseStatus is the HTTP status of the last attempt; 0 when no response was received.
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+}
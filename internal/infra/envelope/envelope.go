@@ -0,0 +1,217 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package envelope encrypts secrets at rest with envelope encryption. Each value is encrypted
+// with its own random data key, and the data key is encrypted ("wrapped") with a master key.
+// Sealed values name the master key that wrapped them, so a Keyring can hold the current key
+// and the keys it replaces while stored values are re-encrypted.
+//
+// A sealed value has the form
+//
+//	enc:v2:<key id>:<wrapped data key>:<ciphertext>
+//
+// where both binary parts are unpadded base64url and each carries its AES-256-GCM nonce. Both
+// layers authenticate the key id and a binding naming where the value is stored, so a sealed
+// value copied to another row or column does not open. Values of the older form enc:v1:...
+// authenticate only the key id; they still open, and re-keying rewrites them as enc:v2.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length in bytes of master and data keys (AES-256).
+const KeySize = 32
+
+const (
+	prefix   = "enc:v2:"
+	prefixV1 = "enc:v1:"
+)
+
+var b64 = base64.RawURLEncoding
+
+// ErrUnknownKey is returned when a value was sealed with a master key the keyring does not hold.
+var ErrUnknownKey = errors.New("envelope: value sealed with an unknown master key")
+
+// Keyring seals values with its primary master key and opens values sealed with any of its keys.
+type Keyring struct {
+	primary *masterKey
+	keys    map[string]*masterKey
+}
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewKeyring returns a keyring that seals with primary and can also open values sealed with
+// any of previous.
+func NewKeyring(primary []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]*masterKey, 1+len(previous))}
+	for i, raw := range append([][]byte{primary}, previous...) {
+		mk, err := newMasterKey(raw)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			k.primary = mk
+		}
+		if _, dup := k.keys[mk.id]; !dup {
+			k.keys[mk.id] = mk
+		}
+	}
+	return k, nil
+}
+
+func newMasterKey(raw []byte) (*masterKey, error) {
+	if len(raw) != KeySize {
+		return nil, fmt.Errorf("envelope: master key must be %d bytes, got %d", KeySize, len(raw))
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return &masterKey{id: hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+// GenerateKey returns a random master key encoded for configuration.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodes a master key from standard base64, as printed by GenerateKey.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("envelope: master key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("envelope: master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// PrimaryID identifies the master key new values are sealed with.
+func (k *Keyring) PrimaryID() string {
+	return k.primary.id
+}
+
+// IsSealed reports whether s looks like a sealed value rather than plaintext.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, prefix) || strings.HasPrefix(s, prefixV1)
+}
+
+// Seal encrypts plaintext under a new data key wrapped with the primary master key. binding
+// names where the value is stored, such as "idp_connector.client_secret:<issuer> <client_id>";
+// it is authenticated with the key id by both layers, and Open must be given the same binding.
+func (k *Keyring) Seal(plaintext, binding string) (string, error) {
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("envelope: generate data key: %w", err)
+	}
+	aad := []byte(k.primary.id + ":" + binding)
+	wrapped, err := seal(k.primary.aead, dek, aad)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(plaintext), aad)
+	if err != nil {
+		return "", err
+	}
+	return prefix + k.primary.id + ":" + b64.EncodeToString(wrapped) + ":" + b64.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value produced by Seal with the same binding. Values that are not sealed are
+// returned unchanged, so that rows written before encryption was enabled stay readable until
+// they are re-keyed; enc:v1 values are opened without the binding.
+func (k *Keyring) Open(value, binding string) (string, error) {
+	var rest string
+	switch {
+	case strings.HasPrefix(value, prefix):
+		rest = strings.TrimPrefix(value, prefix)
+	case strings.HasPrefix(value, prefixV1):
+		rest = strings.TrimPrefix(value, prefixV1)
+	default:
+		return value, nil
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", errors.New("envelope: malformed sealed value")
+	}
+	mk, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrUnknownKey, parts[0])
+	}
+	wrapped, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("envelope: malformed data key")
+	}
+	ciphertext, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("envelope: malformed ciphertext")
+	}
+	aad := []byte(mk.id + ":" + binding)
+	if strings.HasPrefix(value, prefixV1) {
+		aad = []byte(mk.id)
+	}
+	dek, err := open(mk.aead, wrapped, aad)
+	if err != nil {
+		return "", fmt.Errorf("envelope: unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("envelope: decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Current reports whether value is sealed with the primary master key in the current form.
+// Plaintext values, enc:v1 values and values sealed with a previous key need re-keying.
+func (k *Keyring) Current(value string) bool {
+	return strings.HasPrefix(value, prefix+k.primary.id+":")
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext.
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("envelope: generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], aad)
+}
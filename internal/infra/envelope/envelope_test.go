@@ -0,0 +1,120 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package envelope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	s, err := GenerateKey()
+	require.NoError(t, err)
+	key, err := ParseKey(s)
+	require.NoError(t, err)
+	return key
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	k, err := NewKeyring(newKey(t))
+	require.NoError(t, err)
+
+	sealed, err := k.Seal("s3cret", "t.c:1")
+	require.NoError(t, err)
+	require.True(t, IsSealed(sealed))
+	require.True(t, k.Current(sealed))
+	require.NotContains(t, sealed, "s3cret")
+
+	again, err := k.Seal("s3cret", "t.c:1")
+	require.NoError(t, err)
+	require.NotEqual(t, sealed, again, "every value gets its own data key and nonce")
+
+	got, err := k.Open(sealed, "t.c:1")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", got)
+
+	plain, err := k.Open("legacy plaintext", "t.c:1")
+	require.NoError(t, err)
+	require.Equal(t, "legacy plaintext", plain)
+	require.False(t, k.Current("legacy plaintext"))
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKey, newKeyBytes := newKey(t), newKey(t)
+	old, err := NewKeyring(oldKey)
+	require.NoError(t, err)
+	sealed, err := old.Seal("value", "t.c:1")
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring(newKeyBytes, oldKey)
+	require.NoError(t, err)
+	require.False(t, rotated.Current(sealed))
+	got, err := rotated.Open(sealed, "t.c:1")
+	require.NoError(t, err)
+	require.Equal(t, "value", got)
+
+	resealed, err := rotated.Seal(got, "t.c:1")
+	require.NoError(t, err)
+	require.True(t, rotated.Current(resealed))
+
+	withoutOld, err := NewKeyring(newKeyBytes)
+	require.NoError(t, err)
+	_, err = withoutOld.Open(sealed, "t.c:1")
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyring_RejectsTampering(t *testing.T) {
+	k, err := NewKeyring(newKey(t))
+	require.NoError(t, err)
+	a, err := k.Seal("a", "t.c:1")
+	require.NoError(t, err)
+	b, err := k.Seal("b", "t.c:1")
+	require.NoError(t, err)
+
+	// Swap the wrapped data keys between two values.
+	pa, pb := strings.Split(a, ":"), strings.Split(b, ":")
+	pa[3] = pb[3]
+	_, err = k.Open(strings.Join(pa, ":"), "t.c:1")
+	require.Error(t, err)
+
+	_, err = k.Open("enc:v2:"+k.PrimaryID()+":garbage", "t.c:1")
+	require.Error(t, err)
+
+	// A value does not open where it was not sealed for, nor relabeled as the older form.
+	_, err = k.Open(a, "t.c:2")
+	require.Error(t, err)
+	_, err = k.Open(strings.Replace(a, "enc:v2:", "enc:v1:", 1), "t.c:1")
+	require.Error(t, err)
+}
+
+func TestKeyring_OpensV1Values(t *testing.T) {
+	k, err := NewKeyring(newKey(t))
+	require.NoError(t, err)
+	// Seal as releases before bindings did: only the key id is authenticated.
+	dek := newKey(t)
+	aad := []byte(k.PrimaryID())
+	wrapped, err := seal(k.primary.aead, dek, aad)
+	require.NoError(t, err)
+	aead, err := newAEAD(dek)
+	require.NoError(t, err)
+	ciphertext, err := seal(aead, []byte("old"), aad)
+	require.NoError(t, err)
+	v1 := "enc:v1:" + k.PrimaryID() + ":" + b64.EncodeToString(wrapped) + ":" + b64.EncodeToString(ciphertext)
+
+	require.True(t, IsSealed(v1))
+	require.False(t, k.Current(v1), "v1 values are re-keyed")
+	got, err := k.Open(v1, "any")
+	require.NoError(t, err)
+	require.Equal(t, "old", got)
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey("not base64!")
+	require.Error(t, err)
+	_, err = ParseKey("c2hvcnQ=")
+	require.ErrorContains(t, err, "must be 32 bytes")
+}
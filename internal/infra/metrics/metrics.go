@@ -0,0 +1,171 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package metrics exposes Prometheus metrics for HTTP traffic, sign-ins, token issuance and
+// the server's stores.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "sso"
+
+// collectTimeout bounds the database queries run while serving a scrape.
+const collectTimeout = 2 * time.Second
+
+// Metrics owns a Prometheus registry and the collectors recorded by the server.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requestDuration *prometheus.HistogramVec
+	logins          *prometheus.CounterVec
+	tokens          *prometheus.CounterVec
+	federation      *prometheus.CounterVec
+}
+
+// New creates a Metrics with its own registry, including the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_attempts_total",
+			Help:      "Password sign-in attempts by outcome.",
+		}, []string{"outcome"}),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tokens_issued_total",
+			Help:      "Token endpoint responses issued by grant type and client.",
+		}, []string{"grant_type", "client_id"}),
+		federation: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "federation_logins_total",
+			Help:      "Federated sign-ins by connector and outcome.",
+		}, []string{"connector_id", "outcome"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestDuration,
+		m.logins,
+		m.tokens,
+		m.federation,
+	)
+	return m
+}
+
+// Registry returns the registry the collectors are registered with.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the registry in the Prometheus exposition format. A collector that fails is
+// reported in the response without dropping the other metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// ObserveRequest records the latency of one HTTP request. route is the matched route pattern,
+// not the raw path, to keep label cardinality bounded.
+func (m *Metrics) ObserveRequest(method, route string, status int, d time.Duration) {
+	m.requestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// LoginAttempt counts a password sign-in with the given outcome ("success" or a failure reason).
+func (m *Metrics) LoginAttempt(outcome string) {
+	m.logins.WithLabelValues(outcome).Inc()
+}
+
+// TokenIssued counts a successful token endpoint response.
+func (m *Metrics) TokenIssued(grantType, clientID string) {
+	m.tokens.WithLabelValues(grantType, clientID).Inc()
+}
+
+// FederationLogin counts a federated sign-in through connectorID with the given outcome
+// ("success" or a failure reason).
+func (m *Metrics) FederationLogin(connectorID, outcome string) {
+	m.federation.WithLabelValues(connectorID, outcome).Inc()
+}
+
+// SessionCounter counts browser sessions that have not expired.
+type SessionCounter interface {
+	CountActive(ctx context.Context, now time.Time) (int, error)
+}
+
+// StoreSizer reports the number of entries in each in-memory OAuth2 store.
+type StoreSizer interface {
+	StoreSizes() map[string]int
+}
+
+// WatchSessions exports sso_active_sessions, counted at scrape time.
+func (m *Metrics) WatchSessions(c SessionCounter) {
+	m.registry.MustRegister(&sessionCollector{
+		counter: c,
+		desc:    prometheus.NewDesc(namespace+"_active_sessions", "Browser sessions that have not expired.", nil, nil),
+	})
+}
+
+// WatchTokenStore exports sso_oauth2_store_entries with one series per store.
+func (m *Metrics) WatchTokenStore(s StoreSizer) {
+	m.registry.MustRegister(&storeCollector{
+		sizer: s,
+		desc:  prometheus.NewDesc(namespace+"_oauth2_store_entries", "Entries held in each in-memory OAuth2 store.", []string{"store"}, nil),
+	})
+}
+
+// WatchDB exports the connection pool statistics of db as go_sql_* metrics.
+func (m *Metrics) WatchDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+type sessionCollector struct {
+	counter SessionCounter
+	desc    *prometheus.Desc
+}
+
+func (c *sessionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *sessionCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+	n, err := c.counter.CountActive(ctx, time.Now())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n))
+}
+
+type storeCollector struct {
+	sizer StoreSizer
+	desc  *prometheus.Desc
+}
+
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	for store, n := range c.sizer.StoreSizes() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), store)
+	}
+}
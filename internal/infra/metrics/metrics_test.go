@@ -0,0 +1,74 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSessions struct {
+	n   int
+	err error
+}
+
+func (f fakeSessions) CountActive(context.Context, time.Time) (int, error) {
+	return f.n, f.err
+}
+
+type fakeStore map[string]int
+
+func (f fakeStore) StoreSizes() map[string]int {
+	return f
+}
+
+func TestCounters(t *testing.T) {
+	m := New()
+	m.LoginAttempt("success")
+	m.LoginAttempt("success")
+	m.LoginAttempt("invalid_password")
+	m.TokenIssued("authorization_code", "app")
+	m.FederationLogin("google", "upstream_exchange_failed")
+	m.ObserveRequest("GET", "/login", 200, 10*time.Millisecond)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.logins.WithLabelValues("success")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.logins.WithLabelValues("invalid_password")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.tokens.WithLabelValues("authorization_code", "app")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.federation.WithLabelValues("google", "upstream_exchange_failed")))
+	require.Equal(t, 1, testutil.CollectAndCount(m.requestDuration))
+}
+
+func TestGauges(t *testing.T) {
+	m := New()
+	m.WatchSessions(fakeSessions{n: 3})
+	m.WatchTokenStore(fakeStore{"access_tokens": 2, "refresh_tokens": 1})
+
+	err := testutil.GatherAndCompare(m.Registry(), strings.NewReader(`
+# HELP sso_active_sessions Browser sessions that have not expired.
+# TYPE sso_active_sessions gauge
+sso_active_sessions 3
+# HELP sso_oauth2_store_entries Entries held in each in-memory OAuth2 store.
+# TYPE sso_oauth2_store_entries gauge
+sso_oauth2_store_entries{store="access_tokens"} 2
+sso_oauth2_store_entries{store="refresh_tokens"} 1
+`), "sso_active_sessions", "sso_oauth2_store_entries")
+	require.NoError(t, err)
+}
+
+func TestGauges_CountFailure(t *testing.T) {
+	m := New()
+	m.WatchSessions(fakeSessions{err: errors.New("db down")})
+	m.LoginAttempt("success")
+
+	_, err := m.Registry().Gather()
+	require.ErrorContains(t, err, "db down")
+	// Other collectors are unaffected by the failing one.
+	require.Equal(t, 1.0, testutil.ToFloat64(m.logins.WithLabelValues("success")))
+}
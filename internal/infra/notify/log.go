@@ -0,0 +1,39 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package notify delivers user-facing notifications such as email verification links.
+package notify
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// LogNotifier writes notifications to the structured log instead of sending email.
+// Suitable for development until a mail transport is configured.
+type LogNotifier struct {
+	logger  log.Logger
+	baseURL string
+}
+
+// NewLogNotifier creates a LogNotifier that builds links relative to baseURL (the issuer).
+func NewLogNotifier(logger log.Logger, baseURL string) *LogNotifier {
+	return &LogNotifier{logger: logger, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// SendEmailVerification logs the verification link for the new email address.
+func (n *LogNotifier) SendEmailVerification(_ context.Context, u *domain.User, email, token string) error {
+	link := n.baseURL + "/account/verify-email?token=" + url.QueryEscape(token)
+	n.logger.Info("email verification",
+		zap.String("user_id", u.ID),
+		zap.String("email", email),
+		zap.String("link", link),
+	)
+	return nil
+}
@@ -7,21 +7,41 @@ package oidc_client
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/coreos/go-oidc/v3/oidc"
-	"github.com/qinzj/superpowers-demo/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
 )
 
+var tracer = otel.Tracer("github.com/qinzj/superpowers-demo/internal/infra/oidc_client")
+
+// httpClient traces every request made to upstream IdPs: discovery, JWKS, token and userinfo.
+var httpClient = &http.Client{Transport: tracing.Transport(nil)}
+
+// withHTTPClient makes go-oidc and oauth2 use httpClient for requests made with ctx.
+func withHTTPClient(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, httpClient)
+}
+
 // Client exchanges auth codes and fetches userinfo from upstream IdPs.
 type Client struct {
-	provider   *oidc.Provider
-	oauth2Conf oauth2.Config
+	provider    *oidc.Provider
+	oauth2Conf  oauth2.Config
+	groupsClaim string
 }
 
 // NewClient creates an OIDC client for the given connector configuration.
-func NewClient(ctx context.Context, conn *domain.IdPConnector, redirectURL string) (*Client, error) {
-	provider, err := oidc.NewProvider(ctx, conn.Issuer)
+// The provider's discovery document is fetched here, in an "oidc_client.Discover" span.
+func NewClient(ctx context.Context, conn *domain.IdPConnector, redirectURL string) (_ *Client, err error) {
+	ctx, span := tracer.Start(ctx, "oidc_client.Discover", trace.WithAttributes(attribute.String("oidc.issuer", conn.Issuer)))
+	defer func() { tracing.End(span, err) }()
+	provider, err := oidc.NewProvider(withHTTPClient(ctx), conn.Issuer)
 	if err != nil {
 		return nil, fmt.Errorf("create oidc provider: %w", err)
 	}
@@ -32,7 +52,7 @@ func NewClient(ctx context.Context, conn *domain.IdPConnector, redirectURL strin
 		Endpoint:     provider.Endpoint(),
 		Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
 	}
-	return &Client{provider: provider, oauth2Conf: conf}, nil
+	return &Client{provider: provider, oauth2Conf: conf, groupsClaim: conn.GroupsClaim}, nil
 }
 
 // AuthCodeURL returns the URL to redirect the user to for authorization.
@@ -41,8 +61,10 @@ func (c *Client) AuthCodeURL(state string) string {
 }
 
 // Exchange exchanges the authorization code for tokens.
-func (c *Client) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
-	token, err := c.oauth2Conf.Exchange(ctx, code)
+func (c *Client) Exchange(ctx context.Context, code string) (_ *oauth2.Token, err error) {
+	ctx, span := tracer.Start(ctx, "oidc_client.Exchange")
+	defer func() { tracing.End(span, err) }()
+	token, err := c.oauth2Conf.Exchange(withHTTPClient(ctx), code)
 	if err != nil {
 		return nil, fmt.Errorf("exchange code: %w", err)
 	}
@@ -50,7 +72,10 @@ func (c *Client) Exchange(ctx context.Context, code string) (*oauth2.Token, erro
 }
 
 // UserInfo fetches user claims from id_token or the IdP's UserInfo endpoint.
-func (c *Client) UserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo, error) {
+func (c *Client) UserInfo(ctx context.Context, token *oauth2.Token) (_ *UserInfo, err error) {
+	ctx, span := tracer.Start(ctx, "oidc_client.UserInfo")
+	defer func() { tracing.End(span, err) }()
+	ctx = withHTTPClient(ctx)
 	if rawIDToken, ok := token.Extra("id_token").(string); ok && rawIDToken != "" {
 		verifier := c.provider.Verifier(&oidc.Config{ClientID: c.oauth2Conf.ClientID})
 		idToken, err := verifier.Verify(ctx, rawIDToken)
@@ -66,11 +91,15 @@ func (c *Client) UserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo,
 		if err := idToken.Claims(&claims); err != nil {
 			return nil, fmt.Errorf("parse claims: %w", err)
 		}
+		var raw map[string]interface{}
+		_ = idToken.Claims(&raw)
 		return &UserInfo{
 			Sub:               claims.Sub,
 			Email:             claims.Email,
+			EmailVerified:     emailVerified(raw),
 			PreferredUsername: claims.PreferredUsername,
 			Name:              claims.Name,
+			Groups:            c.groups(raw),
 		}, nil
 	}
 	// Fallback to UserInfo endpoint when id_token is not present.
@@ -83,18 +112,59 @@ func (c *Client) UserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo,
 		Name              string `json:"name"`
 	}
 	_ = oi.Claims(&extra)
+	var raw map[string]interface{}
+	_ = oi.Claims(&raw)
 	return &UserInfo{
 		Sub:               oi.Subject,
 		Email:             oi.Email,
+		EmailVerified:     emailVerified(raw),
 		PreferredUsername: extra.PreferredUsername,
 		Name:              extra.Name,
+		Groups:            c.groups(raw),
 	}, nil
 }
 
+// groups reads the configured groups claim, which may be a string array or a single string.
+func (c *Client) groups(claims map[string]interface{}) []string {
+	if c.groupsClaim == "" {
+		return nil
+	}
+	switch v := claims[c.groupsClaim].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// emailVerified reads the email_verified claim, which some IdPs send as the string "true".
+func emailVerified(claims map[string]interface{}) bool {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
 // UserInfo holds claims from upstream IdP userinfo.
 type UserInfo struct {
-	Sub               string
-	Email             string
+	Sub   string
+	Email string
+	// EmailVerified is the email_verified claim; false when the IdP does not send it.
+	EmailVerified     bool
 	PreferredUsername string
 	Name              string
+	// Groups holds the connector's groups claim; nil when no groups claim is configured.
+	Groups []string
 }
@@ -0,0 +1,251 @@
+package password
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Limits on the cost parameters of imported hashes, so that a crafted import cannot make every
+// sign-in attempt for a user arbitrarily expensive.
+const (
+	maxPBKDF2Iterations = 10_000_000
+	maxScryptMemory     = 256 << 20 // bytes; 128 * r * N
+	maxScryptP          = 16
+	maxBcryptCost       = 16
+)
+
+var shaFuncs = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Import converts a password hash exported by another system to the encoding stored here, and
+// checks that Verify can use it. It accepts:
+//
+//   - argon2id and bcrypt hashes, unchanged once their parameters are checked
+//   - PBKDF2: the encoding above, passlib's $pbkdf2-sha256$<iterations>$<salt>$<key> (and
+//     $pbkdf2$ for SHA-1) and Django's pbkdf2_sha256$<iterations>$<salt>$<key>
+//   - scrypt: $scrypt$ln=..,r=..,p=..$<salt>$<key> as written by passlib and PHC libraries
+//   - salted SHA: the encoding above and LDAP's {SSHA}, {SSHA256} and {SSHA512}
+//
+// Raw salted SHA digests with their salt in a separate field are encoded with SaltedSHA.
+func Import(encoded string) (string, error) {
+	encoded = strings.TrimSpace(encoded)
+	var out string
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"), isBcrypt(encoded):
+		out = encoded
+	case strings.HasPrefix(encoded, "pbkdf2_"):
+		// Django: the salt is used as text and the key is padded base64.
+		parts := strings.Split(encoded, "$")
+		if len(parts) != 4 {
+			return "", fmt.Errorf("password: malformed Django pbkdf2 hash")
+		}
+		key, err := decodeB64(parts[3])
+		if err != nil {
+			return "", fmt.Errorf("password: decode pbkdf2 key: %w", err)
+		}
+		out = encodePBKDF2(strings.TrimPrefix(parts[0], "pbkdf2_"), parts[1], []byte(parts[2]), key)
+	case strings.HasPrefix(encoded, "$pbkdf2"):
+		parts := strings.Split(encoded, "$")
+		if len(parts) != 5 {
+			return "", fmt.Errorf("password: malformed pbkdf2 hash")
+		}
+		alg := strings.TrimPrefix(strings.TrimPrefix(parts[1], "pbkdf2"), "-")
+		if alg == "" {
+			alg = "sha1"
+		}
+		salt, err := decodeB64(parts[3])
+		if err != nil {
+			return "", fmt.Errorf("password: decode pbkdf2 salt: %w", err)
+		}
+		key, err := decodeB64(parts[4])
+		if err != nil {
+			return "", fmt.Errorf("password: decode pbkdf2 key: %w", err)
+		}
+		out = encodePBKDF2(alg, strings.TrimPrefix(parts[2], "i="), salt, key)
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		parts := strings.Split(encoded, "$")
+		if len(parts) != 5 {
+			return "", fmt.Errorf("password: malformed scrypt hash")
+		}
+		salt, err := decodeB64(parts[3])
+		if err != nil {
+			return "", fmt.Errorf("password: decode scrypt salt: %w", err)
+		}
+		key, err := decodeB64(parts[4])
+		if err != nil {
+			return "", fmt.Errorf("password: decode scrypt key: %w", err)
+		}
+		out = fmt.Sprintf("$scrypt$%s$%s$%s", parts[2], b64(salt), b64(key))
+	case strings.HasPrefix(encoded, "$salted-"):
+		out = encoded
+	case strings.HasPrefix(encoded, "{SSHA"):
+		scheme, value, _ := strings.Cut(encoded[1:], "}")
+		name, ok := map[string]string{"SSHA": "sha1", "SSHA256": "sha256", "SSHA512": "sha512"}[scheme]
+		if !ok {
+			return "", fmt.Errorf("%w: {%s}", ErrUnknownAlgorithm, scheme)
+		}
+		raw, err := base64.StdEncoding.DecodeString(value)
+		size := shaFuncs[name]().Size()
+		if err != nil || len(raw) <= size {
+			return "", fmt.Errorf("password: malformed {%s} hash", scheme)
+		}
+		if out, err = SaltedSHA(name, raw[:size], raw[size:], false); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: unrecognized hash format", ErrUnknownAlgorithm)
+	}
+	switch {
+	case strings.HasPrefix(out, "$argon2id$"):
+		if _, _, _, err := decodeArgon2id(out); err != nil {
+			return "", err
+		}
+	case isBcrypt(out):
+		cost, err := bcrypt.Cost([]byte(out))
+		if err != nil {
+			return "", fmt.Errorf("password: malformed bcrypt hash: %w", err)
+		}
+		if cost > maxBcryptCost {
+			return "", fmt.Errorf("password: bcrypt cost must be at most %d, got %d", maxBcryptCost, cost)
+		}
+	default:
+		if _, err := parseImported(out); err != nil {
+			return "", err
+		}
+	}
+	return out, nil
+}
+
+// SaltedSHA encodes a raw SHA digest of the password and salt: sha(salt + password) when
+// saltFirst is set, sha(password + salt) otherwise. algorithm is sha1, sha256 or sha512.
+func SaltedSHA(algorithm string, digest, salt []byte, saltFirst bool) (string, error) {
+	algorithm = strings.ToLower(strings.ReplaceAll(algorithm, "-", ""))
+	h, ok := shaFuncs[algorithm]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownAlgorithm, algorithm)
+	}
+	if len(digest) != h().Size() {
+		return "", fmt.Errorf("password: %s digest must be %d bytes, got %d", algorithm, h().Size(), len(digest))
+	}
+	pos := "suffix"
+	if saltFirst {
+		pos = "prefix"
+	}
+	return fmt.Sprintf("$salted-%s$pos=%s$%s$%s", algorithm, pos, b64(salt), b64(digest)), nil
+}
+
+// DecodeDigest decodes a digest given as hex or as base64.
+func DecodeDigest(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := hex.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return decodeB64(s)
+}
+
+func encodePBKDF2(alg, iterations string, salt, key []byte) string {
+	return fmt.Sprintf("$pbkdf2-%s$i=%s$%s$%s", alg, iterations, b64(salt), b64(key))
+}
+
+// importedHash is a parsed hash in one of the imported encodings.
+type importedHash struct {
+	derive func(password string, keyLen int) ([]byte, error)
+	want   []byte
+}
+
+// verifyImported checks password against a hash in one of the imported encodings. A non-nil
+// error means the hash is not in one of them or is malformed.
+func verifyImported(password, encoded string) (bool, error) {
+	h, err := parseImported(encoded)
+	if err != nil {
+		return false, err
+	}
+	got, err := h.derive(password, len(h.want))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(got, h.want) == 1, nil
+}
+
+func parseImported(encoded string) (*importedHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[0] != "" {
+		return nil, fmt.Errorf("%w: unrecognized hash format", ErrUnknownAlgorithm)
+	}
+	alg, params := parts[1], parts[2]
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("password: decode %s salt: %w", alg, err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(want) == 0 {
+		return nil, fmt.Errorf("password: malformed %s hash", alg)
+	}
+
+	h := &importedHash{want: want}
+	switch {
+	case strings.HasPrefix(alg, "pbkdf2-"):
+		fn, ok := shaFuncs[strings.TrimPrefix(alg, "pbkdf2-")]
+		iter, err := strconv.Atoi(strings.TrimPrefix(params, "i="))
+		if !ok || err != nil || iter < 1 || iter > maxPBKDF2Iterations {
+			return nil, fmt.Errorf("password: unsupported pbkdf2 parameters %s %s", alg, params)
+		}
+		h.derive = func(password string, keyLen int) ([]byte, error) {
+			return pbkdf2.Key(fn, password, salt, iter, keyLen)
+		}
+	case alg == "scrypt":
+		var logN, r, p int
+		if _, err := fmt.Sscanf(params, "ln=%d,r=%d,p=%d", &logN, &r, &p); err != nil ||
+			logN < 1 || logN > 30 || r < 1 || r > maxScryptMemory || p < 1 || p > maxScryptP || 128*r<<logN > maxScryptMemory {
+			return nil, fmt.Errorf("password: unsupported scrypt parameters %s", params)
+		}
+		h.derive = func(password string, keyLen int) ([]byte, error) {
+			return scrypt.Key([]byte(password), salt, 1<<logN, r, p, keyLen)
+		}
+	case strings.HasPrefix(alg, "salted-"):
+		fn, ok := shaFuncs[strings.TrimPrefix(alg, "salted-")]
+		if !ok || (params != "pos=prefix" && params != "pos=suffix") || len(want) != fn().Size() {
+			return nil, fmt.Errorf("password: unsupported salted sha parameters %s %s", alg, params)
+		}
+		h.derive = func(password string, _ int) ([]byte, error) {
+			d := fn()
+			if params == "pos=prefix" {
+				d.Write(salt)
+				d.Write([]byte(password))
+			} else {
+				d.Write([]byte(password))
+				d.Write(salt)
+			}
+			return d.Sum(nil), nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, alg)
+	}
+	return h, nil
+}
+
+func b64(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+// decodeB64 decodes standard base64 with or without padding, and passlib's variant that uses
+// "." instead of "+".
+func decodeB64(s string) ([]byte, error) {
+	s = strings.ReplaceAll(strings.TrimRight(s, "="), ".", "+")
+	return base64.RawStdEncoding.DecodeString(s)
+}
@@ -0,0 +1,87 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport_VerifiesAndRequestsRehash(t *testing.T) {
+	// Hashes of "secret" produced by Python's hashlib in the encodings of the exporting systems.
+	for name, encoded := range map[string]string{
+		"django_pbkdf2_sha256": "pbkdf2_sha256$1000$NaCl4django$QMoXf5FsTxfErS6+0q9pa7q5ZU5EvYh3yWcLaJ89l7U=",
+		"passlib_pbkdf2_sha512": "$pbkdf2-sha512$1000$AAECAwQFBgcICQoLDA0ODw$8ltlLe5cI6KNrWOmMFkTi2iE7fEXJKJJR9lhjd/xSn68." +
+			"xOqzv2h20C4IDv/enqlx8f6AaRHE.6XnvHKXKhAjA",
+		"passlib_pbkdf2_sha1": "$pbkdf2$1000$AAECAwQFBgcICQoLDA0ODw$sFSyXPFcXgkxACFLfL2dSbbhY6k",
+		"scrypt":              "$scrypt$ln=10,r=8,p=1$AAECAwQFBgcICQoLDA0ODw==$Mdc/jTCPy+uU8aSLbM+FGBQC/AXSzfWCsAUkF/VYCgk=",
+		"ldap_ssha":           "{SSHA}D4kSuvPIF4rZzl+dW9Km/1788fBwZXBwZXI=",
+		"ldap_ssha512": "{SSHA512}yreFFnGZtsvzvla8i9E2vdgYkV/W/fffWI58oF/Bz6gCBig8LFcqTU6mYV4HpwNgAq53rou6YGObsfQ+" +
+			"inK2+XBlcHBlcg==",
+	} {
+		t.Run(name, func(t *testing.T) {
+			stored, err := Import(encoded)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(stored, "$"))
+
+			ok, needsRehash := Verify("secret", stored)
+			require.True(t, ok)
+			require.True(t, needsRehash)
+
+			ok, needsRehash = Verify("wrong", stored)
+			require.False(t, ok)
+			require.False(t, needsRehash)
+		})
+	}
+}
+
+func TestSaltedSHA(t *testing.T) {
+	// sha256("pepper" + "secret"), with the salt in a separate column.
+	digest, err := DecodeDigest("744a9101f7182a6ae0d978121ff74e33cac8d2832579c0637c1c37e9bbb6c065")
+	require.NoError(t, err)
+
+	stored, err := SaltedSHA("SHA-256", digest, []byte("pepper"), true)
+	require.NoError(t, err)
+	ok, _ := Verify("secret", stored)
+	require.True(t, ok)
+
+	suffix, err := SaltedSHA("sha256", digest, []byte("pepper"), false)
+	require.NoError(t, err)
+	ok, _ = Verify("secret", suffix)
+	require.False(t, ok, "salt position matters")
+
+	_, err = SaltedSHA("sha256", digest[:20], nil, true)
+	require.Error(t, err)
+	_, err = SaltedSHA("md5", make([]byte, 16), nil, true)
+	require.ErrorIs(t, err, ErrUnknownAlgorithm)
+}
+
+func TestImport_Rejects(t *testing.T) {
+	for name, encoded := range map[string]string{
+		"unknown":            "$1$abc$def",
+		"plaintext":          "secret",
+		"malformed_pbkdf2":   "$pbkdf2-sha256$1000$salt",
+		"unknown_digest":     "$pbkdf2-md5$i=1000$AAAA$AAAA",
+		"pbkdf2_iterations":  "$pbkdf2-sha256$i=100000000$AAAA$AAAA",
+		"scrypt_memory":      "$scrypt$ln=20,r=8,p=1$AAAA$AAAA",
+		"scrypt_parallelism": "$scrypt$ln=10,r=8,p=64$AAAA$AAAA",
+		"short_ssha":         "{SSHA}AAAA",
+		"argon2id_no_rounds": "$argon2id$v=19$m=65536,t=0,p=1$c29tZXNhbHRzb21lc2FsdA$" + strings.Repeat("A", 43),
+		"argon2id_memory":    "$argon2id$v=19$m=4294967295,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$" + strings.Repeat("A", 43),
+		"argon2id_no_lanes":  "$argon2id$v=19$m=65536,t=1,p=0$c29tZXNhbHRzb21lc2FsdA$" + strings.Repeat("A", 43),
+		"argon2id_empty_key": "$argon2id$v=19$m=65536,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$",
+		"bcrypt_malformed":   "$2a$10$short",
+		"bcrypt_cost":        "$2a$31$" + strings.Repeat("A", 53),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Import(encoded)
+			require.Error(t, err)
+		})
+	}
+
+	// Verify refuses the same hashes instead of running them.
+	ok, _ := Verify("secret", "$scrypt$ln=20,r=8,p=1$AAAA$AAAA")
+	require.False(t, ok)
+	ok, _ = Verify("secret", "$argon2id$v=19$m=65536,t=0,p=1$c29tZXNhbHRzb21lc2FsdA$"+strings.Repeat("A", 43))
+	require.False(t, ok, "no panic in argon2.IDKey")
+}
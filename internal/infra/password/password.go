@@ -1,22 +1,267 @@
+// Package password hashes and verifies secrets using PHC-style encoded strings.
+//
+// Supported algorithms:
+//   - argon2id: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+//   - bcrypt:   $2a$<cost>$... (also accepts $2b$ and $2y$)
+//
+// Hashes imported from other identity providers (see Import) are verified too, but never
+// produced:
+//   - pbkdf2:   $pbkdf2-<sha1|sha256|sha512>$i=<iterations>$<salt>$<key>
+//   - scrypt:   $scrypt$ln=<log2 N>,r=<block size>,p=<parallelism>$<salt>$<key>
+//   - salted SHA: $salted-<sha1|sha256|sha512>$pos=<prefix|suffix>$<salt>$<digest>, where pos
+//     is where the salt goes relative to the password before hashing
+//
+// with binary parts in unpadded standard base64.
+//
+// Verify reports whether a stored hash was produced with outdated parameters or a
+// different algorithm, so callers can transparently rehash after a successful check; imported
+// hashes are always reported, so they are replaced at a user's first successful sign-in.
 package password
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const defaultCost = bcrypt.DefaultCost
+// Supported algorithm identifiers.
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// ErrUnknownAlgorithm is returned when a hash or config uses an unsupported algorithm.
+var ErrUnknownAlgorithm = errors.New("password: unknown hash algorithm")
+
+// Bounds on argon2id parameters, for configured and stored hashes alike: argon2.IDKey panics
+// below the minimums, and a stored hash above the maximums would make every sign-in attempt for
+// the user exhaust memory or CPU.
+const (
+	maxArgon2Memory     = 1 << 20 // KiB
+	maxArgon2Iterations = 64
+	minArgon2SaltLength = 8
+	minArgon2KeyLength  = 16
+	maxArgon2Length     = 1024 // bytes, for salt and key
+)
+
+// Config holds hasher configuration matching the "password" section of settings.yaml.
+type Config struct {
+	Algorithm  string       `mapstructure:"algorithm"`   // argon2id or bcrypt
+	Argon2     Argon2Params `mapstructure:"argon2"`      // used when algorithm is argon2id
+	BcryptCost int          `mapstructure:"bcrypt_cost"` // used when algorithm is bcrypt
+}
+
+// Argon2Params holds argon2id tuning parameters.
+type Argon2Params struct {
+	Memory      uint32 `mapstructure:"memory"`      // KiB
+	Iterations  uint32 `mapstructure:"iterations"`  // passes over memory
+	Parallelism uint8  `mapstructure:"parallelism"` // lanes
+	SaltLength  uint32 `mapstructure:"salt_length"` // bytes
+	KeyLength   uint32 `mapstructure:"key_length"`  // bytes
+}
+
+// DefaultConfig returns argon2id with the OWASP-recommended minimum parameters.
+func DefaultConfig() *Config {
+	return &Config{
+		Algorithm: AlgorithmArgon2id,
+		Argon2: Argon2Params{
+			Memory:      19 * 1024,
+			Iterations:  2,
+			Parallelism: 1,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+		BcryptCost: bcrypt.DefaultCost,
+	}
+}
+
+// Hasher hashes secrets with the configured algorithm and verifies any supported format.
+type Hasher struct {
+	cfg Config
+}
+
+var defaultHasher = &Hasher{cfg: *DefaultConfig()}
+
+// Default returns the package-level hasher built from DefaultConfig.
+func Default() *Hasher {
+	return defaultHasher
+}
+
+// NewHasher creates a Hasher from cfg. Zero-valued fields fall back to DefaultConfig.
+func NewHasher(cfg *Config) (*Hasher, error) {
+	c := *DefaultConfig()
+	if cfg != nil {
+		if cfg.Algorithm != "" {
+			c.Algorithm = strings.ToLower(strings.TrimSpace(cfg.Algorithm))
+		}
+		if cfg.Argon2.Memory != 0 {
+			c.Argon2.Memory = cfg.Argon2.Memory
+		}
+		if cfg.Argon2.Iterations != 0 {
+			c.Argon2.Iterations = cfg.Argon2.Iterations
+		}
+		if cfg.Argon2.Parallelism != 0 {
+			c.Argon2.Parallelism = cfg.Argon2.Parallelism
+		}
+		if cfg.Argon2.SaltLength != 0 {
+			c.Argon2.SaltLength = cfg.Argon2.SaltLength
+		}
+		if cfg.Argon2.KeyLength != 0 {
+			c.Argon2.KeyLength = cfg.Argon2.KeyLength
+		}
+		if cfg.BcryptCost != 0 {
+			c.BcryptCost = cfg.BcryptCost
+		}
+	}
+	switch c.Algorithm {
+	case AlgorithmArgon2id:
+		if err := c.Argon2.validate(); err != nil {
+			return nil, err
+		}
+	case AlgorithmBcrypt:
+		if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("password: bcrypt_cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, c.Algorithm)
+	}
+	return &Hasher{cfg: c}, nil
+}
+
+// Hash returns an encoded hash of password using the configured algorithm.
+func (h *Hasher) Hash(password string) (string, error) {
+	switch h.cfg.Algorithm {
+	case AlgorithmBcrypt:
+		b, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case AlgorithmArgon2id:
+		return hashArgon2id(password, h.cfg.Argon2)
+	default:
+		return "", ErrUnknownAlgorithm
+	}
+}
+
+// Verify reports whether password matches the encoded hash. needsRehash is true when the
+// password matches but the hash uses a different algorithm or weaker parameters than configured.
+func (h *Hasher) Verify(password, encoded string) (ok bool, needsRehash bool) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, false
+		}
+		got := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return false, false
+		}
+		want := h.cfg.Argon2
+		stale := h.cfg.Algorithm != AlgorithmArgon2id ||
+			params.Memory != want.Memory ||
+			params.Iterations != want.Iterations ||
+			params.Parallelism != want.Parallelism ||
+			uint32(len(salt)) != want.SaltLength ||
+			uint32(len(key)) != want.KeyLength
+		return true, stale
+	case isBcrypt(encoded):
+		if bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) != nil {
+			return false, false
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		stale := h.cfg.Algorithm != AlgorithmBcrypt || err != nil || cost != h.cfg.BcryptCost
+		return true, stale
+	default:
+		// Imported hashes are always replaced by the configured algorithm.
+		ok, err := verifyImported(password, encoded)
+		ok = ok && err == nil
+		return ok, ok
+	}
+}
 
-// Hash returns a bcrypt hash of the given password.
+// Hash returns an encoded hash of password using the default hasher.
 func Hash(password string) (string, error) {
-	b, err := bcrypt.GenerateFromPassword([]byte(password), defaultCost)
+	return defaultHasher.Hash(password)
+}
+
+// Verify reports whether password matches hash using the default hasher,
+// and whether the hash should be upgraded.
+func Verify(password, hash string) (ok bool, needsRehash bool) {
+	return defaultHasher.Verify(password, hash)
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func hashArgon2id(password string, p Argon2Params) (string, error) {
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("password: generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, fmt.Errorf("password: malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, nil, nil, fmt.Errorf("password: parse argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("password: unsupported argon2id version %d", version)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("password: parse argon2id params: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("password: decode argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
 	if err != nil {
-		return "", err
+		return p, nil, nil, fmt.Errorf("password: decode argon2id key: %w", err)
 	}
-	return string(b), nil
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	if err := p.validate(); err != nil {
+		return p, nil, nil, err
+	}
+	return p, salt, key, nil
 }
 
-// Verify returns true if the password matches the bcrypt hash.
-func Verify(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// validate checks p against the argon2id bounds.
+func (p Argon2Params) validate() error {
+	switch {
+	case p.Memory < 8*uint32(p.Parallelism) || p.Memory > maxArgon2Memory:
+		return fmt.Errorf("password: argon2id memory must be between 8*parallelism and %d KiB, got %d", maxArgon2Memory, p.Memory)
+	case p.Iterations < 1 || p.Iterations > maxArgon2Iterations:
+		return fmt.Errorf("password: argon2id iterations must be between 1 and %d, got %d", maxArgon2Iterations, p.Iterations)
+	case p.Parallelism < 1:
+		return fmt.Errorf("password: argon2id parallelism must be between 1 and 255, got %d", p.Parallelism)
+	case p.SaltLength < minArgon2SaltLength || p.SaltLength > maxArgon2Length:
+		return fmt.Errorf("password: argon2id salt must be %d to %d bytes, got %d", minArgon2SaltLength, maxArgon2Length, p.SaltLength)
+	case p.KeyLength < minArgon2KeyLength || p.KeyLength > maxArgon2Length:
+		return fmt.Errorf("password: argon2id key must be %d to %d bytes, got %d", minArgon2KeyLength, maxArgon2Length, p.KeyLength)
+	}
+	return nil
 }
@@ -1,14 +1,79 @@
 package password
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHashAndVerify(t *testing.T) {
 	hash, err := Hash("secret")
 	require.NoError(t, err)
-	require.True(t, Verify("secret", hash))
-	require.False(t, Verify("wrong", hash))
+	require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$"))
+
+	ok, needsRehash := Verify("secret", hash)
+	require.True(t, ok)
+	require.False(t, needsRehash)
+
+	ok, _ = Verify("wrong", hash)
+	require.False(t, ok)
+}
+
+func TestVerify_ReportsOutdatedHash(t *testing.T) {
+	t.Run("bcrypt_hash_under_argon2id_config", func(t *testing.T) {
+		b, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+		require.NoError(t, err)
+		ok, needsRehash := Verify("secret", string(b))
+		require.True(t, ok)
+		require.True(t, needsRehash)
+	})
+
+	t.Run("argon2id_hash_with_weaker_params", func(t *testing.T) {
+		weak, err := NewHasher(&Config{Argon2: Argon2Params{Memory: 8 * 1024, Iterations: 1}})
+		require.NoError(t, err)
+		hash, err := weak.Hash("secret")
+		require.NoError(t, err)
+
+		ok, needsRehash := Verify("secret", hash)
+		require.True(t, ok)
+		require.True(t, needsRehash)
+	})
+
+	t.Run("bcrypt_cost_change", func(t *testing.T) {
+		h, err := NewHasher(&Config{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+		require.NoError(t, err)
+		hash, err := h.Hash("secret")
+		require.NoError(t, err)
+		ok, needsRehash := h.Verify("secret", hash)
+		require.True(t, ok)
+		require.False(t, needsRehash)
+
+		stronger, err := NewHasher(&Config{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1})
+		require.NoError(t, err)
+		ok, needsRehash = stronger.Verify("secret", hash)
+		require.True(t, ok)
+		require.True(t, needsRehash)
+	})
+
+	t.Run("wrong_password_never_requests_rehash", func(t *testing.T) {
+		b, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+		require.NoError(t, err)
+		ok, needsRehash := Verify("wrong", string(b))
+		require.False(t, ok)
+		require.False(t, needsRehash)
+	})
+}
+
+func TestNewHasher_RejectsUnknownAlgorithm(t *testing.T) {
+	_, err := NewHasher(&Config{Algorithm: "md5"})
+	require.ErrorIs(t, err, ErrUnknownAlgorithm)
+}
+
+func TestNewHasher_RejectsArgon2ParamsOutOfBounds(t *testing.T) {
+	_, err := NewHasher(&Config{Argon2: Argon2Params{Memory: maxArgon2Memory + 1}})
+	require.ErrorContains(t, err, "argon2id memory")
+	_, err = NewHasher(&Config{Argon2: Argon2Params{KeyLength: 4}})
+	require.ErrorContains(t, err, "argon2id key")
 }
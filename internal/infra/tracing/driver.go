@@ -0,0 +1,110 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"entgo.io/ent/dialect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var dbTracer = otel.Tracer("github.com/qinzj/superpowers-demo/internal/infra/tracing")
+
+// Driver is an ent driver that runs every statement in a client span. Statements are recorded
+// without their arguments.
+type Driver struct {
+	dialect.Driver
+	system attribute.KeyValue
+}
+
+// WrapDriver returns drv with tracing added.
+func WrapDriver(drv dialect.Driver) *Driver {
+	return &Driver{Driver: drv, system: dbSystem(drv.Dialect())}
+}
+
+func dbSystem(d string) attribute.KeyValue {
+	switch d {
+	case dialect.MySQL:
+		return semconv.DBSystemMySQL
+	case dialect.Postgres:
+		return semconv.DBSystemPostgreSQL
+	case dialect.SQLite:
+		return semconv.DBSystemSqlite
+	default:
+		return semconv.DBSystemKey.String(d)
+	}
+}
+
+// Exec implements dialect.ExecQuerier.
+func (d *Driver) Exec(ctx context.Context, query string, args, v any) (err error) {
+	ctx, span := d.start(ctx, "db.Exec", query)
+	defer func() { End(span, err) }()
+	return d.Driver.Exec(ctx, query, args, v)
+}
+
+// Query implements dialect.ExecQuerier.
+func (d *Driver) Query(ctx context.Context, query string, args, v any) (err error) {
+	ctx, span := d.start(ctx, "db.Query", query)
+	defer func() { End(span, err) }()
+	return d.Driver.Query(ctx, query, args, v)
+}
+
+// Tx starts a transaction whose statements are traced.
+func (d *Driver) Tx(ctx context.Context) (dialect.Tx, error) {
+	return d.BeginTx(ctx, nil)
+}
+
+// BeginTx starts a transaction with the given options whose statements are traced.
+func (d *Driver) BeginTx(ctx context.Context, opts *sql.TxOptions) (dialect.Tx, error) {
+	var (
+		tx  dialect.Tx
+		err error
+	)
+	if b, ok := d.Driver.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (dialect.Tx, error)
+	}); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else if opts == nil {
+		tx, err = d.Driver.Tx(ctx)
+	} else {
+		return nil, fmt.Errorf("tracing: driver %T does not support transaction options", d.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, drv: d}, nil
+}
+
+func (d *Driver) start(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return dbTracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(d.system, semconv.DBStatementKey.String(query)),
+	)
+}
+
+// Tx is a transaction whose statements run in client spans.
+type Tx struct {
+	dialect.Tx
+	drv *Driver
+}
+
+// Exec implements dialect.ExecQuerier.
+func (t *Tx) Exec(ctx context.Context, query string, args, v any) (err error) {
+	ctx, span := t.drv.start(ctx, "db.Exec", query)
+	defer func() { End(span, err) }()
+	return t.Tx.Exec(ctx, query, args, v)
+}
+
+// Query implements dialect.ExecQuerier.
+func (t *Tx) Query(ctx context.Context, query string, args, v any) (err error) {
+	ctx, span := t.drv.start(ctx, "db.Query", query)
+	defer func() { End(span, err) }()
+	return t.Tx.Query(ctx, query, args, v)
+}
@@ -0,0 +1,162 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package tracing configures OpenTelemetry tracing and provides span helpers for the server's
+// handlers, services, database driver and outbound HTTP calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters accepted in Config.Exporter.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Config holds tracing settings matching the "tracing" section of settings.yaml.
+type Config struct {
+	Exporter    string  `mapstructure:"exporter"`     // none, stdout, file or otlp
+	File        string  `mapstructure:"file"`         // output path for the file exporter
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP collector host:port
+	Insecure    bool    `mapstructure:"insecure"`     // send OTLP over plain HTTP
+	ServiceName string  `mapstructure:"service_name"` // service.name resource attribute
+	SampleRatio float64 `mapstructure:"sample_ratio"` // fraction of new traces sampled; parents are honoured
+}
+
+const (
+	defaultServiceName = "sso"
+	defaultFile        = "./logs/traces.json"
+	defaultEndpoint    = "localhost:4318"
+)
+
+func (c Config) withDefaults() Config {
+	if c.Exporter == "" {
+		c.Exporter = ExporterNone
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = defaultServiceName
+	}
+	if c.File == "" {
+		c.File = defaultFile
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint
+	}
+	if c.SampleRatio <= 0 || c.SampleRatio > 1 {
+		c.SampleRatio = 1
+	}
+	return c
+}
+
+// Validate reports an unknown exporter.
+func (c Config) Validate() error {
+	switch c.withDefaults().Exporter {
+	case ExporterNone, ExporterStdout, ExporterFile, ExporterOTLP:
+		return nil
+	default:
+		return fmt.Errorf("tracing.exporter must be none, stdout, file or otlp, got %q", c.Exporter)
+	}
+}
+
+// Setup installs the global tracer provider and the W3C trace context and baggage propagators.
+// With the none exporter only the propagators are installed, so incoming trace IDs still reach
+// the logs. The returned function flushes and closes the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	cfg = cfg.withDefaults()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		var f *os.File
+		if err := os.MkdirAll(filepath.Dir(cfg.File), 0o750); err != nil {
+			return nil, fmt.Errorf("create trace dir: %w", err)
+		}
+		f, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file: %w", err)
+		}
+		closer = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("tracing.exporter must be none, stdout, file or otlp, got %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// End records err on span, if any, and ends it. Use it deferred with a named error result:
+//
+//	ctx, span := tracer.Start(ctx, "Service.Method")
+//	defer func() { tracing.End(span, err) }()
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport wraps base (http.DefaultTransport when nil) so that every outbound request runs in
+// a client span and carries the trace context.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
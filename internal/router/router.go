@@ -2,6 +2,9 @@
 package router
 
 import (
+	"crypto/rand"
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/qinzj/superpowers-demo/internal/server/http/handler"
@@ -10,34 +13,68 @@ import (
 // Config holds route registration configuration.
 type Config struct {
 	Health     *handler.HealthRouteConfig
+	Metrics    *handler.MetricsRouteConfig
 	OIDC      *handler.OIDCRouteConfig
 	Login     *handler.LoginRouteConfig
 	Register  *handler.RegisterRouteConfig
 	Account   *handler.AccountRouteConfig
 	Federation *handler.FederationRouteConfig
+	Admin      *handler.AdminRouteConfig
+	SCIM       *handler.SCIMRouteConfig
+	// Cookie is the attribute policy for every cookie the server sets.
+	Cookie handler.CookiePolicy
+	// CookieSource, when set, replaces Cookie with a policy that can be reloaded at runtime.
+	CookieSource *handler.CookiePolicySource
+	// CSRFKey signs CSRF tokens. When empty a random key is generated, which invalidates
+	// outstanding form tokens on restart and is not shared between replicas.
+	CSRFKey []byte
 }
 
-// Setup registers all routes on the given engine.
-func Setup(e *gin.Engine, cfg *Config) {
+// Setup registers all routes on the given engine. CSRF tokens are derived only on the HTML
+// routes; API, token and SCIM endpoints are left without the CSRF cookie.
+func Setup(e *gin.Engine, cfg *Config) error {
 	if cfg == nil {
-		return
+		return nil
+	}
+	csrfKey := cfg.CSRFKey
+	if len(csrfKey) == 0 {
+		csrfKey = make([]byte, 32)
+		if _, err := rand.Read(csrfKey); err != nil {
+			return fmt.Errorf("generate csrf key: %w", err)
+		}
+	}
+	if cfg.Metrics != nil {
+		handler.RegisterMetricsRoutes(e, cfg.Metrics)
 	}
+	if cfg.CookieSource != nil {
+		e.Use(handler.CookiePolicySourceMiddleware(cfg.CookieSource))
+	} else {
+		e.Use(handler.CookiePolicyMiddleware(cfg.Cookie))
+	}
+	csrf := handler.CSRFMiddleware(csrfKey)
 	if cfg.Health != nil {
 		handler.RegisterHealthRoutes(e, cfg.Health)
 	}
 	if cfg.OIDC != nil {
-		handler.RegisterOIDCRoutes(e, cfg.OIDC)
+		handler.RegisterOIDCRoutes(e, cfg.OIDC, csrf)
 	}
 	if cfg.Login != nil {
-		handler.RegisterLoginRoutes(e, cfg.Login)
+		handler.RegisterLoginRoutes(e, cfg.Login, csrf)
 	}
 	if cfg.Register != nil {
-		handler.RegisterRegisterRoutes(e, cfg.Register)
+		handler.RegisterRegisterRoutes(e, cfg.Register, csrf)
 	}
 	if cfg.Account != nil {
-		handler.RegisterAccountRoutes(e, cfg.Account)
+		handler.RegisterAccountRoutes(e, cfg.Account, csrf)
 	}
 	if cfg.Federation != nil {
 		handler.RegisterFederationRoutes(e, cfg.Federation)
 	}
+	if cfg.Admin != nil {
+		handler.RegisterAdminRoutes(e, cfg.Admin, csrf)
+	}
+	if cfg.SCIM != nil {
+		handler.RegisterSCIMRoutes(e, cfg.SCIM)
+	}
+	return nil
 }
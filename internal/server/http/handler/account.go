@@ -4,21 +4,33 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"html"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
 )
 
-// AccountHandler handles account management (e.g. delete account).
+// AccountHandler handles account self-service: profile, password change, deletion and export.
 type AccountHandler struct {
 	UserService *user.UserService
 	Auth        *auth.AuthService
+	// RBAC supplies group and role memberships for the data export; nil omits them.
+	RBAC *rbac.Service
+	// Audit supplies the user's audit events for the data export; nil omits them.
+	Audit *audit.Service
+	// Federation supplies the user's linked upstream identities for the data export; nil omits them.
+	Federation *federation.FederationService
 }
 
 // NewAccountHandler creates an AccountHandler with the given services.
@@ -26,6 +38,119 @@ func NewAccountHandler(userSvc *user.UserService, authSvc *auth.AuthService) *Ac
 	return &AccountHandler{UserService: userSvc, Auth: authSvc}
 }
 
+// ProfileGet renders the account profile page. Requires login.
+func (h *AccountHandler) ProfileGet(c *gin.Context) {
+	u := h.profileUser(c)
+	if u == nil {
+		return
+	}
+	notice := ""
+	if c.Query("updated") == "password" {
+		notice = "Password changed."
+	}
+	renderHTML(c, http.StatusOK, "account.html", accountTemplateData(u, "", notice))
+}
+
+// ProfilePost updates display name and email. An email change only takes effect once verified.
+func (h *AccountHandler) ProfilePost(c *gin.Context) {
+	u := h.profileUser(c)
+	if u == nil {
+		return
+	}
+	var req dto.ProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		renderHTML(c, http.StatusBadRequest, "account.html", accountTemplateData(u, "Invalid input", ""))
+		return
+	}
+	updated, verifying, err := h.UserService.UpdateProfile(c.Request.Context(), u.ID, req.DisplayName, req.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrEmailTaken) {
+			renderHTML(c, http.StatusConflict, "account.html", accountTemplateData(u, "Email already in use", ""))
+			return
+		}
+		renderHTML(c, http.StatusInternalServerError, "account.html", accountTemplateData(u, "Failed to update profile", ""))
+		return
+	}
+	notice := "Profile updated."
+	if verifying {
+		notice = "Profile updated. Check your new email address for a verification link."
+	}
+	renderHTML(c, http.StatusOK, "account.html", accountTemplateData(updated, "", notice))
+}
+
+// PasswordPost changes the password after checking the current one. When revoke_sessions is set,
+// all other sessions and the user's OAuth2 tokens are revoked.
+func (h *AccountHandler) PasswordPost(c *gin.Context) {
+	u := h.profileUser(c)
+	if u == nil {
+		return
+	}
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		renderHTML(c, http.StatusBadRequest, "account.html", accountTemplateData(u, "Invalid input", ""))
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.UserService.ChangePassword(ctx, u.ID, req.CurrentPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, user.ErrWrongPassword):
+			renderHTML(c, http.StatusUnauthorized, "account.html", accountTemplateData(u, "Current password is incorrect", ""))
+		case errors.Is(err, user.ErrWeakPassword):
+			renderHTML(c, http.StatusBadRequest, "account.html", accountTemplateData(u, "Password must be at least 8 characters", ""))
+		default:
+			renderHTML(c, http.StatusInternalServerError, "account.html", accountTemplateData(u, "Failed to change password", ""))
+		}
+		return
+	}
+	if req.RevokeSessions == "yes" {
+		token := sessionToken(c)
+		if err := h.Auth.RevokeOtherSessions(ctx, u.ID, token); err != nil {
+			renderHTML(c, http.StatusInternalServerError, "account.html",
+				accountTemplateData(u, "Password changed, but signing out other sessions failed", ""))
+			return
+		}
+	}
+	c.Redirect(http.StatusFound, "/account?updated=password")
+}
+
+// VerifyEmail handles GET /account/verify-email?token=... and applies the pending email change.
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	u, err := h.UserService.VerifyEmail(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	if current := currentUser(c, h.Auth); current != nil && current.ID == u.ID {
+		renderHTML(c, http.StatusOK, "account.html", accountTemplateData(u, "", "Email address verified."))
+		return
+	}
+	c.Redirect(http.StatusFound, "/login")
+}
+
+// profileUser loads the full profile of the logged-in user, redirecting to /login when absent.
+func (h *AccountHandler) profileUser(c *gin.Context) *domain.User {
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?next=/account")
+		return nil
+	}
+	full, err := h.UserService.Get(c.Request.Context(), u.ID)
+	if err != nil {
+		WriteError(c, err, "")
+		return nil
+	}
+	return full
+}
+
+// accountTemplateData builds the account.html template data.
+func accountTemplateData(u *domain.User, errMsg, notice string) gin.H {
+	return gin.H{
+		"User":   u,
+		"Error":  errMsg,
+		"Notice": notice,
+	}
+}
+
 // DeleteGet renders the account deletion confirmation page. Requires login.
 func (h *AccountHandler) DeleteGet(c *gin.Context) {
 	u := currentUser(c, h.Auth)
@@ -33,10 +158,11 @@ func (h *AccountHandler) DeleteGet(c *gin.Context) {
 		c.Redirect(http.StatusFound, "/login?next=/account/delete")
 		return
 	}
-	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(deleteAccountFormHTML(u, "")))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(deleteAccountFormHTML(u, "", CSRFToken(c))))
 }
 
-// DeletePost processes account deletion. Requires login and confirmation.
+// DeletePost schedules account deletion after the grace period, then signs the user out
+// everywhere. Signing in again before the deletion date cancels it.
 func (h *AccountHandler) DeletePost(c *gin.Context) {
 	u := currentUser(c, h.Auth)
 	if u == nil {
@@ -47,19 +173,25 @@ func (h *AccountHandler) DeletePost(c *gin.Context) {
 	confirm := c.PostForm("confirm")
 	if confirm != "yes" {
 		c.Data(http.StatusBadRequest, "text/html; charset=utf-8",
-			[]byte(deleteAccountFormHTML(u, "Please confirm by typing 'yes'")))
+			[]byte(deleteAccountFormHTML(u, "Please confirm by typing 'yes'", CSRFToken(c))))
 		return
 	}
 
-	if err := h.UserService.Delete(c.Request.Context(), u.ID); err != nil {
+	ctx := c.Request.Context()
+	at, err := h.UserService.RequestDeletion(ctx, u.ID)
+	if err != nil {
 		c.Data(http.StatusInternalServerError, "text/html; charset=utf-8",
-			[]byte(deleteAccountFormHTML(u, "Failed to delete account. Please try again.")))
+			[]byte(deleteAccountFormHTML(u, "Failed to delete account. Please try again.", CSRFToken(c))))
+		return
+	}
+	if err := h.Auth.RevokeOtherSessions(ctx, u.ID, ""); err != nil {
+		c.Data(http.StatusInternalServerError, "text/html; charset=utf-8",
+			[]byte(deleteAccountFormHTML(u, "Deletion scheduled, but signing out failed. Please try again.", CSRFToken(c))))
 		return
 	}
 
-	// Clear session cookie
-	c.SetCookie(sessionCookieName, "", -1, "/", "", false, true)
-	c.Redirect(http.StatusFound, "/login")
+	clearSessionCookie(c)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(deletionScheduledHTML(u, at)))
 }
 
 // currentUser returns the logged-in user from session, or nil.
@@ -67,7 +199,7 @@ func currentUser(c *gin.Context, authSvc *auth.AuthService) *domain.User {
 	if authSvc == nil {
 		return nil
 	}
-	token, _ := c.Cookie(sessionCookieName)
+	token := sessionToken(c)
 	if token == "" {
 		return nil
 	}
@@ -78,7 +210,7 @@ func currentUser(c *gin.Context, authSvc *auth.AuthService) *domain.User {
 	return u
 }
 
-func deleteAccountFormHTML(u *domain.User, errMsg string) string {
+func deleteAccountFormHTML(u *domain.User, errMsg, csrfToken string) string {
 	errBlock := ""
 	if errMsg != "" {
 		errBlock = fmt.Sprintf(`<p style="color:red;">%s</p>`, html.EscapeString(errMsg))
@@ -89,13 +221,28 @@ func deleteAccountFormHTML(u *domain.User, errMsg string) string {
 <body>
 	<h1>Delete Account</h1>
 	%s
-	<p>You are about to permanently delete your account <strong>%s</strong>.</p>
-	<p>This action cannot be undone. All your data will be removed.</p>
+	<p>You are about to delete your account <strong>%s</strong>.</p>
+	<p>You will be signed out everywhere. Your account and all its data are removed permanently
+	after a grace period; sign in again before then to cancel.</p>
+	<p><a href="/account/export">Download your data</a> first if you want a copy.</p>
 	<form method="POST" action="/account/delete">
+		<input type="hidden" name="csrf_token" value="%s">
 		<label>Type <strong>yes</strong> to confirm: <input name="confirm" required></label><br>
 		<button type="submit">Delete Account</button>
 	</form>
-	<p><a href="/login">Cancel</a></p>
+	<p><a href="/account">Cancel</a></p>
+</body>
+</html>`, errBlock, html.EscapeString(u.Username), html.EscapeString(csrfToken))
+}
+
+func deletionScheduledHTML(u *domain.User, at time.Time) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>Account Deletion Scheduled</title></head>
+<body>
+	<h1>Account Deletion Scheduled</h1>
+	<p>Your account <strong>%s</strong> will be permanently deleted on %s.</p>
+	<p>You have been signed out. To keep your account, <a href="/login">sign in</a> before then.</p>
 </body>
-</html>`, errBlock, html.EscapeString(u.Username))
+</html>`, html.EscapeString(u.Username), html.EscapeString(at.UTC().Format("2006-01-02 15:04 MST")))
 }
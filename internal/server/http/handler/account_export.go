@@ -0,0 +1,113 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+)
+
+// ExportGet serves a JSON archive of everything stored about the signed-in user as a file
+// download. Requires login.
+func (h *AccountHandler) ExportGet(c *gin.Context) {
+	u := h.profileUser(c)
+	if u == nil {
+		return
+	}
+	ctx := c.Request.Context()
+	export := dto.AccountExport{
+		ExportedAt:       time.Now().UTC(),
+		Profile:          dto.NewAccountExportUser(u),
+		Groups:           []string{},
+		Roles:            []string{},
+		Sessions:         []dto.AccountExportSession{},
+		AuthorizedApps:   []dto.AccountExportApp{},
+		Consents:         []dto.AccountExportConsent{},
+		LinkedIdentities: []dto.AccountExportIdentity{},
+		AuditEvents:      []dto.AuditEventResp{},
+	}
+	if h.RBAC != nil {
+		groups, roles, err := h.RBAC.Memberships(ctx, u.ID)
+		if err != nil {
+			WriteError(c, err, "")
+			return
+		}
+		export.Groups, export.Roles = append(export.Groups, groups...), append(export.Roles, roles...)
+	}
+	sessions, err := h.Auth.ListSessions(ctx, u.ID)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	for _, s := range sessions {
+		export.Sessions = append(export.Sessions, dto.AccountExportSession{
+			ID:         s.ID,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			IP:         s.IP,
+			UserAgent:  s.UserAgent,
+		})
+	}
+	apps, err := h.Auth.ListAuthorizedApps(ctx, u.ID)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	for _, a := range apps {
+		export.AuthorizedApps = append(export.AuthorizedApps, dto.AccountExportApp{ClientID: a.ClientID, Scopes: a.Scopes, GrantedAt: a.GrantedAt})
+	}
+	consents, err := h.Auth.ListConsents(ctx, u.ID)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	for _, cs := range consents {
+		export.Consents = append(export.Consents, dto.AccountExportConsent{
+			ClientID:  cs.ClientID,
+			Scopes:    cs.Scopes,
+			GrantedAt: cs.GrantedAt,
+			UpdatedAt: cs.UpdatedAt,
+		})
+	}
+	if h.Federation != nil {
+		identities, err := h.Federation.LinkedIdentities(ctx, u.ID)
+		if err != nil {
+			WriteError(c, err, "")
+			return
+		}
+		for _, i := range identities {
+			export.LinkedIdentities = append(export.LinkedIdentities, dto.AccountExportIdentity{
+				ConnectorID: i.ConnectorID,
+				Subject:     i.Subject,
+				Email:       i.Email,
+				LinkedAt:    i.LinkedAt,
+			})
+		}
+	}
+	if h.Audit != nil {
+		events, err := h.Audit.UserEvents(ctx, u.ID)
+		if err != nil {
+			WriteError(c, err, "")
+			return
+		}
+		for _, e := range events {
+			export.AuditEvents = append(export.AuditEvents, dto.NewAuditEventResp(e))
+		}
+	}
+
+	body, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="account-export.json"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
@@ -0,0 +1,95 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+)
+
+// SessionsGet renders the list of the user's active sessions. Requires login.
+func (h *AccountHandler) SessionsGet(c *gin.Context) {
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?next=/account/sessions")
+		return
+	}
+	h.renderSessions(c, u.ID, http.StatusOK, "")
+}
+
+// SessionRevokePost revokes one of the user's sessions. Revoking the current session logs out.
+func (h *AccountHandler) SessionRevokePost(c *gin.Context) {
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?next=/account/sessions")
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.Auth.RevokeSession(ctx, u.ID, c.Param("id")); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			h.renderSessions(c, u.ID, http.StatusNotFound, "Session not found")
+			return
+		}
+		h.renderSessions(c, u.ID, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	// The current session may have been the one revoked.
+	if currentUser(c, h.Auth) == nil {
+		clearSessionCookie(c)
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+	c.Redirect(http.StatusFound, "/account/sessions")
+}
+
+// AppsGet renders the OAuth2 clients holding tokens for the user. Requires login.
+func (h *AccountHandler) AppsGet(c *gin.Context) {
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?next=/account/apps")
+		return
+	}
+	h.renderApps(c, u.ID, http.StatusOK, "")
+}
+
+// AppRevokePost revokes the access and refresh tokens issued to a client for the user.
+func (h *AccountHandler) AppRevokePost(c *gin.Context) {
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?next=/account/apps")
+		return
+	}
+	if err := h.Auth.RevokeApp(c.Request.Context(), u.ID, c.Param("client_id")); err != nil {
+		h.renderApps(c, u.ID, http.StatusInternalServerError, "Failed to revoke access")
+		return
+	}
+	c.Redirect(http.StatusFound, "/account/apps")
+}
+
+func (h *AccountHandler) renderSessions(c *gin.Context, userID string, status int, errMsg string) {
+	sessions, err := h.Auth.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		status, errMsg = http.StatusInternalServerError, "Failed to load sessions"
+	}
+	renderHTML(c, status, "account_sessions.html", gin.H{
+		"Sessions":    sessions,
+		"CurrentHash": auth.HashSessionToken(sessionToken(c)),
+		"Error":       errMsg,
+	})
+}
+
+func (h *AccountHandler) renderApps(c *gin.Context, userID string, status int, errMsg string) {
+	apps, err := h.Auth.ListAuthorizedApps(c.Request.Context(), userID)
+	if err != nil {
+		status, errMsg = http.StatusInternalServerError, "Failed to load authorized apps"
+	}
+	renderHTML(c, status, "account_apps.html", gin.H{
+		"Apps":  apps,
+		"Error": errMsg,
+	})
+}
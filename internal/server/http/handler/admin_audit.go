@@ -0,0 +1,79 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
+)
+
+// AdminAuditHandler serves the JSON admin API for the audit log.
+type AdminAuditHandler struct {
+	Audit *audit.Service
+}
+
+// NewAdminAuditHandler creates an AdminAuditHandler with the given service.
+func NewAdminAuditHandler(svc *audit.Service) *AdminAuditHandler {
+	return &AdminAuditHandler{Audit: svc}
+}
+
+// ListEvents handles GET /admin/api/audit. Query parameters user_id, client_id and type filter
+// by exact match; since and until (RFC 3339) bound the time range; page and page_size paginate.
+func (h *AdminAuditHandler) ListEvents(c *gin.Context) {
+	f := domain.AuditFilter{
+		UserID:   c.Query("user_id"),
+		ClientID: c.Query("client_id"),
+		Type:     domain.AuditEventType(c.Query("type")),
+	}
+	var err error
+	if f.Since, err = parseAuditTime(c.Query("since")); err != nil {
+		WriteErrorWithStatus(c, http.StatusBadRequest, "invalid_request", "since must be an RFC 3339 timestamp")
+		return
+	}
+	if f.Until, err = parseAuditTime(c.Query("until")); err != nil {
+		WriteErrorWithStatus(c, http.StatusBadRequest, "invalid_request", "until must be an RFC 3339 timestamp")
+		return
+	}
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	p, err := h.Audit.List(c.Request.Context(), f, page, pageSize)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := dto.AuditEventListResp{
+		Events:   make([]dto.AuditEventResp, len(p.Events)),
+		Total:    p.Total,
+		Page:     p.Page,
+		PageSize: p.PageSize,
+	}
+	for i, e := range p.Events {
+		out.Events[i] = dto.NewAuditEventResp(e)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Verify handles GET /admin/api/audit/verify by checking the whole hash chain.
+func (h *AdminAuditHandler) Verify(c *gin.Context) {
+	res, err := h.Audit.Verify(c.Request.Context())
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusOK, dto.AuditVerifyResp{Valid: res.Valid, Checked: res.Checked, BrokenAt: res.BrokenAt})
+}
+
+func parseAuditTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
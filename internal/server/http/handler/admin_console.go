@@ -0,0 +1,383 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+)
+
+// adminUserNotices maps the ?updated= value set after a user action to the notice shown.
+var adminUserNotices = map[string]string{
+	"deactivated": "Status changed. The user's sessions and tokens were revoked.",
+	"reactivated": "User reactivated.",
+	"password":    "Password reset. The user's sessions and tokens were revoked.",
+	"session":     "Session revoked.",
+	"app":         "Access revoked.",
+	"revoked":     "All sessions and tokens revoked.",
+}
+
+// AdminConsoleHandler serves the server-rendered admin console under /admin.
+type AdminConsoleHandler struct {
+	Users      *user.UserService
+	Auth       *auth.AuthService
+	RBAC       *rbac.Service
+	Clients    *oauthclient.Service
+	Federation *federation.FederationService
+}
+
+// NewAdminConsoleHandler creates an AdminConsoleHandler from the admin route configuration.
+func NewAdminConsoleHandler(cfg *AdminRouteConfig) *AdminConsoleHandler {
+	return &AdminConsoleHandler{
+		Users:      cfg.Users,
+		Auth:       cfg.Auth,
+		RBAC:       cfg.RBAC,
+		Clients:    cfg.Clients,
+		Federation: cfg.Federation,
+	}
+}
+
+// UsersGet renders one page of users matching ?q=, paginated by ?page=.
+func (h *AdminConsoleHandler) UsersGet(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	result, err := h.Users.Search(c.Request.Context(), c.Query("q"), page, 0)
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	renderHTML(c, http.StatusOK, "admin_users.html", gin.H{
+		"Result":   result,
+		"PrevPage": result.Page - 1,
+		"NextPage": result.Page + 1,
+	})
+}
+
+// UserGet renders a user's profile, memberships, sessions and authorized apps.
+func (h *AdminConsoleHandler) UserGet(c *gin.Context) {
+	h.renderUser(c, c.Param("id"), http.StatusOK, "", adminUserNotices[c.Query("updated")])
+}
+
+// UserStatusPost changes the user's status. Any status other than active revokes the user's
+// sessions and tokens. Admins cannot deactivate themselves.
+func (h *AdminConsoleHandler) UserStatusPost(c *gin.Context) {
+	id := c.Param("id")
+	var form dto.AdminUserStatusForm
+	if err := c.ShouldBind(&form); err != nil {
+		h.renderUser(c, id, http.StatusBadRequest, "Choose a status", "")
+		return
+	}
+	status := domain.UserStatus(form.Status)
+	if id == AdminSubject(c) && status != domain.UserStatusActive {
+		h.renderUser(c, id, http.StatusBadRequest, "You cannot deactivate your own account", "")
+		return
+	}
+	if err := h.Auth.SetUserStatus(c.Request.Context(), id, status); err != nil {
+		h.renderUserError(c, id, err)
+		return
+	}
+	if status == domain.UserStatusActive {
+		redirectToUser(c, id, "reactivated")
+		return
+	}
+	redirectToUser(c, id, "deactivated")
+}
+
+// UserPasswordPost sets a new password for the user and revokes their sessions and tokens
+// (except the admin's own session when resetting their own password).
+func (h *AdminConsoleHandler) UserPasswordPost(c *gin.Context) {
+	id := c.Param("id")
+	var form dto.AdminPasswordResetForm
+	if err := c.ShouldBind(&form); err != nil {
+		h.renderUser(c, id, http.StatusBadRequest, "Enter a new password", "")
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.Users.ResetPassword(ctx, id, form.Password); err != nil {
+		if errors.Is(err, user.ErrWeakPassword) {
+			h.renderUser(c, id, http.StatusBadRequest, "Password must be at least 8 characters", "")
+			return
+		}
+		h.renderUserError(c, id, err)
+		return
+	}
+	if err := h.revokeAll(c, id); err != nil {
+		h.renderUser(c, id, http.StatusInternalServerError, "Password reset, but revoking sessions failed", "")
+		return
+	}
+	redirectToUser(c, id, "password")
+}
+
+// UserSessionRevokePost revokes one of the user's sessions.
+func (h *AdminConsoleHandler) UserSessionRevokePost(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.Auth.RevokeSession(c.Request.Context(), id, c.Param("session_id")); err != nil {
+		h.renderUserError(c, id, err)
+		return
+	}
+	redirectToUser(c, id, "session")
+}
+
+// UserAppRevokePost revokes the tokens a client holds for the user.
+func (h *AdminConsoleHandler) UserAppRevokePost(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.Auth.RevokeApp(c.Request.Context(), id, c.Param("client_id")); err != nil {
+		h.renderUserError(c, id, err)
+		return
+	}
+	redirectToUser(c, id, "app")
+}
+
+// UserRevokeAllPost signs the user out everywhere and revokes all their OAuth2 tokens.
+func (h *AdminConsoleHandler) UserRevokeAllPost(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.revokeAll(c, id); err != nil {
+		h.renderUserError(c, id, err)
+		return
+	}
+	redirectToUser(c, id, "revoked")
+}
+
+// revokeAll revokes every session and token of the user, keeping the admin's current session
+// when acting on their own account.
+func (h *AdminConsoleHandler) revokeAll(c *gin.Context, userID string) error {
+	keep := ""
+	if userID == AdminSubject(c) {
+		keep = sessionToken(c)
+	}
+	return h.Auth.RevokeOtherSessions(c.Request.Context(), userID, keep)
+}
+
+func (h *AdminConsoleHandler) renderUser(c *gin.Context, userID string, status int, errMsg, notice string) {
+	ctx := c.Request.Context()
+	u, err := h.Users.Get(ctx, userID)
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	groups, roles, err := h.RBAC.Memberships(ctx, userID)
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	sessions, err := h.Auth.ListSessions(ctx, userID)
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	apps, err := h.Auth.ListAuthorizedApps(ctx, userID)
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	renderHTML(c, status, "admin_user.html", gin.H{
+		"User":     u,
+		"Statuses": domain.UserStatuses,
+		"Self":     userID == AdminSubject(c),
+		"Groups":   groups,
+		"Roles":    roles,
+		"Sessions": sessions,
+		"Apps":     apps,
+		"Error":    errMsg,
+		"Notice":   notice,
+	})
+}
+
+// renderUserError shows err on the user's page, or the error page when the user does not exist.
+func (h *AdminConsoleHandler) renderUserError(c *gin.Context, userID string, err error) {
+	if errors.Is(err, user.ErrUserNotFound) {
+		renderAdminError(c, err)
+		return
+	}
+	status, msg := consoleError(err)
+	h.renderUser(c, userID, status, msg, "")
+}
+
+func redirectToUser(c *gin.Context, userID, updated string) {
+	c.Redirect(http.StatusFound, "/admin/users/"+url.PathEscape(userID)+"?updated="+updated)
+}
+
+// ClientsGet renders the registered OAuth2 clients.
+func (h *AdminConsoleHandler) ClientsGet(c *gin.Context) {
+	notice := ""
+	switch c.Query("updated") {
+	case "client":
+		notice = "Redirect URIs updated."
+	case "deleted":
+		notice = "Client deleted. Its tokens were revoked."
+	}
+	h.renderClients(c, http.StatusOK, "", notice, nil)
+}
+
+// ClientCreatePost registers a client and shows its secret once.
+func (h *AdminConsoleHandler) ClientCreatePost(c *gin.Context) {
+	var form dto.ClientForm
+	if err := c.ShouldBind(&form); err != nil {
+		h.renderClients(c, http.StatusBadRequest, "Client ID and at least one redirect URI are required", "", nil)
+		return
+	}
+	cl, secret, err := h.Clients.Create(c.Request.Context(), form.ClientID, strings.Fields(form.RedirectURIs))
+	if err != nil {
+		status, msg := consoleError(err)
+		h.renderClients(c, status, msg, "", nil)
+		return
+	}
+	h.renderClients(c, http.StatusCreated, "", "Client created.", gin.H{"ClientID": cl.ClientID, "Secret": secret})
+}
+
+// ClientUpdatePost replaces a client's redirect URIs.
+func (h *AdminConsoleHandler) ClientUpdatePost(c *gin.Context) {
+	var form dto.ClientForm
+	if err := c.ShouldBind(&form); err != nil {
+		h.renderClients(c, http.StatusBadRequest, "At least one redirect URI is required", "", nil)
+		return
+	}
+	if _, err := h.Clients.UpdateRedirectURIs(c.Request.Context(), c.Param("client_id"), strings.Fields(form.RedirectURIs)); err != nil {
+		status, msg := consoleError(err)
+		h.renderClients(c, status, msg, "", nil)
+		return
+	}
+	c.Redirect(http.StatusFound, "/admin/clients?updated=client")
+}
+
+// ClientRotatePost issues a new secret for a client and shows it once.
+func (h *AdminConsoleHandler) ClientRotatePost(c *gin.Context) {
+	clientID := c.Param("client_id")
+	secret, err := h.Clients.RotateSecret(c.Request.Context(), clientID)
+	if err != nil {
+		status, msg := consoleError(err)
+		h.renderClients(c, status, msg, "", nil)
+		return
+	}
+	h.renderClients(c, http.StatusOK, "", "Secret rotated.", gin.H{"ClientID": clientID, "Secret": secret})
+}
+
+// ClientDeletePost removes a client and revokes its tokens.
+func (h *AdminConsoleHandler) ClientDeletePost(c *gin.Context) {
+	if err := h.Clients.Delete(c.Request.Context(), c.Param("client_id")); err != nil {
+		status, msg := consoleError(err)
+		h.renderClients(c, status, msg, "", nil)
+		return
+	}
+	c.Redirect(http.StatusFound, "/admin/clients?updated=deleted")
+}
+
+// renderClients renders the clients page. secret, when set, carries a newly issued client secret.
+func (h *AdminConsoleHandler) renderClients(c *gin.Context, status int, errMsg, notice string, secret gin.H) {
+	clients, err := h.Clients.List(c.Request.Context())
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	renderHTML(c, status, "admin_clients.html", gin.H{
+		"Clients":   clients,
+		"NewSecret": secret,
+		"Error":     errMsg,
+		"Notice":    notice,
+	})
+}
+
+// ConnectorsGet renders the upstream IdP connectors.
+func (h *AdminConsoleHandler) ConnectorsGet(c *gin.Context) {
+	notice := ""
+	switch c.Query("updated") {
+	case "created":
+		notice = "Connector created."
+	case "connector":
+		notice = "Connector updated."
+	case "deleted":
+		notice = "Connector deleted."
+	}
+	h.renderConnectors(c, http.StatusOK, "", notice)
+}
+
+// ConnectorCreatePost adds an upstream IdP connector.
+func (h *AdminConsoleHandler) ConnectorCreatePost(c *gin.Context) {
+	var form dto.ConnectorForm
+	if err := c.ShouldBind(&form); err != nil {
+		h.renderConnectors(c, http.StatusBadRequest, "Issuer and client ID are required", "")
+		return
+	}
+	if err := h.Federation.CreateConnector(c.Request.Context(), connectorFromForm("", form)); err != nil {
+		status, msg := consoleError(err)
+		h.renderConnectors(c, status, msg, "")
+		return
+	}
+	c.Redirect(http.StatusFound, "/admin/connectors?updated=created")
+}
+
+// ConnectorUpdatePost replaces a connector's settings; an empty secret keeps the stored one.
+func (h *AdminConsoleHandler) ConnectorUpdatePost(c *gin.Context) {
+	var form dto.ConnectorForm
+	if err := c.ShouldBind(&form); err != nil {
+		h.renderConnectors(c, http.StatusBadRequest, "Issuer and client ID are required", "")
+		return
+	}
+	if err := h.Federation.UpdateConnector(c.Request.Context(), connectorFromForm(c.Param("id"), form)); err != nil {
+		status, msg := consoleError(err)
+		h.renderConnectors(c, status, msg, "")
+		return
+	}
+	c.Redirect(http.StatusFound, "/admin/connectors?updated=connector")
+}
+
+// ConnectorDeletePost removes a connector.
+func (h *AdminConsoleHandler) ConnectorDeletePost(c *gin.Context) {
+	if err := h.Federation.DeleteConnector(c.Request.Context(), c.Param("id")); err != nil {
+		status, msg := consoleError(err)
+		h.renderConnectors(c, status, msg, "")
+		return
+	}
+	c.Redirect(http.StatusFound, "/admin/connectors?updated=deleted")
+}
+
+func (h *AdminConsoleHandler) renderConnectors(c *gin.Context, status int, errMsg, notice string) {
+	connectors, err := h.Federation.ListConnectors(c.Request.Context())
+	if err != nil {
+		renderAdminError(c, err)
+		return
+	}
+	renderHTML(c, status, "admin_connectors.html", gin.H{
+		"Connectors": connectors,
+		"Error":      errMsg,
+		"Notice":     notice,
+	})
+}
+
+func connectorFromForm(id string, form dto.ConnectorForm) *domain.IdPConnector {
+	return &domain.IdPConnector{
+		ID:           id,
+		Issuer:       form.Issuer,
+		ClientID:     form.ClientID,
+		ClientSecret: form.ClientSecret,
+		GroupsClaim:  form.GroupsClaim,
+	}
+}
+
+// consoleError maps err to an HTTP status and a message safe to show to the admin.
+func consoleError(err error) (int, string) {
+	status, _ := ErrorMapping(err)
+	if status == http.StatusInternalServerError {
+		return status, "An unexpected error occurred"
+	}
+	return status, err.Error()
+}
+
+// renderAdminError renders the console error page for err.
+func renderAdminError(c *gin.Context, err error) {
+	status, msg := consoleError(err)
+	renderHTML(c, status, "admin_error.html", gin.H{"Error": msg})
+}
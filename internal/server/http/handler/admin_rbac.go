@@ -0,0 +1,201 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+)
+
+// AdminRBACHandler serves the JSON admin API for groups, roles and membership.
+type AdminRBACHandler struct {
+	RBAC *rbac.Service
+}
+
+// NewAdminRBACHandler creates an AdminRBACHandler with the given service.
+func NewAdminRBACHandler(svc *rbac.Service) *AdminRBACHandler {
+	return &AdminRBACHandler{RBAC: svc}
+}
+
+// ListGroups handles GET /admin/api/groups.
+func (h *AdminRBACHandler) ListGroups(c *gin.Context) {
+	groups, err := h.RBAC.ListGroups(c.Request.Context())
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := make([]dto.GroupResp, len(groups))
+	for i, g := range groups {
+		out[i] = dto.NewGroupResp(g)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateGroup handles POST /admin/api/groups.
+func (h *AdminRBACHandler) CreateGroup(c *gin.Context) {
+	var req dto.GroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		WriteErrorWithStatus(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	g, err := h.RBAC.CreateGroup(c.Request.Context(), req.Name, req.Description)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewGroupResp(g))
+}
+
+// GetGroup handles GET /admin/api/groups/:id.
+func (h *AdminRBACHandler) GetGroup(c *gin.Context) {
+	g, err := h.RBAC.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewGroupResp(g))
+}
+
+// DeleteGroup handles DELETE /admin/api/groups/:id.
+func (h *AdminRBACHandler) DeleteGroup(c *gin.Context) {
+	if err := h.RBAC.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroupMembers handles GET /admin/api/groups/:id/members.
+func (h *AdminRBACHandler) ListGroupMembers(c *gin.Context) {
+	users, err := h.RBAC.ListGroupMembers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := make([]dto.UserSummaryResp, len(users))
+	for i, u := range users {
+		out[i] = dto.NewUserSummaryResp(u)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// AddGroupMember handles PUT /admin/api/groups/:id/members/:user_id.
+func (h *AdminRBACHandler) AddGroupMember(c *gin.Context) {
+	if err := h.RBAC.AddGroupMember(c.Request.Context(), c.Param("id"), c.Param("user_id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveGroupMember handles DELETE /admin/api/groups/:id/members/:user_id.
+func (h *AdminRBACHandler) RemoveGroupMember(c *gin.Context) {
+	if err := h.RBAC.RemoveGroupMember(c.Request.Context(), c.Param("id"), c.Param("user_id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroupRoles handles GET /admin/api/groups/:id/roles.
+func (h *AdminRBACHandler) ListGroupRoles(c *gin.Context) {
+	roles, err := h.RBAC.ListGroupRoles(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := make([]dto.RoleResp, len(roles))
+	for i, r := range roles {
+		out[i] = dto.NewRoleResp(r)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// AssignGroupRole handles PUT /admin/api/groups/:id/roles/:role_id.
+func (h *AdminRBACHandler) AssignGroupRole(c *gin.Context) {
+	if err := h.RBAC.AssignGroupRole(c.Request.Context(), c.Param("role_id"), c.Param("id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UnassignGroupRole handles DELETE /admin/api/groups/:id/roles/:role_id.
+func (h *AdminRBACHandler) UnassignGroupRole(c *gin.Context) {
+	if err := h.RBAC.UnassignGroupRole(c.Request.Context(), c.Param("role_id"), c.Param("id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListRoles handles GET /admin/api/roles.
+func (h *AdminRBACHandler) ListRoles(c *gin.Context) {
+	roles, err := h.RBAC.ListRoles(c.Request.Context())
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := make([]dto.RoleResp, len(roles))
+	for i, r := range roles {
+		out[i] = dto.NewRoleResp(r)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateRole handles POST /admin/api/roles.
+func (h *AdminRBACHandler) CreateRole(c *gin.Context) {
+	var req dto.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		WriteErrorWithStatus(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	r, err := h.RBAC.CreateRole(c.Request.Context(), req.Name, req.Description)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewRoleResp(r))
+}
+
+// DeleteRole handles DELETE /admin/api/roles/:id.
+func (h *AdminRBACHandler) DeleteRole(c *gin.Context) {
+	if err := h.RBAC.DeleteRole(c.Request.Context(), c.Param("id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UserMemberships handles GET /admin/api/users/:user_id/memberships.
+func (h *AdminRBACHandler) UserMemberships(c *gin.Context) {
+	groups, roles, err := h.RBAC.Memberships(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusOK, dto.MembershipsResp{Groups: groups, Roles: roles})
+}
+
+// AssignUserRole handles PUT /admin/api/users/:user_id/roles/:role_id.
+func (h *AdminRBACHandler) AssignUserRole(c *gin.Context) {
+	if err := h.RBAC.AssignUserRole(c.Request.Context(), c.Param("role_id"), c.Param("user_id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UnassignUserRole handles DELETE /admin/api/users/:user_id/roles/:role_id.
+func (h *AdminRBACHandler) UnassignUserRole(c *gin.Context) {
+	if err := h.RBAC.UnassignUserRole(c.Request.Context(), c.Param("role_id"), c.Param("user_id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
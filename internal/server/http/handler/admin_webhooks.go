@@ -0,0 +1,145 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
+)
+
+// AdminWebhookHandler serves the JSON admin API for webhook subscriptions and deliveries.
+type AdminWebhookHandler struct {
+	Webhooks *webhook.Service
+}
+
+// NewAdminWebhookHandler creates an AdminWebhookHandler with the given service.
+func NewAdminWebhookHandler(svc *webhook.Service) *AdminWebhookHandler {
+	return &AdminWebhookHandler{Webhooks: svc}
+}
+
+// ListWebhooks handles GET /admin/api/webhooks.
+func (h *AdminWebhookHandler) ListWebhooks(c *gin.Context) {
+	subs, err := h.Webhooks.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := make([]dto.WebhookResp, len(subs))
+	for i, s := range subs {
+		out[i] = dto.NewWebhookResp(s)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateWebhook handles POST /admin/api/webhooks. The response is the only one carrying the
+// signing secret, apart from secret rotation.
+func (h *AdminWebhookHandler) CreateWebhook(c *gin.Context) {
+	attrs, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+	sub, err := h.Webhooks.CreateSubscription(c.Request.Context(), attrs)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := dto.NewWebhookResp(sub)
+	out.Secret = sub.Secret
+	c.JSON(http.StatusCreated, out)
+}
+
+// GetWebhook handles GET /admin/api/webhooks/:id.
+func (h *AdminWebhookHandler) GetWebhook(c *gin.Context) {
+	sub, err := h.Webhooks.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewWebhookResp(sub))
+}
+
+// UpdateWebhook handles PUT /admin/api/webhooks/:id.
+func (h *AdminWebhookHandler) UpdateWebhook(c *gin.Context) {
+	attrs, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+	sub, err := h.Webhooks.UpdateSubscription(c.Request.Context(), c.Param("id"), attrs)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewWebhookResp(sub))
+}
+
+// DeleteWebhook handles DELETE /admin/api/webhooks/:id.
+func (h *AdminWebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.Webhooks.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RotateSecret handles POST /admin/api/webhooks/:id/rotate-secret and returns the new secret.
+func (h *AdminWebhookHandler) RotateSecret(c *gin.Context) {
+	sub, err := h.Webhooks.RotateSecret(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := dto.NewWebhookResp(sub)
+	out.Secret = sub.Secret
+	c.JSON(http.StatusOK, out)
+}
+
+// ListDeliveries handles GET /admin/api/webhooks/:id/deliveries?page=&page_size=.
+func (h *AdminWebhookHandler) ListDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+	p, err := h.Webhooks.ListDeliveries(c.Request.Context(), c.Param("id"), page, pageSize)
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	out := dto.WebhookDeliveryListResp{
+		Deliveries: make([]dto.WebhookDeliveryResp, len(p.Deliveries)),
+		Total:      p.Total,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+	}
+	for i, d := range p.Deliveries {
+		out.Deliveries[i] = dto.NewWebhookDeliveryResp(d)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Redeliver handles POST /admin/api/webhooks/:id/deliveries/:delivery_id/redeliver by queueing
+// the event again.
+func (h *AdminWebhookHandler) Redeliver(c *gin.Context) {
+	d, err := h.Webhooks.Redeliver(c.Request.Context(), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		WriteError(c, err, "")
+		return
+	}
+	c.JSON(http.StatusAccepted, dto.NewWebhookDeliveryResp(d))
+}
+
+func bindWebhookRequest(c *gin.Context) (webhook.SubscriptionAttrs, bool) {
+	var req dto.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		WriteErrorWithStatus(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return webhook.SubscriptionAttrs{}, false
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return webhook.SubscriptionAttrs{URL: req.URL, Events: req.Events, Description: req.Description, Enabled: enabled}, true
+}
@@ -6,11 +6,13 @@ package handler
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
 	"github.com/qinzj/superpowers-demo/internal/service/federation"
 )
 
@@ -21,6 +23,7 @@ type FederationParams struct {
 	ResponseType string `json:"response_type"`
 	Scope        string `json:"scope"`
 	State        string `json:"state"`
+	Next         string `json:"next,omitempty"`
 }
 
 // CallbackHandler handles the upstream IdP OAuth callback.
@@ -60,13 +63,25 @@ func (h *CallbackHandler) GetCallback(c *gin.Context) {
 	redirectURI := buildCallbackRedirectURI(h.Issuer, connectorID)
 	ctx := c.Request.Context()
 	sess, err := h.Federation.LoginWithUpstream(ctx, connectorID, stateB64, code, redirectURI)
+	if errors.Is(err, auth.ErrAccountInactive) {
+		c.Redirect(http.StatusFound, "/login?error=account_inactive")
+		return
+	}
+	if errors.Is(err, federation.ErrEmailNotVerified) {
+		c.Redirect(http.StatusFound, "/login?error=email_not_verified")
+		return
+	}
 	if err != nil {
 		c.Redirect(http.StatusFound, "/login?error=federation_failed")
 		return
 	}
 
-	c.SetCookie(sessionCookieName, sess.Token, sessionCookieMaxAge, "/", "", false, true)
+	setSessionCookie(c, sess)
 
+	if next, ok := localPath(params.Next); ok {
+		c.Redirect(http.StatusFound, next)
+		return
+	}
 	if params.ClientID == "" && params.RedirectURI == "" {
 		c.Redirect(http.StatusFound, "/login")
 		return
@@ -0,0 +1,23 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+)
+
+// ClientInfoMiddleware attaches the client IP and User-Agent to the request context so that
+// sessions created while handling the request record the device they belong to.
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := auth.ContextWithClientInfo(c.Request.Context(), auth.ClientInfo{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+const sessionCookieName = "sso_session"
+const hostCookiePrefix = "__Host-"
+const cookiePolicyKey = "cookie_policy"
+
+// CookiePolicy holds cookie attributes matching the "cookie" section of settings.yaml.
+// It applies to every cookie the server sets (session and CSRF).
+type CookiePolicy struct {
+	Secure   bool   `mapstructure:"secure"`    // send only over HTTPS
+	SameSite string `mapstructure:"same_site"` // lax (default), strict, or none
+	Domain   string `mapstructure:"domain"`    // empty for host-only cookies
+	// HostPrefix names cookies with the __Host- prefix; requires Secure and an empty Domain.
+	HostPrefix bool `mapstructure:"host_prefix"`
+}
+
+// Validate reports configuration combinations browsers would reject.
+func (p CookiePolicy) Validate() error {
+	switch strings.ToLower(p.SameSite) {
+	case "", "lax", "strict":
+	case "none":
+		if !p.Secure {
+			return errors.New("cookie: same_site=none requires secure=true")
+		}
+	default:
+		return errors.New("cookie: same_site must be lax, strict, or none")
+	}
+	if p.HostPrefix && (!p.Secure || p.Domain != "") {
+		return errors.New("cookie: host_prefix requires secure=true and an empty domain")
+	}
+	return nil
+}
+
+func (p CookiePolicy) name(base string) string {
+	if p.HostPrefix {
+		return hostCookiePrefix + base
+	}
+	return base
+}
+
+func (p CookiePolicy) sameSite() http.SameSite {
+	switch strings.ToLower(p.SameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// CookiePolicyMiddleware makes the cookie policy available to handlers on the gin.Context.
+func CookiePolicyMiddleware(p CookiePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(cookiePolicyKey, p)
+		c.Next()
+	}
+}
+
+// CookiePolicySource holds the cookie policy in effect so that it can be replaced while the
+// server runs. Changing the cookie names (host_prefix) signs everyone out.
+type CookiePolicySource struct {
+	p atomic.Pointer[CookiePolicy]
+}
+
+// NewCookiePolicySource returns a source that starts with p.
+func NewCookiePolicySource(p CookiePolicy) *CookiePolicySource {
+	s := &CookiePolicySource{}
+	s.p.Store(&p)
+	return s
+}
+
+// Load returns the current policy.
+func (s *CookiePolicySource) Load() CookiePolicy {
+	return *s.p.Load()
+}
+
+// Store validates p and makes it the current policy; an invalid policy is rejected and the
+// previous one kept.
+func (s *CookiePolicySource) Store(p CookiePolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	s.p.Store(&p)
+	return nil
+}
+
+// CookiePolicySourceMiddleware is CookiePolicyMiddleware for a policy that may change; each
+// request sees the policy current when it started.
+func CookiePolicySourceMiddleware(src *CookiePolicySource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(cookiePolicyKey, src.Load())
+		c.Next()
+	}
+}
+
+// cookiePolicy returns the policy set by CookiePolicyMiddleware, or the zero (development) policy.
+func cookiePolicy(c *gin.Context) CookiePolicy {
+	v, _ := c.Get(cookiePolicyKey)
+	p, _ := v.(CookiePolicy)
+	return p
+}
+
+// setCookie writes an HttpOnly cookie with the configured policy. maxAge 0 means a browser-session
+// cookie; a negative maxAge deletes it.
+func setCookie(c *gin.Context, base, value string, maxAge int) {
+	p := cookiePolicy(c)
+	ck := &http.Cookie{
+		Name:     p.name(base),
+		Value:    value,
+		Path:     "/",
+		Domain:   p.Domain,
+		MaxAge:   maxAge,
+		Secure:   p.Secure,
+		HttpOnly: true,
+		SameSite: p.sameSite(),
+	}
+	if maxAge < 0 {
+		ck.Expires = time.Unix(0, 0)
+	}
+	http.SetCookie(c.Writer, ck)
+}
+
+// readCookie returns the value of the cookie named base under the configured policy.
+func readCookie(c *gin.Context, base string) string {
+	v, _ := c.Cookie(cookiePolicy(c).name(base))
+	return v
+}
+
+// sessionToken returns the raw session token from the request cookie, or "".
+func sessionToken(c *gin.Context) string {
+	return readCookie(c, sessionCookieName)
+}
+
+// setSessionCookie writes the session cookie. "Remember me" sessions get a persistent cookie that
+// lives until the absolute expiry; other sessions use a browser-session cookie.
+func setSessionCookie(c *gin.Context, sess *domain.Session) {
+	maxAge := 0
+	if sess.Remember {
+		maxAge = int(time.Until(sess.AbsoluteExpiresAt).Seconds())
+	}
+	setCookie(c, sessionCookieName, sess.Token, maxAge)
+}
+
+// clearSessionCookie deletes the session cookie.
+func clearSessionCookie(c *gin.Context) {
+	setCookie(c, sessionCookieName, "", -1)
+}
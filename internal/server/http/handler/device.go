@@ -0,0 +1,170 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ory/fosite"
+
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+)
+
+// clientAuthenticator authenticates the client of a request the way the token endpoint does;
+// the provider built by oidc.NewOAuth2Provider implements it.
+type clientAuthenticator interface {
+	AuthenticateClient(ctx context.Context, r *http.Request, form url.Values) (fosite.Client, error)
+}
+
+// DeviceHandler serves the device authorization endpoint and the /device page where users
+// approve devices (RFC 8628).
+type DeviceHandler struct {
+	Provider fosite.OAuth2Provider
+	Flow     *oidc.DeviceFlow
+	Issuer   string
+	Auth     *auth.AuthService
+	// RBAC supplies the groups and roles claims; nil omits them.
+	RBAC *rbac.Service
+}
+
+// NewDeviceHandler creates a DeviceHandler.
+func NewDeviceHandler(provider fosite.OAuth2Provider, flow *oidc.DeviceFlow, issuer string, authSvc *auth.AuthService, rbacSvc *rbac.Service) *DeviceHandler {
+	return &DeviceHandler{Provider: provider, Flow: flow, Issuer: issuer, Auth: authSvc, RBAC: rbacSvc}
+}
+
+// Code handles POST /device/code: the client authenticates as at the token endpoint and gets a
+// device code to poll /token with, and a user code to show to the user.
+func (h *DeviceHandler) Code(c *gin.Context) {
+	ctx := c.Request.Context()
+	authenticator, ok := h.Provider.(clientAuthenticator)
+	if !ok {
+		h.Provider.WriteAccessError(ctx, c.Writer, nil, fosite.ErrServerError.WithDebug("the provider cannot authenticate clients"))
+		return
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		h.Provider.WriteAccessError(ctx, c.Writer, nil, fosite.ErrInvalidRequest.WithWrap(err))
+		return
+	}
+	client, err := authenticator.AuthenticateClient(ctx, c.Request, c.Request.PostForm)
+	if err != nil {
+		h.Provider.WriteAccessError(ctx, c.Writer, nil, err)
+		return
+	}
+	issuer := h.Issuer
+	if issuer == "" {
+		issuer = oidc.DefaultIssuerFromRequest(c.Request)
+	}
+	resp, err := h.Flow.Authorize(ctx, client,
+		fosite.RemoveEmpty(strings.Split(c.Request.PostForm.Get("scope"), " ")),
+		fosite.GetAudiences(c.Request.PostForm),
+		strings.TrimSuffix(issuer, "/")+oidc.DeviceVerificationPath)
+	if err != nil {
+		h.Provider.WriteAccessError(ctx, c.Writer, nil, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// Verify handles GET /device: it asks for the user code (unless given as user_code) and then
+// shows which client requests which scopes, for the signed-in user to approve or deny.
+func (h *DeviceHandler) Verify(c *gin.Context) {
+	userCode := c.Query("user_code")
+	if currentUser(c, h.Auth) == nil {
+		next := oidc.DeviceVerificationPath
+		if userCode != "" {
+			next += "?" + url.Values{"user_code": {userCode}}.Encode()
+		}
+		c.Redirect(http.StatusFound, "/login?"+url.Values{"next": {next}}.Encode())
+		return
+	}
+	if userCode == "" {
+		renderHTML(c, http.StatusOK, "device.html", nil)
+		return
+	}
+	h.renderRequest(c, userCode)
+}
+
+// Decide handles POST /device with the user code and action approve or deny.
+func (h *DeviceHandler) Decide(c *gin.Context) {
+	ctx := c.Request.Context()
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?"+url.Values{"next": {oidc.DeviceVerificationPath}}.Encode())
+		return
+	}
+	userCode := c.PostForm("user_code")
+	var err error
+	switch c.PostForm("action") {
+	case "approve":
+		d, perr := h.Flow.Pending(ctx, userCode)
+		if perr != nil {
+			err = perr
+			break
+		}
+		session := userToFositeSession(u)
+		now := time.Now().UTC()
+		session.Claims.AuthTime = now
+		session.Claims.RequestedAt = now
+		if d.GetRequestedScopes().Has(oidc.ScopeGroups) {
+			if err = addMembershipClaims(ctx, h.RBAC, u.ID, session.Claims.Extra); err != nil {
+				break
+			}
+		}
+		// Approving the device is consent, so the client need not ask again in a browser.
+		if err = h.Auth.GrantConsent(ctx, u.ID, d.GetClient().GetID(), d.GetRequestedScopes()); err != nil {
+			break
+		}
+		err = h.Flow.Approve(ctx, userCode, session)
+	case "deny":
+		err = h.Flow.Deny(ctx, userCode)
+	default:
+		h.renderRequest(c, userCode)
+		return
+	}
+	if errors.Is(err, oidc.ErrUnknownUserCode) {
+		renderHTML(c, http.StatusBadRequest, "device.html", gin.H{"Error": "This code is invalid or has expired. Check the code shown on your device."})
+		return
+	}
+	if err != nil {
+		renderHTML(c, http.StatusInternalServerError, "device.html", gin.H{"Error": "The device could not be authorized. Please try again."})
+		return
+	}
+	renderHTML(c, http.StatusOK, "device.html", gin.H{"Done": true, "Approved": c.PostForm("action") == "approve"})
+}
+
+// renderRequest shows the pending request for userCode. The user still confirms the code when
+// they have allowed the client these scopes before, since it ties the page to their device.
+func (h *DeviceHandler) renderRequest(c *gin.Context, userCode string) {
+	ctx := c.Request.Context()
+	d, err := h.Flow.Pending(ctx, userCode)
+	if err != nil {
+		renderHTML(c, http.StatusBadRequest, "device.html", gin.H{"Error": "This code is invalid or has expired. Check the code shown on your device."})
+		return
+	}
+	u := currentUser(c, h.Auth)
+	if u == nil {
+		c.Redirect(http.StatusFound, "/login?"+url.Values{"next": {oidc.DeviceVerificationPath}}.Encode())
+		return
+	}
+	missing, err := h.Auth.MissingConsent(ctx, u.ID, d.GetClient().GetID(), d.GetRequestedScopes())
+	if err != nil {
+		renderHTML(c, http.StatusInternalServerError, "device.html", gin.H{"Error": "The request could not be loaded. Please try again."})
+		return
+	}
+	renderHTML(c, http.StatusOK, "device.html", gin.H{
+		"UserCode": oidc.FormatUserCode(d.UserCode),
+		"ClientID": d.GetClient().GetID(),
+		"Scopes":   d.GetRequestedScopes(),
+		"Allowed":  len(missing) == 0,
+	})
+}
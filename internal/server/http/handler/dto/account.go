@@ -0,0 +1,108 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package dto
+
+import (
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// ProfileRequest holds the profile edit form data.
+type ProfileRequest struct {
+	DisplayName string `form:"display_name" binding:"max=128"`
+	Email       string `form:"email" binding:"required,email"`
+}
+
+// ChangePasswordRequest holds the change-password form data.
+type ChangePasswordRequest struct {
+	CurrentPassword string `form:"current_password" binding:"required"`
+	NewPassword     string `form:"new_password" binding:"required"`
+	RevokeSessions  string `form:"revoke_sessions"`
+}
+
+// AccountExport is the JSON archive served by /account/export: everything stored about the user.
+type AccountExport struct {
+	ExportedAt time.Time              `json:"exported_at"`
+	Profile    AccountExportUser      `json:"profile"`
+	Groups     []string               `json:"groups"`
+	Roles      []string               `json:"roles"`
+	Sessions   []AccountExportSession `json:"sessions"`
+	// AuthorizedApps lists the OAuth2 clients holding tokens for, or consented to by, the user.
+	AuthorizedApps []AccountExportApp `json:"authorized_apps"`
+	// Consents lists the scopes the user has approved on the consent screen, per client.
+	Consents []AccountExportConsent `json:"consents"`
+	// LinkedIdentities lists the upstream accounts that sign in as the user.
+	LinkedIdentities []AccountExportIdentity `json:"linked_identities"`
+	// AuditEvents lists the security events recorded about the user, newest first.
+	AuditEvents []AuditEventResp `json:"audit_events"`
+}
+
+// AccountExportUser is the profile section of an AccountExport. Secrets (password hash,
+// verification token) are omitted.
+type AccountExportUser struct {
+	ID                  string     `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	EmailVerified       bool       `json:"email_verified"`
+	PendingEmail        string     `json:"pending_email,omitempty"`
+	DisplayName         string     `json:"display_name"`
+	ExternalID          string     `json:"external_id,omitempty"`
+	Status              string     `json:"status"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// AccountExportSession is one session in an AccountExport.
+type AccountExportSession struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+}
+
+// AccountExportApp is one authorized OAuth2 client in an AccountExport.
+type AccountExportApp struct {
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+// AccountExportConsent is one stored consent decision in an AccountExport.
+type AccountExportConsent struct {
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	GrantedAt time.Time `json:"granted_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountExportIdentity is one linked upstream identity in an AccountExport.
+type AccountExportIdentity struct {
+	ConnectorID string    `json:"connector_id"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email,omitempty"`
+	LinkedAt    time.Time `json:"linked_at"`
+}
+
+// NewAccountExportUser converts a domain user.
+func NewAccountExportUser(u *domain.User) AccountExportUser {
+	out := AccountExportUser{
+		ID:            u.ID,
+		Username:      u.Username,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		PendingEmail:  u.PendingEmail,
+		DisplayName:   u.DisplayName,
+		ExternalID:    u.ExternalID,
+		Status:        string(u.Status),
+		CreatedAt:     u.CreatedAt,
+	}
+	if !u.DeletionScheduledAt.IsZero() {
+		at := u.DeletionScheduledAt
+		out.DeletionScheduledAt = &at
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package dto
+
+import (
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// GroupRequest is the JSON body for creating a group.
+type GroupRequest struct {
+	Name        string `json:"name" binding:"required,max=128"`
+	Description string `json:"description" binding:"max=512"`
+}
+
+// RoleRequest is the JSON body for creating a role.
+type RoleRequest struct {
+	Name        string `json:"name" binding:"required,max=128"`
+	Description string `json:"description" binding:"max=512"`
+}
+
+// GroupResp is the admin API representation of a group.
+type GroupResp struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ConnectorID string    `json:"connector_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RoleResp is the admin API representation of a role.
+type RoleResp struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UserSummaryResp identifies a user in membership listings.
+type UserSummaryResp struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// MembershipsResp lists a user's group names and effective role names.
+type MembershipsResp struct {
+	Groups []string `json:"groups"`
+	Roles  []string `json:"roles"`
+}
+
+// NewGroupResp converts a domain group.
+func NewGroupResp(g *domain.Group) GroupResp {
+	return GroupResp{ID: g.ID, Name: g.Name, Description: g.Description, ConnectorID: g.ConnectorID, CreatedAt: g.CreatedAt}
+}
+
+// NewRoleResp converts a domain role.
+func NewRoleResp(r *domain.Role) RoleResp {
+	return RoleResp{ID: r.ID, Name: r.Name, Description: r.Description, CreatedAt: r.CreatedAt}
+}
+
+// NewUserSummaryResp converts a domain user.
+func NewUserSummaryResp(u *domain.User) UserSummaryResp {
+	return UserSummaryResp{ID: u.ID, Username: u.Username, Email: u.Email}
+}
+
+// AdminPasswordResetForm holds the admin console password reset form data.
+type AdminPasswordResetForm struct {
+	Password string `form:"password" binding:"required"`
+}
+
+// AdminUserStatusForm holds the admin console user status form data.
+type AdminUserStatusForm struct {
+	Status string `form:"status" binding:"required"`
+}
+
+// ClientForm holds the admin console OAuth2 client form data. RedirectURIs has one URI per line.
+type ClientForm struct {
+	ClientID     string `form:"client_id"`
+	RedirectURIs string `form:"redirect_uris" binding:"required"`
+}
+
+// ConnectorForm holds the admin console IdP connector form data. An empty ClientSecret keeps
+// the stored secret when editing.
+type ConnectorForm struct {
+	Issuer       string `form:"issuer" binding:"required"`
+	ClientID     string `form:"client_id" binding:"required"`
+	ClientSecret string `form:"client_secret"`
+	GroupsClaim  string `form:"groups_claim"`
+}
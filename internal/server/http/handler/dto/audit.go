@@ -0,0 +1,55 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package dto
+
+import (
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// AuditEventResp is the JSON representation of an audit event.
+type AuditEventResp struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	UserID    string            `json:"user_id,omitempty"`
+	ClientID  string            `json:"client_id,omitempty"`
+	IP        string            `json:"ip,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	PrevHash  string            `json:"prev_hash"`
+	Hash      string            `json:"hash"`
+}
+
+// AuditEventListResp is one page of audit events, newest first.
+type AuditEventListResp struct {
+	Events   []AuditEventResp `json:"events"`
+	Total    int              `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
+
+// AuditVerifyResp reports the result of checking the audit log's hash chain.
+type AuditVerifyResp struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt string `json:"broken_at,omitempty"`
+}
+
+// NewAuditEventResp converts a domain audit event.
+func NewAuditEventResp(e *domain.AuditEvent) AuditEventResp {
+	return AuditEventResp{
+		ID:        e.ID,
+		Type:      string(e.Type),
+		UserID:    e.UserID,
+		ClientID:  e.ClientID,
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		Details:   e.Details,
+		CreatedAt: e.CreatedAt,
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+	}
+}
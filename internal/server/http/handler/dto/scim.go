@@ -0,0 +1,116 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SCIM schema URNs (RFC 7643, RFC 7644).
+const (
+	SCIMUserSchema            = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMGroupSchema           = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMListResponseSchema    = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMPatchOpSchema         = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMErrorSchema           = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMServiceProviderSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMResourceTypeSchema    = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SCIMSchemaSchema          = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+)
+
+// SCIMMeta is the meta attribute of a SCIM resource.
+type SCIMMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	Location     string     `json:"location"`
+}
+
+// SCIMName is the name attribute of a SCIM user.
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMEmail is one entry of a SCIM user's emails.
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMUser is the SCIM User resource. Password is accepted on input and never returned.
+type SCIMUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *SCIMName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []SCIMEmail `json:"emails,omitempty"`
+	// Active defaults to true when omitted on input.
+	Active   *bool     `json:"active,omitempty"`
+	Password string    `json:"password,omitempty"`
+	Meta     *SCIMMeta `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary email, or the first one when none is marked primary.
+func (u *SCIMUser) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// SCIMMember is one entry of a SCIM group's members.
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIMGroup is the SCIM Group resource.
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members"`
+	Meta        *SCIMMeta    `json:"meta,omitempty"`
+}
+
+// SCIMListResponse is the response of list and search requests.
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// SCIMPatchRequest is the body of a PATCH request.
+type SCIMPatchRequest struct {
+	Schemas    []string      `json:"schemas"`
+	Operations []SCIMPatchOp `json:"Operations" binding:"required,min=1"`
+}
+
+// SCIMPatchOp is one PATCH operation. Op is add, replace or remove (case-insensitive).
+type SCIMPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// SCIMError is the SCIM error response.
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
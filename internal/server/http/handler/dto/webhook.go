@@ -0,0 +1,90 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package dto
+
+import (
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// WebhookRequest is the JSON body for creating or replacing a webhook subscription.
+// Enabled defaults to true when omitted.
+type WebhookRequest struct {
+	URL         string   `json:"url" binding:"required,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Description string   `json:"description" binding:"max=512"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// WebhookResp is the admin API representation of a webhook subscription. Secret is only set
+// when the subscription is created or its secret rotated.
+type WebhookResp struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Enabled     bool      `json:"enabled"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryResp is one entry of a subscription's delivery log.
+type WebhookDeliveryResp struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	Payload        string     `json:"payload"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookDeliveryListResp is one page of a delivery log, newest first.
+type WebhookDeliveryListResp struct {
+	Deliveries []WebhookDeliveryResp `json:"deliveries"`
+	Total      int                   `json:"total"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"page_size"`
+}
+
+// NewWebhookResp converts a domain subscription, omitting the secret.
+func NewWebhookResp(s *domain.WebhookSubscription) WebhookResp {
+	return WebhookResp{
+		ID:          s.ID,
+		URL:         s.URL,
+		Events:      s.Events,
+		Description: s.Description,
+		Enabled:     s.Enabled,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// NewWebhookDeliveryResp converts a domain delivery.
+func NewWebhookDeliveryResp(d *domain.WebhookDelivery) WebhookDeliveryResp {
+	out := WebhookDeliveryResp{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		at := d.NextAttemptAt
+		out.NextAttemptAt = &at
+	}
+	if !d.LastAttemptAt.IsZero() {
+		at := d.LastAttemptAt
+		out.LastAttemptAt = &at
+	}
+	return out
+}
@@ -12,7 +12,10 @@ import (
 
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
 	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
 )
 
 const requestIDKey = "request_id"
@@ -55,12 +58,56 @@ func ErrorMapping(err error) (status int, code string) {
 		return http.StatusOK, ""
 	case errors.Is(err, auth.ErrInvalidCredentials):
 		return http.StatusUnauthorized, "invalid_credentials"
+	case errors.Is(err, auth.ErrAccountInactive):
+		return http.StatusForbidden, "account_inactive"
+	case errors.Is(err, auth.ErrInvalidStatus):
+		return http.StatusBadRequest, "invalid_status"
+	case errors.Is(err, auth.ErrSessionNotFound):
+		return http.StatusNotFound, "session_not_found"
 	case errors.Is(err, user.ErrUsernameTaken):
 		return http.StatusConflict, "username_taken"
 	case errors.Is(err, user.ErrWeakPassword):
 		return http.StatusBadRequest, "weak_password"
+	case errors.Is(err, user.ErrUserNotFound):
+		return http.StatusNotFound, "user_not_found"
+	case errors.Is(err, user.ErrEmailTaken):
+		return http.StatusConflict, "email_taken"
+	case errors.Is(err, user.ErrWrongPassword):
+		return http.StatusUnauthorized, "wrong_password"
+	case errors.Is(err, user.ErrInvalidVerificationToken):
+		return http.StatusBadRequest, "invalid_verification_token"
 	case errors.Is(err, federation.ErrConnectorNotFound):
 		return http.StatusNotFound, "connector_not_found"
+	case errors.Is(err, federation.ErrInvalidConnector):
+		return http.StatusBadRequest, "invalid_connector"
+	case errors.Is(err, oauthclient.ErrClientNotFound):
+		return http.StatusNotFound, "client_not_found"
+	case errors.Is(err, oauthclient.ErrClientExists):
+		return http.StatusConflict, "client_exists"
+	case errors.Is(err, oauthclient.ErrInvalidClientID):
+		return http.StatusBadRequest, "invalid_client_id"
+	case errors.Is(err, oauthclient.ErrInvalidRedirectURI):
+		return http.StatusBadRequest, "invalid_redirect_uri"
+	case errors.Is(err, rbac.ErrGroupNotFound):
+		return http.StatusNotFound, "group_not_found"
+	case errors.Is(err, rbac.ErrRoleNotFound):
+		return http.StatusNotFound, "role_not_found"
+	case errors.Is(err, rbac.ErrUserNotFound):
+		return http.StatusNotFound, "user_not_found"
+	case errors.Is(err, rbac.ErrGroupExists):
+		return http.StatusConflict, "group_exists"
+	case errors.Is(err, rbac.ErrRoleExists):
+		return http.StatusConflict, "role_exists"
+	case errors.Is(err, rbac.ErrInvalidName):
+		return http.StatusBadRequest, "invalid_name"
+	case errors.Is(err, rbac.ErrSyncedGroup):
+		return http.StatusConflict, "group_managed_upstream"
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		return http.StatusNotFound, "webhook_not_found"
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		return http.StatusNotFound, "delivery_not_found"
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		return http.StatusBadRequest, "invalid_webhook"
 	default:
 		return http.StatusInternalServerError, "internal_error"
 	}
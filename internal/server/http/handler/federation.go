@@ -39,6 +39,7 @@ func (h *FederationHandler) Init(c *gin.Context) {
 		ResponseType: c.Query("response_type"),
 		Scope:        c.Query("scope"),
 		State:        c.Query("state"),
+		Next:         c.Query("next"),
 	}
 	stateJSON, err := json.Marshal(params)
 	if err != nil {
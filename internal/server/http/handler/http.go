@@ -4,16 +4,31 @@
 package handler
 
 import (
-	"path/filepath"
+	"html/template"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ory/fosite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"github.com/qinzj/superpowers-demo/internal/infra/metrics"
+	"github.com/qinzj/superpowers-demo/internal/server/http/templates"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
 	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/scim"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
 	"github.com/qinzj/superpowers-demo/pkg/log"
 )
 
@@ -22,6 +37,11 @@ type OIDCRouteConfig struct {
 	Provider fosite.OAuth2Provider
 	Issuer   string
 	Auth     *auth.AuthService
+	RBAC     *rbac.Service
+	Audit    *audit.Service
+	Metrics  *metrics.Metrics
+	// Device serves the device authorization grant endpoints; nil disables them.
+	Device *oidc.DeviceFlow
 }
 
 // LoginRouteConfig holds login handler configuration.
@@ -45,6 +65,47 @@ type RegisterRouteConfig struct {
 type AccountRouteConfig struct {
 	UserService *user.UserService
 	Auth        *auth.AuthService
+	// RBAC adds group and role memberships to /account/export when set.
+	RBAC *rbac.Service
+	// Audit adds the user's audit events to /account/export when set.
+	Audit *audit.Service
+	// Federation adds the user's linked upstream identities to /account/export when set.
+	Federation *federation.FederationService
+}
+
+// AdminRouteConfig holds admin API and console configuration. The console is registered when
+// Users is set; its client and connector pages need Clients and Federation respectively.
+type AdminRouteConfig struct {
+	Provider   fosite.OAuth2Provider
+	Auth       *auth.AuthService
+	RBAC       *rbac.Service
+	Users      *user.UserService
+	Clients    *oauthclient.Service
+	Federation *federation.FederationService
+	// Audit serves the audit log API when set.
+	Audit *audit.Service
+	// Webhooks serves the webhook subscription API when set.
+	Webhooks *webhook.Service
+	// APIClients lists the OAuth2 client IDs whose access tokens may call the admin API.
+	// Empty means the API is only reachable with an admin's browser session.
+	APIClients []string
+}
+
+// SCIMRouteConfig holds SCIM provisioning API configuration. Routes are registered only when
+// at least one bearer token is configured.
+type SCIMRouteConfig struct {
+	Service *scim.Service
+	// Tokens are the static bearer tokens accepted from provisioning clients.
+	Tokens []string
+	// BaseURL is the externally visible server URL, used for resource locations.
+	BaseURL string
+}
+
+// MetricsRouteConfig holds Prometheus metrics configuration.
+type MetricsRouteConfig struct {
+	Metrics *metrics.Metrics
+	// Path serves the metrics; defaults to /metrics.
+	Path string
 }
 
 // NewEngine creates a new Gin engine with HTML templates and optional structured logging.
@@ -53,14 +114,48 @@ func NewEngine(logger log.Logger) *gin.Engine {
 	e := gin.New()
 	e.Use(gin.Recovery())
 	e.Use(RequestIDMiddleware())
+	e.Use(ClientInfoMiddleware())
+	e.Use(TracingMiddleware())
 	if logger != nil {
 		e.Use(ZapLoggerMiddleware(logger))
 	}
-	e.LoadHTMLGlob(filepath.Join("internal", "server", "http", "templates", "*.html"))
+	e.SetHTMLTemplate(template.Must(template.New("").Funcs(e.FuncMap).ParseFS(templates.FS, "*.html")))
 	return e
 }
 
+// TracingMiddleware runs each request in a server span named after its route, continuing the
+// trace from the incoming traceparent header when present. The span carries the request_id so
+// that traces and logs can be joined either way.
+func TracingMiddleware() gin.HandlerFunc {
+	tracer := otel.Tracer("github.com/qinzj/superpowers-demo/internal/server/http/handler")
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				attribute.String("request_id", GetRequestID(c)),
+			),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
 // ZapLoggerMiddleware logs each request with method, path, status, request_id, and latency.
+// When the request is traced, trace_id and span_id are logged too.
 func ZapLoggerMiddleware(l log.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -79,6 +174,9 @@ func ZapLoggerMiddleware(l log.Logger) gin.HandlerFunc {
 			zap.Duration("latency", latency),
 			zap.String("client_ip", clientIP),
 		}
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			fields = append(fields, zap.String("trace_id", sc.TraceID().String()), zap.String("span_id", sc.SpanID().String()))
+		}
 		if status >= 500 {
 			l.Error("request", fields...)
 		} else if status >= 400 {
@@ -89,26 +187,65 @@ func ZapLoggerMiddleware(l log.Logger) gin.HandlerFunc {
 	}
 }
 
-// RegisterOIDCRoutes adds OIDC endpoints to the given engine.
-func RegisterOIDCRoutes(e *gin.Engine, cfg *OIDCRouteConfig) {
+// MetricsMiddleware records each request's latency by method, route pattern and status.
+// Requests that match no route are recorded under the route "unmatched".
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// RegisterMetricsRoutes adds the request latency middleware and the metrics endpoint. It must
+// run before other routes are registered so that their handler chains include the middleware.
+func RegisterMetricsRoutes(e *gin.Engine, cfg *MetricsRouteConfig) {
+	if cfg == nil || cfg.Metrics == nil {
+		return
+	}
+	path := cfg.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	e.Use(MetricsMiddleware(cfg.Metrics))
+	e.GET(path, gin.WrapH(cfg.Metrics.Handler()))
+}
+
+// RegisterOIDCRoutes adds OIDC endpoints to the given engine. csrf (see CSRFMiddleware) runs
+// on the HTML consent and device verification pages only.
+func RegisterOIDCRoutes(e *gin.Engine, cfg *OIDCRouteConfig, csrf gin.HandlerFunc) {
 	if cfg == nil || cfg.Provider == nil {
 		return
 	}
-	h := NewOIDCHandler(cfg.Provider, cfg.Issuer, cfg.Auth)
+	h := NewOIDCHandler(cfg.Provider, cfg.Issuer, cfg.Auth, cfg.RBAC)
+	h.Audit = cfg.Audit
+	h.Metrics = cfg.Metrics
 	e.GET("/.well-known/openid-configuration", h.WellKnown)
-	e.GET("/authorize", h.Authorize)
+	e.GET("/authorize", csrf, h.Authorize)
+	e.POST("/authorize", csrf, RequireCSRF(), h.Authorize)
 	e.POST("/token", h.Token)
+	e.POST("/introspect", h.Introspect)
 	e.GET("/userinfo", h.UserInfo)
+	if cfg.Device != nil {
+		d := NewDeviceHandler(cfg.Provider, cfg.Device, cfg.Issuer, cfg.Auth, cfg.RBAC)
+		e.POST(oidc.DeviceAuthorizationPath, d.Code)
+		e.GET(oidc.DeviceVerificationPath, csrf, d.Verify)
+		e.POST(oidc.DeviceVerificationPath, csrf, RequireCSRF(), d.Decide)
+	}
 }
 
-// RegisterLoginRoutes adds login endpoints to the given engine.
-func RegisterLoginRoutes(e *gin.Engine, cfg *LoginRouteConfig) {
+// RegisterLoginRoutes adds login endpoints to the given engine, behind csrf (see CSRFMiddleware).
+func RegisterLoginRoutes(e *gin.Engine, cfg *LoginRouteConfig, csrf gin.HandlerFunc) {
 	if cfg == nil || cfg.Auth == nil {
 		return
 	}
 	h := NewLoginHandler(cfg.Auth, cfg.Federation)
-	e.GET("/login", h.GetLogin)
-	e.POST("/login", h.PostLogin)
+	e.GET("/login", csrf, h.GetLogin)
+	e.POST("/login", csrf, RequireCSRF(), h.PostLogin)
 }
 
 // RegisterFederationRoutes adds federation init and callback endpoints.
@@ -132,22 +269,138 @@ func RegisterHealthRoutes(e *gin.Engine, cfg *HealthRouteConfig) {
 	e.GET("/ready", h.Ready)
 }
 
-// RegisterRegisterRoutes adds registration endpoints to the given engine.
-func RegisterRegisterRoutes(e *gin.Engine, cfg *RegisterRouteConfig) {
+// RegisterRegisterRoutes adds registration endpoints to the given engine, behind csrf (see
+// CSRFMiddleware).
+func RegisterRegisterRoutes(e *gin.Engine, cfg *RegisterRouteConfig, csrf gin.HandlerFunc) {
 	if cfg == nil || cfg.UserService == nil {
 		return
 	}
 	h := NewRegisterHandler(cfg.UserService)
-	e.GET("/register", h.RegisterGet)
-	e.POST("/register", h.RegisterPost)
+	e.GET("/register", csrf, h.RegisterGet)
+	e.POST("/register", csrf, RequireCSRF(), h.RegisterPost)
 }
 
-// RegisterAccountRoutes adds account management endpoints (e.g. delete account).
-func RegisterAccountRoutes(e *gin.Engine, cfg *AccountRouteConfig) {
+// RegisterAccountRoutes adds account self-service endpoints (profile, password, deletion, export).
+// Every route runs csrf (see CSRFMiddleware) and every form POST requires a CSRF token.
+func RegisterAccountRoutes(e *gin.Engine, cfg *AccountRouteConfig, csrf gin.HandlerFunc) {
 	if cfg == nil || cfg.UserService == nil || cfg.Auth == nil {
 		return
 	}
 	h := NewAccountHandler(cfg.UserService, cfg.Auth)
-	e.GET("/account/delete", h.DeleteGet)
-	e.POST("/account/delete", h.DeletePost)
+	h.RBAC = cfg.RBAC
+	h.Audit = cfg.Audit
+	h.Federation = cfg.Federation
+	account := e.Group("/account", csrf)
+	account.GET("", h.ProfileGet)
+	account.POST("/profile", RequireCSRF(), h.ProfilePost)
+	account.POST("/password", RequireCSRF(), h.PasswordPost)
+	account.GET("/verify-email", h.VerifyEmail)
+	account.GET("/sessions", h.SessionsGet)
+	account.POST("/sessions/:id/revoke", RequireCSRF(), h.SessionRevokePost)
+	account.GET("/apps", h.AppsGet)
+	account.POST("/apps/:client_id/revoke", RequireCSRF(), h.AppRevokePost)
+	account.GET("/delete", h.DeleteGet)
+	account.POST("/delete", RequireCSRF(), h.DeletePost)
+	account.GET("/export", h.ExportGet)
+}
+
+// RegisterAdminRoutes adds the admin API under /admin/api and, when configured, the admin console
+// under /admin. Every route requires the admin role. The console runs csrf (see CSRFMiddleware);
+// the API runs it only for session-authenticated requests, which RequireAdmin checks for a token.
+func RegisterAdminRoutes(e *gin.Engine, cfg *AdminRouteConfig, csrf gin.HandlerFunc) {
+	if cfg == nil || cfg.Auth == nil || cfg.RBAC == nil {
+		return
+	}
+	h := NewAdminRBACHandler(cfg.RBAC)
+	api := e.Group("/admin/api", withSession(csrf), RequireAdmin(cfg))
+	api.GET("/groups", h.ListGroups)
+	api.POST("/groups", h.CreateGroup)
+	api.GET("/groups/:id", h.GetGroup)
+	api.DELETE("/groups/:id", h.DeleteGroup)
+	api.GET("/groups/:id/members", h.ListGroupMembers)
+	api.PUT("/groups/:id/members/:user_id", h.AddGroupMember)
+	api.DELETE("/groups/:id/members/:user_id", h.RemoveGroupMember)
+	api.GET("/groups/:id/roles", h.ListGroupRoles)
+	api.PUT("/groups/:id/roles/:role_id", h.AssignGroupRole)
+	api.DELETE("/groups/:id/roles/:role_id", h.UnassignGroupRole)
+	api.GET("/roles", h.ListRoles)
+	api.POST("/roles", h.CreateRole)
+	api.DELETE("/roles/:id", h.DeleteRole)
+	api.GET("/users/:user_id/memberships", h.UserMemberships)
+	api.PUT("/users/:user_id/roles/:role_id", h.AssignUserRole)
+	api.DELETE("/users/:user_id/roles/:role_id", h.UnassignUserRole)
+	if cfg.Audit != nil {
+		ah := NewAdminAuditHandler(cfg.Audit)
+		api.GET("/audit", ah.ListEvents)
+		api.GET("/audit/verify", ah.Verify)
+	}
+	if cfg.Webhooks != nil {
+		wh := NewAdminWebhookHandler(cfg.Webhooks)
+		api.GET("/webhooks", wh.ListWebhooks)
+		api.POST("/webhooks", wh.CreateWebhook)
+		api.GET("/webhooks/:id", wh.GetWebhook)
+		api.PUT("/webhooks/:id", wh.UpdateWebhook)
+		api.DELETE("/webhooks/:id", wh.DeleteWebhook)
+		api.POST("/webhooks/:id/rotate-secret", wh.RotateSecret)
+		api.GET("/webhooks/:id/deliveries", wh.ListDeliveries)
+		api.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", wh.Redeliver)
+	}
+
+	if cfg.Users != nil {
+		registerAdminConsoleRoutes(e, cfg, csrf)
+	}
+}
+
+// registerAdminConsoleRoutes adds the HTML admin console. Every form POST requires a CSRF token.
+func registerAdminConsoleRoutes(e *gin.Engine, cfg *AdminRouteConfig, csrf gin.HandlerFunc) {
+	h := NewAdminConsoleHandler(cfg)
+	console := e.Group("/admin", csrf, RequireAdminConsole(cfg))
+	console.GET("", func(c *gin.Context) { c.Redirect(http.StatusFound, "/admin/users") })
+	console.GET("/users", h.UsersGet)
+	console.GET("/users/:id", h.UserGet)
+	console.POST("/users/:id/status", RequireCSRF(), h.UserStatusPost)
+	console.POST("/users/:id/password", RequireCSRF(), h.UserPasswordPost)
+	console.POST("/users/:id/revoke", RequireCSRF(), h.UserRevokeAllPost)
+	console.POST("/users/:id/sessions/:session_id/revoke", RequireCSRF(), h.UserSessionRevokePost)
+	console.POST("/users/:id/apps/:client_id/revoke", RequireCSRF(), h.UserAppRevokePost)
+	if cfg.Clients != nil {
+		console.GET("/clients", h.ClientsGet)
+		console.POST("/clients", RequireCSRF(), h.ClientCreatePost)
+		console.POST("/clients/:client_id", RequireCSRF(), h.ClientUpdatePost)
+		console.POST("/clients/:client_id/rotate", RequireCSRF(), h.ClientRotatePost)
+		console.POST("/clients/:client_id/delete", RequireCSRF(), h.ClientDeletePost)
+	}
+	if cfg.Federation != nil {
+		console.GET("/connectors", h.ConnectorsGet)
+		console.POST("/connectors", RequireCSRF(), h.ConnectorCreatePost)
+		console.POST("/connectors/:id", RequireCSRF(), h.ConnectorUpdatePost)
+		console.POST("/connectors/:id/delete", RequireCSRF(), h.ConnectorDeletePost)
+	}
+}
+
+// RegisterSCIMRoutes adds the SCIM 2.0 API under /scim/v2. Every route requires a configured
+// bearer token.
+func RegisterSCIMRoutes(e *gin.Engine, cfg *SCIMRouteConfig) {
+	if cfg == nil || cfg.Service == nil || len(cfg.Tokens) == 0 {
+		return
+	}
+	h := NewSCIMHandler(cfg.Service, cfg.BaseURL)
+	g := e.Group(scimPathPrefix, RequireSCIMToken(cfg.Tokens))
+	g.GET("/ServiceProviderConfig", h.ServiceProviderConfig)
+	g.GET("/Schemas", h.Schemas)
+	g.GET("/Schemas/:id", h.Schema)
+	g.GET("/ResourceTypes", h.ResourceTypes)
+	g.GET("/ResourceTypes/:id", h.ResourceType)
+	g.GET("/Users", h.ListUsers)
+	g.POST("/Users", h.CreateUser)
+	g.GET("/Users/:id", h.GetUser)
+	g.PUT("/Users/:id", h.ReplaceUser)
+	g.PATCH("/Users/:id", h.PatchUser)
+	g.DELETE("/Users/:id", h.DeleteUser)
+	g.GET("/Groups", h.ListGroups)
+	g.POST("/Groups", h.CreateGroup)
+	g.GET("/Groups/:id", h.GetGroup)
+	g.PUT("/Groups/:id", h.ReplaceGroup)
+	g.PATCH("/Groups/:id", h.PatchGroup)
+	g.DELETE("/Groups/:id", h.DeleteGroup)
 }
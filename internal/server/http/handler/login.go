@@ -7,6 +7,8 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strings"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,9 +16,6 @@ import (
 	"github.com/qinzj/superpowers-demo/internal/service/federation"
 )
 
-const sessionCookieName = "sso_session"
-const sessionCookieMaxAge = 86400 // 24 hours in seconds
-
 // LoginHandler handles the login page and form submission.
 type LoginHandler struct {
 	Auth       *auth.AuthService
@@ -39,15 +38,30 @@ type LoginParams struct {
 	ResponseType string `form:"response_type"`
 	Scope        string `form:"scope"`
 	State        string `form:"state"`
+	// Next is a local path to return to after signing in instead of /authorize, for pages that
+	// require a session (account, admin console, device verification).
+	Next string `form:"next"`
 }
 
 // LoginForm holds the POST form fields.
 type LoginForm struct {
 	LoginParams
-	Username string `form:"username" binding:"required"`
-	Password string `form:"password" binding:"required"`
+	Username   string `form:"username" binding:"required"`
+	Password   string `form:"password" binding:"required"`
+	RememberMe string `form:"remember_me"`
+}
+
+// loginErrorMessages are shown for the error codes the federation flow redirects back with.
+var loginErrorMessages = map[string]string{
+	"account_inactive":  inactiveAccountMessage,
+	"federation_failed": "Sign-in with the identity provider failed",
+	"email_not_verified": "An account with this email already exists, but the identity provider " +
+		"has not verified the email; sign in with your password instead",
 }
 
+// inactiveAccountMessage is shown when a user who is not active tries to sign in.
+const inactiveAccountMessage = "This account is not active. Contact your administrator."
+
 // GetLogin renders the login page with OAuth2 params preserved as hidden fields.
 // If federation is configured and connectors exist, shows "企业 SSO" links.
 func (h *LoginHandler) GetLogin(c *gin.Context) {
@@ -57,22 +71,24 @@ func (h *LoginHandler) GetLogin(c *gin.Context) {
 		ResponseType: c.Query("response_type"),
 		Scope:        c.Query("scope"),
 		State:        c.Query("state"),
+		Next:         c.Query("next"),
 	}
-	data := loginTemplateData(params, "")
+	data := loginTemplateData(params, loginErrorMessages[c.Query("error")])
 	if h.Federation != nil {
 		connectors, _ := h.Federation.ListConnectors(c.Request.Context())
 		if len(connectors) > 0 {
 			data["Connectors"] = connectors
 		}
 	}
-	c.HTML(http.StatusOK, "login.html", data)
+	renderHTML(c, http.StatusOK, "login.html", data)
 }
 
-// PostLogin processes the login form, validates credentials, creates session, and redirects to /authorize.
+// PostLogin processes the login form, validates credentials, creates session, and redirects to
+// /authorize, or to the next page.
 func (h *LoginHandler) PostLogin(c *gin.Context) {
 	var form LoginForm
 	if err := c.ShouldBind(&form); err != nil {
-		c.HTML(http.StatusBadRequest, "login.html", loginTemplateData(form.LoginParams, "Invalid form"))
+		renderHTML(c, http.StatusBadRequest, "login.html", loginTemplateData(form.LoginParams, "Invalid form"))
 		return
 	}
 
@@ -80,25 +96,33 @@ func (h *LoginHandler) PostLogin(c *gin.Context) {
 	user, err := h.Auth.ValidateCredentials(ctx, form.Username, form.Password)
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
-			c.HTML(http.StatusUnauthorized, "login.html", loginTemplateData(form.LoginParams, "Invalid username or password"))
+			renderHTML(c, http.StatusUnauthorized, "login.html", loginTemplateData(form.LoginParams, "Invalid username or password"))
 			return
 		}
-		c.HTML(http.StatusInternalServerError, "login.html", loginTemplateData(form.LoginParams, "Authentication error"))
+		if errors.Is(err, auth.ErrAccountInactive) {
+			renderHTML(c, http.StatusForbidden, "login.html", loginTemplateData(form.LoginParams, inactiveAccountMessage))
+			return
+		}
+		renderHTML(c, http.StatusInternalServerError, "login.html", loginTemplateData(form.LoginParams, "Authentication error"))
 		return
 	}
 	if user == nil {
-		c.HTML(http.StatusUnauthorized, "login.html", loginTemplateData(form.LoginParams, "Invalid username or password"))
+		renderHTML(c, http.StatusUnauthorized, "login.html", loginTemplateData(form.LoginParams, "Invalid username or password"))
 		return
 	}
 
-	sess, err := h.Auth.CreateSession(ctx, user.ID)
+	sess, err := h.Auth.CreateSession(ctx, user.ID, form.RememberMe == "yes")
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "login.html", loginTemplateData(form.LoginParams, "Session creation failed"))
+		renderHTML(c, http.StatusInternalServerError, "login.html", loginTemplateData(form.LoginParams, "Session creation failed"))
 		return
 	}
 
-	c.SetCookie(sessionCookieName, sess.Token, sessionCookieMaxAge, "/", "", false, true)
+	setSessionCookie(c, sess)
 
+	if next, ok := localPath(form.Next); ok {
+		c.Redirect(http.StatusFound, next)
+		return
+	}
 	authURL := buildAuthorizeURL(form.LoginParams)
 	c.Redirect(http.StatusFound, authURL)
 }
@@ -111,10 +135,28 @@ func loginTemplateData(p LoginParams, errMsg string) gin.H {
 		"ResponseType": p.ResponseType,
 		"Scope":        p.Scope,
 		"State":        p.State,
+		"Next":         p.Next,
 		"Error":        errMsg,
 	}
 }
 
+// localPath reports whether next is a path on this server, so that it is safe to redirect to
+// after signing in. Browsers treat "//host" and "/\host" as other hosts, and drop tabs and
+// newlines before resolving, so any backslash or control character, raw or percent-encoded,
+// rejects next.
+func localPath(next string) (string, bool) {
+	u, err := url.Parse(next)
+	if err != nil || u.Scheme != "" || u.Host != "" || !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") {
+		return "", false
+	}
+	for _, s := range []string{next, u.Path} {
+		if strings.ContainsFunc(s, func(r rune) bool { return r == '\\' || unicode.IsControl(r) }) {
+			return "", false
+		}
+	}
+	return next, true
+}
+
 func buildAuthorizeURL(p LoginParams) string {
 	q := url.Values{}
 	q.Set("client_id", p.ClientID)
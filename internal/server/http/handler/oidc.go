@@ -4,16 +4,23 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/ory/fosite/token/jwt"
 
 	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/metrics"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
 	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
 )
 
 // OIDCHandler handles OIDC/OAuth2 endpoints by delegating to Fosite.
@@ -21,11 +28,17 @@ type OIDCHandler struct {
 	Provider fosite.OAuth2Provider
 	Issuer   string
 	Auth     *auth.AuthService
+	// RBAC supplies the groups and roles claims; nil omits them.
+	RBAC *rbac.Service
+	// Audit records issued tokens; nil disables auditing.
+	Audit *audit.Service
+	// Metrics counts issued tokens; nil disables counting.
+	Metrics *metrics.Metrics
 }
 
 // NewOIDCHandler creates an OIDC handler with the given provider, issuer, and auth service.
-func NewOIDCHandler(provider fosite.OAuth2Provider, issuer string, authSvc *auth.AuthService) *OIDCHandler {
-	return &OIDCHandler{Provider: provider, Issuer: issuer, Auth: authSvc}
+func NewOIDCHandler(provider fosite.OAuth2Provider, issuer string, authSvc *auth.AuthService, rbacSvc *rbac.Service) *OIDCHandler {
+	return &OIDCHandler{Provider: provider, Issuer: issuer, Auth: authSvc, RBAC: rbacSvc}
 }
 
 // WellKnown serves GET /.well-known/openid-configuration (OIDC discovery).
@@ -39,7 +52,8 @@ func (h *OIDCHandler) WellKnown(c *gin.Context) {
 }
 
 // Authorize handles GET /authorize. Validates the request and redirects to login
-// when no session exists, or writes the authorize response (302) when session exists.
+// when no session exists, asks for consent to scopes the user has not allowed the client yet,
+// or writes the authorize response (302). POST /authorize submits the consent form.
 func (h *OIDCHandler) Authorize(c *gin.Context) {
 	ctx := c.Request.Context()
 	ar, err := h.Provider.NewAuthorizeRequest(ctx, c.Request)
@@ -54,6 +68,23 @@ func (h *OIDCHandler) Authorize(c *gin.Context) {
 		return
 	}
 
+	if !h.consent(c, ar, session.Subject) {
+		return
+	}
+	// fosite has already checked the requested scopes and audiences against the client.
+	for _, scope := range ar.GetRequestedScopes() {
+		ar.GrantScope(scope)
+	}
+	for _, aud := range ar.GetRequestedAudience() {
+		ar.GrantAudience(aud)
+	}
+	if ar.GetGrantedScopes().Has(oidc.ScopeGroups) {
+		if err := addMembershipClaims(ctx, h.RBAC, session.Subject, session.Claims.Extra); err != nil {
+			h.Provider.WriteAuthorizeError(ctx, c.Writer, ar, fosite.ErrServerError.WithWrap(err))
+			return
+		}
+	}
+
 	response, err := h.Provider.NewAuthorizeResponse(ctx, ar, session)
 	if err != nil {
 		h.Provider.WriteAuthorizeError(ctx, c.Writer, ar, err)
@@ -62,24 +93,112 @@ func (h *OIDCHandler) Authorize(c *gin.Context) {
 	h.Provider.WriteAuthorizeResponse(ctx, c.Writer, ar, response)
 }
 
+// consent reports whether the user allows the client every requested scope, recording the
+// decision when the consent form is posted. Otherwise it writes the response itself: the consent
+// page, access_denied, or consent_required for prompt=none.
+func (h *OIDCHandler) consent(c *gin.Context, ar fosite.AuthorizeRequester, userID string) bool {
+	ctx := c.Request.Context()
+	clientID := ar.GetClient().GetID()
+	scopes := ar.GetRequestedScopes()
+	if c.Request.Method == http.MethodPost {
+		switch c.PostForm("consent") {
+		case "approve":
+			if err := h.Auth.GrantConsent(ctx, userID, clientID, scopes); err != nil {
+				h.Provider.WriteAuthorizeError(ctx, c.Writer, ar, fosite.ErrServerError.WithWrap(err))
+				return false
+			}
+			return true
+		case "deny":
+			h.Provider.WriteAuthorizeError(ctx, c.Writer, ar, fosite.ErrAccessDenied.WithHint("The user denied the request."))
+			return false
+		}
+	}
+	missing, err := h.Auth.MissingConsent(ctx, userID, clientID, scopes)
+	if err != nil {
+		h.Provider.WriteAuthorizeError(ctx, c.Writer, ar, fosite.ErrServerError.WithWrap(err))
+		return false
+	}
+	prompt := fosite.Arguments(fosite.RemoveEmpty(strings.Split(ar.GetRequestForm().Get("prompt"), " ")))
+	if len(missing) == 0 && !prompt.Has("consent") {
+		return true
+	}
+	if prompt.Has("none") {
+		h.Provider.WriteAuthorizeError(ctx, c.Writer, ar, fosite.ErrConsentRequired)
+		return false
+	}
+	renderHTML(c, http.StatusOK, "consent.html", gin.H{
+		"ClientID": clientID,
+		"Scopes":   scopes,
+		// The form posts the authorize request back with the decision.
+		"Action": "/authorize?" + c.Request.URL.RawQuery,
+	})
+	return false
+}
+
 // Token handles POST /token. Returns JSON with access_token, token_type, etc.
 func (h *OIDCHandler) Token(c *gin.Context) {
 	ctx := c.Request.Context()
-	session := new(fosite.DefaultSession)
+	session := openid.NewDefaultSession()
 	accessRequest, err := h.Provider.NewAccessRequest(ctx, c.Request, session)
 	if err != nil {
 		h.Provider.WriteAccessError(ctx, c.Writer, accessRequest, err)
 		return
 	}
+	if err := h.ensureSubjectActive(ctx, accessRequest); err != nil {
+		h.Provider.WriteAccessError(ctx, c.Writer, accessRequest, err)
+		return
+	}
 
 	response, err := h.Provider.NewAccessResponse(ctx, accessRequest)
 	if err != nil {
 		h.Provider.WriteAccessError(ctx, c.Writer, accessRequest, err)
 		return
 	}
+	h.auditTokenIssued(ctx, accessRequest)
+	if h.Metrics != nil {
+		h.Metrics.TokenIssued(strings.Join(accessRequest.GetGrantTypes(), " "), accessRequest.GetClient().GetID())
+	}
 	h.Provider.WriteAccessResponse(ctx, c.Writer, accessRequest, response)
 }
 
+func (h *OIDCHandler) auditTokenIssued(ctx context.Context, ar fosite.AccessRequester) {
+	if h.Audit == nil {
+		return
+	}
+	subject := ""
+	if ar.GetSession() != nil {
+		subject = ar.GetSession().GetSubject()
+	}
+	h.Audit.Record(ctx, domain.AuditEvent{
+		Type:     domain.AuditTokenIssued,
+		UserID:   subject,
+		ClientID: ar.GetClient().GetID(),
+		Details: map[string]string{
+			"grant_type": strings.Join(ar.GetGrantTypes(), " "),
+			"scope":      strings.Join(ar.GetGrantedScopes(), " "),
+		},
+	})
+}
+
+// ensureSubjectActive rejects code and refresh token grants for users that are no longer active.
+// Suspension revokes tokens already, but a grant may race the revocation or predate it.
+func (h *OIDCHandler) ensureSubjectActive(ctx context.Context, ar fosite.AccessRequester) error {
+	if h.Auth == nil || ar.GetSession() == nil {
+		return nil
+	}
+	subject := ar.GetSession().GetSubject()
+	if subject == "" {
+		return nil
+	}
+	if err := h.Auth.EnsureActive(ctx, subject); err != nil {
+		if errors.Is(err, auth.ErrAccountInactive) {
+			return fosite.ErrInvalidGrant.WithHint("The user account is not active.")
+		}
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	return nil
+}
+
 // UserInfo handles GET /userinfo. Validates Bearer token and returns user claims as JSON.
 func (h *OIDCHandler) UserInfo(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -89,7 +208,7 @@ func (h *OIDCHandler) UserInfo(c *gin.Context) {
 		return
 	}
 
-	session := new(fosite.DefaultSession)
+	session := openid.NewDefaultSession()
 	_, ar, err := h.Provider.IntrospectToken(ctx, token, fosite.AccessToken, session, "openid")
 	if err != nil {
 		WriteErrorWithStatus(c, http.StatusUnauthorized, "invalid_token", "token is invalid or expired")
@@ -97,14 +216,68 @@ func (h *OIDCHandler) UserInfo(c *gin.Context) {
 	}
 
 	claims := userInfoClaims(ar)
+	// Userinfo reports current membership rather than the snapshot taken at login.
+	if sub, _ := claims["sub"].(string); sub != "" && ar.GetGrantedScopes().Has(oidc.ScopeGroups) {
+		if err := addMembershipClaims(ctx, h.RBAC, sub, claims); err != nil {
+			WriteError(c, err, "")
+			return
+		}
+	}
 	c.JSON(http.StatusOK, claims)
 }
 
-func (h *OIDCHandler) sessionFromContext(c *gin.Context) *fosite.DefaultSession {
+// Introspect handles POST /introspect (RFC 7662). The caller authenticates as a client; an
+// active token is described by client_id, scope, aud, sub, exp and, for delegated tokens, act.
+// Other ID token claims are not disclosed.
+func (h *OIDCHandler) Introspect(c *gin.Context) {
+	ctx := c.Request.Context()
+	ir, err := h.Provider.NewIntrospectionRequest(ctx, c.Request, openid.NewDefaultSession())
+	if err != nil {
+		h.Provider.WriteIntrospectionError(ctx, c.Writer, err)
+		return
+	}
+	if session, ok := ir.GetAccessRequester().GetSession().(*openid.DefaultSession); ok {
+		ir.GetAccessRequester().SetSession(introspectionSession{session})
+	}
+	h.Provider.WriteIntrospectionResponse(ctx, c.Writer, ir)
+}
+
+// introspectionSession hands the act claim, and only that, to fosite's introspection response.
+type introspectionSession struct {
+	*openid.DefaultSession
+}
+
+// GetExtraClaims implements fosite.ExtraClaimsSession.
+func (s introspectionSession) GetExtraClaims() map[string]interface{} {
+	claims := map[string]interface{}{}
+	if s.Claims == nil {
+		return claims
+	}
+	if act, ok := s.Claims.Extra[oidc.ClaimActor]; ok {
+		claims[oidc.ClaimActor] = act
+	}
+	return claims
+}
+
+// addMembershipClaims sets the "groups" and "roles" claims for subject; a nil rbacSvc omits them.
+func addMembershipClaims(ctx context.Context, rbacSvc *rbac.Service, subject string, claims map[string]interface{}) error {
+	if rbacSvc == nil {
+		return nil
+	}
+	groups, roles, err := rbacSvc.Memberships(ctx, subject)
+	if err != nil {
+		return err
+	}
+	claims["groups"] = groups
+	claims["roles"] = roles
+	return nil
+}
+
+func (h *OIDCHandler) sessionFromContext(c *gin.Context) *openid.DefaultSession {
 	if h.Auth == nil {
 		return nil
 	}
-	token, _ := c.Cookie(sessionCookieName)
+	token := sessionToken(c)
 	if token == "" {
 		return nil
 	}
@@ -116,10 +289,13 @@ func (h *OIDCHandler) sessionFromContext(c *gin.Context) *fosite.DefaultSession
 	return userToFositeSession(user)
 }
 
-func userToFositeSession(u *domain.User) *fosite.DefaultSession {
-	s := &fosite.DefaultSession{}
+func userToFositeSession(u *domain.User) *openid.DefaultSession {
+	s := openid.NewDefaultSession()
 	s.SetSubject(u.ID)
 	s.Username = u.Username
+	s.Claims.Subject = u.ID
+	s.Claims.Extra = map[string]interface{}{"preferred_username": u.Username}
+	s.Headers = &jwt.Headers{}
 	return s
 }
 
@@ -149,8 +325,8 @@ func userInfoClaims(ar fosite.AccessRequester) map[string]interface{} {
 	if username := sess.GetUsername(); username != "" {
 		claims["preferred_username"] = username
 	}
-	if ext, ok := sess.(interface{ GetExtraClaims() map[string]interface{} }); ok {
-		for k, v := range ext.GetExtraClaims() {
+	if oidcSess, ok := sess.(openid.Session); ok && oidcSess.IDTokenClaims() != nil {
+		for k, v := range oidcSess.IDTokenClaims().Extra {
 			claims[k] = v
 		}
 	}
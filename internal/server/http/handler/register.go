@@ -27,7 +27,7 @@ func NewRegisterHandler(svc *user.UserService) *RegisterHandler {
 
 // RegisterGet renders the registration form.
 func (h *RegisterHandler) RegisterGet(c *gin.Context) {
-	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(registerFormHTML("", "", "")))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(registerFormHTML("", "", "", CSRFToken(c))))
 }
 
 // RegisterPost processes the registration form.
@@ -35,7 +35,7 @@ func (h *RegisterHandler) RegisterPost(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.Data(http.StatusBadRequest, "text/html; charset=utf-8",
-			[]byte(registerFormHTML("Invalid input", req.Username, req.Email)))
+			[]byte(registerFormHTML("Invalid input", req.Username, req.Email, CSRFToken(c))))
 		return
 	}
 
@@ -43,23 +43,28 @@ func (h *RegisterHandler) RegisterPost(c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, user.ErrUsernameTaken) {
 			c.Data(http.StatusConflict, "text/html; charset=utf-8",
-				[]byte(registerFormHTML("Username already taken", req.Username, req.Email)))
+				[]byte(registerFormHTML("Username already taken", req.Username, req.Email, CSRFToken(c))))
+			return
+		}
+		if errors.Is(err, user.ErrEmailTaken) {
+			c.Data(http.StatusConflict, "text/html; charset=utf-8",
+				[]byte(registerFormHTML("Email already in use", req.Username, req.Email, CSRFToken(c))))
 			return
 		}
 		if errors.Is(err, user.ErrWeakPassword) {
 			c.Data(http.StatusBadRequest, "text/html; charset=utf-8",
-				[]byte(registerFormHTML("Password must be at least 8 characters", req.Username, req.Email)))
+				[]byte(registerFormHTML("Password must be at least 8 characters", req.Username, req.Email, CSRFToken(c))))
 			return
 		}
 		c.Data(http.StatusInternalServerError, "text/html; charset=utf-8",
-			[]byte(registerFormHTML("Registration failed", req.Username, req.Email)))
+			[]byte(registerFormHTML("Registration failed", req.Username, req.Email, CSRFToken(c))))
 		return
 	}
 
 	c.Redirect(http.StatusFound, "/login")
 }
 
-func registerFormHTML(errMsg, username, email string) string {
+func registerFormHTML(errMsg, username, email, csrfToken string) string {
 	errBlock := ""
 	if errMsg != "" {
 		errBlock = fmt.Sprintf(`<p style="color:red;">%s</p>`, html.EscapeString(errMsg))
@@ -73,6 +78,7 @@ func registerFormHTML(errMsg, username, email string) string {
 	<h1>Register</h1>
 	%s
 	<form method="POST" action="/register">
+		<input type="hidden" name="csrf_token" value="%s">
 		<label>Username: <input name="username" value="%s" required maxlength="64"></label><br>
 		<label>Email: <input name="email" type="email" value="%s" required></label><br>
 		<label>Password: <input name="password" type="password" required minlength="8"></label><br>
@@ -80,5 +86,5 @@ func registerFormHTML(errMsg, username, email string) string {
 	</form>
 	<p><a href="/login">Back to Login</a></p>
 </body>
-</html>`, errBlock, userVal, emailVal)
+</html>`, errBlock, html.EscapeString(csrfToken), userVal, emailVal)
 }
@@ -0,0 +1,615 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ory/fosite"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+	"github.com/qinzj/superpowers-demo/internal/service/scim"
+)
+
+const (
+	scimContentType     = "application/scim+json"
+	scimDefaultCount    = 100
+	scimMaxCount        = 200
+	scimPathPrefix      = "/scim/v2"
+	errSCIMInvalidPatch = "invalid patch operation"
+)
+
+// errSCIMSyntax marks request bodies that cannot be parsed.
+var errSCIMSyntax = errors.New("invalid request syntax")
+
+// SCIMHandler serves the SCIM 2.0 provisioning API.
+type SCIMHandler struct {
+	SCIM *scim.Service
+	// BaseURL is the externally visible server URL used in resource locations.
+	BaseURL string
+}
+
+// NewSCIMHandler creates a SCIMHandler with the given service and base URL.
+func NewSCIMHandler(svc *scim.Service, baseURL string) *SCIMHandler {
+	return &SCIMHandler{SCIM: svc, BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// RequireSCIMToken accepts requests whose bearer token equals one of tokens. Tokens are compared
+// by SHA-256 digest in constant time.
+func RequireSCIMToken(tokens []string) gin.HandlerFunc {
+	digests := make([][sha256.Size]byte, len(tokens))
+	for i, t := range tokens {
+		digests[i] = sha256.Sum256([]byte(t))
+	}
+	return func(c *gin.Context) {
+		token := fosite.AccessTokenFromRequest(c.Request)
+		sum := sha256.Sum256([]byte(token))
+		ok := 0
+		for _, d := range digests {
+			ok |= subtle.ConstantTimeCompare(sum[:], d[:])
+		}
+		if token == "" || ok != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			writeSCIMErrorStatus(c, http.StatusUnauthorized, "", "missing or invalid bearer token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ListUsers handles GET /scim/v2/Users with filter, startIndex and count.
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	f, startIndex, count, err := scimListParams(c)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	users, total, err := h.SCIM.ListUsers(c.Request.Context(), f, startIndex, count)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	out := make([]dto.SCIMUser, len(users))
+	for i, u := range users {
+		out[i] = h.userResource(u)
+	}
+	writeSCIMList(c, out, total, startIndex)
+}
+
+// GetUser handles GET /scim/v2/Users/:id.
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	u, err := h.SCIM.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, h.userResource(u))
+}
+
+// CreateUser handles POST /scim/v2/Users.
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var req dto.SCIMUser
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, errSCIMSyntax)
+		return
+	}
+	u, err := h.SCIM.CreateUser(c.Request.Context(), userAttrs(&req))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", h.location("Users", u.ID))
+	writeSCIM(c, http.StatusCreated, h.userResource(u))
+}
+
+// ReplaceUser handles PUT /scim/v2/Users/:id.
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	var req dto.SCIMUser
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, errSCIMSyntax)
+		return
+	}
+	u, err := h.SCIM.ReplaceUser(c.Request.Context(), c.Param("id"), userAttrs(&req))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, h.userResource(u))
+}
+
+// PatchUser handles PATCH /scim/v2/Users/:id by applying the operations to the current resource
+// and replacing it.
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var req dto.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, errSCIMSyntax)
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	u, err := h.SCIM.GetUser(ctx, id)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	res := h.userResource(u)
+	// The status changes only when an operation sets active.
+	res.Active = nil
+	for _, op := range req.Operations {
+		if err := applyUserPatch(&res, op); err != nil {
+			writeSCIMError(c, err)
+			return
+		}
+	}
+	u, err = h.SCIM.ReplaceUser(ctx, id, userAttrs(&res))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, h.userResource(u))
+}
+
+// DeleteUser handles DELETE /scim/v2/Users/:id. The user is deactivated, not deleted.
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	if err := h.SCIM.DeactivateUser(c.Request.Context(), c.Param("id")); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups handles GET /scim/v2/Groups with filter, startIndex and count.
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	f, startIndex, count, err := scimListParams(c)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	ctx := c.Request.Context()
+	groups, total, err := h.SCIM.ListGroups(ctx, f, startIndex, count)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	out := make([]dto.SCIMGroup, len(groups))
+	for i, g := range groups {
+		_, members, err := h.SCIM.GetGroup(ctx, g.ID)
+		if err != nil {
+			writeSCIMError(c, err)
+			return
+		}
+		out[i] = h.groupResource(g, members)
+	}
+	writeSCIMList(c, out, total, startIndex)
+}
+
+// GetGroup handles GET /scim/v2/Groups/:id.
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	g, members, err := h.SCIM.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, h.groupResource(g, members))
+}
+
+// CreateGroup handles POST /scim/v2/Groups.
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var req dto.SCIMGroup
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, errSCIMSyntax)
+		return
+	}
+	ctx := c.Request.Context()
+	g, err := h.SCIM.CreateGroup(ctx, req.DisplayName, memberIDs(req.Members))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	h.writeGroup(c, http.StatusCreated, g.ID)
+}
+
+// ReplaceGroup handles PUT /scim/v2/Groups/:id.
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	var req dto.SCIMGroup
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, errSCIMSyntax)
+		return
+	}
+	id := c.Param("id")
+	if _, err := h.SCIM.ReplaceGroup(c.Request.Context(), id, req.DisplayName, memberIDs(req.Members)); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	h.writeGroup(c, http.StatusOK, id)
+}
+
+// PatchGroup handles PATCH /scim/v2/Groups/:id, typically used to add and remove members.
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	var req dto.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeSCIMError(c, errSCIMSyntax)
+		return
+	}
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	g, members, err := h.SCIM.GetGroup(ctx, id)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	res := h.groupResource(g, members)
+	for _, op := range req.Operations {
+		if err := applyGroupPatch(&res, op); err != nil {
+			writeSCIMError(c, err)
+			return
+		}
+	}
+	if _, err := h.SCIM.ReplaceGroup(ctx, id, res.DisplayName, memberIDs(res.Members)); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	h.writeGroup(c, http.StatusOK, id)
+}
+
+// DeleteGroup handles DELETE /scim/v2/Groups/:id.
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	if err := h.SCIM.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) writeGroup(c *gin.Context, status int, id string) {
+	g, members, err := h.SCIM.GetGroup(c.Request.Context(), id)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	if status == http.StatusCreated {
+		c.Header("Location", h.location("Groups", g.ID))
+	}
+	writeSCIM(c, status, h.groupResource(g, members))
+}
+
+func (h *SCIMHandler) location(resource, id string) string {
+	return h.BaseURL + scimPathPrefix + "/" + resource + "/" + id
+}
+
+func (h *SCIMHandler) userResource(u *domain.User) dto.SCIMUser {
+	active := u.Active()
+	created := u.CreatedAt
+	res := dto.SCIMUser{
+		Schemas:     []string{dto.SCIMUserSchema},
+		ID:          u.ID,
+		ExternalID:  u.ExternalID,
+		UserName:    u.Username,
+		DisplayName: u.DisplayName,
+		Active:      &active,
+		Meta:        &dto.SCIMMeta{ResourceType: "User", Created: &created, Location: h.location("Users", u.ID)},
+	}
+	if u.DisplayName != "" {
+		res.Name = &dto.SCIMName{Formatted: u.DisplayName}
+	}
+	if u.Email != "" {
+		res.Emails = []dto.SCIMEmail{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	return res
+}
+
+func (h *SCIMHandler) groupResource(g *domain.Group, members []*domain.User) dto.SCIMGroup {
+	created := g.CreatedAt
+	res := dto.SCIMGroup{
+		Schemas:     []string{dto.SCIMGroupSchema},
+		ID:          g.ID,
+		DisplayName: g.Name,
+		Members:     make([]dto.SCIMMember, len(members)),
+		Meta:        &dto.SCIMMeta{ResourceType: "Group", Created: &created, Location: h.location("Groups", g.ID)},
+	}
+	for i, m := range members {
+		res.Members[i] = dto.SCIMMember{Value: m.ID, Display: m.Username, Ref: h.location("Users", m.ID)}
+	}
+	return res
+}
+
+// userAttrs converts a SCIM user to the service's attributes. A missing active is passed on as
+// nil, and name.formatted stands in for a missing displayName.
+func userAttrs(u *dto.SCIMUser) scim.UserAttrs {
+	displayName := u.DisplayName
+	if displayName == "" && u.Name != nil {
+		displayName = u.Name.Formatted
+		if displayName == "" {
+			displayName = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+	return scim.UserAttrs{
+		UserName:    u.UserName,
+		ExternalID:  u.ExternalID,
+		DisplayName: displayName,
+		Email:       u.PrimaryEmail(),
+		Password:    u.Password,
+		Active:      u.Active,
+	}
+}
+
+func memberIDs(members []dto.SCIMMember) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.Value)
+	}
+	return ids
+}
+
+// applyUserPatch applies one PATCH operation to u. Attributes this server does not store
+// (e.g. extension schemas) are ignored.
+func applyUserPatch(u *dto.SCIMUser, op dto.SCIMPatchOp) error {
+	kind := strings.ToLower(op.Op)
+	if kind != "add" && kind != "replace" && kind != "remove" {
+		return fmt.Errorf("%w: %s: unknown op %q", scim.ErrInvalidValue, errSCIMInvalidPatch, op.Op)
+	}
+	if op.Path == "" {
+		if kind == "remove" {
+			return fmt.Errorf("%w: %s: remove requires a path", scim.ErrInvalidValue, errSCIMInvalidPatch)
+		}
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return fmt.Errorf("%w: %s: value must be an object", scim.ErrInvalidValue, errSCIMInvalidPatch)
+		}
+		for name, raw := range attrs {
+			if err := setUserAttr(u, name, raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if kind == "remove" {
+		return setUserAttr(u, op.Path, nil)
+	}
+	return setUserAttr(u, op.Path, op.Value)
+}
+
+// setUserAttr sets the attribute at path to raw; a nil raw clears it.
+func setUserAttr(u *dto.SCIMUser, path string, raw json.RawMessage) error {
+	p := strings.ToLower(strings.TrimPrefix(path, dto.SCIMUserSchema+":"))
+	switch {
+	case p == "username":
+		return decodeSCIMString(raw, &u.UserName)
+	case p == "displayname":
+		u.Name = nil
+		return decodeSCIMString(raw, &u.DisplayName)
+	case p == "externalid":
+		return decodeSCIMString(raw, &u.ExternalID)
+	case p == "password":
+		return decodeSCIMString(raw, &u.Password)
+	case p == "name":
+		u.Name = nil
+		if raw == nil {
+			u.DisplayName = ""
+			return nil
+		}
+		var name dto.SCIMName
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return fmt.Errorf("%w: name", scim.ErrInvalidValue)
+		}
+		u.Name, u.DisplayName = &name, ""
+		return nil
+	case p == "name.formatted":
+		u.Name = nil
+		return decodeSCIMString(raw, &u.DisplayName)
+	case p == "active":
+		active := raw == nil
+		if raw != nil {
+			var err error
+			if active, err = decodeSCIMBool(raw); err != nil {
+				return err
+			}
+		}
+		u.Active = &active
+		return nil
+	case p == "emails":
+		u.Emails = nil
+		if raw == nil {
+			return nil
+		}
+		if err := json.Unmarshal(raw, &u.Emails); err != nil {
+			return fmt.Errorf("%w: emails", scim.ErrInvalidValue)
+		}
+		return nil
+	case strings.HasPrefix(p, "emails"):
+		// emails.value or emails[type eq "work"].value: the user has a single address.
+		var email string
+		if err := decodeSCIMString(raw, &email); err != nil {
+			return err
+		}
+		u.Emails = []dto.SCIMEmail{{Value: email, Type: "work", Primary: true}}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// applyGroupPatch applies one PATCH operation to g.
+func applyGroupPatch(g *dto.SCIMGroup, op dto.SCIMPatchOp) error {
+	kind := strings.ToLower(op.Op)
+	path := strings.ToLower(strings.TrimSpace(op.Path))
+	switch {
+	case kind != "add" && kind != "replace" && kind != "remove":
+		return fmt.Errorf("%w: %s: unknown op %q", scim.ErrInvalidValue, errSCIMInvalidPatch, op.Op)
+	case path == "" && kind != "remove":
+		var attrs struct {
+			DisplayName *string          `json:"displayName"`
+			Members     []dto.SCIMMember `json:"members"`
+		}
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return fmt.Errorf("%w: %s: value must be an object", scim.ErrInvalidValue, errSCIMInvalidPatch)
+		}
+		if attrs.DisplayName != nil {
+			g.DisplayName = *attrs.DisplayName
+		}
+		if attrs.Members != nil {
+			if kind == "replace" {
+				g.Members = nil
+			}
+			g.Members = append(g.Members, attrs.Members...)
+		}
+		return nil
+	case path == "displayname" && kind != "remove":
+		return decodeSCIMString(op.Value, &g.DisplayName)
+	case path == "members":
+		var members []dto.SCIMMember
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return fmt.Errorf("%w: members", scim.ErrInvalidValue)
+			}
+		}
+		switch kind {
+		case "add":
+			g.Members = append(g.Members, members...)
+		case "replace":
+			g.Members = members
+		default:
+			if len(members) == 0 {
+				g.Members = nil
+			}
+			g.Members = removeMembers(g.Members, memberIDs(members))
+		}
+		return nil
+	case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]") && kind == "remove":
+		f, err := scim.ParseFilter(op.Path[len("members[") : len(op.Path)-1])
+		if err != nil || f == nil || !f.Is("value") {
+			return fmt.Errorf("%w: %s: unsupported member selector", scim.ErrInvalidValue, errSCIMInvalidPatch)
+		}
+		g.Members = removeMembers(g.Members, []string{f.Value})
+		return nil
+	default:
+		return fmt.Errorf("%w: %s: unsupported path %q", scim.ErrInvalidValue, errSCIMInvalidPatch, op.Path)
+	}
+}
+
+func removeMembers(members []dto.SCIMMember, ids []string) []dto.SCIMMember {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	out := members[:0]
+	for _, m := range members {
+		if !drop[m.Value] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func decodeSCIMString(raw json.RawMessage, dst *string) error {
+	if raw == nil {
+		*dst = ""
+		return nil
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("%w: expected a string", scim.ErrInvalidValue)
+	}
+	return nil
+}
+
+// decodeSCIMBool accepts JSON booleans and the "True"/"False" strings some clients send.
+func decodeSCIMBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("%w: expected a boolean", scim.ErrInvalidValue)
+}
+
+// scimListParams reads filter, startIndex (1-based, default 1) and count (default 100, capped at
+// 200) from the query.
+func scimListParams(c *gin.Context) (*scim.Filter, int, int, error) {
+	f, err := scim.ParseFilter(c.Query("filter"))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	startIndex, err := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: startIndex", scim.ErrInvalidValue)
+	}
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(scimDefaultCount)))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: count", scim.ErrInvalidValue)
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+	return f, startIndex, count, nil
+}
+
+func writeSCIMList[T any](c *gin.Context, resources []T, total, startIndex int) {
+	writeSCIM(c, http.StatusOK, dto.SCIMListResponse{
+		Schemas:      []string{dto.SCIMListResponseSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func writeSCIM(c *gin.Context, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeSCIMErrorStatus(c, http.StatusInternalServerError, "", "An unexpected error occurred")
+		return
+	}
+	c.Data(status, scimContentType, body)
+}
+
+// writeSCIMError maps scim errors to SCIM error responses (RFC 7644 section 3.12).
+func writeSCIMError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, scim.ErrNotFound):
+		writeSCIMErrorStatus(c, http.StatusNotFound, "", "resource not found")
+	case errors.Is(err, scim.ErrUniqueness):
+		writeSCIMErrorStatus(c, http.StatusConflict, "uniqueness", err.Error())
+	case errors.Is(err, scim.ErrInvalidFilter):
+		writeSCIMErrorStatus(c, http.StatusBadRequest, "invalidFilter", err.Error())
+	case errors.Is(err, scim.ErrMutability):
+		writeSCIMErrorStatus(c, http.StatusBadRequest, "mutability", err.Error())
+	case errors.Is(err, scim.ErrInvalidValue):
+		writeSCIMErrorStatus(c, http.StatusBadRequest, "invalidValue", err.Error())
+	case errors.Is(err, errSCIMSyntax):
+		writeSCIMErrorStatus(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+	default:
+		writeSCIMErrorStatus(c, http.StatusInternalServerError, "", "An unexpected error occurred")
+	}
+}
+
+func writeSCIMErrorStatus(c *gin.Context, status int, scimType, detail string) {
+	body, _ := json.Marshal(dto.SCIMError{
+		Schemas:  []string{dto.SCIMErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+	c.Data(status, scimContentType, body)
+}
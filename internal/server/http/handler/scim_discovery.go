@@ -0,0 +1,142 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qinzj/superpowers-demo/internal/server/http/handler/dto"
+)
+
+// scimAttr describes one attribute in a SCIM schema definition (RFC 7643 section 7).
+type scimAttr struct {
+	Name          string     `json:"name"`
+	Type          string     `json:"type"`
+	MultiValued   bool       `json:"multiValued"`
+	Required      bool       `json:"required"`
+	CaseExact     bool       `json:"caseExact"`
+	Mutability    string     `json:"mutability"`
+	Returned      string     `json:"returned"`
+	Uniqueness    string     `json:"uniqueness"`
+	SubAttributes []scimAttr `json:"subAttributes,omitempty"`
+}
+
+func scimString(name string, required bool, uniqueness string) scimAttr {
+	return scimAttr{Name: name, Type: "string", Required: required, Mutability: "readWrite", Returned: "default", Uniqueness: uniqueness}
+}
+
+var scimSchemas = []gin.H{
+	{
+		"schemas":     []string{dto.SCIMSchemaSchema},
+		"id":          dto.SCIMUserSchema,
+		"name":        "User",
+		"description": "User Account",
+		"attributes": []scimAttr{
+			scimString("userName", true, "server"),
+			scimString("externalId", false, "none"),
+			scimString("displayName", false, "none"),
+			{Name: "name", Type: "complex", Mutability: "readWrite", Returned: "default", Uniqueness: "none", SubAttributes: []scimAttr{
+				scimString("formatted", false, "none"),
+				scimString("givenName", false, "none"),
+				scimString("familyName", false, "none"),
+			}},
+			{Name: "emails", Type: "complex", MultiValued: true, Mutability: "readWrite", Returned: "default", Uniqueness: "none", SubAttributes: []scimAttr{
+				scimString("value", false, "none"),
+				scimString("type", false, "none"),
+				{Name: "primary", Type: "boolean", Mutability: "readWrite", Returned: "default", Uniqueness: "none"},
+			}},
+			{Name: "active", Type: "boolean", Mutability: "readWrite", Returned: "default", Uniqueness: "none"},
+			{Name: "password", Type: "string", Mutability: "writeOnly", Returned: "never", Uniqueness: "none"},
+		},
+		"meta": gin.H{"resourceType": "Schema", "location": scimPathPrefix + "/Schemas/" + dto.SCIMUserSchema},
+	},
+	{
+		"schemas":     []string{dto.SCIMSchemaSchema},
+		"id":          dto.SCIMGroupSchema,
+		"name":        "Group",
+		"description": "Group",
+		"attributes": []scimAttr{
+			scimString("displayName", true, "server"),
+			{Name: "members", Type: "complex", MultiValued: true, Mutability: "readWrite", Returned: "default", Uniqueness: "none", SubAttributes: []scimAttr{
+				{Name: "value", Type: "string", Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+				{Name: "display", Type: "string", Mutability: "readOnly", Returned: "default", Uniqueness: "none"},
+				{Name: "$ref", Type: "reference", Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+			}},
+		},
+		"meta": gin.H{"resourceType": "Schema", "location": scimPathPrefix + "/Schemas/" + dto.SCIMGroupSchema},
+	},
+}
+
+var scimResourceTypes = []gin.H{
+	{
+		"schemas":     []string{dto.SCIMResourceTypeSchema},
+		"id":          "User",
+		"name":        "User",
+		"endpoint":    "/Users",
+		"description": "User Account",
+		"schema":      dto.SCIMUserSchema,
+		"meta":        gin.H{"resourceType": "ResourceType", "location": scimPathPrefix + "/ResourceTypes/User"},
+	},
+	{
+		"schemas":     []string{dto.SCIMResourceTypeSchema},
+		"id":          "Group",
+		"name":        "Group",
+		"endpoint":    "/Groups",
+		"description": "Group",
+		"schema":      dto.SCIMGroupSchema,
+		"meta":        gin.H{"resourceType": "ResourceType", "location": scimPathPrefix + "/ResourceTypes/Group"},
+	},
+}
+
+// ServiceProviderConfig handles GET /scim/v2/ServiceProviderConfig.
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	writeSCIM(c, http.StatusOK, gin.H{
+		"schemas":        []string{dto.SCIMServiceProviderSchema},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": scimMaxCount},
+		"changePassword": gin.H{"supported": true},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with a static bearer token from scim.bearer_tokens",
+			"primary":     true,
+		}},
+		"meta": gin.H{"resourceType": "ServiceProviderConfig", "location": h.BaseURL + scimPathPrefix + "/ServiceProviderConfig"},
+	})
+}
+
+// Schemas handles GET /scim/v2/Schemas.
+func (h *SCIMHandler) Schemas(c *gin.Context) {
+	writeSCIMList(c, scimSchemas, len(scimSchemas), 1)
+}
+
+// Schema handles GET /scim/v2/Schemas/:id.
+func (h *SCIMHandler) Schema(c *gin.Context) {
+	writeSCIMDiscoveryItem(c, scimSchemas, c.Param("id"))
+}
+
+// ResourceTypes handles GET /scim/v2/ResourceTypes.
+func (h *SCIMHandler) ResourceTypes(c *gin.Context) {
+	writeSCIMList(c, scimResourceTypes, len(scimResourceTypes), 1)
+}
+
+// ResourceType handles GET /scim/v2/ResourceTypes/:id.
+func (h *SCIMHandler) ResourceType(c *gin.Context) {
+	writeSCIMDiscoveryItem(c, scimResourceTypes, c.Param("id"))
+}
+
+func writeSCIMDiscoveryItem(c *gin.Context, items []gin.H, id string) {
+	for _, item := range items {
+		if item["id"] == id {
+			writeSCIM(c, http.StatusOK, item)
+			return
+		}
+	}
+	writeSCIMErrorStatus(c, http.StatusNotFound, "", "resource not found")
+}
@@ -0,0 +1,13 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package templates embeds the HTML pages rendered by the handlers, so that the binary does not
+// depend on its working directory.
+package templates
+
+import "embed"
+
+// FS holds the *.html templates.
+//
+//go:embed *.html
+var FS embed.FS
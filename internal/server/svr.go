@@ -1,10 +1,235 @@
 // Package server provides the HTTP server context and lifecycle.
 package server
 
-// Server is a placeholder for the application HTTP server.
-type Server struct{}
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
 
-// New creates a new Server (placeholder).
-func New() *Server {
-	return &Server{}
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// Config holds settings matching the "server" section of settings.yaml.
+type Config struct {
+	Port              int           `mapstructure:"port"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"` // time to read request headers
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`        // time to read the whole request
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`       // time from end of headers to end of response
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`        // keep-alive idle time
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`    // grace period for in-flight requests and workers
+	TLS               TLSConfig     `mapstructure:"tls"`
+}
+
+// DefaultConfig returns the settings used for keys missing from the configuration.
+func DefaultConfig() Config {
+	return Config{
+		Port:              8888,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		ShutdownTimeout:   30 * time.Second,
+		TLS: TLSConfig{
+			ClientAuth:     ClientAuthRequire,
+			MinVersion:     "1.2",
+			ReloadInterval: time.Minute,
+		},
+	}
+}
+
+// Validate reports settings the server cannot start with.
+func (c Config) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("server.port must be between 0 and 65535, got %d", c.Port)
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("server.shutdown_timeout must be positive")
+	}
+	return c.TLS.Validate()
+}
+
+// Addr returns the listen address.
+func (c Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+// Worker is a background task that runs until its context is cancelled.
+type Worker func(ctx context.Context)
+
+type namedWorker struct {
+	name string
+	run  Worker
+}
+
+// Server owns the HTTP listener and the background workers. Run starts them and, when its
+// context is cancelled, stops accepting connections, drains in-flight requests and then stops
+// the workers in reverse order of registration.
+type Server struct {
+	cfg     Config
+	handler http.Handler
+	logger  log.Logger
+	workers []namedWorker
+	certs   *certReloader
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger sets the logger used for lifecycle events.
+func WithLogger(l log.Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// New creates a Server that serves handler. When TLS is configured the certificate and client CA
+// files are loaded now, so that a bad path fails at startup rather than on the first handshake.
+func New(cfg Config, handler http.Handler, opts ...Option) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Server{cfg: cfg, handler: handler}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if cfg.TLS.Enabled() {
+		certs, err := newCertReloader(cfg.TLS, s.logger)
+		if err != nil {
+			return nil, err
+		}
+		s.certs = certs
+	}
+	return s, nil
+}
+
+// TLS reports whether the server serves HTTPS.
+func (s *Server) TLS() bool {
+	return s.certs != nil
+}
+
+// AddWorker registers a background task started by Run. Workers are stopped in reverse order
+// of registration, after the HTTP server has drained, so a worker may rely on those added
+// before it.
+func (s *Server) AddWorker(name string, w Worker) {
+	s.workers = append(s.workers, namedWorker{name: name, run: w})
+}
+
+// ReloadCertificates reloads the TLS certificate and client CAs from disk. It is a no-op without
+// TLS. Files are also reloaded automatically when they change.
+func (s *Server) ReloadCertificates() error {
+	if s.certs == nil {
+		return nil
+	}
+	return s.certs.reload()
+}
+
+// Run listens on the configured port and serves until ctx is cancelled; see Serve.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.cfg.Addr())
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled or serving fails, then shuts down
+// gracefully: in-flight requests get up to ShutdownTimeout to finish, after which the workers
+// are stopped one by one. It returns nil after a shutdown triggered by ctx.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: s.cfg.ReadHeaderTimeout,
+		ReadTimeout:       s.cfg.ReadTimeout,
+		WriteTimeout:      s.cfg.WriteTimeout,
+		IdleTimeout:       s.cfg.IdleTimeout,
+	}
+	if s.certs != nil {
+		srv.TLSConfig = s.certs.tlsConfig()
+	}
+
+	stops := make([]func(), 0, len(s.workers)+1)
+	if s.certs != nil {
+		stops = append(stops, s.start("tls_reloader", s.certs.watch))
+	}
+	for _, w := range s.workers {
+		stops = append(stops, s.start(w.name, w.run))
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.certs != nil {
+			// The certificate comes from TLSConfig.GetConfigForClient.
+			serveErr <- srv.ServeTLS(ln, "", "")
+			return
+		}
+		serveErr <- srv.Serve(ln)
+	}()
+	s.info("server started", zap.String("addr", ln.Addr().String()), zap.Bool("tls", s.certs != nil))
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-serveErr:
+		err = fmt.Errorf("serve: %w", err)
+	}
+
+	s.info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if serr := srv.Shutdown(shutdownCtx); serr != nil {
+		s.warn("drain requests", zap.Error(serr))
+		_ = srv.Close()
+	}
+	for i := len(stops) - 1; i >= 0; i-- {
+		stops[i]()
+	}
+	s.info("server stopped")
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// start runs w in a goroutine and returns a function that cancels it and waits for it to
+// return, giving up after ShutdownTimeout.
+func (s *Server) start(name string, w Worker) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w(ctx)
+	}()
+	return func() {
+		cancel()
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			s.info("worker stopped", zap.String("worker", name))
+		case <-time.After(s.cfg.ShutdownTimeout):
+			s.warn("worker did not stop in time", zap.String("worker", name))
+		}
+	}
+}
+
+func (s *Server) info(msg string, fields ...zap.Field) {
+	if s.logger != nil {
+		s.logger.Info(msg, fields...)
+	}
+}
+
+func (s *Server) warn(msg string, fields ...zap.Field) {
+	if s.logger != nil {
+		s.logger.Warn(msg, fields...)
+	}
 }
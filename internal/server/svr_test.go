@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.ShutdownTimeout = 5 * time.Second
+	return cfg
+}
+
+func serve(t *testing.T, s *Server) (addr string, cancel context.CancelFunc, done <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Serve(ctx, ln) }()
+	return ln.Addr().String(), cancel, errc
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, DefaultConfig().Validate())
+
+	cfg := DefaultConfig()
+	cfg.TLS.KeyFile = "key.pem"
+	require.ErrorContains(t, cfg.Validate(), "cert_file is required")
+
+	cfg = DefaultConfig()
+	cfg.TLS.CertFile = "cert.pem"
+	require.ErrorContains(t, cfg.Validate(), "key_file is required")
+
+	cfg.TLS.KeyFile = "key.pem"
+	cfg.TLS.MinVersion = "1.0"
+	require.ErrorContains(t, cfg.Validate(), "min_version")
+
+	cfg.TLS.MinVersion = "1.3"
+	cfg.TLS.ClientAuth = "maybe"
+	require.ErrorContains(t, cfg.Validate(), "client_auth")
+}
+
+func TestServe_DrainsRequestsThenStopsWorkersInReverse(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+	s, err := New(testConfig(), handler)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var stopped []string
+	worker := func(name string) Worker {
+		return func(ctx context.Context) {
+			<-ctx.Done()
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		}
+	}
+	s.AddWorker("first", worker("first"))
+	s.AddWorker("second", worker("second"))
+
+	addr, cancel, done := serve(t, s)
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+		t.Fatal("Serve returned before the in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+	mu.Lock()
+	require.Empty(t, stopped, "workers keep running while requests drain")
+	mu.Unlock()
+
+	close(release)
+	require.Equal(t, "done", <-body)
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"second", "first"}, stopped)
+
+	_, err = http.Get("http://" + addr + "/")
+	require.Error(t, err, "listener is closed")
+}
+
+type testPKI struct {
+	dir    string
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	caPool *x509.CertPool
+	serial int64
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	p := &testPKI{dir: t.TempDir(), ca: ca, caKey: key, caPool: pool, serial: 1}
+	writePEM(t, p.path("ca.pem"), "CERTIFICATE", der)
+	return p
+}
+
+func (p *testPKI) path(name string) string {
+	return filepath.Join(p.dir, name)
+}
+
+// issue signs a leaf certificate and writes it and its key to <name>.pem and <name>-key.pem.
+func (p *testPKI) issue(t *testing.T, name string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p.serial++
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(p.serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, p.ca, &key.PublicKey, p.caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	writePEM(t, p.path(name+".pem"), "CERTIFICATE", der)
+	writePEM(t, p.path(name+"-key.pem"), "EC PRIVATE KEY", keyDER)
+	cert, err := tls.LoadX509KeyPair(p.path(name+".pem"), p.path(name+"-key.pem"))
+	require.NoError(t, err)
+	return cert
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+}
+
+func TestServe_MutualTLSWithReload(t *testing.T) {
+	pki := newTestPKI(t)
+	pki.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	clientCert := pki.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	cfg := testConfig()
+	cfg.TLS.CertFile = pki.path("server.pem")
+	cfg.TLS.KeyFile = pki.path("server-key.pem")
+	cfg.TLS.ClientCAFile = pki.path("ca.pem")
+	cfg.TLS.ReloadInterval = 20 * time.Millisecond
+	s, err := New(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	require.NoError(t, err)
+	require.True(t, s.TLS())
+	addr, cancel, done := serve(t, s)
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	get := func(certs ...tls.Certificate) (serverSerial int64, body string, err error) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      pki.caPool,
+			Certificates: certs,
+		}}}
+		defer client.CloseIdleConnections()
+		resp, err := client.Get("https://" + addr + "/")
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64(), string(b), nil
+	}
+
+	_, _, err = get()
+	require.Error(t, err, "client certificate is required")
+
+	serial, body, err := get(clientCert)
+	require.NoError(t, err)
+	require.Equal(t, "client", body)
+
+	// Renew the server certificate in place; the watcher picks it up.
+	renewed := pki.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	renewedLeaf, err := x509.ParseCertificate(renewed.Certificate[0])
+	require.NoError(t, err)
+	require.NotEqual(t, serial, renewedLeaf.SerialNumber.Int64())
+	require.Eventually(t, func() bool {
+		got, _, err := get(clientCert)
+		return err == nil && got == renewedLeaf.SerialNumber.Int64()
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestNew_RejectsUnreadableCertificate(t *testing.T) {
+	cfg := testConfig()
+	cfg.TLS.CertFile = filepath.Join(t.TempDir(), "missing.pem")
+	cfg.TLS.KeyFile = cfg.TLS.CertFile
+	_, err := New(cfg, http.NotFoundHandler())
+	require.ErrorContains(t, err, "stat tls file")
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// Client certificate policies for mutual TLS.
+const (
+	// ClientAuthRequire rejects clients that do not present a certificate signed by a client CA.
+	ClientAuthRequire = "require"
+	// ClientAuthOptional verifies a client certificate when one is presented.
+	ClientAuthOptional = "optional"
+)
+
+// TLSConfig holds HTTPS settings. TLS is enabled when CertFile is set; setting ClientCAFile
+// as well enables mutual TLS.
+type TLSConfig struct {
+	CertFile       string        `mapstructure:"cert_file"`       // PEM certificate chain
+	KeyFile        string        `mapstructure:"key_file"`        // PEM private key
+	ClientCAFile   string        `mapstructure:"client_ca_file"`  // PEM CAs that sign client certificates
+	ClientAuth     string        `mapstructure:"client_auth"`     // require or optional
+	MinVersion     string        `mapstructure:"min_version"`     // 1.2 or 1.3
+	ReloadInterval time.Duration `mapstructure:"reload_interval"` // how often the files are checked for changes; 0 disables
+}
+
+// Enabled reports whether the server should serve HTTPS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// Validate reports inconsistent TLS settings.
+func (c TLSConfig) Validate() error {
+	if !c.Enabled() {
+		if c.KeyFile != "" || c.ClientCAFile != "" {
+			return errors.New("server.tls.cert_file is required when key_file or client_ca_file is set")
+		}
+		return nil
+	}
+	if c.KeyFile == "" {
+		return errors.New("server.tls.key_file is required with cert_file")
+	}
+	switch c.ClientAuth {
+	case ClientAuthRequire, ClientAuthOptional:
+	default:
+		return fmt.Errorf("server.tls.client_auth must be %q or %q, got %q", ClientAuthRequire, ClientAuthOptional, c.ClientAuth)
+	}
+	if _, err := c.minVersion(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c TLSConfig) minVersion() (uint16, error) {
+	switch c.MinVersion {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("server.tls.min_version must be 1.2 or 1.3, got %q", c.MinVersion)
+	}
+}
+
+// certReloader holds the current certificate and client CAs and swaps them when the files change,
+// so that renewed certificates are picked up without a restart. Handshakes in progress keep the
+// material they started with.
+type certReloader struct {
+	cfg    TLSConfig
+	logger log.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamps    map[string]fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newCertReloader(cfg TLSConfig, logger log.Logger) (*certReloader, error) {
+	r := &certReloader{cfg: cfg, logger: logger}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// reload reads every file and swaps the material in only if all of it is valid.
+func (r *certReloader) reload() error {
+	stamps, err := r.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load tls certificate: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read tls client ca: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls client ca %s contains no certificates", r.cfg.ClientCAFile)
+		}
+	}
+	r.mu.Lock()
+	r.cert, r.clientCAs, r.stamps = &cert, pool, stamps
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) stat() (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp, 3)
+	for _, f := range r.files() {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("stat tls file: %w", err)
+		}
+		stamps[f] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+	}
+	return stamps, nil
+}
+
+// changed reports whether any file differs from when it was last loaded.
+func (r *certReloader) changed() bool {
+	stamps, err := r.stat()
+	if err != nil {
+		// A file mid-rotation may be briefly missing; try again on the next tick.
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for f, st := range stamps {
+		if r.stamps[f] != st {
+			return true
+		}
+	}
+	return false
+}
+
+// watch reloads the files every ReloadInterval when they have changed. A failed reload keeps
+// serving the previous certificate.
+func (r *certReloader) watch(ctx context.Context) {
+	if r.cfg.ReloadInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		err := r.reload()
+		if r.logger == nil {
+			continue
+		}
+		if err != nil {
+			r.logger.Error("reload tls certificate", zap.Error(err))
+		} else {
+			r.logger.Info("reloaded tls certificate", zap.String("cert_file", r.cfg.CertFile))
+		}
+	}
+}
+
+// tlsConfig returns a server config that resolves the certificate and client CAs per handshake.
+func (r *certReloader) tlsConfig() *tls.Config {
+	minVersion, _ := r.cfg.minVersion()
+	clientAuth := tls.RequireAndVerifyClientCert
+	if r.cfg.ClientAuth == ClientAuthOptional {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+	return &tls.Config{
+		MinVersion: minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			c := &tls.Config{
+				MinVersion:   minVersion,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if r.clientCAs != nil {
+				c.ClientCAs = r.clientCAs
+				c.ClientAuth = clientAuth
+			}
+			return c, nil
+		},
+	}
+}
@@ -0,0 +1,163 @@
+// Package audit records security events in an append-only, hash-chained log.
+//
+// The chain is a plain SHA-256 chain: it detects an event that was edited or deleted, but not
+// someone with write access to the database who recomputes the hashes of every later event.
+// Copying the log (or just the latest hash) somewhere they cannot write closes that gap.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+const (
+	// DefaultPageSize is the page size used by List when pageSize is not positive.
+	DefaultPageSize = 50
+	// MaxPageSize caps the page size accepted by List.
+	MaxPageSize = 500
+
+	verifyBatchSize = 500
+)
+
+// Repository defines persistence operations for audit events.
+// Interface is defined in the consuming (service) layer per project architecture.
+type Repository interface {
+	// Append links e to the most recent event, sets e.PrevHash and e.Hash and persists it. When
+	// another process extends the chain concurrently it links e to the new head instead of failing.
+	Append(ctx context.Context, e *domain.AuditEvent) error
+	// List returns one page of events matching f, newest first, and the total number of matches.
+	List(ctx context.Context, f domain.AuditFilter, offset, limit int) ([]*domain.AuditEvent, int, error)
+	// ListAfter returns up to limit events recorded after afterID (from the start when empty),
+	// oldest first.
+	ListAfter(ctx context.Context, afterID string, limit int) ([]*domain.AuditEvent, error)
+}
+
+// Service records and queries audit events.
+type Service struct {
+	repo   Repository
+	logger log.Logger
+	// mu serializes appends from this process so they do not race for the chain head; appends
+	// from other replicas are retried by the repository.
+	mu sync.Mutex
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithLogger sets the logger used to report events that could not be recorded.
+func WithLogger(l log.Logger) Option {
+	return func(s *Service) {
+		s.logger = l
+	}
+}
+
+// NewService creates a Service with the given repository.
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Record appends e to the log. IP and UserAgent default to the client info carried by ctx.
+// Recording is best effort: a failure is logged rather than returned so that an unavailable
+// audit table never blocks sign-in.
+func (s *Service) Record(ctx context.Context, e domain.AuditEvent) {
+	info := auth.ClientInfoFromContext(ctx)
+	if e.IP == "" {
+		e.IP = info.IP
+	}
+	if e.UserAgent == "" {
+		e.UserAgent = info.UserAgent
+	}
+	s.mu.Lock()
+	err := s.repo.Append(ctx, &e)
+	s.mu.Unlock()
+	if err != nil && s.logger != nil {
+		s.logger.Error("record audit event", zap.String("type", string(e.Type)), zap.String("user_id", e.UserID), zap.Error(err))
+	}
+}
+
+// Page is one page of audit events, newest first.
+type Page struct {
+	Events   []*domain.AuditEvent
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// List returns the 1-based page of events matching f.
+func (s *Service) List(ctx context.Context, f domain.AuditFilter, page, pageSize int) (*Page, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	events, total, err := s.repo.List(ctx, f, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	return &Page{Events: events, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// UserEvents returns every event concerning the user, newest first.
+func (s *Service) UserEvents(ctx context.Context, userID string) ([]*domain.AuditEvent, error) {
+	var out []*domain.AuditEvent
+	for page := 1; ; page++ {
+		p, err := s.List(ctx, domain.AuditFilter{UserID: userID}, page, MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p.Events...)
+		if len(p.Events) < MaxPageSize {
+			return out, nil
+		}
+	}
+}
+
+// VerifyResult reports the outcome of checking the hash chain.
+type VerifyResult struct {
+	// Valid is false when an event was altered, removed or inserted out of order.
+	Valid bool
+	// Checked is the number of events examined.
+	Checked int
+	// BrokenAt is the ID of the first event whose hash or link does not match; empty when Valid.
+	BrokenAt string
+}
+
+// Verify walks the whole log oldest first, recomputing each event's hash and checking that it
+// links to its predecessor. It stops at the first mismatch.
+func (s *Service) Verify(ctx context.Context) (*VerifyResult, error) {
+	res := &VerifyResult{Valid: true}
+	prevHash, afterID := "", ""
+	for {
+		events, err := s.repo.ListAfter(ctx, afterID, verifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("verify audit log: %w", err)
+		}
+		for _, e := range events {
+			res.Checked++
+			if e.PrevHash != prevHash || e.ComputeHash() != e.Hash {
+				res.Valid = false
+				res.BrokenAt = e.ID
+				return res, nil
+			}
+			prevHash, afterID = e.Hash, e.ID
+		}
+		if len(events) < verifyBatchSize {
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/ent/hook"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestService_RecordAndList(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+	svc := NewService(storage.NewAuditRepository(client))
+
+	ctx := auth.ContextWithClientInfo(context.Background(), auth.ClientInfo{IP: "203.0.113.7", UserAgent: "curl/8"})
+	svc.Record(ctx, domain.AuditEvent{Type: domain.AuditLoginFailed, Details: map[string]string{"username": "ghost"}})
+	svc.Record(ctx, domain.AuditEvent{Type: domain.AuditLoginSucceeded, UserID: "1"})
+	svc.Record(ctx, domain.AuditEvent{Type: domain.AuditTokenIssued, UserID: "1", ClientID: "sso-demo"})
+	svc.Record(ctx, domain.AuditEvent{Type: domain.AuditLoginSucceeded, UserID: "2"})
+
+	page, err := svc.List(ctx, domain.AuditFilter{}, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, page.Total)
+	require.Equal(t, domain.AuditLoginSucceeded, page.Events[0].Type, "newest first")
+	first := page.Events[3]
+	require.Equal(t, "203.0.113.7", first.IP)
+	require.Equal(t, "curl/8", first.UserAgent)
+	require.Equal(t, "ghost", first.Details["username"])
+	require.Empty(t, first.PrevHash)
+	require.Equal(t, first.Hash, page.Events[2].PrevHash)
+
+	page, err = svc.List(ctx, domain.AuditFilter{UserID: "1"}, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, page.Total)
+	page, err = svc.List(ctx, domain.AuditFilter{ClientID: "sso-demo"}, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, page.Total)
+	page, err = svc.List(ctx, domain.AuditFilter{Type: domain.AuditLoginSucceeded}, 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, page.Total)
+	require.Equal(t, "1", page.Events[0].UserID)
+	page, err = svc.List(ctx, domain.AuditFilter{Since: time.Now().Add(time.Hour)}, 1, 0)
+	require.NoError(t, err)
+	require.Zero(t, page.Total)
+	page, err = svc.List(ctx, domain.AuditFilter{Until: time.Now().Add(time.Hour)}, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, page.Total)
+
+	events, err := svc.UserEvents(ctx, "2")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestService_RecordRetriesAfterLosingTheChainHead(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:audit-race?mode=memory&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+	svc := NewService(storage.NewAuditRepository(client))
+	svc.Record(ctx, domain.AuditEvent{Type: domain.AuditLoginSucceeded, UserID: "1"})
+
+	// Another replica extends the same head just before the insert: the insert fails on the
+	// unique prev_hash index and the event is linked to the new head instead of being dropped.
+	attempts := 0
+	client.AuditEvent.Use(func(next ent.Mutator) ent.Mutator {
+		return hook.AuditEventFunc(func(ctx context.Context, m *ent.AuditEventMutation) (ent.Value, error) {
+			attempts++
+			if attempts == 1 {
+				prev, _ := m.PrevHash()
+				if err := m.Client().AuditEvent.Create().SetType("other").SetPrevHash(prev).SetHash("other").Exec(ctx); err != nil {
+					return nil, err
+				}
+			}
+			return next.Mutate(ctx, m)
+		})
+	})
+	svc.Record(ctx, domain.AuditEvent{Type: domain.AuditLoginSucceeded, UserID: "2"})
+	require.Greater(t, attempts, 2, "the append was retried")
+
+	page, err := svc.List(ctx, domain.AuditFilter{UserID: "2"}, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, page.Total)
+	res, err := svc.Verify(ctx)
+	require.NoError(t, err)
+	require.True(t, res.Valid)
+}
+
+func TestService_VerifyDetectsTampering(t *testing.T) {
+	drv, err := entsql.Open(dialect.SQLite, "file:ent?mode=memory&_fk=1")
+	require.NoError(t, err)
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(drv)))
+	defer client.Close()
+	svc := NewService(storage.NewAuditRepository(client))
+	ctx := context.Background()
+
+	res, err := svc.Verify(ctx)
+	require.NoError(t, err)
+	require.True(t, res.Valid)
+	require.Zero(t, res.Checked)
+
+	for _, id := range []string{"1", "2", "3"} {
+		svc.Record(ctx, domain.AuditEvent{Type: domain.AuditLoginSucceeded, UserID: id, Details: map[string]string{"method": "password"}})
+	}
+	res, err = svc.Verify(ctx)
+	require.NoError(t, err)
+	require.True(t, res.Valid)
+	require.Equal(t, 3, res.Checked)
+
+	// Rewriting a row's content behind the service's back breaks its hash.
+	ids, err := client.AuditEvent.Query().IDs(ctx)
+	require.NoError(t, err)
+	_, err = drv.DB().ExecContext(ctx, "UPDATE audit_events SET user_id = '9' WHERE id = ?", ids[1])
+	require.NoError(t, err)
+	res, err = svc.Verify(ctx)
+	require.NoError(t, err)
+	require.False(t, res.Valid)
+	require.Equal(t, 2, res.Checked)
+
+	// Removing that row instead breaks the next event's link.
+	require.NoError(t, client.AuditEvent.DeleteOneID(ids[1]).Exec(ctx))
+	res, err = svc.Verify(ctx)
+	require.NoError(t, err)
+	require.False(t, res.Valid)
+	require.Equal(t, "3", res.BrokenAt)
+}
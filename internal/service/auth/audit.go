@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// Auditor records security events. Recording is best effort and never fails the operation.
+type Auditor interface {
+	Record(ctx context.Context, e domain.AuditEvent)
+}
+
+// WithAuditor sets the Auditor that receives sign-in, session and status events.
+func WithAuditor(a Auditor) Option {
+	return func(s *AuthService) {
+		s.auditor = a
+	}
+}
+
+func (s *AuthService) audit(ctx context.Context, e domain.AuditEvent) {
+	if s.auditor != nil {
+		s.auditor.Record(ctx, e)
+	}
+}
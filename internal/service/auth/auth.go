@@ -6,40 +6,165 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel"
+
 	"github.com/qinzj/superpowers-demo/internal/domain"
 	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
 )
 
 // ErrInvalidCredentials is returned when username or password is invalid.
 var ErrInvalidCredentials = errors.New("invalid credentials")
 
+// ErrAccountInactive is returned when valid credentials or a grant belong to a user whose
+// status is not active.
+var ErrAccountInactive = errors.New("account not active")
+
+// ErrSessionNotFound is returned when a session does not exist or belongs to another user.
+var ErrSessionNotFound = errors.New("session not found")
+
 const sessionTokenBytes = 32
-const sessionDuration = 24 * time.Hour
+
+var tracer = otel.Tracer("github.com/qinzj/superpowers-demo/internal/service/auth")
+
+// lastSeenInterval throttles session activity writes (last seen and sliding expiry).
+const lastSeenInterval = time.Minute
 
 // AuthService provides authentication and credential validation.
 type AuthService struct {
 	userRepo    user.UserRepository
 	sessionRepo SessionRepository
+	hasher      *password.Hasher
+	tokens      TokenStore
+	consents    ConsentRepository
+	sessionCfg  SessionConfig
+	auditor     Auditor
+	events      user.EventPublisher
+	metrics     LoginMetrics
+}
+
+// Option configures optional AuthService dependencies.
+type Option func(*AuthService)
+
+// WithPasswordHasher sets the hasher used to verify and upgrade password hashes.
+// Defaults to password.Default().
+func WithPasswordHasher(h *password.Hasher) Option {
+	return func(s *AuthService) {
+		if h != nil {
+			s.hasher = h
+		}
+	}
+}
+
+// WithSessionConfig sets session lifetimes. Zero lifetimes fall back to DefaultSessionConfig.
+func WithSessionConfig(cfg SessionConfig) Option {
+	return func(s *AuthService) {
+		s.sessionCfg = cfg.withDefaults()
+	}
+}
+
+// WithTokenStore sets the TokenStore used to list and revoke a user's OAuth2 grants.
+func WithTokenStore(t TokenStore) Option {
+	return func(s *AuthService) {
+		s.tokens = t
+	}
+}
+
+// WithConsentRepository sets where the scopes users allow clients are remembered. Without it
+// every authorization asks for consent again.
+func WithConsentRepository(r ConsentRepository) Option {
+	return func(s *AuthService) {
+		s.consents = r
+	}
+}
+
+// WithEventPublisher sets the EventPublisher that receives user.login for password sign-ins.
+func WithEventPublisher(p user.EventPublisher) Option {
+	return func(s *AuthService) {
+		s.events = p
+	}
+}
+
+// LoginMetrics counts password sign-in attempts by outcome.
+type LoginMetrics interface {
+	LoginAttempt(outcome string)
+}
+
+// WithMetrics sets the LoginMetrics that count password sign-ins.
+func WithMetrics(m LoginMetrics) Option {
+	return func(s *AuthService) {
+		s.metrics = m
+	}
 }
 
 // NewAuthService creates an AuthService with the given repositories.
-func NewAuthService(userRepo user.UserRepository, sessionRepo SessionRepository) *AuthService {
-	return &AuthService{userRepo: userRepo, sessionRepo: sessionRepo}
+func NewAuthService(userRepo user.UserRepository, sessionRepo SessionRepository, opts ...Option) *AuthService {
+	s := &AuthService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		hasher:      password.Default(),
+		sessionCfg:  DefaultSessionConfig(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ValidateCredentials checks username and password against stored user.
-// Returns the user if valid, ErrInvalidCredentials for wrong credentials, or an error on failure.
-func (s *AuthService) ValidateCredentials(ctx context.Context, username, pwd string) (*domain.User, error) {
+// Returns the user if valid, ErrInvalidCredentials for wrong credentials, ErrAccountInactive when
+// the password is right but the user is not active, or an error on failure.
+// When the stored hash uses an outdated algorithm or parameters, it is upgraded in place.
+func (s *AuthService) ValidateCredentials(ctx context.Context, username, pwd string) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ValidateCredentials")
+	defer func() { tracing.End(span, err) }()
 	u, err := s.userRepo.ByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("validate credentials: %w", err)
 	}
 	if u == nil {
+		s.audit(ctx, loginFailed("", username, "unknown_user"))
+		s.observeLogin("unknown_user")
 		return nil, ErrInvalidCredentials
 	}
-	if !password.Verify(pwd, u.PasswordHash) {
+	ok, needsRehash := s.hasher.Verify(pwd, u.PasswordHash)
+	if !ok {
+		s.audit(ctx, loginFailed(u.ID, username, "invalid_password"))
+		s.observeLogin("invalid_password")
 		return nil, ErrInvalidCredentials
 	}
+	if !u.Active() {
+		s.audit(ctx, loginFailed(u.ID, username, "inactive"))
+		s.observeLogin("inactive")
+		return nil, ErrAccountInactive
+	}
+	if needsRehash {
+		// Best effort: a failed upgrade must not block a valid login; it is retried next time.
+		if hash, err := s.hasher.Hash(pwd); err == nil {
+			if err := s.userRepo.UpdatePasswordHash(ctx, u.ID, hash); err == nil {
+				u.PasswordHash = hash
+			}
+		}
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditLoginSucceeded, UserID: u.ID, Details: map[string]string{"method": "password"}})
+	s.observeLogin("success")
+	if s.events != nil {
+		s.events.Publish(ctx, domain.WebhookUserLogin, map[string]string{"user_id": u.ID, "username": u.Username, "method": "password"})
+	}
 	return u, nil
 }
+
+func (s *AuthService) observeLogin(outcome string) {
+	if s.metrics != nil {
+		s.metrics.LoginAttempt(outcome)
+	}
+}
+
+func loginFailed(userID, username, reason string) domain.AuditEvent {
+	return domain.AuditEvent{
+		Type:    domain.AuditLoginFailed,
+		UserID:  userID,
+		Details: map[string]string{"method": "password", "username": username, "reason": reason},
+	}
+}
@@ -3,15 +3,18 @@ package auth
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/qinzj/superpowers-demo/ent/enttest"
 	"github.com/qinzj/superpowers-demo/internal/domain"
 	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
 	"github.com/qinzj/superpowers-demo/internal/storage"
 )
 
@@ -56,3 +59,388 @@ func TestAuthService_ValidateCredentials(t *testing.T) {
 		require.True(t, errors.Is(err, ErrInvalidCredentials))
 	})
 }
+
+func TestAuthService_ValidateCredentials_RehashesOutdatedHash(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	authSvc := NewAuthService(userRepo, sessionRepo)
+
+	ctx := context.Background()
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	u := &domain.User{
+		Username:     "legacy",
+		Email:        "legacy@example.com",
+		PasswordHash: string(legacy),
+		CreatedAt:    time.Now(),
+	}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	_, err = authSvc.ValidateCredentials(ctx, "legacy", "secret123")
+	require.NoError(t, err)
+
+	stored, err := userRepo.ByUsername(ctx, "legacy")
+	require.NoError(t, err)
+	require.NotEqual(t, string(legacy), stored.PasswordHash)
+	ok, needsRehash := password.Verify("secret123", stored.PasswordHash)
+	require.True(t, ok)
+	require.False(t, needsRehash)
+}
+
+// fakeTokenStore records subjects whose tokens were revoked.
+type fakeTokenStore struct {
+	subjects []string
+}
+
+func (r *fakeTokenStore) RevokeSubjectTokens(_ context.Context, subject string) error {
+	r.subjects = append(r.subjects, subject)
+	return nil
+}
+
+func (r *fakeTokenStore) RevokeSubjectClientTokens(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (r *fakeTokenStore) ListSubjectGrants(_ context.Context, _ string) ([]*domain.Grant, error) {
+	return nil, nil
+}
+
+func TestAuthService_RevokeOtherSessions(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	revoker := &fakeTokenStore{}
+	authSvc := NewAuthService(userRepo, sessionRepo, WithTokenStore(revoker))
+
+	ctx := context.Background()
+	u := &domain.User{Username: "gina", Email: "gina@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	keep, err := authSvc.CreateSession(ctx, u.ID, false)
+	require.NoError(t, err)
+	other, err := authSvc.CreateSession(ctx, u.ID, false)
+	require.NoError(t, err)
+
+	require.NoError(t, authSvc.RevokeOtherSessions(ctx, u.ID, keep.Token))
+
+	got, err := authSvc.GetSession(ctx, keep.Token)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	got, err = authSvc.GetSession(ctx, other.Token)
+	require.NoError(t, err)
+	require.Nil(t, got)
+	require.Equal(t, []string{u.ID}, revoker.subjects)
+}
+
+func TestAuthService_ListAndRevokeSessions(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	authSvc := NewAuthService(userRepo, sessionRepo)
+
+	ctx := context.Background()
+	alice := &domain.User{Username: "hana", Email: "hana@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, alice))
+	mallory := &domain.User{Username: "ivan", Email: "ivan@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, mallory))
+
+	devCtx := ContextWithClientInfo(ctx, ClientInfo{IP: "203.0.113.7", UserAgent: "test-agent/1.0"})
+	sess, err := authSvc.CreateSession(devCtx, alice.ID, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, sess.ID)
+
+	sessions, err := authSvc.ListSessions(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, "203.0.113.7", sessions[0].IP)
+	require.Equal(t, "test-agent/1.0", sessions[0].UserAgent)
+	require.False(t, sessions[0].CreatedAt.IsZero())
+
+	// Another user cannot revoke it
+	err = authSvc.RevokeSession(ctx, mallory.ID, sess.ID)
+	require.ErrorIs(t, err, ErrSessionNotFound)
+
+	require.NoError(t, authSvc.RevokeSession(ctx, alice.ID, sess.ID))
+	sessions, err = authSvc.ListSessions(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestAuthService_SessionLifetimes(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	authSvc := NewAuthService(userRepo, sessionRepo, WithSessionConfig(SessionConfig{
+		AbsoluteLifetime:   4 * time.Hour,
+		IdleTimeout:        time.Hour,
+		RememberMeLifetime: 48 * time.Hour,
+	}))
+
+	ctx := context.Background()
+	u := &domain.User{Username: "jack", Email: "jack@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	t.Run("idle_deadline_is_capped_by_absolute_lifetime", func(t *testing.T) {
+		sess, err := authSvc.CreateSession(ctx, u.ID, false)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), sess.ExpiresAt, time.Minute)
+		require.WithinDuration(t, time.Now().Add(4*time.Hour), sess.AbsoluteExpiresAt, time.Minute)
+	})
+
+	t.Run("remember_me_uses_longer_lifetime_without_idle_timeout", func(t *testing.T) {
+		sess, err := authSvc.CreateSession(ctx, u.ID, true)
+		require.NoError(t, err)
+		require.True(t, sess.Remember)
+		require.WithinDuration(t, time.Now().Add(48*time.Hour), sess.ExpiresAt, time.Minute)
+		require.Equal(t, sess.AbsoluteExpiresAt, sess.ExpiresAt)
+	})
+
+	t.Run("activity_slides_idle_deadline", func(t *testing.T) {
+		sess, err := authSvc.CreateSession(ctx, u.ID, false)
+		require.NoError(t, err)
+		id, err := strconv.Atoi(sess.ID)
+		require.NoError(t, err)
+		// Pretend the session was last used 50 minutes ago.
+		require.NoError(t, client.Session.UpdateOneID(id).
+			SetLastSeenAt(time.Now().Add(-50*time.Minute)).
+			SetExpiresAt(time.Now().Add(10*time.Minute)).
+			Exec(ctx))
+
+		got, err := authSvc.GetSession(ctx, sess.Token)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		stored, err := client.Session.Get(ctx, id)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+		require.WithinDuration(t, time.Now(), stored.LastSeenAt, time.Minute)
+	})
+
+	t.Run("idle_session_is_rejected", func(t *testing.T) {
+		sess, err := authSvc.CreateSession(ctx, u.ID, false)
+		require.NoError(t, err)
+		id, err := strconv.Atoi(sess.ID)
+		require.NoError(t, err)
+		require.NoError(t, client.Session.UpdateOneID(id).SetExpiresAt(time.Now().Add(-time.Second)).Exec(ctx))
+
+		got, err := authSvc.GetSession(ctx, sess.Token)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestAuthService_SessionTokenStoredHashed(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	authSvc := NewAuthService(userRepo, storage.NewSessionRepository(client))
+
+	ctx := context.Background()
+	u := &domain.User{Username: "lena", Email: "lena@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	sess, err := authSvc.CreateSession(ctx, u.ID, false)
+	require.NoError(t, err)
+
+	stored, err := client.Session.Query().Only(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, sess.Token, stored.TokenHash)
+	require.Equal(t, HashSessionToken(sess.Token), stored.TokenHash)
+
+	// The stored digest is not itself a valid session token.
+	got, err := authSvc.GetSession(ctx, stored.TokenHash)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	got, err = authSvc.GetSession(ctx, sess.Token)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+}
+
+func TestAuthService_DeviceChangePolicy(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	ctx := context.Background()
+	u := &domain.User{Username: "mira", Email: "mira@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	laptop := ContextWithClientInfo(ctx, ClientInfo{IP: "10.0.0.1", UserAgent: "Firefox"})
+	phone := ContextWithClientInfo(ctx, ClientInfo{IP: "10.0.0.2", UserAgent: "Safari"})
+	laptopNewIP := ContextWithClientInfo(ctx, ClientInfo{IP: "10.0.0.9", UserAgent: "Firefox"})
+
+	storedSession := func(t *testing.T, id string) (string, string, bool) {
+		n, err := strconv.Atoi(id)
+		require.NoError(t, err)
+		e, err := client.Session.Get(ctx, n)
+		if err != nil {
+			return "", "", false
+		}
+		return e.IP, e.UserAgent, true
+	}
+
+	t.Run("update_records_new_device", func(t *testing.T) {
+		authSvc := NewAuthService(userRepo, sessionRepo)
+		sess, err := authSvc.CreateSession(laptop, u.ID, false)
+		require.NoError(t, err)
+
+		got, err := authSvc.GetSession(phone, sess.Token)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		ip, ua, ok := storedSession(t, sess.ID)
+		require.True(t, ok)
+		require.Equal(t, "10.0.0.2", ip)
+		require.Equal(t, "Safari", ua)
+	})
+
+	t.Run("ignore_keeps_original_device", func(t *testing.T) {
+		authSvc := NewAuthService(userRepo, sessionRepo, WithSessionConfig(SessionConfig{DeviceChange: DeviceChangeIgnore}))
+		sess, err := authSvc.CreateSession(laptop, u.ID, false)
+		require.NoError(t, err)
+
+		got, err := authSvc.GetSession(phone, sess.Token)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		_, ua, ok := storedSession(t, sess.ID)
+		require.True(t, ok)
+		require.Equal(t, "Firefox", ua)
+	})
+
+	t.Run("revoke_ends_session", func(t *testing.T) {
+		authSvc := NewAuthService(userRepo, sessionRepo, WithSessionConfig(SessionConfig{DeviceChange: DeviceChangeRevoke}))
+		sess, err := authSvc.CreateSession(laptop, u.ID, false)
+		require.NoError(t, err)
+
+		// Same user agent from a new IP is not a device change unless IPs are matched.
+		got, err := authSvc.GetSession(laptopNewIP, sess.Token)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		got, err = authSvc.GetSession(phone, sess.Token)
+		require.NoError(t, err)
+		require.Nil(t, got)
+		_, _, ok := storedSession(t, sess.ID)
+		require.False(t, ok)
+	})
+
+	t.Run("revoke_with_ip_matching", func(t *testing.T) {
+		authSvc := NewAuthService(userRepo, sessionRepo, WithSessionConfig(SessionConfig{
+			DeviceChange:  DeviceChangeRevoke,
+			DeviceMatchIP: true,
+		}))
+		sess, err := authSvc.CreateSession(laptop, u.ID, false)
+		require.NoError(t, err)
+
+		got, err := authSvc.GetSession(laptopNewIP, sess.Token)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func TestSessionConfig_Validate(t *testing.T) {
+	require.NoError(t, SessionConfig{}.Validate())
+	require.NoError(t, SessionConfig{DeviceChange: "Revoke"}.Validate())
+	require.Error(t, SessionConfig{DeviceChange: "block"}.Validate())
+}
+
+func TestAuthService_UserStatus(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	revoker := &fakeTokenStore{}
+	authSvc := NewAuthService(userRepo, sessionRepo, WithTokenStore(revoker))
+
+	ctx := context.Background()
+	hash, err := password.Hash("secret123")
+	require.NoError(t, err)
+	u := &domain.User{Username: "dora", Email: "dora@example.com", PasswordHash: hash, CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+	require.Equal(t, domain.UserStatusActive, u.Status)
+	require.NoError(t, authSvc.EnsureActive(ctx, u.ID))
+
+	for _, status := range []domain.UserStatus{domain.UserStatusSuspended, domain.UserStatusLocked, domain.UserStatusPendingVerification} {
+		sess, err := authSvc.CreateSession(ctx, u.ID, false)
+		require.NoError(t, err)
+		revoker.subjects = nil
+
+		require.NoError(t, authSvc.SetUserStatus(ctx, u.ID, status))
+		require.Equal(t, []string{u.ID}, revoker.subjects, "%s revokes tokens", status)
+		_, err = authSvc.ValidateCredentials(ctx, "dora", "secret123")
+		require.ErrorIs(t, err, ErrAccountInactive, status)
+		_, err = authSvc.ValidateCredentials(ctx, "dora", "wrong")
+		require.ErrorIs(t, err, ErrInvalidCredentials, "a wrong password must not reveal the account state")
+		require.ErrorIs(t, authSvc.EnsureActive(ctx, u.ID), ErrAccountInactive, status)
+
+		require.NoError(t, authSvc.SetUserStatus(ctx, u.ID, domain.UserStatusActive))
+		got, err := authSvc.GetSession(ctx, sess.Token)
+		require.NoError(t, err)
+		require.Nil(t, got, "sessions revoked by %s stay revoked after reactivation", status)
+	}
+
+	sess, err := authSvc.CreateSession(ctx, u.ID, false)
+	require.NoError(t, err)
+	got, err := authSvc.GetSession(ctx, sess.Token)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	_, err = authSvc.ValidateCredentials(ctx, "dora", "secret123")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, authSvc.SetUserStatus(ctx, u.ID, "deleted"), ErrInvalidStatus)
+	require.ErrorIs(t, authSvc.SetUserStatus(ctx, "999", domain.UserStatusSuspended), user.ErrUserNotFound)
+	require.ErrorIs(t, authSvc.EnsureActive(ctx, "999"), ErrAccountInactive)
+}
+
+func TestAuthService_Consent(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	authSvc := NewAuthService(userRepo, storage.NewSessionRepository(client),
+		WithConsentRepository(storage.NewConsentRepository(client)))
+
+	ctx := context.Background()
+	u := &domain.User{Username: "kai", Email: "kai@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	missing, err := authSvc.MissingConsent(ctx, u.ID, "app", []string{"openid", "profile"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"openid", "profile"}, missing)
+
+	// Consents accumulate per client.
+	require.NoError(t, authSvc.GrantConsent(ctx, u.ID, "app", []string{"openid"}))
+	require.NoError(t, authSvc.GrantConsent(ctx, u.ID, "app", []string{"openid", "profile"}))
+	missing, err = authSvc.MissingConsent(ctx, u.ID, "app", []string{"profile", "email"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"email"}, missing)
+	missing, err = authSvc.MissingConsent(ctx, u.ID, "other", []string{"openid"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"openid"}, missing)
+
+	apps, err := authSvc.ListAuthorizedApps(ctx, u.ID)
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	require.Equal(t, []string{"openid", "profile"}, apps[0].Scopes)
+
+	// Revoking the app forgets the consent, and deleting the user removes it.
+	require.NoError(t, authSvc.RevokeApp(ctx, u.ID, "app"))
+	consents, err := authSvc.ListConsents(ctx, u.ID)
+	require.NoError(t, err)
+	require.Empty(t, consents)
+	require.NoError(t, authSvc.GrantConsent(ctx, u.ID, "app", []string{"openid"}))
+	require.NoError(t, userRepo.Delete(ctx, u.ID))
+	require.Zero(t, client.Consent.Query().CountX(ctx))
+}
@@ -0,0 +1,23 @@
+package auth
+
+import "context"
+
+// ClientInfo describes the device a request originates from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type clientInfoKey struct{}
+
+// ContextWithClientInfo returns a copy of ctx carrying the request's client info.
+// Sessions created with the returned context record it.
+func ContextWithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client info stored in ctx, if any.
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
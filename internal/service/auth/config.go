@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Actions taken when a session is used from a device other than the one it was created on.
+const (
+	// DeviceChangeUpdate records the new IP and user agent and keeps the session.
+	DeviceChangeUpdate = "update"
+	// DeviceChangeIgnore keeps the session and the originally recorded device.
+	DeviceChangeIgnore = "ignore"
+	// DeviceChangeRevoke ends the session; the user has to sign in again.
+	DeviceChangeRevoke = "revoke"
+)
+
+// SessionConfig holds session lifetime configuration matching the "session" section of settings.yaml.
+type SessionConfig struct {
+	// AbsoluteLifetime caps a session's total lifetime regardless of activity.
+	AbsoluteLifetime time.Duration `mapstructure:"absolute_lifetime"`
+	// IdleTimeout expires a session after this long without activity; 0 disables it.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// RememberMeLifetime replaces AbsoluteLifetime (and the idle timeout) for "remember me" logins.
+	RememberMeLifetime time.Duration `mapstructure:"remember_me_lifetime"`
+	// DeviceChange is the action taken when the device fingerprint changes mid-session:
+	// update (default), ignore, or revoke.
+	DeviceChange string `mapstructure:"device_change"`
+	// DeviceMatchIP adds the client IP to the fingerprint. By default only the user agent is
+	// compared, since client IPs change routinely on mobile networks.
+	DeviceMatchIP bool `mapstructure:"device_match_ip"`
+}
+
+// DefaultSessionConfig returns the lifetimes used when none are configured.
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		AbsoluteLifetime:   24 * time.Hour,
+		IdleTimeout:        2 * time.Hour,
+		RememberMeLifetime: 30 * 24 * time.Hour,
+		DeviceChange:       DeviceChangeUpdate,
+	}
+}
+
+// Validate reports an unknown device_change action.
+func (c SessionConfig) Validate() error {
+	switch strings.ToLower(c.DeviceChange) {
+	case "", DeviceChangeUpdate, DeviceChangeIgnore, DeviceChangeRevoke:
+		return nil
+	default:
+		return fmt.Errorf("session: device_change must be %s, %s, or %s", DeviceChangeUpdate, DeviceChangeIgnore, DeviceChangeRevoke)
+	}
+}
+
+// withDefaults fills zero lifetimes from DefaultSessionConfig. IdleTimeout stays as given.
+func (c SessionConfig) withDefaults() SessionConfig {
+	d := DefaultSessionConfig()
+	if c.AbsoluteLifetime <= 0 {
+		c.AbsoluteLifetime = d.AbsoluteLifetime
+	}
+	if c.RememberMeLifetime <= 0 {
+		c.RememberMeLifetime = d.RememberMeLifetime
+	}
+	if c.IdleTimeout < 0 {
+		c.IdleTimeout = 0
+	}
+	c.DeviceChange = strings.ToLower(c.DeviceChange)
+	if c.DeviceChange == "" {
+		c.DeviceChange = d.DeviceChange
+	}
+	return c
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
+)
+
+// MissingConsent returns the scopes the user has not yet allowed clientID, in the order
+// requested. Every scope is missing when no ConsentRepository is configured.
+func (s *AuthService) MissingConsent(ctx context.Context, userID, clientID string, scopes []string) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.MissingConsent")
+	defer func() { tracing.End(span, err) }()
+	if s.consents == nil {
+		return scopes, nil
+	}
+	c, err := s.consents.Get(ctx, userID, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("get consent: %w", err)
+	}
+	allowed := map[string]bool{}
+	if c != nil {
+		for _, scope := range c.Scopes {
+			allowed[scope] = true
+		}
+	}
+	var missing []string
+	for _, scope := range scopes {
+		if !allowed[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing, nil
+}
+
+// GrantConsent remembers that the user allows clientID the given scopes, in addition to those
+// allowed before.
+func (s *AuthService) GrantConsent(ctx context.Context, userID, clientID string, scopes []string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.GrantConsent")
+	defer func() { tracing.End(span, err) }()
+	if s.consents == nil {
+		return nil
+	}
+	c, err := s.consents.Get(ctx, userID, clientID)
+	if err != nil {
+		return fmt.Errorf("get consent: %w", err)
+	}
+	var all []string
+	seen := map[string]bool{}
+	if c != nil {
+		all = append(all, c.Scopes...)
+		for _, scope := range c.Scopes {
+			seen[scope] = true
+		}
+	}
+	var added []string
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			added = append(added, scope)
+		}
+	}
+	if c != nil && len(added) == 0 {
+		return nil
+	}
+	if err := s.consents.Save(ctx, userID, clientID, append(all, added...)); err != nil {
+		return fmt.Errorf("save consent: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{
+		Type:     domain.AuditConsentGranted,
+		UserID:   userID,
+		ClientID: clientID,
+		Details:  map[string]string{"scope": strings.Join(added, " ")},
+	})
+	return nil
+}
+
+// ListConsents returns the clients the user has allowed and the scopes allowed to each.
+func (s *AuthService) ListConsents(ctx context.Context, userID string) (_ []*domain.Consent, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ListConsents")
+	defer func() { tracing.End(span, err) }()
+	if s.consents == nil {
+		return nil, nil
+	}
+	consents, err := s.consents.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	return consents, nil
+}
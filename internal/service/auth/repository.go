@@ -3,6 +3,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/qinzj/superpowers-demo/internal/domain"
 )
@@ -10,8 +11,41 @@ import (
 // SessionRepository defines persistence operations for HTTP sessions.
 // Interface is defined in the consuming (service) layer per project architecture.
 type SessionRepository interface {
+	// Create persists s using s.TokenHash; the raw token must not be stored.
 	Create(ctx context.Context, s *domain.Session) error
-	GetByToken(ctx context.Context, token string) (*domain.Session, error)
-	// GetByTokenWithUser returns the session and its user if valid and not expired.
-	GetByTokenWithUser(ctx context.Context, token string) (*domain.Session, *domain.User, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error)
+	// GetByTokenHashWithUser returns the session and its user if valid and not expired.
+	GetByTokenHashWithUser(ctx context.Context, tokenHash string) (*domain.Session, *domain.User, error)
+	// DeleteByUser removes all sessions of the user except the one with exceptTokenHash (may be empty).
+	DeleteByUser(ctx context.Context, userID, exceptTokenHash string) error
+	// ListByUser returns the user's unexpired sessions, most recently seen first.
+	ListByUser(ctx context.Context, userID string) ([]*domain.Session, error)
+	// DeleteByID removes the session if it belongs to the user. Returns false if nothing was deleted.
+	DeleteByID(ctx context.Context, userID, sessionID string) (bool, error)
+	// Touch records activity on the session and moves its idle deadline to expiresAt.
+	Touch(ctx context.Context, sessionID string, lastSeen, expiresAt time.Time) error
+	// UpdateDevice replaces the IP and user agent recorded for the session.
+	UpdateDevice(ctx context.Context, sessionID, ip, userAgent string) error
+}
+
+// TokenStore lists and revokes OAuth2 access and refresh tokens issued on behalf of a user.
+type TokenStore interface {
+	// RevokeSubjectTokens revokes every token issued for subject.
+	RevokeSubjectTokens(ctx context.Context, subject string) error
+	// RevokeSubjectClientTokens revokes the tokens issued to clientID for subject.
+	RevokeSubjectClientTokens(ctx context.Context, subject, clientID string) error
+	// ListSubjectGrants returns the clients currently holding tokens for subject.
+	ListSubjectGrants(ctx context.Context, subject string) ([]*domain.Grant, error)
+}
+
+// ConsentRepository defines persistence operations for the scopes users have allowed clients.
+type ConsentRepository interface {
+	// Get returns the user's consent for clientID, or nil if there is none.
+	Get(ctx context.Context, userID, clientID string) (*domain.Consent, error)
+	// Save stores scopes as the user's consent for clientID, replacing any earlier consent.
+	Save(ctx context.Context, userID, clientID string, scopes []string) error
+	// ListByUser returns the user's consents ordered by client ID.
+	ListByUser(ctx context.Context, userID string) ([]*domain.Consent, error)
+	// Delete removes the user's consent for clientID, if any.
+	Delete(ctx context.Context, userID, clientID string) error
 }
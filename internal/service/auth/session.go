@@ -3,11 +3,13 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
 )
 
 func generateSessionToken() (string, error) {
@@ -18,29 +20,206 @@ func generateSessionToken() (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
+// HashSessionToken returns the digest under which a session token is stored.
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // CreateSession creates a new HTTP session for the given user and returns it.
-func (s *AuthService) CreateSession(ctx context.Context, userID string) (*domain.Session, error) {
+// When remember is true the session uses the "remember me" lifetime and no idle timeout.
+// Signing in cancels a pending account deletion.
+func (s *AuthService) CreateSession(ctx context.Context, userID string, remember bool) (_ *domain.Session, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.CreateSession")
+	defer func() { tracing.End(span, err) }()
+	if _, err := s.userRepo.CancelDeletion(ctx, userID); err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
 	token, err := generateSessionToken()
 	if err != nil {
 		return nil, fmt.Errorf("create session: %w", err)
 	}
-	expiresAt := time.Now().Add(sessionDuration)
+	now := time.Now()
+	info := ClientInfoFromContext(ctx)
+	lifetime := s.sessionCfg.AbsoluteLifetime
+	if remember {
+		lifetime = s.sessionCfg.RememberMeLifetime
+	}
 	sess := &domain.Session{
-		UserID:    userID,
-		Token:     token,
-		ExpiresAt: expiresAt,
+		UserID:            userID,
+		Token:             token,
+		TokenHash:         HashSessionToken(token),
+		AbsoluteExpiresAt: now.Add(lifetime),
+		Remember:          remember,
+		CreatedAt:         now,
+		LastSeenAt:        now,
+		IP:                info.IP,
+		UserAgent:         info.UserAgent,
 	}
+	sess.ExpiresAt = s.idleDeadline(sess, now)
 	if err := s.sessionRepo.Create(ctx, sess); err != nil {
 		return nil, fmt.Errorf("create session: %w", err)
 	}
 	return sess, nil
 }
 
-// GetSession returns the user associated with the given session token if valid.
-func (s *AuthService) GetSession(ctx context.Context, token string) (*domain.User, error) {
-	_, u, err := s.sessionRepo.GetByTokenWithUser(ctx, token)
+// GetSession returns the user associated with the given session token if valid. Sessions of
+// users that are not active do not resolve.
+// Activity slides the idle deadline forward (never past the absolute lifetime); it is
+// recorded at most once per lastSeenInterval. A change of device is handled according to
+// SessionConfig.DeviceChange.
+func (s *AuthService) GetSession(ctx context.Context, token string) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.GetSession")
+	defer func() { tracing.End(span, err) }()
+	if token == "" {
+		return nil, nil
+	}
+	sess, u, err := s.sessionRepo.GetByTokenHashWithUser(ctx, HashSessionToken(token))
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
+	if sess == nil || u == nil || !u.Active() {
+		return nil, nil
+	}
+	if info := ClientInfoFromContext(ctx); s.deviceChanged(sess, info) {
+		switch s.sessionCfg.DeviceChange {
+		case DeviceChangeRevoke:
+			if _, err := s.sessionRepo.DeleteByID(ctx, sess.UserID, sess.ID); err != nil {
+				return nil, fmt.Errorf("revoke session on device change: %w", err)
+			}
+			return nil, nil
+		case DeviceChangeUpdate:
+			// Best effort, like Touch below.
+			_ = s.sessionRepo.UpdateDevice(ctx, sess.ID, info.IP, info.UserAgent)
+		}
+	}
+	if time.Since(sess.LastSeenAt) >= lastSeenInterval {
+		now := time.Now()
+		// Best effort: failing to record activity must not reject a valid session.
+		_ = s.sessionRepo.Touch(ctx, sess.ID, now, s.idleDeadline(sess, now))
+	}
 	return u, nil
 }
+
+// deviceChanged reports whether the request comes from a different device than the one recorded
+// on the session. Requests without client info (e.g. internal calls) never count as a change.
+func (s *AuthService) deviceChanged(sess *domain.Session, info ClientInfo) bool {
+	if info == (ClientInfo{}) {
+		return false
+	}
+	if sess.UserAgent != info.UserAgent {
+		return true
+	}
+	return s.sessionCfg.DeviceMatchIP && sess.IP != info.IP
+}
+
+// idleDeadline returns when sess expires if no further activity happens after now.
+func (s *AuthService) idleDeadline(sess *domain.Session, now time.Time) time.Time {
+	abs := sess.AbsoluteExpiresAt
+	if abs.IsZero() {
+		// Sessions created before absolute lifetimes existed keep their original deadline.
+		return sess.ExpiresAt
+	}
+	if sess.Remember || s.sessionCfg.IdleTimeout <= 0 {
+		return abs
+	}
+	if deadline := now.Add(s.sessionCfg.IdleTimeout); deadline.Before(abs) {
+		return deadline
+	}
+	return abs
+}
+
+// ListSessions returns the user's active sessions, most recently seen first.
+func (s *AuthService) ListSessions(ctx context.Context, userID string) (_ []*domain.Session, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ListSessions")
+	defer func() { tracing.End(span, err) }()
+	sessions, err := s.sessionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession deletes one of the user's sessions. Returns ErrSessionNotFound if the session
+// does not exist or belongs to another user.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RevokeSession")
+	defer func() { tracing.End(span, err) }()
+	ok, err := s.sessionRepo.DeleteByID(ctx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditSessionRevoked, UserID: userID, Details: map[string]string{"session_id": sessionID}})
+	return nil
+}
+
+// ListAuthorizedApps returns the OAuth2 clients currently holding tokens for the user, followed
+// by those the user has allowed that hold none.
+func (s *AuthService) ListAuthorizedApps(ctx context.Context, userID string) (_ []*domain.Grant, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ListAuthorizedApps")
+	defer func() { tracing.End(span, err) }()
+	var grants []*domain.Grant
+	if s.tokens != nil {
+		if grants, err = s.tokens.ListSubjectGrants(ctx, userID); err != nil {
+			return nil, fmt.Errorf("list authorized apps: %w", err)
+		}
+	}
+	consents, err := s.ListConsents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	holding := map[string]bool{}
+	for _, g := range grants {
+		holding[g.ClientID] = true
+	}
+	for _, c := range consents {
+		if !holding[c.ClientID] {
+			grants = append(grants, &domain.Grant{ClientID: c.ClientID, Scopes: c.Scopes, GrantedAt: c.GrantedAt})
+		}
+	}
+	return grants, nil
+}
+
+// RevokeApp revokes the access and refresh tokens issued to clientID for the user and forgets
+// the user's consent, so that the client has to ask again.
+func (s *AuthService) RevokeApp(ctx context.Context, userID, clientID string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RevokeApp")
+	defer func() { tracing.End(span, err) }()
+	if s.consents != nil {
+		if err := s.consents.Delete(ctx, userID, clientID); err != nil {
+			return fmt.Errorf("revoke consent: %w", err)
+		}
+	}
+	if s.tokens == nil {
+		return nil
+	}
+	if err := s.tokens.RevokeSubjectClientTokens(ctx, userID, clientID); err != nil {
+		return fmt.Errorf("revoke app: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditAppRevoked, UserID: userID, ClientID: clientID})
+	return nil
+}
+
+// RevokeOtherSessions signs the user out everywhere except the session identified by keepToken,
+// and revokes the OAuth2 access and refresh tokens issued to the user.
+func (s *AuthService) RevokeOtherSessions(ctx context.Context, userID, keepToken string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RevokeOtherSessions")
+	defer func() { tracing.End(span, err) }()
+	keepHash := ""
+	if keepToken != "" {
+		keepHash = HashSessionToken(keepToken)
+	}
+	if err := s.sessionRepo.DeleteByUser(ctx, userID, keepHash); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	if s.tokens != nil {
+		if err := s.tokens.RevokeSubjectTokens(ctx, userID); err != nil {
+			return fmt.Errorf("revoke tokens: %w", err)
+		}
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditSessionsRevoked, UserID: userID})
+	return nil
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+)
+
+// ErrInvalidStatus is returned for a status that is not one of domain.UserStatuses.
+var ErrInvalidStatus = errors.New("invalid user status")
+
+// SetUserStatus changes the user's lifecycle status. Moving a user to any status other than
+// active immediately signs them out everywhere and revokes their OAuth2 tokens; reactivating
+// restores access without restoring anything that was revoked.
+func (s *AuthService) SetUserStatus(ctx context.Context, userID string, status domain.UserStatus) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.SetUserStatus")
+	defer func() { tracing.End(span, err) }()
+	if !status.Valid() {
+		return ErrInvalidStatus
+	}
+	u, err := s.userRepo.ByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("set user status: %w", err)
+	}
+	if u == nil {
+		return user.ErrUserNotFound
+	}
+	if err := s.userRepo.SetStatus(ctx, userID, status); err != nil {
+		return fmt.Errorf("set user status: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{
+		Type:    domain.AuditUserStatusChanged,
+		UserID:  userID,
+		Details: map[string]string{"from": string(u.Status), "to": string(status)},
+	})
+	if status == domain.UserStatusActive {
+		return nil
+	}
+	return s.RevokeOtherSessions(ctx, userID, "")
+}
+
+// EnsureActive returns ErrAccountInactive unless the user exists and is active. It guards grants
+// that authenticate a user without a password or session, such as refresh tokens.
+func (s *AuthService) EnsureActive(ctx context.Context, userID string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.EnsureActive")
+	defer func() { tracing.End(span, err) }()
+	u, err := s.userRepo.ByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("check user status: %w", err)
+	}
+	if u == nil || !u.Active() {
+		return ErrAccountInactive
+	}
+	return nil
+}
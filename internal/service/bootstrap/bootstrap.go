@@ -0,0 +1,262 @@
+// Package bootstrap creates the OAuth2 clients, upstream connectors and admin users an instance
+// starts with, as declared in a YAML bootstrap file.
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+)
+
+// File is the content of a bootstrap file. Entries that already exist are left untouched, so the
+// file can stay in place across restarts.
+type File struct {
+	Admins     []Admin     `yaml:"admins"`
+	Clients    []Client    `yaml:"clients"`
+	Connectors []Connector `yaml:"connectors"`
+}
+
+// Admin is a local user granted the admin role. Email and Password are only needed to create
+// the user; an existing user is listed by Username alone.
+type Admin struct {
+	Username string    `yaml:"username"`
+	Email    string    `yaml:"email"`
+	Password SecretRef `yaml:"password"`
+}
+
+// Client is an OAuth2 client registration.
+type Client struct {
+	ClientID     string    `yaml:"client_id"`
+	RedirectURIs []string  `yaml:"redirect_uris"`
+	Secret       SecretRef `yaml:"secret"`
+}
+
+// Connector is an upstream OIDC identity provider.
+type Connector struct {
+	Issuer       string    `yaml:"issuer"`
+	ClientID     string    `yaml:"client_id"`
+	ClientSecret SecretRef `yaml:"client_secret"`
+	GroupsClaim  string    `yaml:"groups_claim"`
+}
+
+// SecretRef points at a secret instead of holding it, so that bootstrap files can be committed:
+// exactly one of File (read, without a trailing newline) and Env (an environment variable) is set.
+type SecretRef struct {
+	File string `yaml:"file"`
+	Env  string `yaml:"env"`
+}
+
+// Resolve reads the referenced secret.
+func (r SecretRef) Resolve() (string, error) {
+	switch {
+	case r.File != "" && r.Env != "":
+		return "", errors.New("file and env are mutually exclusive")
+	case r.File != "":
+		b, err := os.ReadFile(r.File)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	case r.Env != "":
+		v, ok := os.LookupEnv(r.Env)
+		if !ok || v == "" {
+			return "", fmt.Errorf("environment variable %s is not set", r.Env)
+		}
+		return v, nil
+	}
+	return "", errors.New("file or env is required")
+}
+
+// Load reads and validates the bootstrap file at path. Unknown keys are rejected so that typos do
+// not silently drop entries.
+func Load(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read bootstrap file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	var f File
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parse bootstrap file %s: %w", path, err)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bootstrap file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Validate reports entries without their identifying fields. Secrets are resolved by Apply, and
+// only for entries it creates.
+func (f *File) Validate() error {
+	var errs []error
+	for i, a := range f.Admins {
+		if a.Username == "" {
+			errs = append(errs, fmt.Errorf("admins[%d].username is required", i))
+		}
+	}
+	for i, c := range f.Clients {
+		if c.ClientID == "" {
+			errs = append(errs, fmt.Errorf("clients[%d].client_id is required", i))
+		}
+	}
+	for i, c := range f.Connectors {
+		if c.Issuer == "" || c.ClientID == "" {
+			errs = append(errs, fmt.Errorf("connectors[%d]: issuer and client_id are required", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Users creates and finds local users.
+// Interface is defined in the consuming (service) layer per project architecture.
+type Users interface {
+	// GetByUsername returns the user, or user.ErrUserNotFound.
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	CreateByAdmin(ctx context.Context, username, email, pwd string) (*domain.User, error)
+}
+
+// Clients creates and finds OAuth2 clients.
+type Clients interface {
+	// Get returns the client, or oauthclient.ErrClientNotFound.
+	Get(ctx context.Context, clientID string) (*domain.OAuth2Client, error)
+	CreateWithSecret(ctx context.Context, clientID string, redirectURIs []string, secret string) (*domain.OAuth2Client, error)
+}
+
+// Connectors creates and lists upstream connectors.
+type Connectors interface {
+	ListConnectors(ctx context.Context) ([]*domain.IdPConnector, error)
+	CreateConnector(ctx context.Context, c *domain.IdPConnector) error
+}
+
+// Admins grants the admin role.
+type Admins interface {
+	// BootstrapAdmins grants the admin role to each username and returns those not found.
+	BootstrapAdmins(ctx context.Context, usernames []string) ([]string, error)
+}
+
+// Service applies bootstrap files.
+type Service struct {
+	users      Users
+	clients    Clients
+	connectors Connectors
+	admins     Admins
+}
+
+// NewService creates a Service.
+func NewService(users Users, clients Clients, connectors Connectors, admins Admins) *Service {
+	return &Service{users: users, clients: clients, connectors: connectors, admins: admins}
+}
+
+// Result lists what Apply created and what already existed, as "kind name" strings.
+type Result struct {
+	Created  []string
+	Existing []string
+}
+
+// Apply creates every entry of f that does not exist yet and grants the admin role to every
+// listed admin. It stops at the first error; entries created before it are kept and are skipped
+// when the file is applied again.
+func (s *Service) Apply(ctx context.Context, f *File) (*Result, error) {
+	res := &Result{}
+	note := func(created bool, kind, name string) {
+		if created {
+			res.Created = append(res.Created, kind+" "+name)
+		} else {
+			res.Existing = append(res.Existing, kind+" "+name)
+		}
+	}
+
+	usernames := make([]string, 0, len(f.Admins))
+	for i, a := range f.Admins {
+		usernames = append(usernames, a.Username)
+		_, err := s.users.GetByUsername(ctx, a.Username)
+		if err == nil {
+			note(false, "user", a.Username)
+			continue
+		}
+		if !errors.Is(err, user.ErrUserNotFound) {
+			return res, err
+		}
+		if a.Email == "" {
+			return res, fmt.Errorf("admins[%d]: user %s does not exist and no email is given to create it", i, a.Username)
+		}
+		pwd, err := a.Password.Resolve()
+		if err != nil {
+			return res, fmt.Errorf("admins[%d].password: %w", i, err)
+		}
+		if _, err := s.users.CreateByAdmin(ctx, a.Username, a.Email, pwd); err != nil {
+			return res, fmt.Errorf("create user %s: %w", a.Username, err)
+		}
+		note(true, "user", a.Username)
+	}
+	if len(usernames) > 0 {
+		if _, err := s.admins.BootstrapAdmins(ctx, usernames); err != nil {
+			return res, err
+		}
+	}
+
+	for i, c := range f.Clients {
+		_, err := s.clients.Get(ctx, c.ClientID)
+		if err == nil {
+			note(false, "client", c.ClientID)
+			continue
+		}
+		if !errors.Is(err, oauthclient.ErrClientNotFound) {
+			return res, err
+		}
+		secret, err := c.Secret.Resolve()
+		if err != nil {
+			return res, fmt.Errorf("clients[%d].secret: %w", i, err)
+		}
+		if _, err := s.clients.CreateWithSecret(ctx, c.ClientID, c.RedirectURIs, secret); err != nil {
+			return res, fmt.Errorf("create client %s: %w", c.ClientID, err)
+		}
+		note(true, "client", c.ClientID)
+	}
+
+	if len(f.Connectors) > 0 {
+		existing, err := s.connectors.ListConnectors(ctx)
+		if err != nil {
+			return res, err
+		}
+		for i, c := range f.Connectors {
+			name := c.Issuer + " (" + c.ClientID + ")"
+			if hasConnector(existing, c) {
+				note(false, "connector", name)
+				continue
+			}
+			secret, err := c.ClientSecret.Resolve()
+			if err != nil {
+				return res, fmt.Errorf("connectors[%d].client_secret: %w", i, err)
+			}
+			conn := &domain.IdPConnector{Issuer: c.Issuer, ClientID: c.ClientID, ClientSecret: secret, GroupsClaim: c.GroupsClaim}
+			if err := s.connectors.CreateConnector(ctx, conn); err != nil {
+				return res, fmt.Errorf("create connector %s: %w", name, err)
+			}
+			existing = append(existing, conn)
+			note(true, "connector", name)
+		}
+	}
+	return res, nil
+}
+
+// hasConnector reports whether a connector for the same issuer and client ID exists.
+func hasConnector(existing []*domain.IdPConnector, c Connector) bool {
+	issuer := strings.TrimSuffix(c.Issuer, "/")
+	for _, e := range existing {
+		if strings.TrimSuffix(e.Issuer, "/") == issuer && e.ClientID == c.ClientID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+const testFile = `
+admins:
+  - username: root
+    email: root@example.com
+    password:
+      file: %PWFILE%
+clients:
+  - client_id: portal
+    redirect_uris: [https://portal.example.com/cb]
+    secret:
+      env: BOOTSTRAP_TEST_CLIENT_SECRET
+connectors:
+  - issuer: https://idp.example.com/
+    client_id: sso
+    client_secret:
+      env: BOOTSTRAP_TEST_CONNECTOR_SECRET
+    groups_claim: groups
+`
+
+func TestService_Apply(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:bootstrap?mode=memory&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	pwFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(pwFile, []byte("correct-horse\n"), 0o600))
+	path := filepath.Join(dir, "bootstrap.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(strings.ReplaceAll(testFile, "%PWFILE%", pwFile)), 0o600))
+	t.Setenv("BOOTSTRAP_TEST_CLIENT_SECRET", "portal-secret-0123456789")
+	t.Setenv("BOOTSTRAP_TEST_CONNECTOR_SECRET", "upstream-secret")
+
+	userRepo := storage.NewUserRepository(client)
+	users := user.NewUserService(userRepo)
+	clients := oauthclient.NewService(storage.NewOAuth2ClientRepository(client))
+	authSvc := auth.NewAuthService(userRepo, storage.NewSessionRepository(client))
+	connectors := federation.NewFederationService(storage.NewIdPConnectorRepository(client), federation.NewOIDCClientAdapter(), userRepo, authSvc)
+	roles := rbac.NewService(storage.NewRBACRepository(client), userRepo)
+	svc := NewService(users, clients, connectors, roles)
+
+	f, err := Load(path)
+	require.NoError(t, err)
+	res, err := svc.Apply(ctx, f)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user root", "client portal", "connector https://idp.example.com/ (sso)"}, res.Created)
+	require.Empty(t, res.Existing)
+
+	root, err := users.GetByUsername(ctx, "root")
+	require.NoError(t, err)
+	require.True(t, root.EmailVerified)
+	ok, _ := password.Verify("correct-horse", root.PasswordHash)
+	require.True(t, ok, "the trailing newline of the password file is not part of the password")
+	isAdmin, err := roles.HasRole(ctx, root.ID, rbac.AdminRole)
+	require.NoError(t, err)
+	require.True(t, isAdmin)
+	portal, err := clients.Get(ctx, "portal")
+	require.NoError(t, err)
+	ok, _ = password.Verify("portal-secret-0123456789", portal.SecretHash)
+	require.True(t, ok)
+
+	// Applying again changes nothing and no longer needs the secrets.
+	require.NoError(t, os.Remove(pwFile))
+	t.Setenv("BOOTSTRAP_TEST_CLIENT_SECRET", "")
+	f.Connectors[0].Issuer = "https://idp.example.com"
+	res, err = svc.Apply(ctx, f)
+	require.NoError(t, err)
+	require.Empty(t, res.Created)
+	require.Len(t, res.Existing, 3)
+	all, err := connectors.ListConnectors(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+
+	// Existing users are made admins by username alone; missing ones need an email to be created.
+	dana, err := users.CreateByAdmin(ctx, "dana", "dana@example.com", "password123")
+	require.NoError(t, err)
+	res, err = svc.Apply(ctx, &File{Admins: []Admin{{Username: "dana"}}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"user dana"}, res.Existing)
+	isAdmin, err = roles.HasRole(ctx, dana.ID, rbac.AdminRole)
+	require.NoError(t, err)
+	require.True(t, isAdmin)
+	_, err = svc.Apply(ctx, &File{Admins: []Admin{{Username: "ghost"}}})
+	require.ErrorContains(t, err, "admins[0]: user ghost does not exist and no email is given to create it")
+}
+
+func TestService_ApplyReportsMissingSecret(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:bootstrap-missing?mode=memory&_fk=1")
+	defer client.Close()
+	userRepo := storage.NewUserRepository(client)
+	svc := NewService(user.NewUserService(userRepo), oauthclient.NewService(storage.NewOAuth2ClientRepository(client)),
+		nil, rbac.NewService(storage.NewRBACRepository(client), userRepo))
+
+	_, err := svc.Apply(context.Background(), &File{Clients: []Client{{
+		ClientID:     "portal",
+		RedirectURIs: []string{"https://portal.example.com/cb"},
+		Secret:       SecretRef{Env: "BOOTSTRAP_TEST_UNSET"},
+	}}})
+	require.ErrorContains(t, err, "clients[0].secret: environment variable BOOTSTRAP_TEST_UNSET is not set")
+}
+
+func TestLoad_RejectsUnknownKeysAndMissingIDs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bootstrap.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("clients:\n  - clientid: typo\n"), 0o600))
+	_, err := Load(path)
+	require.ErrorContains(t, err, "field clientid not found")
+
+	require.NoError(t, os.WriteFile(path, []byte("admins:\n  - email: a@example.com\nconnectors:\n  - issuer: https://idp\n"), 0o600))
+	_, err = Load(path)
+	require.ErrorContains(t, err, "admins[0].username is required")
+	require.ErrorContains(t, err, "connectors[0]: issuer and client_id are required")
+}
@@ -0,0 +1,102 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package cleanup periodically removes expired server-side state (sessions, codes, tokens).
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// Purger deletes up to limit records that expired before now and reports how many it removed.
+type Purger interface {
+	PurgeExpired(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// Config holds reaper configuration matching the "cleanup" section of settings.yaml.
+type Config struct {
+	Interval  time.Duration `mapstructure:"interval"`   // time between sweeps
+	BatchSize int           `mapstructure:"batch_size"` // max records deleted per purge call
+}
+
+const (
+	defaultInterval  = 10 * time.Minute
+	defaultBatchSize = 500
+)
+
+type namedPurger struct {
+	name string
+	p    Purger
+}
+
+// Reaper sweeps registered Purgers on a fixed interval, deleting in batches until each is drained.
+type Reaper struct {
+	cfg     Config
+	logger  log.Logger
+	purgers []namedPurger
+}
+
+// NewReaper creates a Reaper. Zero config values fall back to defaults; logger may be nil.
+func NewReaper(cfg Config, logger log.Logger) *Reaper {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	return &Reaper{cfg: cfg, logger: logger}
+}
+
+// Register adds a Purger under name (used in logs).
+func (r *Reaper) Register(name string, p Purger) {
+	r.purgers = append(r.purgers, namedPurger{name: name, p: p})
+}
+
+// Run sweeps immediately and then every Interval until ctx is cancelled.
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.RunOnce(ctx); err != nil && r.logger != nil && ctx.Err() == nil {
+			r.logger.Warn("cleanup sweep failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce drains every Purger in batches of BatchSize. Errors from one Purger do not stop the others;
+// the first error is returned.
+func (r *Reaper) RunOnce(ctx context.Context) error {
+	var firstErr error
+	now := time.Now()
+	for _, np := range r.purgers {
+		total := 0
+		for ctx.Err() == nil {
+			n, err := np.p.PurgeExpired(ctx, now, r.cfg.BatchSize)
+			total += n
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("purge %s: %w", np.name, err)
+				}
+				break
+			}
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+		if total > 0 && r.logger != nil {
+			r.logger.Info("cleanup purged expired records", zap.String("store", np.name), zap.Int("count", total))
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,44 @@
+package cleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestReaper_PurgesExpiredSessionsInBatches(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(client)
+	sessionRepo := storage.NewSessionRepository(client)
+	u := &domain.User{Username: "kate", Email: "kate@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, sessionRepo.Create(ctx, &domain.Session{
+			UserID:    u.ID,
+			TokenHash: "expired-" + string(rune('a'+i)),
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}))
+	}
+	live := &domain.Session{UserID: u.ID, TokenHash: "live", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, sessionRepo.Create(ctx, live))
+
+	reaper := NewReaper(Config{BatchSize: 2}, nil)
+	reaper.Register("sessions", sessionRepo)
+	require.NoError(t, reaper.RunOnce(ctx))
+
+	remaining, err := client.Session.Query().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	require.Equal(t, "live", remaining[0].TokenHash)
+}
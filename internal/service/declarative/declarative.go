@@ -0,0 +1,415 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+// Package declarative keeps OAuth2 clients and upstream connectors in sync with YAML files kept
+// under version control: it diffs the declared state against the database, shows the plan and
+// creates, updates and (optionally) prunes entries to match.
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/service/bootstrap"
+)
+
+// State is the declared set of clients and connectors. It has the clients and connectors
+// sections of a bootstrap file; secrets are bootstrap.SecretRef references, never literals.
+type State struct {
+	Clients    []bootstrap.Client    `yaml:"clients"`
+	Connectors []bootstrap.Connector `yaml:"connectors"`
+}
+
+// Load reads the files at paths into one State. A directory contributes its *.yaml and *.yml
+// files in name order, skipping hidden ones. Unknown keys, entries without their identifying
+// fields or secret, and entries declared twice are rejected.
+func Load(paths ...string) (*State, error) {
+	var files []string
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			ext := filepath.Ext(name)
+			if e.IsDir() || strings.HasPrefix(name, ".") || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			files = append(files, filepath.Join(p, name))
+		}
+	}
+
+	st := &State{}
+	seen := make(map[string]string)
+	var errs []error
+	declare := func(key, name, file string) {
+		if prev, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("%s is declared in %s and %s", name, prev, file))
+		}
+		seen[key] = file
+	}
+	for _, file := range files {
+		part, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range part.Clients {
+			declare("client "+strings.TrimSpace(c.ClientID), clientName(c.ClientID), file)
+		}
+		for _, c := range part.Connectors {
+			declare("connector "+connectorKey(c.Issuer, c.ClientID), connectorName(c.Issuer, c.ClientID), file)
+		}
+		st.Clients = append(st.Clients, part.Clients...)
+		st.Connectors = append(st.Connectors, part.Connectors...)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func loadFile(path string) (*State, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	var st State
+	if err := dec.Decode(&st); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := st.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &st, nil
+}
+
+// Validate reports entries without their identifying fields or secret reference. The secrets
+// themselves are resolved when planning.
+func (st *State) Validate() error {
+	var errs []error
+	for i, c := range st.Clients {
+		if c.ClientID == "" {
+			errs = append(errs, fmt.Errorf("clients[%d].client_id is required", i))
+		}
+		if c.Secret == (bootstrap.SecretRef{}) {
+			errs = append(errs, fmt.Errorf("clients[%d].secret: file or env is required", i))
+		}
+	}
+	for i, c := range st.Connectors {
+		if c.Issuer == "" || c.ClientID == "" {
+			errs = append(errs, fmt.Errorf("connectors[%d]: issuer and client_id are required", i))
+		}
+		if c.ClientSecret == (bootstrap.SecretRef{}) {
+			errs = append(errs, fmt.Errorf("connectors[%d].client_secret: file or env is required", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Clients manages OAuth2 clients.
+// Interface is defined in the consuming (service) layer per project architecture.
+type Clients interface {
+	List(ctx context.Context) ([]*domain.OAuth2Client, error)
+	CreateWithSecret(ctx context.Context, clientID string, redirectURIs []string, secret string) (*domain.OAuth2Client, error)
+	UpdateRedirectURIs(ctx context.Context, clientID string, redirectURIs []string) (*domain.OAuth2Client, error)
+	SetSecret(ctx context.Context, clientID, secret string) error
+	SecretMatches(c *domain.OAuth2Client, secret string) bool
+	Delete(ctx context.Context, clientID string) error
+}
+
+// Connectors manages upstream connectors.
+type Connectors interface {
+	ListConnectors(ctx context.Context) ([]*domain.IdPConnector, error)
+	CreateConnector(ctx context.Context, c *domain.IdPConnector) error
+	UpdateConnector(ctx context.Context, c *domain.IdPConnector) error
+	DeleteConnector(ctx context.Context, id string) error
+}
+
+// Service plans and applies States.
+type Service struct {
+	clients    Clients
+	connectors Connectors
+}
+
+// NewService creates a Service.
+func NewService(clients Clients, connectors Connectors) *Service {
+	return &Service{clients: clients, connectors: connectors}
+}
+
+// Action is what a Change does.
+type Action string
+
+// Actions of a Change.
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is one step of a Plan.
+type Change struct {
+	Action Action
+	Kind   string   // "client" or "connector"
+	Name   string   // client ID, or "issuer (client ID)" for connectors
+	Fields []string // fields an update changes
+
+	apply func(context.Context) error
+}
+
+// String formats the change as a plan line: "+ client web", "~ client web (secret)" or
+// "- connector https://idp.example.com (sso)".
+func (c Change) String() string {
+	sign := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}[c.Action]
+	s := sign + " " + c.Kind + " " + c.Name
+	if len(c.Fields) > 0 {
+		s += " (" + strings.Join(c.Fields, ", ") + ")"
+	}
+	return s
+}
+
+// Plan is the list of changes that brings the database to a State.
+type Plan struct {
+	Changes []Change
+	// Unchanged counts declared entries that already match.
+	Unchanged int
+	// Undeclared lists entries in the database that are not declared and are kept because the
+	// plan was made without pruning.
+	Undeclared []string
+}
+
+// Summary counts the changes by action, e.g. "1 to create, 2 to update, 0 to delete, 3 unchanged".
+func (p *Plan) Summary() string {
+	n := map[Action]int{}
+	for _, c := range p.Changes {
+		n[c.Action]++
+	}
+	return fmt.Sprintf("%d to create, %d to update, %d to delete, %d unchanged",
+		n[ActionCreate], n[ActionUpdate], n[ActionDelete], p.Unchanged)
+}
+
+// ErrNothingDeclared is returned by Plan when pruning a state that declares no client or
+// connector, which would delete every one of them. An emptied or unmounted directory is far more
+// often a mistake than intent; PlanOptions.AllowEmpty opts in.
+var ErrNothingDeclared = errors.New("no clients or connectors declared; refusing to prune everything")
+
+// PlanOptions controls Plan.
+type PlanOptions struct {
+	// Prune deletes clients and connectors that the state does not declare.
+	Prune bool
+	// AllowEmpty lets Prune delete everything when the state declares nothing.
+	AllowEmpty bool
+}
+
+// Plan diffs st against the database. Every declared secret is resolved, since secrets are
+// compared too: a client's against its stored hash, a connector's against the stored value. With
+// opts.Prune, clients and connectors that st does not declare are deleted; if st declares
+// nothing, Plan returns ErrNothingDeclared unless opts.AllowEmpty is set.
+func (s *Service) Plan(ctx context.Context, st *State, opts PlanOptions) (*Plan, error) {
+	if opts.Prune && !opts.AllowEmpty && len(st.Clients) == 0 && len(st.Connectors) == 0 {
+		return nil, ErrNothingDeclared
+	}
+	p := &Plan{}
+	if err := s.planClients(ctx, st.Clients, opts.Prune, p); err != nil {
+		return nil, err
+	}
+	if err := s.planConnectors(ctx, st.Connectors, opts.Prune, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *Service) planClients(ctx context.Context, declared []bootstrap.Client, prune bool, p *Plan) error {
+	existing, err := s.clients.List(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*domain.OAuth2Client, len(existing))
+	for _, c := range existing {
+		byID[c.ClientID] = c
+	}
+	for _, want := range declared {
+		id := strings.TrimSpace(want.ClientID)
+		secret, err := want.Secret.Resolve()
+		if err != nil {
+			return fmt.Errorf("%s: secret: %w", clientName(id), err)
+		}
+		uris := want.RedirectURIs
+		have, ok := byID[id]
+		if !ok {
+			p.Changes = append(p.Changes, Change{Action: ActionCreate, Kind: "client", Name: id,
+				apply: func(ctx context.Context) error {
+					_, err := s.clients.CreateWithSecret(ctx, id, uris, secret)
+					return err
+				}})
+			continue
+		}
+		delete(byID, id)
+		var fields []string
+		var steps []func(context.Context) error
+		if !slices.Equal(normalizeURIs(uris), have.RedirectURIs) {
+			fields = append(fields, "redirect_uris")
+			steps = append(steps, func(ctx context.Context) error {
+				_, err := s.clients.UpdateRedirectURIs(ctx, id, uris)
+				return err
+			})
+		}
+		if !s.clients.SecretMatches(have, secret) {
+			fields = append(fields, "secret")
+			steps = append(steps, func(ctx context.Context) error {
+				return s.clients.SetSecret(ctx, id, secret)
+			})
+		}
+		if len(fields) == 0 {
+			p.Unchanged++
+			continue
+		}
+		p.Changes = append(p.Changes, Change{Action: ActionUpdate, Kind: "client", Name: id, Fields: fields,
+			apply: func(ctx context.Context) error {
+				for _, step := range steps {
+					if err := step(ctx); err != nil {
+						return err
+					}
+				}
+				return nil
+			}})
+	}
+	for _, id := range sortedKeys(byID) {
+		if !prune {
+			p.Undeclared = append(p.Undeclared, clientName(id))
+			continue
+		}
+		p.Changes = append(p.Changes, Change{Action: ActionDelete, Kind: "client", Name: id,
+			apply: func(ctx context.Context) error { return s.clients.Delete(ctx, id) }})
+	}
+	return nil
+}
+
+func (s *Service) planConnectors(ctx context.Context, declared []bootstrap.Connector, prune bool, p *Plan) error {
+	existing, err := s.connectors.ListConnectors(ctx)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]*domain.IdPConnector, len(existing))
+	for _, c := range existing {
+		byKey[connectorKey(c.Issuer, c.ClientID)] = c
+	}
+	for _, want := range declared {
+		name := connectorLabel(want.Issuer, want.ClientID)
+		secret, err := want.ClientSecret.Resolve()
+		if err != nil {
+			return fmt.Errorf("%s: client_secret: %w", connectorName(want.Issuer, want.ClientID), err)
+		}
+		conn := &domain.IdPConnector{
+			Issuer:       strings.TrimSpace(want.Issuer),
+			ClientID:     strings.TrimSpace(want.ClientID),
+			ClientSecret: strings.TrimSpace(secret),
+			GroupsClaim:  strings.TrimSpace(want.GroupsClaim),
+		}
+		key := connectorKey(want.Issuer, want.ClientID)
+		have, ok := byKey[key]
+		if !ok {
+			p.Changes = append(p.Changes, Change{Action: ActionCreate, Kind: "connector", Name: name,
+				apply: func(ctx context.Context) error { return s.connectors.CreateConnector(ctx, conn) }})
+			continue
+		}
+		delete(byKey, key)
+		var fields []string
+		if conn.ClientSecret != have.ClientSecret {
+			fields = append(fields, "client_secret")
+		}
+		if conn.GroupsClaim != have.GroupsClaim {
+			fields = append(fields, "groups_claim")
+		}
+		if len(fields) == 0 {
+			p.Unchanged++
+			continue
+		}
+		conn.ID = have.ID
+		conn.Issuer = have.Issuer
+		p.Changes = append(p.Changes, Change{Action: ActionUpdate, Kind: "connector", Name: name, Fields: fields,
+			apply: func(ctx context.Context) error { return s.connectors.UpdateConnector(ctx, conn) }})
+	}
+	for _, key := range sortedKeys(byKey) {
+		c := byKey[key]
+		if !prune {
+			p.Undeclared = append(p.Undeclared, connectorName(c.Issuer, c.ClientID))
+			continue
+		}
+		p.Changes = append(p.Changes, Change{Action: ActionDelete, Kind: "connector", Name: connectorLabel(c.Issuer, c.ClientID),
+			apply: func(ctx context.Context) error { return s.connectors.DeleteConnector(ctx, c.ID) }})
+	}
+	return nil
+}
+
+// Apply carries out the plan's changes in order and returns those it completed. It stops at the
+// first error; planning again afterwards picks up the remaining changes.
+func (s *Service) Apply(ctx context.Context, p *Plan) ([]Change, error) {
+	done := make([]Change, 0, len(p.Changes))
+	for _, c := range p.Changes {
+		if err := c.apply(ctx); err != nil {
+			return done, fmt.Errorf("%s %s %s: %w", c.Action, c.Kind, c.Name, err)
+		}
+		done = append(done, c)
+	}
+	return done, nil
+}
+
+// normalizeURIs trims and de-duplicates uris the way the client service stores them.
+func normalizeURIs(uris []string) []string {
+	out := make([]string, 0, len(uris))
+	for _, u := range uris {
+		u = strings.TrimSpace(u)
+		if u != "" && !slices.Contains(out, u) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// connectorKey identifies a connector by issuer, ignoring a trailing slash, and client ID.
+func connectorKey(issuer, clientID string) string {
+	return strings.TrimSuffix(strings.TrimSpace(issuer), "/") + " " + strings.TrimSpace(clientID)
+}
+
+func connectorLabel(issuer, clientID string) string {
+	return strings.TrimSpace(issuer) + " (" + strings.TrimSpace(clientID) + ")"
+}
+
+func clientName(clientID string) string {
+	return "client " + strings.TrimSpace(clientID)
+}
+
+func connectorName(issuer, clientID string) string {
+	return "connector " + connectorLabel(issuer, clientID)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,182 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/bootstrap"
+	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+const portalFile = `
+clients:
+  - client_id: portal
+    redirect_uris: [https://portal.example.com/cb]
+    secret:
+      env: DECLARATIVE_TEST_PORTAL_SECRET
+`
+
+const idpFile = `
+connectors:
+  - issuer: https://idp.example.com
+    client_id: sso
+    client_secret:
+      file: %s
+    groups_claim: groups
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func changeLines(changes []Change) []string {
+	out := make([]string, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, c.String())
+	}
+	return out
+}
+
+func TestService_PlanAndApply(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:declarative?mode=memory&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+
+	userRepo := storage.NewUserRepository(client)
+	clients := oauthclient.NewService(storage.NewOAuth2ClientRepository(client))
+	connectors := federation.NewFederationService(storage.NewIdPConnectorRepository(client), federation.NewOIDCClientAdapter(),
+		userRepo, auth.NewAuthService(userRepo, storage.NewSessionRepository(client)))
+	svc := NewService(clients, connectors)
+
+	// An entry created by hand, outside the files.
+	_, _, err := clients.Create(ctx, "manual", []string{"https://manual.example.com/cb"})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	secretFile := filepath.Join(t.TempDir(), "idp-secret")
+	writeFile(t, secretFile, "upstream-secret\n")
+	writeFile(t, filepath.Join(dir, "clients.yaml"), portalFile)
+	writeFile(t, filepath.Join(dir, "idp.yml"), fmt.Sprintf(idpFile, secretFile))
+	writeFile(t, filepath.Join(dir, ".clients.yaml.swp"), "not yaml: [")
+	writeFile(t, filepath.Join(dir, "README.md"), "# not applied")
+	t.Setenv("DECLARATIVE_TEST_PORTAL_SECRET", "portal-secret-0123456789")
+
+	st, err := Load(dir)
+	require.NoError(t, err)
+	plan, err := svc.Plan(ctx, st, PlanOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"+ client portal", "+ connector https://idp.example.com (sso)"}, changeLines(plan.Changes))
+	require.Equal(t, []string{"client manual"}, plan.Undeclared)
+	done, err := svc.Apply(ctx, plan)
+	require.NoError(t, err)
+	require.Len(t, done, 2)
+
+	plan, err = svc.Plan(ctx, st, PlanOptions{})
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes, "applying the same files again changes nothing")
+	require.Equal(t, 2, plan.Unchanged)
+
+	// Changed redirect URIs, a rotated secret and a new groups claim are updates; with prune the
+	// undeclared client is deleted.
+	t.Setenv("DECLARATIVE_TEST_PORTAL_SECRET", "portal-secret-rotated-0123")
+	writeFile(t, secretFile, "upstream-secret-2\n")
+	st.Clients[0].RedirectURIs = []string{"https://portal.example.com/cb", "https://portal.example.com/cb2"}
+	st.Connectors[0].GroupsClaim = "roles"
+	plan, err = svc.Plan(ctx, st, PlanOptions{Prune: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"~ client portal (redirect_uris, secret)",
+		"- client manual",
+		"~ connector https://idp.example.com (sso) (client_secret, groups_claim)",
+	}, changeLines(plan.Changes))
+	_, err = svc.Apply(ctx, plan)
+	require.NoError(t, err)
+
+	portal, err := clients.Get(ctx, "portal")
+	require.NoError(t, err)
+	require.Len(t, portal.RedirectURIs, 2)
+	ok, _ := password.Verify("portal-secret-rotated-0123", portal.SecretHash)
+	require.True(t, ok)
+	_, err = clients.Get(ctx, "manual")
+	require.ErrorIs(t, err, oauthclient.ErrClientNotFound)
+	all, err := connectors.ListConnectors(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, &domain.IdPConnector{ID: all[0].ID, Issuer: "https://idp.example.com", ClientID: "sso",
+		ClientSecret: "upstream-secret-2", GroupsClaim: "roles"}, all[0])
+
+	// Pruning an empty state is refused unless explicitly allowed, and then deletes everything.
+	_, err = svc.Plan(ctx, &State{}, PlanOptions{Prune: true})
+	require.ErrorIs(t, err, ErrNothingDeclared)
+	plan, err = svc.Plan(ctx, &State{}, PlanOptions{Prune: true, AllowEmpty: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"- client portal", "- connector https://idp.example.com (sso)"}, changeLines(plan.Changes))
+}
+
+func TestSyncer_RefusesToPruneEverything(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:declarative-empty?mode=memory&_fk=1")
+	defer client.Close()
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(client)
+	clients := oauthclient.NewService(storage.NewOAuth2ClientRepository(client))
+	connectors := federation.NewFederationService(storage.NewIdPConnectorRepository(client), federation.NewOIDCClientAdapter(),
+		userRepo, auth.NewAuthService(userRepo, storage.NewSessionRepository(client)))
+	svc := NewService(clients, connectors)
+	_, _, err := clients.Create(ctx, "manual", []string{"https://manual.example.com/cb"})
+	require.NoError(t, err)
+
+	// An empty directory, as left by a failed checkout or a missing volume, deletes nothing.
+	cfg := Config{Dir: t.TempDir(), Prune: true}
+	require.ErrorIs(t, NewSyncer(svc, cfg, zap.NewNop()).SyncOnce(ctx), ErrNothingDeclared)
+	_, err = clients.Get(ctx, "manual")
+	require.NoError(t, err)
+
+	cfg.AllowEmpty = true
+	require.NoError(t, NewSyncer(svc, cfg, zap.NewNop()).SyncOnce(ctx))
+	_, err = clients.Get(ctx, "manual")
+	require.ErrorIs(t, err, oauthclient.ErrClientNotFound)
+}
+
+func TestService_PlanReportsMissingSecret(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:declarative-missing?mode=memory&_fk=1")
+	defer client.Close()
+	svc := NewService(oauthclient.NewService(storage.NewOAuth2ClientRepository(client)), nil)
+
+	_, err := svc.Plan(context.Background(), &State{Clients: []bootstrap.Client{{
+		ClientID:     "portal",
+		RedirectURIs: []string{"https://portal.example.com/cb"},
+		Secret:       bootstrap.SecretRef{Env: "DECLARATIVE_TEST_UNSET"},
+	}}}, PlanOptions{})
+	require.ErrorContains(t, err, "client portal: secret: environment variable DECLARATIVE_TEST_UNSET is not set")
+}
+
+func TestLoad_RejectsDuplicatesAndLiteralSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), portalFile)
+	writeFile(t, filepath.Join(dir, "b.yaml"), portalFile)
+	_, err := Load(dir)
+	require.ErrorContains(t, err, "client portal is declared in "+filepath.Join(dir, "a.yaml")+" and "+filepath.Join(dir, "b.yaml"))
+
+	path := filepath.Join(dir, "a.yaml")
+	writeFile(t, path, "clients:\n  - client_id: portal\n    secret: hunter2\n")
+	_, err = Load(path)
+	require.ErrorContains(t, err, "cannot unmarshal")
+
+	writeFile(t, path, "connectors:\n  - issuer: https://idp.example.com\n    client_id: sso\n")
+	_, err = Load(path)
+	require.ErrorContains(t, err, "connectors[0].client_secret: file or env is required")
+}
@@ -0,0 +1,106 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+// Config holds the "declarative" section of settings.yaml.
+type Config struct {
+	Dir        string        `mapstructure:"dir"`         // directory of YAML files to keep in sync; empty disables
+	Prune      bool          `mapstructure:"prune"`       // delete clients and connectors the files do not declare
+	AllowEmpty bool          `mapstructure:"allow_empty"` // with Prune, also sync a directory declaring nothing, deleting everything
+	Interval   time.Duration `mapstructure:"interval"`    // also re-apply this often, reverting changes made elsewhere; 0 disables
+}
+
+// settle is how long the syncer waits after a file event for further events, so that a checkout
+// touching several files is applied once.
+const settle = 500 * time.Millisecond
+
+// Syncer applies a directory at startup and again whenever a file in it changes.
+type Syncer struct {
+	svc    *Service
+	cfg    Config
+	logger log.Logger
+}
+
+// NewSyncer creates a Syncer for cfg.Dir.
+func NewSyncer(svc *Service, cfg Config, logger log.Logger) *Syncer {
+	return &Syncer{svc: svc, cfg: cfg, logger: logger}
+}
+
+// SyncOnce loads the directory, plans and applies it, logging each change. With Prune it
+// returns ErrNothingDeclared, changing nothing, when the directory is empty unless AllowEmpty
+// is set.
+func (s *Syncer) SyncOnce(ctx context.Context) error {
+	st, err := Load(s.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	p, err := s.svc.Plan(ctx, st, PlanOptions{Prune: s.cfg.Prune, AllowEmpty: s.cfg.AllowEmpty})
+	if errors.Is(err, ErrNothingDeclared) {
+		return fmt.Errorf("%s: %w (set declarative.allow_empty to allow)", s.cfg.Dir, err)
+	}
+	if err != nil {
+		return err
+	}
+	done, err := s.svc.Apply(ctx, p)
+	for _, c := range done {
+		s.logger.Info("declarative change applied", zap.String("change", c.String()))
+	}
+	return err
+}
+
+// Run watches the directory until ctx is cancelled, re-applying it after changes and every
+// Interval. Failures are logged and leave the database as the last successful sync left it,
+// apart from changes applied before the failing one.
+func (s *Syncer) Run(ctx context.Context) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.logger.Error("watch declarative dir", zap.Error(err))
+		return
+	}
+	defer w.Close()
+	if err := w.Add(s.cfg.Dir); err != nil {
+		s.logger.Error("watch declarative dir", zap.String("dir", s.cfg.Dir), zap.Error(err))
+		return
+	}
+
+	var tick <-chan time.Time
+	if s.cfg.Interval > 0 {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	debounce := time.NewTimer(settle)
+	debounce.Stop()
+	sync := func() {
+		if err := s.SyncOnce(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("apply declarative dir", zap.String("dir", s.cfg.Dir), zap.Error(err))
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.Events:
+			debounce.Reset(settle)
+		case err := <-w.Errors:
+			s.logger.Warn("watch declarative dir", zap.Error(err))
+		case <-debounce.C:
+			sync()
+		case <-tick:
+			sync()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
+)
+
+// ErrInvalidConnector is returned when a connector's issuer or client credentials are malformed.
+var ErrInvalidConnector = errors.New("invalid connector")
+
+// GetConnector returns the connector with the given ID, or ErrConnectorNotFound.
+func (s *FederationService) GetConnector(ctx context.Context, id string) (_ *domain.IdPConnector, err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.GetConnector")
+	defer func() { tracing.End(span, err) }()
+	conn, err := s.connectorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get connector: %w", err)
+	}
+	if conn == nil {
+		return nil, ErrConnectorNotFound
+	}
+	return conn, nil
+}
+
+// CreateConnector validates and persists a new upstream IdP connector.
+func (s *FederationService) CreateConnector(ctx context.Context, c *domain.IdPConnector) (err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.CreateConnector")
+	defer func() { tracing.End(span, err) }()
+	if err := normalizeConnector(c); err != nil {
+		return err
+	}
+	if c.ClientSecret == "" {
+		return fmt.Errorf("%w: client secret is required", ErrInvalidConnector)
+	}
+	if err := s.connectorRepo.Create(ctx, c); err != nil {
+		return fmt.Errorf("create connector: %w", err)
+	}
+	return nil
+}
+
+// UpdateConnector replaces the connector's settings. An empty ClientSecret keeps the stored one.
+func (s *FederationService) UpdateConnector(ctx context.Context, c *domain.IdPConnector) (err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.UpdateConnector")
+	defer func() { tracing.End(span, err) }()
+	existing, err := s.GetConnector(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if err := normalizeConnector(c); err != nil {
+		return err
+	}
+	if c.ClientSecret == "" {
+		c.ClientSecret = existing.ClientSecret
+	}
+	if err := s.connectorRepo.Update(ctx, c); err != nil {
+		return fmt.Errorf("update connector: %w", err)
+	}
+	return nil
+}
+
+// DeleteConnector removes the connector. Users it created and groups it synced are kept.
+func (s *FederationService) DeleteConnector(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.DeleteConnector")
+	defer func() { tracing.End(span, err) }()
+	ok, err := s.connectorRepo.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete connector: %w", err)
+	}
+	if !ok {
+		return ErrConnectorNotFound
+	}
+	return nil
+}
+
+// normalizeConnector trims c's fields and requires an absolute http(s) issuer and a client ID.
+func normalizeConnector(c *domain.IdPConnector) error {
+	c.Issuer = strings.TrimSpace(c.Issuer)
+	c.ClientID = strings.TrimSpace(c.ClientID)
+	c.ClientSecret = strings.TrimSpace(c.ClientSecret)
+	c.GroupsClaim = strings.TrimSpace(c.GroupsClaim)
+	u, err := url.Parse(c.Issuer)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: issuer must be an absolute http(s) URL", ErrInvalidConnector)
+	}
+	if c.ClientID == "" {
+		return fmt.Errorf("%w: client ID is required", ErrInvalidConnector)
+	}
+	return nil
+}
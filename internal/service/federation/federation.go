@@ -9,22 +9,84 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/qinzj/superpowers-demo/internal/domain"
 	"github.com/qinzj/superpowers-demo/internal/infra/oidc_client"
 	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
 )
 
+var tracer = otel.Tracer("github.com/qinzj/superpowers-demo/internal/service/federation")
+
 // ErrConnectorNotFound is returned when the IdP connector does not exist.
 var ErrConnectorNotFound = errors.New("connector not found")
 
+// ErrEmailNotVerified is returned when an upstream identity that is not linked yet has the email
+// of a local user but the upstream IdP does not assert email_verified, so it cannot be trusted to
+// sign in as that user.
+var ErrEmailNotVerified = errors.New("upstream email is not verified")
+
 // FederationService handles upstream IdP login and identity linking.
 type FederationService struct {
 	connectorRepo IdPConnectorRepository
 	oidcExchange  OIDCExchange
 	userRepo      user.UserRepository
+	identities    IdentityRepository
 	authSvc       *auth.AuthService
+	groups        GroupSyncer
+	auditor       auth.Auditor
+	events        user.EventPublisher
+	metrics       Metrics
+}
+
+// Option configures a FederationService.
+type Option func(*FederationService)
+
+// WithGroupSync enables syncing upstream groups for connectors that configure a groups claim.
+func WithGroupSync(g GroupSyncer) Option {
+	return func(s *FederationService) {
+		s.groups = g
+	}
+}
+
+// WithIdentityRepository sets where upstream identities are linked to local users. Without it
+// sign-ins are matched by email alone, and identity.linked is only published for new users.
+func WithIdentityRepository(r IdentityRepository) Option {
+	return func(s *FederationService) {
+		s.identities = r
+	}
+}
+
+// WithAuditor sets the Auditor that receives federated sign-in events.
+func WithAuditor(a auth.Auditor) Option {
+	return func(s *FederationService) {
+		s.auditor = a
+	}
+}
+
+// WithEventPublisher sets the EventPublisher that receives user.created, identity.linked and
+// user.login for federated sign-ins.
+func WithEventPublisher(p user.EventPublisher) Option {
+	return func(s *FederationService) {
+		s.events = p
+	}
+}
+
+// Metrics counts federated sign-ins by connector and outcome.
+type Metrics interface {
+	FederationLogin(connectorID, outcome string)
+}
+
+// WithMetrics sets the Metrics that count federated sign-ins.
+func WithMetrics(m Metrics) Option {
+	return func(s *FederationService) {
+		s.metrics = m
+	}
 }
 
 // NewFederationService creates a FederationService with the given dependencies.
@@ -33,21 +95,30 @@ func NewFederationService(
 	oidcExchange OIDCExchange,
 	userRepo user.UserRepository,
 	authSvc *auth.AuthService,
+	opts ...Option,
 ) *FederationService {
-	return &FederationService{
+	s := &FederationService{
 		connectorRepo: connectorRepo,
 		oidcExchange: oidcExchange,
 		userRepo:     userRepo,
 		authSvc:      authSvc,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // LoginWithUpstream exchanges the auth code for tokens, fetches userinfo from upstream IdP,
-// maps/links identity to a local User, and creates a Session.
+// maps/links identity to a local User, syncs upstream groups when the connector has a groups
+// claim, and creates a Session. It returns auth.ErrAccountInactive when the linked user is not
+// active.
 func (s *FederationService) LoginWithUpstream(
 	ctx context.Context,
 	connectorID, state, code, redirectURI string,
-) (*domain.Session, error) {
+) (_ *domain.Session, err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.LoginWithUpstream", trace.WithAttributes(attribute.String("connector_id", connectorID)))
+	defer func() { tracing.End(span, err) }()
 	_ = state // state validation can be done at handler layer
 
 	connector, err := s.connectorRepo.GetByID(ctx, connectorID)
@@ -60,25 +131,91 @@ func (s *FederationService) LoginWithUpstream(
 
 	userInfo, err := s.oidcExchange.ExchangeAndUserInfo(ctx, connector, code, redirectURI)
 	if err != nil {
+		s.audit(ctx, connector.ID, "", "upstream_exchange_failed")
+		s.observe(connector.ID, "upstream_exchange_failed")
 		return nil, err
 	}
 
-	// Resolve or create local user by email or upstream subject
-	u, err := s.resolveOrCreateUser(ctx, userInfo)
+	u, created, linked, err := s.resolveOrCreateUser(ctx, connector.ID, userInfo)
 	if err != nil {
+		s.audit(ctx, connector.ID, "", "resolve_user_failed")
+		s.observe(connector.ID, "resolve_user_failed")
 		return nil, err
 	}
+	if created {
+		s.publish(ctx, domain.WebhookUserCreated, u, connector.ID, map[string]string{"source": "federation"})
+	}
+	if linked {
+		s.publish(ctx, domain.WebhookIdentityLinked, u, connector.ID, map[string]string{"upstream_subject": userInfo.Sub})
+	}
+	if !u.Active() {
+		s.audit(ctx, connector.ID, u.ID, "inactive")
+		s.observe(connector.ID, "inactive")
+		return nil, auth.ErrAccountInactive
+	}
+
+	if connector.GroupsClaim != "" && s.groups != nil {
+		if err := s.groups.SyncConnectorGroups(ctx, connector.ID, u.ID, userInfo.Groups); err != nil {
+			s.audit(ctx, connector.ID, u.ID, "group_sync_failed")
+			s.observe(connector.ID, "group_sync_failed")
+			return nil, fmt.Errorf("sync upstream groups: %w", err)
+		}
+	}
 
-	return s.authSvc.CreateSession(ctx, u.ID)
+	sess, err := s.authSvc.CreateSession(ctx, u.ID, false)
+	if err != nil {
+		s.audit(ctx, connector.ID, u.ID, "session_failed")
+		s.observe(connector.ID, "session_failed")
+		return nil, err
+	}
+	s.audit(ctx, connector.ID, u.ID, "")
+	s.observe(connector.ID, "success")
+	s.publish(ctx, domain.WebhookUserLogin, u, connector.ID, map[string]string{"method": "federation"})
+	return sess, nil
+}
+
+// publish announces a federated lifecycle event about u with the connector and extra data.
+func (s *FederationService) publish(ctx context.Context, t domain.WebhookEventType, u *domain.User, connectorID string, extra map[string]string) {
+	if s.events == nil {
+		return
+	}
+	data := map[string]string{"user_id": u.ID, "username": u.Username, "email": u.Email, "connector_id": connectorID}
+	for k, v := range extra {
+		data[k] = v
+	}
+	s.events.Publish(ctx, t, data)
+}
+
+// audit records a federated sign-in; a non-empty failure reason records a failed one.
+func (s *FederationService) audit(ctx context.Context, connectorID, userID, failure string) {
+	if s.auditor == nil {
+		return
+	}
+	e := domain.AuditEvent{Type: domain.AuditFederatedLogin, UserID: userID, Details: map[string]string{"connector_id": connectorID}}
+	if failure != "" {
+		e.Type = domain.AuditFederatedLoginFailed
+		e.Details["reason"] = failure
+	}
+	s.auditor.Record(ctx, e)
+}
+
+func (s *FederationService) observe(connectorID, outcome string) {
+	if s.metrics != nil {
+		s.metrics.FederationLogin(connectorID, outcome)
+	}
 }
 
 // ListConnectors returns all configured IdP connectors.
-func (s *FederationService) ListConnectors(ctx context.Context) ([]*domain.IdPConnector, error) {
+func (s *FederationService) ListConnectors(ctx context.Context) (_ []*domain.IdPConnector, err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.ListConnectors")
+	defer func() { tracing.End(span, err) }()
 	return s.connectorRepo.List(ctx)
 }
 
 // AuthCodeURL returns the upstream IdP authorize URL for the given connector.
-func (s *FederationService) AuthCodeURL(ctx context.Context, connectorID, issuer, state string) (string, error) {
+func (s *FederationService) AuthCodeURL(ctx context.Context, connectorID, issuer, state string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.AuthCodeURL", trace.WithAttributes(attribute.String("connector_id", connectorID)))
+	defer func() { tracing.End(span, err) }()
 	conn, err := s.connectorRepo.GetByID(ctx, connectorID)
 	if err != nil {
 		return "", fmt.Errorf("get connector: %w", err)
@@ -94,15 +231,36 @@ func (s *FederationService) AuthCodeURL(ctx context.Context, connectorID, issuer
 	return client.AuthCodeURL(state), nil
 }
 
-func (s *FederationService) resolveOrCreateUser(ctx context.Context, info *UpstreamUserInfo) (*domain.User, error) {
-	// Try by email first
+// resolveOrCreateUser returns the local user the upstream identity is linked to. An identity
+// not linked yet is linked to the user with the same email if the upstream IdP asserts that the
+// email is verified (ErrEmailNotVerified otherwise), or else to a new user; created and linked
+// report which happened.
+func (s *FederationService) resolveOrCreateUser(ctx context.Context, connectorID string, info *UpstreamUserInfo) (u *domain.User, created, linked bool, err error) {
+	if s.identities != nil {
+		userID, err := s.identities.UserID(ctx, connectorID, info.Sub)
+		if err != nil {
+			return nil, false, false, err
+		}
+		if userID != "" {
+			if u, err = s.userRepo.ByID(ctx, userID); err != nil || u != nil {
+				return u, false, false, err
+			}
+		}
+	}
+
 	if info.Email != "" {
 		u, err := s.userRepo.ByEmail(ctx, info.Email)
 		if err != nil {
-			return nil, err
+			return nil, false, false, err
 		}
 		if u != nil {
-			return u, nil
+			if !info.EmailVerified {
+				return nil, false, false, ErrEmailNotVerified
+			}
+			if s.identities == nil {
+				return u, false, false, nil
+			}
+			return u, false, true, s.link(ctx, connectorID, u, info)
 		}
 	}
 
@@ -117,19 +275,42 @@ func (s *FederationService) resolveOrCreateUser(ctx context.Context, info *Upstr
 
 	hash, err := federatedUserPasswordHash()
 	if err != nil {
-		return nil, err
+		return nil, false, false, err
 	}
 
-	u := &domain.User{
+	u = &domain.User{
 		Username:     username,
 		Email:        info.Email,
 		PasswordHash: hash,
 		CreatedAt:    time.Now(),
 	}
 	if err := s.userRepo.Create(ctx, u); err != nil {
-		return nil, err
+		return nil, false, false, err
+	}
+	return u, true, true, s.link(ctx, connectorID, u, info)
+}
+
+// link records that the upstream identity signs in as u; a no-op without an IdentityRepository.
+func (s *FederationService) link(ctx context.Context, connectorID string, u *domain.User, info *UpstreamUserInfo) error {
+	if s.identities == nil {
+		return nil
+	}
+	return s.identities.Create(ctx, &domain.LinkedIdentity{
+		UserID:      u.ID,
+		ConnectorID: connectorID,
+		Subject:     info.Sub,
+		Email:       info.Email,
+	})
+}
+
+// LinkedIdentities returns the upstream identities linked to the user, oldest first.
+func (s *FederationService) LinkedIdentities(ctx context.Context, userID string) (_ []*domain.LinkedIdentity, err error) {
+	ctx, span := tracer.Start(ctx, "FederationService.LinkedIdentities")
+	defer func() { tracing.End(span, err) }()
+	if s.identities == nil {
+		return nil, nil
 	}
-	return u, nil
+	return s.identities.ListByUser(ctx, userID)
 }
 
 func federatedUserPasswordHash() (string, error) {
@@ -12,6 +12,7 @@ import (
 	"github.com/qinzj/superpowers-demo/ent/enttest"
 	"github.com/qinzj/superpowers-demo/internal/domain"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
 	"github.com/qinzj/superpowers-demo/internal/storage"
 )
 
@@ -28,6 +29,17 @@ func (f *fakeOIDCExchange) ExchangeAndUserInfo(_ context.Context, _ *domain.IdPC
 	return f.userInfo, nil
 }
 
+// recordingPublisher captures published lifecycle events.
+type recordingPublisher struct {
+	types []domain.WebhookEventType
+	data  []map[string]string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, t domain.WebhookEventType, data map[string]string) {
+	p.types = append(p.types, t)
+	p.data = append(p.data, data)
+}
+
 func TestFederationService_LoginWithUpstream(t *testing.T) {
 	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
 	defer client.Close()
@@ -56,7 +68,9 @@ func TestFederationService_LoginWithUpstream(t *testing.T) {
 		},
 	}
 
-	svc := NewFederationService(connectorRepo, fakeOIDC, userRepo, authSvc)
+	events := &recordingPublisher{}
+	svc := NewFederationService(connectorRepo, fakeOIDC, userRepo, authSvc,
+		WithIdentityRepository(storage.NewLinkedIdentityRepository(client)), WithEventPublisher(events))
 
 	t.Run("creates_new_user_and_session_when_email_not_found", func(t *testing.T) {
 		callbackURL := fmt.Sprintf("http://localhost/auth/callback/%s", connectorID)
@@ -72,6 +86,16 @@ func TestFederationService_LoginWithUpstream(t *testing.T) {
 		require.NotNil(t, u)
 		require.Equal(t, "federateduser", u.Username)
 		require.Equal(t, "federated@example.com", u.Email)
+
+		require.Equal(t, []domain.WebhookEventType{domain.WebhookUserCreated, domain.WebhookIdentityLinked, domain.WebhookUserLogin}, events.types)
+		require.Equal(t, "upstream-sub-123", events.data[1]["upstream_subject"])
+		require.Equal(t, connectorID, events.data[2]["connector_id"])
+
+		// Signing in again links nothing new.
+		events.types = nil
+		_, err = svc.LoginWithUpstream(ctx, connectorID, "state-ok", "auth-code", callbackURL)
+		require.NoError(t, err)
+		require.Equal(t, []domain.WebhookEventType{domain.WebhookUserLogin}, events.types)
 	})
 
 	t.Run("returns_ErrConnectorNotFound_when_connector_missing", func(t *testing.T) {
@@ -99,10 +123,178 @@ func TestFederationService_LoginWithUpstream(t *testing.T) {
 			PreferredUsername: "existing",
 		}
 
+		// Without email_verified the upstream account could have claimed any address.
+		events.types, events.data = nil, nil
 		callbackURL := fmt.Sprintf("http://localhost/auth/callback/%s", connectorID)
 		sess, err := svc.LoginWithUpstream(ctx, connectorID, "state-ok", "auth-code", callbackURL)
+		require.ErrorIs(t, err, ErrEmailNotVerified)
+		require.Nil(t, sess)
+		require.Empty(t, events.types)
+		identities, err := svc.LinkedIdentities(ctx, existing.ID)
+		require.NoError(t, err)
+		require.Empty(t, identities)
+
+		fakeOIDC.userInfo.EmailVerified = true
+		sess, err = svc.LoginWithUpstream(ctx, connectorID, "state-ok", "auth-code", callbackURL)
 		require.NoError(t, err)
 		require.NotNil(t, sess)
 		require.Equal(t, existing.ID, sess.UserID)
+		require.Equal(t, []domain.WebhookEventType{domain.WebhookIdentityLinked, domain.WebhookUserLogin}, events.types)
+		require.Equal(t, "other-sub", events.data[0]["upstream_subject"])
+		require.Equal(t, existing.ID, events.data[0]["user_id"])
+
+		identities, err = svc.LinkedIdentities(ctx, existing.ID)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+		require.Equal(t, connectorID, identities[0].ConnectorID)
+		require.Equal(t, "other-sub", identities[0].Subject)
+
+		// Once linked, the upstream subject signs in as the user even if its email changes.
+		events.types = nil
+		fakeOIDC.userInfo.Email = "renamed@example.com"
+		sess, err = svc.LoginWithUpstream(ctx, connectorID, "state-ok", "auth-code", callbackURL)
+		require.NoError(t, err)
+		require.Equal(t, existing.ID, sess.UserID)
+		require.Equal(t, []domain.WebhookEventType{domain.WebhookUserLogin}, events.types)
+		fakeOIDC.userInfo.Email = "existing@example.com"
 	})
+
+	t.Run("refuses_inactive_user", func(t *testing.T) {
+		u, err := userRepo.ByEmail(ctx, "existing@example.com")
+		require.NoError(t, err)
+		require.NoError(t, authSvc.SetUserStatus(ctx, u.ID, domain.UserStatusSuspended))
+
+		callbackURL := fmt.Sprintf("http://localhost/auth/callback/%s", connectorID)
+		sess, err := svc.LoginWithUpstream(ctx, connectorID, "state-ok", "auth-code", callbackURL)
+		require.ErrorIs(t, err, auth.ErrAccountInactive)
+		require.Nil(t, sess)
+	})
+}
+
+func TestFederationService_SyncsUpstreamGroups(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	authSvc := auth.NewAuthService(userRepo, storage.NewSessionRepository(client))
+	rbacSvc := rbac.NewService(storage.NewRBACRepository(client), userRepo)
+
+	ctx := context.Background()
+	entConn, err := client.IdPConnector.Create().
+		SetIssuer("https://idp.example.com").
+		SetClientID("test-client").
+		SetClientSecret("secret").
+		SetGroupsClaim("groups").
+		Save(ctx)
+	require.NoError(t, err)
+	connectorID := fmt.Sprintf("%d", entConn.ID)
+
+	// A local group with the same name as an upstream group must not be joined.
+	_, err = rbacSvc.CreateGroup(ctx, "admins", "local")
+	require.NoError(t, err)
+
+	fakeOIDC := &fakeOIDCExchange{userInfo: &UpstreamUserInfo{
+		Sub:           "sub-g",
+		Email:         "grouped@example.com",
+		EmailVerified: true,
+		Groups:        []string{"engineering", "admins"},
+	}}
+	svc := NewFederationService(storage.NewIdPConnectorRepository(client), fakeOIDC, userRepo, authSvc,
+		WithGroupSync(rbacSvc))
+
+	sess, err := svc.LoginWithUpstream(ctx, connectorID, "state", "code", "http://localhost/callback")
+	require.NoError(t, err)
+	groups, _, err := rbacSvc.Memberships(ctx, sess.UserID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"engineering"}, groups)
+
+	// Membership follows the upstream claim on the next login.
+	fakeOIDC.userInfo.Groups = []string{"support"}
+	sess, err = svc.LoginWithUpstream(ctx, connectorID, "state", "code", "http://localhost/callback")
+	require.NoError(t, err)
+	groups, _, err = rbacSvc.Memberships(ctx, sess.UserID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"support"}, groups)
+}
+
+// recordingMetrics captures federated sign-in outcomes.
+type recordingMetrics struct{ outcomes []string }
+
+func (m *recordingMetrics) FederationLogin(_, outcome string) { m.outcomes = append(m.outcomes, outcome) }
+
+// failingGroupSyncer rejects every group sync.
+type failingGroupSyncer struct{}
+
+func (failingGroupSyncer) SyncConnectorGroups(context.Context, string, string, []string) error {
+	return fmt.Errorf("groups unavailable")
+}
+
+func TestFederationService_CountsFailedLogins(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	authSvc := auth.NewAuthService(userRepo, storage.NewSessionRepository(client))
+
+	ctx := context.Background()
+	entConn, err := client.IdPConnector.Create().
+		SetIssuer("https://idp.example.com").
+		SetClientID("test-client").
+		SetClientSecret("secret").
+		SetGroupsClaim("groups").
+		Save(ctx)
+	require.NoError(t, err)
+	connectorID := fmt.Sprintf("%d", entConn.ID)
+
+	m := &recordingMetrics{}
+	fakeOIDC := &fakeOIDCExchange{userInfo: &UpstreamUserInfo{Sub: "sub-f", Email: "failing@example.com"}}
+	svc := NewFederationService(storage.NewIdPConnectorRepository(client), fakeOIDC, userRepo, authSvc,
+		WithGroupSync(failingGroupSyncer{}), WithMetrics(m))
+
+	_, err = svc.LoginWithUpstream(ctx, connectorID, "state", "code", "http://localhost/callback")
+	require.Error(t, err)
+
+	// The first attempt created a user named after its email, so this username is taken.
+	fakeOIDC.userInfo = &UpstreamUserInfo{Sub: "sub-g", Email: "other@example.com", PreferredUsername: "failing@example.com"}
+	_, err = svc.LoginWithUpstream(ctx, connectorID, "state", "code", "http://localhost/callback")
+	require.Error(t, err)
+
+	require.Equal(t, []string{"group_sync_failed", "resolve_user_failed"}, m.outcomes)
+}
+
+func TestFederationService_ConnectorManagement(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	userRepo := storage.NewUserRepository(client)
+	authSvc := auth.NewAuthService(userRepo, storage.NewSessionRepository(client))
+	svc := NewFederationService(storage.NewIdPConnectorRepository(client), &fakeOIDCExchange{}, userRepo, authSvc)
+	ctx := context.Background()
+
+	err := svc.CreateConnector(ctx, &domain.IdPConnector{Issuer: "idp.example.com", ClientID: "c", ClientSecret: "s"})
+	require.ErrorIs(t, err, ErrInvalidConnector, "issuer must be absolute")
+	err = svc.CreateConnector(ctx, &domain.IdPConnector{Issuer: "https://idp.example.com", ClientID: "c"})
+	require.ErrorIs(t, err, ErrInvalidConnector, "new connectors need a secret")
+
+	conn := &domain.IdPConnector{Issuer: " https://idp.example.com ", ClientID: "c", ClientSecret: "s"}
+	require.NoError(t, svc.CreateConnector(ctx, conn))
+	require.NotEmpty(t, conn.ID)
+
+	// An empty secret on update keeps the stored one.
+	require.NoError(t, svc.UpdateConnector(ctx, &domain.IdPConnector{
+		ID: conn.ID, Issuer: "https://login.example.com", ClientID: "c2", GroupsClaim: "groups",
+	}))
+	got, err := svc.GetConnector(ctx, conn.ID)
+	require.NoError(t, err)
+	require.Equal(t, "https://login.example.com", got.Issuer)
+	require.Equal(t, "c2", got.ClientID)
+	require.Equal(t, "s", got.ClientSecret)
+	require.Equal(t, "groups", got.GroupsClaim)
+
+	require.NoError(t, svc.DeleteConnector(ctx, conn.ID))
+	require.ErrorIs(t, svc.DeleteConnector(ctx, conn.ID), ErrConnectorNotFound)
+	_, err = svc.GetConnector(ctx, conn.ID)
+	require.ErrorIs(t, err, ErrConnectorNotFound)
+	err = svc.UpdateConnector(ctx, &domain.IdPConnector{ID: "x", Issuer: "https://idp.example.com", ClientID: "c"})
+	require.Error(t, err)
 }
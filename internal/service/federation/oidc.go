@@ -8,9 +8,13 @@ import (
 
 // UpstreamUserInfo holds claims from the upstream IdP's UserInfo endpoint.
 type UpstreamUserInfo struct {
-	Sub               string
-	Email             string
+	Sub   string
+	Email string
+	// EmailVerified reports whether the upstream IdP asserts that the user controls Email.
+	EmailVerified     bool
 	PreferredUsername string
+	// Groups lists upstream group names read from the connector's groups claim.
+	Groups []string
 }
 
 // OIDCExchange exchanges an authorization code for tokens and fetches user info from upstream IdP.
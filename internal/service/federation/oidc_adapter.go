@@ -37,6 +37,8 @@ func (a *OIDCClientAdapter) ExchangeAndUserInfo(
 	return &UpstreamUserInfo{
 		Sub:               info.Sub,
 		Email:             info.Email,
+		EmailVerified:     info.EmailVerified,
 		PreferredUsername: info.PreferredUsername,
+		Groups:            info.Groups,
 	}, nil
 }
@@ -10,4 +10,26 @@ import (
 type IdPConnectorRepository interface {
 	List(ctx context.Context) ([]*domain.IdPConnector, error)
 	GetByID(ctx context.Context, id string) (*domain.IdPConnector, error)
+	// Create persists c and populates c.ID.
+	Create(ctx context.Context, c *domain.IdPConnector) error
+	// Update persists every field of c.
+	Update(ctx context.Context, c *domain.IdPConnector) error
+	// Delete removes the connector and the identities linked through it. Returns false if it
+	// did not exist.
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+// IdentityRepository stores which upstream identities sign in as which local users.
+type IdentityRepository interface {
+	// UserID returns the ID of the user that subject at connectorID is linked to, or "" if none.
+	UserID(ctx context.Context, connectorID, subject string) (string, error)
+	// Create links i.Subject at i.ConnectorID to i.UserID and populates i.LinkedAt.
+	Create(ctx context.Context, i *domain.LinkedIdentity) error
+	// ListByUser returns the identities linked to the user, oldest first.
+	ListByUser(ctx context.Context, userID string) ([]*domain.LinkedIdentity, error)
+}
+
+// GroupSyncer mirrors upstream group membership into local groups owned by the connector.
+type GroupSyncer interface {
+	SyncConnectorGroups(ctx context.Context, connectorID, userID string, names []string) error
 }
@@ -0,0 +1,257 @@
+package oauthclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+)
+
+var (
+	// ErrClientNotFound is returned when the client does not exist.
+	ErrClientNotFound = errors.New("client not found")
+	// ErrClientExists is returned when creating a client whose client_id is taken.
+	ErrClientExists = errors.New("client already exists")
+	// ErrInvalidClientID is returned for an empty or malformed client_id.
+	ErrInvalidClientID = errors.New("invalid client id")
+	// ErrInvalidRedirectURI is returned when a redirect URI is not an absolute URL without fragment.
+	ErrInvalidRedirectURI = errors.New("invalid redirect uri")
+	// ErrWeakSecret is returned when a caller-chosen client secret is shorter than minSecretLen.
+	ErrWeakSecret = errors.New("client secret is too short")
+)
+
+const (
+	secretBytes  = 32
+	minSecretLen = 16
+)
+
+var clientIDPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]{1,128}$`)
+
+// Service manages OAuth2 client registrations.
+type Service struct {
+	repo   Repository
+	hasher *password.Hasher
+	tokens TokenRevoker
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithPasswordHasher sets the hasher for client secrets; it must match the provider's client
+// secret hasher. Defaults to password.Default().
+func WithPasswordHasher(h *password.Hasher) Option {
+	return func(s *Service) {
+		if h != nil {
+			s.hasher = h
+		}
+	}
+}
+
+// WithTokenRevoker sets the TokenRevoker used to revoke a deleted client's tokens.
+func WithTokenRevoker(t TokenRevoker) Option {
+	return func(s *Service) {
+		s.tokens = t
+	}
+}
+
+// NewService creates a Service with the given repository.
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{repo: repo, hasher: password.Default()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// List returns all registered clients.
+func (s *Service) List(ctx context.Context) ([]*domain.OAuth2Client, error) {
+	clients, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list clients: %w", err)
+	}
+	return clients, nil
+}
+
+// Get returns the client with the given client_id, or ErrClientNotFound.
+func (s *Service) Get(ctx context.Context, clientID string) (*domain.OAuth2Client, error) {
+	c, err := s.repo.ByClientID(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("get client: %w", err)
+	}
+	if c == nil {
+		return nil, ErrClientNotFound
+	}
+	return c, nil
+}
+
+// Create registers a client with a freshly generated secret. The plaintext secret is returned
+// once; only its hash is stored.
+func (s *Service) Create(ctx context.Context, clientID string, redirectURIs []string) (*domain.OAuth2Client, string, error) {
+	secret, hash, err := s.newSecret()
+	if err != nil {
+		return nil, "", fmt.Errorf("create client: %w", err)
+	}
+	c, err := s.create(ctx, clientID, redirectURIs, hash)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, secret, nil
+}
+
+// CreateWithSecret registers a client with a secret chosen by the caller, for clients whose
+// secret is provisioned elsewhere (bootstrap files, secret managers).
+func (s *Service) CreateWithSecret(ctx context.Context, clientID string, redirectURIs []string, secret string) (*domain.OAuth2Client, error) {
+	if len(secret) < minSecretLen {
+		return nil, ErrWeakSecret
+	}
+	hash, err := s.hasher.Hash(secret)
+	if err != nil {
+		return nil, fmt.Errorf("hash secret: %w", err)
+	}
+	return s.create(ctx, clientID, redirectURIs, hash)
+}
+
+func (s *Service) create(ctx context.Context, clientID string, redirectURIs []string, secretHash string) (*domain.OAuth2Client, error) {
+	clientID = strings.TrimSpace(clientID)
+	if !clientIDPattern.MatchString(clientID) {
+		return nil, ErrInvalidClientID
+	}
+	uris, err := normalizeRedirectURIs(redirectURIs)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.ByClientID(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrClientExists
+	}
+	c := &domain.OAuth2Client{ClientID: clientID, SecretHash: secretHash, RedirectURIs: uris}
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateRedirectURIs replaces the client's registered redirect URIs.
+func (s *Service) UpdateRedirectURIs(ctx context.Context, clientID string, redirectURIs []string) (*domain.OAuth2Client, error) {
+	uris, err := normalizeRedirectURIs(redirectURIs)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.Get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	c.RedirectURIs = uris
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, fmt.Errorf("update client: %w", err)
+	}
+	return c, nil
+}
+
+// RotateSecret replaces the client secret and returns the new plaintext secret.
+// The previous secret stops working immediately.
+func (s *Service) RotateSecret(ctx context.Context, clientID string) (string, error) {
+	c, err := s.Get(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+	secret, hash, err := s.newSecret()
+	if err != nil {
+		return "", fmt.Errorf("rotate secret: %w", err)
+	}
+	c.SecretHash = hash
+	if err := s.repo.Update(ctx, c); err != nil {
+		return "", fmt.Errorf("rotate secret: %w", err)
+	}
+	return secret, nil
+}
+
+// SetSecret replaces the client secret with one chosen by the caller. The previous secret stops
+// working immediately.
+func (s *Service) SetSecret(ctx context.Context, clientID, secret string) error {
+	if len(secret) < minSecretLen {
+		return ErrWeakSecret
+	}
+	c, err := s.Get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	hash, err := s.hasher.Hash(secret)
+	if err != nil {
+		return fmt.Errorf("hash secret: %w", err)
+	}
+	c.SecretHash = hash
+	if err := s.repo.Update(ctx, c); err != nil {
+		return fmt.Errorf("set secret: %w", err)
+	}
+	return nil
+}
+
+// SecretMatches reports whether secret is the client's current secret.
+func (s *Service) SecretMatches(c *domain.OAuth2Client, secret string) bool {
+	ok, _ := s.hasher.Verify(secret, c.SecretHash)
+	return ok
+}
+
+// Delete removes the client registration and revokes the tokens issued to it.
+func (s *Service) Delete(ctx context.Context, clientID string) error {
+	ok, err := s.repo.Delete(ctx, clientID)
+	if err != nil {
+		return fmt.Errorf("delete client: %w", err)
+	}
+	if !ok {
+		return ErrClientNotFound
+	}
+	if s.tokens != nil {
+		if err := s.tokens.RevokeClientTokens(ctx, clientID); err != nil {
+			return fmt.Errorf("revoke client tokens: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) newSecret() (secret, hash string, err error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret = base64.RawURLEncoding.EncodeToString(b)
+	hash, err = s.hasher.Hash(secret)
+	if err != nil {
+		return "", "", fmt.Errorf("hash secret: %w", err)
+	}
+	return secret, hash, nil
+}
+
+// normalizeRedirectURIs trims and de-duplicates uris, requiring at least one absolute URL
+// without a fragment (RFC 6749 section 3.1.2).
+func normalizeRedirectURIs(uris []string) ([]string, error) {
+	out := make([]string, 0, len(uris))
+	seen := make(map[string]bool, len(uris))
+	for _, raw := range uris {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || seen[raw] {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRedirectURI, raw)
+		}
+		seen[raw] = true
+		out = append(out, raw)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: at least one is required", ErrInvalidRedirectURI)
+	}
+	return out, nil
+}
@@ -0,0 +1,85 @@
+package oauthclient
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+type fakeRevoker struct {
+	clients []string
+}
+
+func (f *fakeRevoker) RevokeClientTokens(_ context.Context, clientID string) error {
+	f.clients = append(f.clients, clientID)
+	return nil
+}
+
+func TestService_Lifecycle(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	revoker := &fakeRevoker{}
+	svc := NewService(storage.NewOAuth2ClientRepository(client), WithTokenRevoker(revoker))
+	ctx := context.Background()
+
+	c, secret, err := svc.Create(ctx, "web-app", []string{" https://app.example.com/cb ", "https://app.example.com/cb", ""})
+	require.NoError(t, err)
+	require.NotEmpty(t, secret)
+	require.Equal(t, []string{"https://app.example.com/cb"}, c.RedirectURIs)
+	require.NotContains(t, c.SecretHash, secret)
+	ok, _ := password.Verify(secret, c.SecretHash)
+	require.True(t, ok, "stored hash must verify the issued secret")
+
+	_, _, err = svc.Create(ctx, "web-app", []string{"https://other.example.com/cb"})
+	require.ErrorIs(t, err, ErrClientExists)
+
+	rotated, err := svc.RotateSecret(ctx, "web-app")
+	require.NoError(t, err)
+	require.NotEqual(t, secret, rotated)
+	got, err := svc.Get(ctx, "web-app")
+	require.NoError(t, err)
+	ok, _ = password.Verify(secret, got.SecretHash)
+	require.False(t, ok, "the previous secret must stop working")
+	ok, _ = password.Verify(rotated, got.SecretHash)
+	require.True(t, ok)
+
+	updated, err := svc.UpdateRedirectURIs(ctx, "web-app", []string{"https://app.example.com/a", "http://localhost:3000/b"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://app.example.com/a", "http://localhost:3000/b"}, updated.RedirectURIs)
+
+	list, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, svc.Delete(ctx, "web-app"))
+	require.Equal(t, []string{"web-app"}, revoker.clients)
+	require.ErrorIs(t, svc.Delete(ctx, "web-app"), ErrClientNotFound)
+	_, err = svc.Get(ctx, "web-app")
+	require.ErrorIs(t, err, ErrClientNotFound)
+}
+
+func TestService_Validation(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	svc := NewService(storage.NewOAuth2ClientRepository(client))
+	ctx := context.Background()
+
+	for _, id := range []string{"", "has space", "semi;colon"} {
+		_, _, err := svc.Create(ctx, id, []string{"https://app.example.com/cb"})
+		require.ErrorIs(t, err, ErrInvalidClientID, id)
+	}
+	for _, uris := range [][]string{nil, {"/relative"}, {"https://app.example.com/cb#frag"}, {"not a url"}} {
+		_, _, err := svc.Create(ctx, "app", uris)
+		require.ErrorIs(t, err, ErrInvalidRedirectURI, "%v", uris)
+	}
+	_, err := svc.UpdateRedirectURIs(ctx, "missing", []string{"https://app.example.com/cb"})
+	require.ErrorIs(t, err, ErrClientNotFound)
+}
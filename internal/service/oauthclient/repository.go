@@ -0,0 +1,29 @@
+// Package oauthclient manages the OAuth2 clients (relying parties) registered with the provider.
+package oauthclient
+
+import (
+	"context"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// Repository defines persistence operations for OAuth2 clients.
+// Interface is defined in the consuming (service) layer per project architecture.
+type Repository interface {
+	// Create persists c and populates c.ID.
+	Create(ctx context.Context, c *domain.OAuth2Client) error
+	// ByClientID returns the client with the given client_id, or nil if not found.
+	ByClientID(ctx context.Context, clientID string) (*domain.OAuth2Client, error)
+	// List returns all clients ordered by client_id.
+	List(ctx context.Context) ([]*domain.OAuth2Client, error)
+	// Update persists the secret hash and redirect URIs of c.
+	Update(ctx context.Context, c *domain.OAuth2Client) error
+	// Delete removes the client. Returns false if it did not exist.
+	Delete(ctx context.Context, clientID string) (bool, error)
+}
+
+// TokenRevoker revokes the OAuth2 tokens issued to a client.
+type TokenRevoker interface {
+	// RevokeClientTokens revokes every access and refresh token issued to clientID.
+	RevokeClientTokens(ctx context.Context, clientID string) error
+}
@@ -7,10 +7,13 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"sync/atomic"
 	"time"
 
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/handler/openid"
 	"github.com/ory/fosite/token/jwt"
 )
 
@@ -20,8 +23,28 @@ type OIDCConfig struct {
 	AccessTokenLifespan time.Duration
 	RefreshTokenLifespan time.Duration
 	IDTokenLifespan     time.Duration
+	// AuthorizeCodeLifespan defaults to fosite's 15 minutes when zero.
+	AuthorizeCodeLifespan time.Duration
 	GlobalSecret       []byte
 	PrivateKey         *rsa.PrivateKey
+	// SecretsHasher hashes and verifies OAuth2 client secrets. Defaults to NewSecretsHasher(nil).
+	SecretsHasher fosite.Hasher
+	// DeviceCodeLifespan and DevicePollInterval configure the device authorization grant; they
+	// default to DefaultDeviceCodeLifespan and DefaultDevicePollInterval when zero.
+	DeviceCodeLifespan time.Duration
+	DevicePollInterval time.Duration
+
+	// lifespans is read by providers built from this config; see SetLifespans.
+	lifespans atomic.Pointer[Lifespans]
+}
+
+// Lifespans are the token lifetimes a running provider can be switched to without a restart.
+// Tokens already issued keep the expiry they were issued with.
+type Lifespans struct {
+	AccessToken   time.Duration
+	RefreshToken  time.Duration
+	IDToken       time.Duration
+	AuthorizeCode time.Duration
 }
 
 // DefaultOIDCConfig returns config with sensible defaults.
@@ -42,34 +65,163 @@ func DefaultOIDCConfig(issuer string) (*OIDCConfig, error) {
 		IDTokenLifespan:      1 * time.Hour,
 		GlobalSecret:         secret,
 		PrivateKey:           key,
+		SecretsHasher:        NewSecretsHasher(nil),
 	}, nil
 }
 
 // NewFositeConfig builds fosite.Config for the OIDC provider.
 func (c *OIDCConfig) NewFositeConfig() *fosite.Config {
+	hasher := c.SecretsHasher
+	if hasher == nil {
+		hasher = NewSecretsHasher(nil)
+	}
 	return &fosite.Config{
 		AccessTokenLifespan:       c.AccessTokenLifespan,
 		RefreshTokenLifespan:      c.RefreshTokenLifespan,
 		IDTokenLifespan:           c.IDTokenLifespan,
+		AuthorizeCodeLifespan:     c.AuthorizeCodeLifespan,
 		GlobalSecret:              c.GlobalSecret,
 		IDTokenIssuer:             c.Issuer,
 		AccessTokenIssuer:         c.Issuer,
 		ScopeStrategy:             fosite.HierarchicScopeStrategy,
 		AudienceMatchingStrategy:  fosite.DefaultAudienceMatchingStrategy,
+		ClientSecretsHasher:       hasher,
+	}
+}
+
+// Lifespans returns the token lifetimes currently in effect.
+func (c *OIDCConfig) Lifespans() Lifespans {
+	if l := c.lifespans.Load(); l != nil {
+		return *l
+	}
+	return Lifespans{
+		AccessToken:   c.AccessTokenLifespan,
+		RefreshToken:  c.RefreshTokenLifespan,
+		IDToken:       c.IDTokenLifespan,
+		AuthorizeCode: c.AuthorizeCodeLifespan,
+	}
+}
+
+// SetLifespans changes the token lifetimes used by providers built from this config. It is safe
+// to call while requests are being served.
+func (c *OIDCConfig) SetLifespans(l Lifespans) {
+	c.lifespans.Store(&l)
+}
+
+func (c *OIDCConfig) deviceCodeLifespan() time.Duration {
+	if c.DeviceCodeLifespan > 0 {
+		return c.DeviceCodeLifespan
+	}
+	return DefaultDeviceCodeLifespan
+}
+
+func (c *OIDCConfig) devicePollInterval() time.Duration {
+	if c.DevicePollInterval > 0 {
+		return c.DevicePollInterval
+	}
+	return DefaultDevicePollInterval
+}
+
+// providerConfig overrides the lifespan getters of fosite.Config with the values from
+// OIDCConfig.Lifespans, so that they can change after the handlers have been composed.
+type providerConfig struct {
+	*fosite.Config
+	oidc *OIDCConfig
+}
+
+func (p *providerConfig) GetAccessTokenLifespan(context.Context) time.Duration {
+	return p.oidc.Lifespans().AccessToken
+}
+
+func (p *providerConfig) GetRefreshTokenLifespan(context.Context) time.Duration {
+	return p.oidc.Lifespans().RefreshToken
+}
+
+func (p *providerConfig) GetIDTokenLifespan(context.Context) time.Duration {
+	return p.oidc.Lifespans().IDToken
+}
+
+func (p *providerConfig) GetAuthorizeCodeLifespan(ctx context.Context) time.Duration {
+	if l := p.oidc.Lifespans().AuthorizeCode; l > 0 {
+		return l
 	}
+	return p.Config.GetAuthorizeCodeLifespan(ctx)
 }
 
-// NewOAuth2Provider creates a Fosite OAuth2/OIDC provider with all standard handlers.
+// NewOAuth2Provider creates a Fosite OAuth2/OIDC provider with all standard handlers. It mirrors
+// compose.ComposeAllEnabled, but hands the handlers a configurator whose token lifespans follow
+// SetLifespans. When storage also stores device authorizations, the device code grant (RFC 8628)
+// is enabled too, and when it holds token exchange policies, the token exchange grant (RFC 8693).
 func NewOAuth2Provider(cfg *OIDCConfig, storage fosite.Storage) fosite.OAuth2Provider {
-	config := cfg.NewFositeConfig()
+	base := cfg.NewFositeConfig()
+	config := &providerConfig{Config: base, oidc: cfg}
 	keyGetter := func(context.Context) (interface{}, error) {
 		return cfg.PrivateKey, nil
 	}
-	return compose.ComposeAllEnabled(
-		config,
-		storage,
-		keyGetter,
-	)
+	strategy := &compose.CommonStrategy{
+		CoreStrategy:               compose.NewOAuth2HMACStrategy(config),
+		OpenIDConnectTokenStrategy: compose.NewOpenIDConnectStrategy(keyGetter, config),
+		Signer:                     &jwt.DefaultSigner{GetPrivateKey: keyGetter},
+	}
+	provider := fosite.NewOAuth2Provider(storage, config)
+	for _, factory := range []compose.Factory{
+		compose.OAuth2AuthorizeExplicitFactory,
+		compose.OAuth2AuthorizeImplicitFactory,
+		compose.OAuth2ClientCredentialsGrantFactory,
+		compose.OAuth2RefreshTokenGrantFactory,
+		compose.OAuth2ResourceOwnerPasswordCredentialsFactory,
+		compose.RFC7523AssertionGrantFactory,
+
+		compose.OpenIDConnectExplicitFactory,
+		compose.OpenIDConnectImplicitFactory,
+		compose.OpenIDConnectHybridFactory,
+		compose.OpenIDConnectRefreshFactory,
+
+		compose.OAuth2TokenIntrospectionFactory,
+		compose.OAuth2TokenRevocationFactory,
+
+		compose.OAuth2PKCEFactory,
+		compose.PushedAuthorizeHandlerFactory,
+	} {
+		res := factory(config, storage, strategy)
+		if ah, ok := res.(fosite.AuthorizeEndpointHandler); ok {
+			base.AuthorizeEndpointHandlers.Append(ah)
+		}
+		if th, ok := res.(fosite.TokenEndpointHandler); ok {
+			base.TokenEndpointHandlers.Append(th)
+		}
+		if tv, ok := res.(fosite.TokenIntrospector); ok {
+			base.TokenIntrospectionHandlers.Append(tv)
+		}
+		if rh, ok := res.(fosite.RevocationHandler); ok {
+			base.RevocationHandlers.Append(rh)
+		}
+		if ph, ok := res.(fosite.PushedAuthorizeEndpointHandler); ok {
+			base.PushedAuthorizeEndpointHandlers.Append(ph)
+		}
+	}
+	if ds, ok := storage.(interface {
+		DeviceAuthorizationStorage
+		oauth2.CoreStorage
+	}); ok {
+		base.TokenEndpointHandlers.Append(&DeviceCodeGrantHandler{
+			Storage:             ds,
+			Strategy:            strategy.CoreStrategy,
+			IDTokenHandleHelper: &openid.IDTokenHandleHelper{IDTokenStrategy: strategy.OpenIDConnectTokenStrategy},
+			Config:              config,
+		})
+	}
+	if ts, ok := storage.(interface {
+		TokenExchangeStorage
+		oauth2.CoreStorage
+	}); ok {
+		base.TokenEndpointHandlers.Append(&TokenExchangeHandler{
+			Storage:  ts,
+			Strategy: strategy.CoreStrategy,
+			Config:   config,
+		})
+	}
+	return provider
 }
 
 // GetSigner returns a JWT signer for the config.
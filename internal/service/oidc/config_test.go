@@ -0,0 +1,44 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOAuth2Provider_FollowsSetLifespans(t *testing.T) {
+	cfg, err := DefaultOIDCConfig("http://localhost:8888")
+	require.NoError(t, err)
+	provider := NewOAuth2Provider(cfg, storage.NewExampleStore())
+
+	// issue runs a client_credentials grant and returns the access token's expires_in.
+	issue := func() time.Duration {
+		ctx := context.Background()
+		form := url.Values{"grant_type": {"client_credentials"}}
+		req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("my-client", "foobar")
+		ar, err := provider.NewAccessRequest(ctx, req, &fosite.DefaultSession{})
+		require.NoError(t, err)
+		resp, err := provider.NewAccessResponse(ctx, ar)
+		require.NoError(t, err)
+		expiresIn, ok := resp.GetExtra("expires_in").(int64)
+		require.True(t, ok)
+		return (time.Duration(expiresIn) * time.Second).Round(time.Minute)
+	}
+
+	require.Equal(t, 30*time.Minute, issue())
+	cfg.SetLifespans(Lifespans{AccessToken: 5 * time.Minute, RefreshToken: time.Hour, IDToken: time.Hour})
+	require.Equal(t, 5*time.Minute, issue())
+}
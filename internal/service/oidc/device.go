@@ -0,0 +1,413 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/handler/openid"
+)
+
+// GrantTypeDeviceCode is the grant type of the device authorization grant (RFC 8628).
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthorizationPath is the path of the device authorization endpoint, where devices
+// request a device code, and DeviceVerificationPath that of the page where users enter the
+// user code.
+const (
+	DeviceAuthorizationPath = "/device/code"
+	DeviceVerificationPath  = "/device"
+)
+
+const (
+	// DefaultDeviceCodeLifespan is how long a device code and its user code stay valid.
+	DefaultDeviceCodeLifespan = 10 * time.Minute
+	// DefaultDevicePollInterval is the minimum time between token requests for a device code.
+	DefaultDevicePollInterval = 5 * time.Second
+
+	// userCodeAlphabet has no vowels, so that user codes do not spell words, and no characters
+	// that are easily confused (RFC 8628 section 6.1).
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+// Token endpoint errors of the device authorization grant (RFC 8628 section 3.5).
+var (
+	ErrAuthorizationPending = &fosite.RFC6749Error{
+		ErrorField:       "authorization_pending",
+		DescriptionField: "The authorization request is still pending as the end user has not yet completed the user interaction steps.",
+		CodeField:        http.StatusBadRequest,
+	}
+	ErrSlowDown = &fosite.RFC6749Error{
+		ErrorField:       "slow_down",
+		DescriptionField: "The authorization request is still pending and polling should be slowed down.",
+		CodeField:        http.StatusBadRequest,
+	}
+	ErrExpiredToken = &fosite.RFC6749Error{
+		ErrorField:       "expired_token",
+		DescriptionField: "The device code has expired. Start a new device authorization request.",
+		CodeField:        http.StatusBadRequest,
+	}
+)
+
+// DeviceStatus is the state of a device authorization request.
+type DeviceStatus string
+
+const (
+	DeviceStatusPending  DeviceStatus = "pending"
+	DeviceStatusApproved DeviceStatus = "approved"
+	DeviceStatusDenied   DeviceStatus = "denied"
+	// DeviceStatusUsed is set once the device code has been exchanged for tokens.
+	DeviceStatusUsed DeviceStatus = "used"
+)
+
+// DeviceAuthorization is a device authorization request. The embedded requester holds the
+// client and requested scopes and, once approved, the user's session and granted scopes.
+type DeviceAuthorization struct {
+	fosite.Requester
+	UserCode  string
+	Status    DeviceStatus
+	ExpiresAt time.Time
+	Interval  time.Duration
+	// LastPolledAt is the time of the previous token request, zero before the first.
+	LastPolledAt time.Time
+}
+
+// DeviceAuthorizationStorage stores device authorization requests, keyed by the signature of
+// the device code and by user code. FositeStorage implements it.
+type DeviceAuthorizationStorage interface {
+	CreateDeviceAuthorization(ctx context.Context, signature string, d *DeviceAuthorization) error
+	// GetDeviceAuthorizationByUserCode returns fosite.ErrNotFound for unknown user codes.
+	GetDeviceAuthorizationByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+	// PollDeviceAuthorization records a token request at now and returns the request as it was
+	// before, so LastPolledAt is the time of the previous poll.
+	PollDeviceAuthorization(ctx context.Context, signature string, now time.Time) (*DeviceAuthorization, error)
+	// CompleteDeviceAuthorization approves (with session and scopes) or denies a pending
+	// request; it returns fosite.ErrNotFound when the user code is unknown or not pending.
+	CompleteDeviceAuthorization(ctx context.Context, userCode string, approved bool, session fosite.Session, scopes []string) error
+	// InvalidateDeviceAuthorization marks an approved request used; it returns
+	// fosite.ErrInvalidatedAuthorizeCode when it is not approved, so tokens are issued once.
+	InvalidateDeviceAuthorization(ctx context.Context, signature string) error
+}
+
+// DeviceAuthorizationResponse is the response of the device authorization endpoint.
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceFlow implements the device authorization endpoint and the verification step of the
+// device authorization grant. Token requests are handled by DeviceCodeGrantHandler, which
+// NewOAuth2Provider registers.
+type DeviceFlow struct {
+	storage  DeviceAuthorizationStorage
+	lifespan time.Duration
+	interval time.Duration
+}
+
+// NewDeviceFlow creates a DeviceFlow storing requests in storage.
+func NewDeviceFlow(cfg *OIDCConfig, storage DeviceAuthorizationStorage) *DeviceFlow {
+	return &DeviceFlow{storage: storage, lifespan: cfg.deviceCodeLifespan(), interval: cfg.devicePollInterval()}
+}
+
+// Authorize starts a device authorization request for an authenticated client.
+// verificationURI is the address of the page where the user enters the user code.
+func (f *DeviceFlow) Authorize(ctx context.Context, client fosite.Client, scopes, audience []string, verificationURI string) (*DeviceAuthorizationResponse, error) {
+	if !client.GetGrantTypes().Has(GrantTypeDeviceCode) {
+		return nil, fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use grant %q.", GrantTypeDeviceCode)
+	}
+	for _, scope := range scopes {
+		if !fosite.HierarchicScopeStrategy(client.GetScopes(), scope) {
+			return nil, fosite.ErrInvalidScope.WithHintf("The OAuth 2.0 Client is not allowed to request scope %q.", scope)
+		}
+	}
+	if err := fosite.DefaultAudienceMatchingStrategy(client.GetAudience(), audience); err != nil {
+		return nil, err
+	}
+	deviceCode, err := newDeviceCode()
+	if err != nil {
+		return nil, fosite.ErrServerError.WithWrap(err)
+	}
+	userCode, err := newUserCode()
+	if err != nil {
+		return nil, fosite.ErrServerError.WithWrap(err)
+	}
+
+	req := fosite.NewRequest()
+	req.Client = client
+	req.SetRequestedScopes(scopes)
+	req.SetRequestedAudience(audience)
+	req.SetSession(openid.NewDefaultSession())
+	// GetID assigns the ID lazily; do it before the request is shared between goroutines.
+	req.GetID()
+	now := time.Now().UTC()
+	d := &DeviceAuthorization{
+		Requester: req,
+		UserCode:  userCode,
+		Status:    DeviceStatusPending,
+		ExpiresAt: now.Add(f.lifespan),
+		Interval:  f.interval,
+	}
+	if err := f.storage.CreateDeviceAuthorization(ctx, deviceCodeSignature(deviceCode), d); err != nil {
+		return nil, fosite.ErrServerError.WithWrap(err)
+	}
+	return &DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                FormatUserCode(userCode),
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + FormatUserCode(userCode),
+		ExpiresIn:               int(f.lifespan / time.Second),
+		Interval:                int(f.interval / time.Second),
+	}, nil
+}
+
+// ErrUnknownUserCode is returned for user codes that do not belong to a pending, unexpired
+// device authorization request.
+var ErrUnknownUserCode = errors.New("unknown or expired user code")
+
+// Pending returns the pending request with the given user code, as typed by the user.
+func (f *DeviceFlow) Pending(ctx context.Context, userCode string) (*DeviceAuthorization, error) {
+	d, err := f.storage.GetDeviceAuthorizationByUserCode(ctx, NormalizeUserCode(userCode))
+	if errors.Is(err, fosite.ErrNotFound) {
+		return nil, ErrUnknownUserCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.Status != DeviceStatusPending || time.Now().After(d.ExpiresAt) {
+		return nil, ErrUnknownUserCode
+	}
+	return d, nil
+}
+
+// Approve grants the requested scopes of the pending request to the user of session.
+func (f *DeviceFlow) Approve(ctx context.Context, userCode string, session *openid.DefaultSession) error {
+	d, err := f.Pending(ctx, userCode)
+	if err != nil {
+		return err
+	}
+	return f.complete(ctx, d, true, session, d.GetRequestedScopes())
+}
+
+// Deny rejects the pending request; the device then receives access_denied.
+func (f *DeviceFlow) Deny(ctx context.Context, userCode string) error {
+	d, err := f.Pending(ctx, userCode)
+	if err != nil {
+		return err
+	}
+	return f.complete(ctx, d, false, nil, nil)
+}
+
+func (f *DeviceFlow) complete(ctx context.Context, d *DeviceAuthorization, approved bool, session fosite.Session, scopes []string) error {
+	err := f.storage.CompleteDeviceAuthorization(ctx, d.UserCode, approved, session, scopes)
+	if errors.Is(err, fosite.ErrNotFound) {
+		return ErrUnknownUserCode
+	}
+	return err
+}
+
+// DeviceCodeGrantHandler handles token requests with the device code grant type.
+type DeviceCodeGrantHandler struct {
+	Storage interface {
+		DeviceAuthorizationStorage
+		oauth2.CoreStorage
+	}
+	Strategy interface {
+		oauth2.AccessTokenStrategy
+		oauth2.RefreshTokenStrategy
+	}
+	*openid.IDTokenHandleHelper
+	Config interface {
+		fosite.AccessTokenLifespanProvider
+		fosite.RefreshTokenLifespanProvider
+		fosite.IDTokenLifespanProvider
+		fosite.RefreshTokenScopesProvider
+	}
+}
+
+var _ fosite.TokenEndpointHandler = (*DeviceCodeGrantHandler)(nil)
+
+// HandleTokenEndpointRequest answers polls with authorization_pending, slow_down,
+// expired_token or access_denied until the user has approved the request, and then takes the
+// user's session and the granted scopes from it.
+func (h *DeviceCodeGrantHandler) HandleTokenEndpointRequest(ctx context.Context, request fosite.AccessRequester) error {
+	if !h.CanHandleTokenEndpointRequest(ctx, request) {
+		return fosite.ErrUnknownRequest
+	}
+	if !request.GetClient().GetGrantTypes().Has(GrantTypeDeviceCode) {
+		return fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use grant %q.", GrantTypeDeviceCode)
+	}
+	code := request.GetRequestForm().Get("device_code")
+	if code == "" {
+		return fosite.ErrInvalidRequest.WithHint("The device_code parameter is missing.")
+	}
+	now := time.Now().UTC()
+	d, err := h.Storage.PollDeviceAuthorization(ctx, deviceCodeSignature(code), now)
+	if errors.Is(err, fosite.ErrNotFound) {
+		return fosite.ErrInvalidGrant.WithHint("The device code is unknown.")
+	} else if err != nil {
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	if d.GetClient().GetID() != request.GetClient().GetID() {
+		return fosite.ErrInvalidGrant.WithHint("The device code was issued to another client.")
+	}
+	if now.After(d.ExpiresAt) {
+		return ErrExpiredToken
+	}
+	switch d.Status {
+	case DeviceStatusDenied:
+		return fosite.ErrAccessDenied.WithHint("The user denied the authorization request.")
+	case DeviceStatusUsed:
+		return fosite.ErrInvalidGrant.WithHint("The device code has already been used.")
+	case DeviceStatusPending:
+		if !d.LastPolledAt.IsZero() && now.Sub(d.LastPolledAt) < d.Interval {
+			return ErrSlowDown
+		}
+		return ErrAuthorizationPending
+	}
+
+	request.SetID(d.GetID())
+	request.SetSession(d.GetSession())
+	request.SetRequestedScopes(d.GetRequestedScopes())
+	request.SetRequestedAudience(d.GetRequestedAudience())
+	for _, scope := range d.GetGrantedScopes() {
+		request.GrantScope(scope)
+	}
+	for _, aud := range d.GetGrantedAudience() {
+		request.GrantAudience(aud)
+	}
+	atLifespan := fosite.GetEffectiveLifespan(request.GetClient(), GrantTypeDeviceCode, fosite.AccessToken, h.Config.GetAccessTokenLifespan(ctx))
+	request.GetSession().SetExpiresAt(fosite.AccessToken, now.Add(atLifespan).Round(time.Second))
+	rtLifespan := fosite.GetEffectiveLifespan(request.GetClient(), GrantTypeDeviceCode, fosite.RefreshToken, h.Config.GetRefreshTokenLifespan(ctx))
+	if rtLifespan > -1 {
+		request.GetSession().SetExpiresAt(fosite.RefreshToken, now.Add(rtLifespan).Round(time.Second))
+	}
+	return nil
+}
+
+// PopulateTokenEndpointResponse issues the access token, a refresh token when an offline scope
+// was granted and the client may refresh, and an ID token for the openid scope.
+func (h *DeviceCodeGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return fosite.ErrUnknownRequest
+	}
+	code := requester.GetRequestForm().Get("device_code")
+	if err := h.Storage.InvalidateDeviceAuthorization(ctx, deviceCodeSignature(code)); err != nil {
+		return fosite.ErrInvalidGrant.WithHint("The device code has already been used.").WithWrap(err)
+	}
+
+	access, accessSignature, err := h.Strategy.GenerateAccessToken(ctx, requester)
+	if err != nil {
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	if err := h.Storage.CreateAccessTokenSession(ctx, accessSignature, requester.Sanitize([]string{})); err != nil {
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	responder.SetAccessToken(access)
+	responder.SetTokenType("bearer")
+	responder.SetExpiresIn(time.Until(requester.GetSession().GetExpiresAt(fosite.AccessToken)).Round(time.Second))
+	responder.SetScopes(requester.GetGrantedScopes())
+
+	refreshScopes := h.Config.GetRefreshTokenScopes(ctx)
+	if requester.GetClient().GetGrantTypes().Has("refresh_token") &&
+		(len(refreshScopes) == 0 || requester.GetGrantedScopes().HasOneOf(refreshScopes...)) {
+		refresh, refreshSignature, err := h.Strategy.GenerateRefreshToken(ctx, requester)
+		if err != nil {
+			return fosite.ErrServerError.WithWrap(err)
+		}
+		if err := h.Storage.CreateRefreshTokenSession(ctx, refreshSignature, accessSignature, requester.Sanitize([]string{})); err != nil {
+			return fosite.ErrServerError.WithWrap(err)
+		}
+		responder.SetExtra("refresh_token", refresh)
+	}
+
+	if requester.GetGrantedScopes().Has("openid") {
+		sess, ok := requester.GetSession().(openid.Session)
+		if !ok || sess.IDTokenClaims().Subject == "" {
+			return fosite.ErrServerError.WithDebug("The device authorization has no OpenID Connect session.")
+		}
+		sess.IDTokenClaims().AccessTokenHash = h.GetAccessTokenHash(ctx, requester, responder)
+		lifespan := fosite.GetEffectiveLifespan(requester.GetClient(), GrantTypeDeviceCode, fosite.IDToken, h.Config.GetIDTokenLifespan(ctx))
+		if err := h.IssueExplicitIDToken(ctx, lifespan, requester, responder); err != nil {
+			return fosite.ErrServerError.WithWrap(err)
+		}
+	}
+	return nil
+}
+
+// CanSkipClientAuth reports false: the client authenticates as it did at the device
+// authorization endpoint.
+func (h *DeviceCodeGrantHandler) CanSkipClientAuth(context.Context, fosite.AccessRequester) bool {
+	return false
+}
+
+// CanHandleTokenEndpointRequest reports whether the request uses the device code grant.
+func (h *DeviceCodeGrantHandler) CanHandleTokenEndpointRequest(_ context.Context, requester fosite.AccessRequester) bool {
+	return requester.GetGrantTypes().ExactOne(GrantTypeDeviceCode)
+}
+
+// FormatUserCode formats a user code for display, as two groups of four characters.
+func FormatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+// NormalizeUserCode undoes FormatUserCode and the variations of typing it: case, dashes and
+// spaces.
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			return r
+		}
+		return -1
+	}, code)
+}
+
+func newUserCode() (string, error) {
+	b := make([]byte, userCodeLength)
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = userCodeAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func newDeviceCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// deviceCodeSignature is the key device codes are stored under, so that the storage does not
+// hold codes that could be redeemed.
+func deviceCodeSignature(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
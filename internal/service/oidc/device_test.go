@@ -0,0 +1,68 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+)
+
+func TestDeviceCodeGrant_Expires(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:device?mode=memory&_fk=1")
+	defer client.Close()
+	hash, err := password.Hash("secret")
+	require.NoError(t, err)
+	client.OAuth2Client.Create().SetClientID("tv").SetClientSecret(hash).SetRedirectUris([]string{}).ExecX(ctx)
+
+	cfg, err := DefaultOIDCConfig("http://localhost:8888")
+	require.NoError(t, err)
+	cfg.DeviceCodeLifespan = time.Millisecond
+	store := NewFositeStorage(client)
+	store.SetDeviceClients([]string{"tv"})
+	provider := NewOAuth2Provider(cfg, store)
+	flow := NewDeviceFlow(cfg, store)
+
+	fc, err := store.GetClient(ctx, "tv")
+	require.NoError(t, err)
+	resp, err := flow.Authorize(ctx, fc, []string{"openid"}, nil, "http://localhost:8888/device")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = flow.Pending(ctx, resp.UserCode)
+	require.ErrorIs(t, err, ErrUnknownUserCode, "expired user codes are not shown")
+	form := url.Values{"grant_type": {GrantTypeDeviceCode}, "device_code": {resp.DeviceCode}}
+	req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("tv", "secret")
+	_, err = provider.NewAccessRequest(ctx, req, openid.NewDefaultSession())
+	require.ErrorIs(t, err, ErrExpiredToken)
+
+	n, err := store.PurgeExpired(ctx, time.Now(), 100)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Zero(t, store.StoreSizes()["device_codes"])
+}
+
+func TestUserCode_Normalize(t *testing.T) {
+	code, err := newUserCode()
+	require.NoError(t, err)
+	require.Len(t, code, userCodeLength)
+	formatted := FormatUserCode(code)
+	require.Equal(t, code, NormalizeUserCode(formatted))
+	require.Equal(t, code, NormalizeUserCode(" "+strings.ToLower(formatted[:4])+" "+formatted[5:]))
+	require.Equal(t, "BCDF", NormalizeUserCode("b-c-d-f-0-1-a"), "characters outside the alphabet are dropped")
+}
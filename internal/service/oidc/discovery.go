@@ -8,6 +8,9 @@ import (
 	"strings"
 )
 
+// ScopeGroups requests the groups and roles claims in ID tokens and userinfo.
+const ScopeGroups = "groups"
+
 // DiscoveryDocument returns the OIDC discovery metadata for the given issuer.
 func DiscoveryDocument(issuer string) map[string]interface{} {
 	// Strip trailing slash for consistent URLs.
@@ -16,15 +19,17 @@ func DiscoveryDocument(issuer string) map[string]interface{} {
 		"issuer":                                base,
 		"authorization_endpoint":                base + "/authorize",
 		"token_endpoint":                        base + "/token",
+		"device_authorization_endpoint":         base + DeviceAuthorizationPath,
+		"introspection_endpoint":                base + "/introspect",
 		"userinfo_endpoint":                     base + "/userinfo",
 		"jwks_uri":                             base + "/jwks.json",
-		"scopes_supported":                     []string{"openid", "profile", "email", "offline_access"},
+		"scopes_supported":                     []string{"openid", "profile", "email", "offline_access", ScopeGroups},
 		"response_types_supported":             []string{"code", "token", "id_token", "code token", "code id_token", "id_token token", "code id_token token"},
-		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "implicit"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token", "implicit", GrantTypeDeviceCode, GrantTypeTokenExchange},
 		"subject_types_supported":              []string{"public"},
 		"id_token_signing_alg_values_supported": []string{"RS256"},
 		"token_endpoint_auth_methods_supported": []string{"client_secret_post", "client_secret_basic"},
-		"claims_supported":                     []string{"sub", "iss", "aud", "exp", "iat", "email", "name", "preferred_username"},
+		"claims_supported":                     []string{"sub", "iss", "aud", "exp", "iat", "email", "name", "preferred_username", "groups", "roles"},
 	}
 }
 
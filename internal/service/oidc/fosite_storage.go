@@ -5,6 +5,8 @@ package oidc
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,11 +20,12 @@ import (
 
 	"github.com/qinzj/superpowers-demo/ent"
 	"github.com/qinzj/superpowers-demo/ent/oauth2client"
+	"github.com/qinzj/superpowers-demo/internal/domain"
 )
 
 // FositeStorage implements fosite.Storage using ent for clients and in-memory for sessions.
-// OAuth2Client entities are loaded from the database; authorize codes, access tokens, refresh
-// tokens, PKCE, and OIDC sessions are stored in memory (suitable for development).
+// OAuth2Client entities are loaded from the database; authorize codes, device codes, access
+// tokens, refresh tokens, PKCE, and OIDC sessions are stored in memory (suitable for development).
 type FositeStorage struct {
 	client *ent.Client
 
@@ -35,6 +38,13 @@ type FositeStorage struct {
 	accessTokenIDs   map[string]string // requestID -> signature
 	refreshTokenIDs  map[string]string // requestID -> signature
 	blacklistedJTIs  map[string]time.Time
+	deviceCodes      map[string]*DeviceAuthorization // device code signature -> request
+	deviceUserCodes  map[string]string               // user code -> device code signature
+
+	// Token exchange policies by client ID; see SetTokenExchangePolicies.
+	tokenExchangePolicies map[string]TokenExchangePolicy
+	// Clients allowed the device authorization grant; see SetDeviceClients.
+	deviceClients map[string]bool
 
 	mu sync.RWMutex
 }
@@ -62,6 +72,8 @@ func NewFositeStorage(client *ent.Client) *FositeStorage {
 		accessTokenIDs:  make(map[string]string),
 		refreshTokenIDs: make(map[string]string),
 		blacklistedJTIs: make(map[string]time.Time),
+		deviceCodes:     make(map[string]*DeviceAuthorization),
+		deviceUserCodes: make(map[string]string),
 	}
 }
 
@@ -74,9 +86,13 @@ var (
 	_ openid.OpenIDConnectRequestStorage           = (*FositeStorage)(nil)
 	_ pkce.PKCERequestStorage                     = (*FositeStorage)(nil)
 	_ rfc7523.RFC7523KeyStorage                   = (*FositeStorage)(nil)
+	_ DeviceAuthorizationStorage                  = (*FositeStorage)(nil)
+	_ TokenExchangeStorage                        = (*FositeStorage)(nil)
 )
 
-// GetClient loads the OAuth2 client by ID from the database.
+// GetClient loads the OAuth2 client by ID from the database. Clients with a token exchange
+// policy may also use the token exchange grant, and clients set with SetDeviceClients the device
+// authorization grant.
 func (s *FositeStorage) GetClient(ctx context.Context, id string) (fosite.Client, error) {
 	c, err := s.client.OAuth2Client.Query().
 		Where(oauth2client.ClientIDEQ(id)).
@@ -87,7 +103,53 @@ func (s *FositeStorage) GetClient(ctx context.Context, id string) (fosite.Client
 		}
 		return nil, err
 	}
-	return entClientToFosite(c), nil
+	fc := entClientToFosite(c)
+	s.mu.RLock()
+	_, exchange := s.tokenExchangePolicies[id]
+	device := s.deviceClients[id]
+	s.mu.RUnlock()
+	if exchange {
+		fc.GrantTypes = append(fc.GrantTypes, GrantTypeTokenExchange)
+	}
+	if device {
+		fc.GrantTypes = append(fc.GrantTypes, GrantTypeDeviceCode)
+	}
+	return fc, nil
+}
+
+// SetDeviceClients replaces the clients allowed the device authorization grant; other clients
+// cannot use it.
+func (s *FositeStorage) SetDeviceClients(clientIDs []string) {
+	m := make(map[string]bool, len(clientIDs))
+	for _, id := range clientIDs {
+		m[id] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deviceClients = m
+}
+
+// SetTokenExchangePolicies replaces the token exchange policies; clients without one cannot
+// use the token exchange grant.
+func (s *FositeStorage) SetTokenExchangePolicies(policies []TokenExchangePolicy) {
+	m := make(map[string]TokenExchangePolicy, len(policies))
+	for _, p := range policies {
+		m[p.ClientID] = p
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenExchangePolicies = m
+}
+
+// GetTokenExchangePolicy returns the token exchange policy of the client, or nil if it has none.
+func (s *FositeStorage) GetTokenExchangePolicy(_ context.Context, clientID string) (*TokenExchangePolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.tokenExchangePolicies[clientID]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
 }
 
 // ClientAssertionJWTValid returns nil if the JTI is not known (valid to use).
@@ -151,6 +213,90 @@ func (s *FositeStorage) InvalidateAuthorizeCodeSession(_ context.Context, code s
 	return nil
 }
 
+// CreateDeviceAuthorization stores a device authorization request. User codes are random, and
+// a collision with a live one is reported as an error rather than overwriting it.
+func (s *FositeStorage) CreateDeviceAuthorization(_ context.Context, signature string, d *DeviceAuthorization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, taken := s.deviceUserCodes[d.UserCode]; taken {
+		return fosite.ErrServerError.WithDebug("user code collision")
+	}
+	stored := *d
+	s.deviceCodes[signature] = &stored
+	s.deviceUserCodes[d.UserCode] = signature
+	return nil
+}
+
+// GetDeviceAuthorizationByUserCode returns a copy of the device authorization request with the
+// given user code.
+func (s *FositeStorage) GetDeviceAuthorizationByUserCode(_ context.Context, userCode string) (*DeviceAuthorization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.deviceCodes[s.deviceUserCodes[userCode]]
+	if !ok {
+		return nil, fosite.ErrNotFound
+	}
+	out := *d
+	return &out, nil
+}
+
+// PollDeviceAuthorization records a token request for the device code and returns a copy of the
+// request as it was before.
+func (s *FositeStorage) PollDeviceAuthorization(_ context.Context, signature string, now time.Time) (*DeviceAuthorization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deviceCodes[signature]
+	if !ok {
+		return nil, fosite.ErrNotFound
+	}
+	out := *d
+	d.LastPolledAt = now
+	return &out, nil
+}
+
+// CompleteDeviceAuthorization approves or denies a pending device authorization request. An
+// approval grants scopes and the requested audience to session.
+func (s *FositeStorage) CompleteDeviceAuthorization(_ context.Context, userCode string, approved bool, session fosite.Session, scopes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deviceCodes[s.deviceUserCodes[userCode]]
+	if !ok || d.Status != DeviceStatusPending {
+		return fosite.ErrNotFound
+	}
+	if !approved {
+		d.Status = DeviceStatusDenied
+		return nil
+	}
+	req := fosite.NewRequest()
+	req.Merge(d.Requester)
+	req.SetID(d.GetID())
+	req.SetSession(session)
+	for _, scope := range scopes {
+		req.GrantScope(scope)
+	}
+	for _, aud := range req.GetRequestedAudience() {
+		req.GrantAudience(aud)
+	}
+	d.Requester = req
+	d.Status = DeviceStatusApproved
+	return nil
+}
+
+// InvalidateDeviceAuthorization marks an approved device authorization request used.
+func (s *FositeStorage) InvalidateDeviceAuthorization(_ context.Context, signature string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deviceCodes[signature]
+	if !ok {
+		return fosite.ErrNotFound
+	}
+	if d.Status != DeviceStatusApproved {
+		return fosite.ErrInvalidatedAuthorizeCode
+	}
+	d.Status = DeviceStatusUsed
+	return nil
+}
+
 // CreateAccessTokenSession stores the access token session.
 func (s *FositeStorage) CreateAccessTokenSession(_ context.Context, sig string, req fosite.Requester) error {
 	s.mu.Lock()
@@ -252,6 +398,224 @@ func (s *FositeStorage) RevokeRefreshToken(_ context.Context, requestID string)
 	return nil
 }
 
+// RevokeSubjectTokens deletes every access token and deactivates every refresh token
+// whose session subject is the given user ID.
+func (s *FositeStorage) RevokeSubjectTokens(_ context.Context, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sig, req := range s.accessTokens {
+		if requesterSubject(req) == subject {
+			delete(s.accessTokenIDs, req.GetID())
+			delete(s.accessTokens, sig)
+		}
+	}
+	for sig, rel := range s.refreshTokens {
+		if rel.active && requesterSubject(rel.Requester) == subject {
+			rel.active = false
+			s.refreshTokens[sig] = rel
+		}
+	}
+	return nil
+}
+
+// RevokeSubjectClientTokens deletes the access tokens and deactivates the refresh tokens
+// issued to clientID for the given user ID.
+func (s *FositeStorage) RevokeSubjectClientTokens(_ context.Context, subject, clientID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sig, req := range s.accessTokens {
+		if requesterSubject(req) == subject && requesterClientID(req) == clientID {
+			delete(s.accessTokenIDs, req.GetID())
+			delete(s.accessTokens, sig)
+		}
+	}
+	for sig, rel := range s.refreshTokens {
+		if rel.active && requesterSubject(rel.Requester) == subject && requesterClientID(rel.Requester) == clientID {
+			rel.active = false
+			s.refreshTokens[sig] = rel
+		}
+	}
+	return nil
+}
+
+// RevokeClientTokens deletes the access tokens and deactivates the refresh tokens issued to
+// clientID for any user.
+func (s *FositeStorage) RevokeClientTokens(_ context.Context, clientID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sig, req := range s.accessTokens {
+		if requesterClientID(req) == clientID {
+			delete(s.accessTokenIDs, req.GetID())
+			delete(s.accessTokens, sig)
+		}
+	}
+	for sig, rel := range s.refreshTokens {
+		if rel.active && requesterClientID(rel.Requester) == clientID {
+			rel.active = false
+			s.refreshTokens[sig] = rel
+		}
+	}
+	return nil
+}
+
+// ListSubjectGrants returns one entry per client holding an access token or active refresh
+// token for the given user ID, with the union of granted scopes and the earliest grant time.
+func (s *FositeStorage) ListSubjectGrants(_ context.Context, subject string) ([]*domain.Grant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	byClient := make(map[string]*domain.Grant)
+	add := func(req fosite.Requester) {
+		if requesterSubject(req) != subject {
+			return
+		}
+		clientID := requesterClientID(req)
+		g, ok := byClient[clientID]
+		if !ok {
+			g = &domain.Grant{ClientID: clientID, GrantedAt: req.GetRequestedAt()}
+			byClient[clientID] = g
+		}
+		if at := req.GetRequestedAt(); at.Before(g.GrantedAt) {
+			g.GrantedAt = at
+		}
+		for _, scope := range req.GetGrantedScopes() {
+			if !slices.Contains(g.Scopes, scope) {
+				g.Scopes = append(g.Scopes, scope)
+			}
+		}
+	}
+	for _, req := range s.accessTokens {
+		add(req)
+	}
+	for _, rel := range s.refreshTokens {
+		if rel.active {
+			add(rel.Requester)
+		}
+	}
+	out := make([]*domain.Grant, 0, len(byClient))
+	for _, g := range byClient {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ClientID < out[j].ClientID })
+	return out, nil
+}
+
+func requesterClientID(req fosite.Requester) string {
+	if req == nil || req.GetClient() == nil {
+		return ""
+	}
+	return req.GetClient().GetID()
+}
+
+func requesterSubject(req fosite.Requester) string {
+	if req == nil || req.GetSession() == nil {
+		return ""
+	}
+	return req.GetSession().GetSubject()
+}
+
+// StoreSizes returns the number of entries held in each in-memory store, keyed by store name.
+func (s *FositeStorage) StoreSizes() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return map[string]int{
+		"authorize_codes": len(s.authorizeCodes),
+		"access_tokens":   len(s.accessTokens),
+		"refresh_tokens":  len(s.refreshTokens),
+		"oidc_sessions":   len(s.oidcSessions),
+		"pkce_sessions":   len(s.pkceSessions),
+		"used_jtis":       len(s.blacklistedJTIs),
+		"device_codes":    len(s.deviceCodes),
+	}
+}
+
+// PurgeExpired removes up to limit expired authorize codes (with their PKCE and OIDC
+// sessions), device codes, access tokens, refresh tokens and used JWT IDs, and returns how many
+// were removed.
+func (s *FositeStorage) PurgeExpired(_ context.Context, now time.Time, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for code, rel := range s.authorizeCodes {
+		if n >= limit {
+			return n, nil
+		}
+		if expired(rel.Requester, fosite.AuthorizeCode, now) {
+			delete(s.authorizeCodes, code)
+			delete(s.pkceSessions, code)
+			delete(s.oidcSessions, code)
+			n++
+		}
+	}
+	for code, req := range s.oidcSessions {
+		if n >= limit {
+			return n, nil
+		}
+		if expired(req, fosite.AuthorizeCode, now) {
+			delete(s.oidcSessions, code)
+			n++
+		}
+	}
+	for code, req := range s.pkceSessions {
+		if n >= limit {
+			return n, nil
+		}
+		if expired(req, fosite.AuthorizeCode, now) {
+			delete(s.pkceSessions, code)
+			n++
+		}
+	}
+	for sig, d := range s.deviceCodes {
+		if n >= limit {
+			return n, nil
+		}
+		if d.ExpiresAt.Before(now) {
+			delete(s.deviceUserCodes, d.UserCode)
+			delete(s.deviceCodes, sig)
+			n++
+		}
+	}
+	for sig, req := range s.accessTokens {
+		if n >= limit {
+			return n, nil
+		}
+		if expired(req, fosite.AccessToken, now) {
+			delete(s.accessTokenIDs, req.GetID())
+			delete(s.accessTokens, sig)
+			n++
+		}
+	}
+	for sig, rel := range s.refreshTokens {
+		if n >= limit {
+			return n, nil
+		}
+		if expired(rel.Requester, fosite.RefreshToken, now) {
+			delete(s.refreshTokenIDs, rel.GetID())
+			delete(s.refreshTokens, sig)
+			n++
+		}
+	}
+	for jti, exp := range s.blacklistedJTIs {
+		if n >= limit {
+			return n, nil
+		}
+		if exp.Before(now) {
+			delete(s.blacklistedJTIs, jti)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// expired reports whether the requester's token of the given type expired before now.
+// Tokens without an expiry never expire.
+func expired(req fosite.Requester, tt fosite.TokenType, now time.Time) bool {
+	if req == nil || req.GetSession() == nil {
+		return false
+	}
+	exp := req.GetSession().GetExpiresAt(tt)
+	return !exp.IsZero() && exp.Before(now)
+}
+
 // Authenticate implements ResourceOwnerPasswordCredentialsGrantStorage.
 // Returns ErrNotFound; password grant is not supported in this minimal implementation.
 func (s *FositeStorage) Authenticate(_ context.Context, _, _ string) (string, error) {
@@ -342,7 +706,7 @@ func (s *FositeStorage) MarkJWTUsedForTime(ctx context.Context, jti string, exp
 }
 
 // entClientToFosite converts an ent OAuth2Client to fosite.DefaultClient.
-// The client_secret in the DB should be stored as a bcrypt hash.
+// The client_secret in the DB is stored as a PHC-encoded hash (see SecretsHasher).
 func entClientToFosite(c *ent.OAuth2Client) *fosite.DefaultClient {
 	redirectURIs := c.RedirectUris
 	if redirectURIs == nil {
@@ -354,6 +718,6 @@ func entClientToFosite(c *ent.OAuth2Client) *fosite.DefaultClient {
 		RedirectURIs:  redirectURIs,
 		GrantTypes:   []string{"authorization_code", "refresh_token", "implicit"},
 		ResponseTypes: []string{"code", "token", "id_token", "id_token token", "code id_token", "code token", "code id_token token"},
-		Scopes:       []string{"openid", "profile", "email", "offline"},
+		Scopes:       []string{"openid", "profile", "email", "offline", ScopeGroups},
 	}
 }
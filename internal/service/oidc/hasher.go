@@ -0,0 +1,48 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ory/fosite"
+
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+)
+
+var errSecretMismatch = errors.New("client secret mismatch")
+
+// SecretsHasher adapts password.Hasher to fosite.Hasher so OAuth2 client secrets use
+// the same PHC-encoded hashes (argon2id or bcrypt) as user passwords.
+type SecretsHasher struct {
+	hasher *password.Hasher
+}
+
+var _ fosite.Hasher = (*SecretsHasher)(nil)
+
+// NewSecretsHasher returns a fosite.Hasher backed by h. A nil h uses password.Default().
+func NewSecretsHasher(h *password.Hasher) *SecretsHasher {
+	if h == nil {
+		h = password.Default()
+	}
+	return &SecretsHasher{hasher: h}
+}
+
+// Compare returns nil if data matches the encoded hash.
+func (s *SecretsHasher) Compare(_ context.Context, hash, data []byte) error {
+	if ok, _ := s.hasher.Verify(string(data), string(hash)); !ok {
+		return errSecretMismatch
+	}
+	return nil
+}
+
+// Hash returns the encoded hash of data.
+func (s *SecretsHasher) Hash(_ context.Context, data []byte) ([]byte, error) {
+	h, err := s.hasher.Hash(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(h), nil
+}
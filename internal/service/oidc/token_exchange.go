@@ -0,0 +1,240 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/handler/openid"
+)
+
+// GrantTypeTokenExchange is the grant type of OAuth 2.0 Token Exchange (RFC 8693).
+const GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// TokenTypeAccessToken identifies access tokens in subject_token_type, actor_token_type,
+// requested_token_type and issued_token_type. They are the only tokens that can be exchanged,
+// and the only ones issued.
+const TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+// ClaimActor is the claim naming the party acting on behalf of the subject (RFC 8693 section
+// 4.1). When the subject token was itself delegated, the previous actor is nested in it.
+const ClaimActor = "act"
+
+// ErrInvalidTarget is returned for audiences and resources a client may not exchange tokens
+// for (RFC 8693 section 2.2.2).
+var ErrInvalidTarget = &fosite.RFC6749Error{
+	ErrorField:       "invalid_target",
+	DescriptionField: "The requested audience or resource is invalid, unknown, or not allowed for this client.",
+	CodeField:        http.StatusBadRequest,
+}
+
+// TokenExchangePolicy is what a client may obtain with the token exchange grant. Clients
+// without a policy cannot use the grant.
+type TokenExchangePolicy struct {
+	ClientID string `mapstructure:"client_id"`
+	// Audiences are the audiences and resources the client may request tokens for; every token
+	// exchange names at least one.
+	Audiences []string `mapstructure:"audiences"`
+	// Scopes may be carried over from the subject token; the issued token never has a scope
+	// the subject token was not granted.
+	Scopes []string `mapstructure:"scopes"`
+	// Impersonation allows exchanges without an actor token: the issued token is
+	// indistinguishable from one issued to the subject.
+	Impersonation bool `mapstructure:"impersonation"`
+	// Delegation allows exchanges with an actor token issued to the client: the issued token
+	// names the actor in the act claim.
+	Delegation bool `mapstructure:"delegation"`
+}
+
+// TokenExchangeStorage looks up token exchange policies.
+type TokenExchangeStorage interface {
+	// GetTokenExchangePolicy returns the policy of the client, or nil if it has none.
+	GetTokenExchangePolicy(ctx context.Context, clientID string) (*TokenExchangePolicy, error)
+}
+
+// TokenExchangeHandler handles token requests with the token exchange grant type. It
+// exchanges an access token (the subject token) for one with a narrower scope addressed to
+// another audience, optionally on behalf of an actor. It issues no refresh or ID tokens.
+type TokenExchangeHandler struct {
+	Storage interface {
+		TokenExchangeStorage
+		oauth2.CoreStorage
+	}
+	Strategy oauth2.AccessTokenStrategy
+	Config   fosite.AccessTokenLifespanProvider
+}
+
+var _ fosite.TokenEndpointHandler = (*TokenExchangeHandler)(nil)
+
+// HandleTokenEndpointRequest validates the subject and actor tokens against the client's
+// policy, and takes the subject's session, with the act claim for delegation, from the
+// subject token.
+func (h *TokenExchangeHandler) HandleTokenEndpointRequest(ctx context.Context, request fosite.AccessRequester) error {
+	if !h.CanHandleTokenEndpointRequest(ctx, request) {
+		return fosite.ErrUnknownRequest
+	}
+	client := request.GetClient()
+	if !client.GetGrantTypes().Has(GrantTypeTokenExchange) {
+		return fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use grant %q.", GrantTypeTokenExchange)
+	}
+	policy, err := h.Storage.GetTokenExchangePolicy(ctx, client.GetID())
+	if err != nil {
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	if policy == nil {
+		return fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use grant %q.", GrantTypeTokenExchange)
+	}
+	form := request.GetRequestForm()
+	if t := form.Get("requested_token_type"); t != "" && t != TokenTypeAccessToken {
+		return fosite.ErrInvalidRequest.WithHintf("The requested_token_type %q is not supported; only access tokens are issued.", t)
+	}
+
+	subject, err := h.validateToken(ctx, "subject_token", form.Get("subject_token"), form.Get("subject_token_type"))
+	if err != nil {
+		return err
+	}
+	var actor fosite.Requester
+	if form.Get("actor_token") != "" {
+		if !policy.Delegation {
+			return fosite.ErrInvalidRequest.WithHint("The OAuth 2.0 Client is not allowed to exchange tokens on behalf of an actor.")
+		}
+		actor, err = h.validateToken(ctx, "actor_token", form.Get("actor_token"), form.Get("actor_token_type"))
+		if err != nil {
+			return err
+		}
+		if actor.GetClient().GetID() != client.GetID() {
+			return fosite.ErrInvalidRequest.WithHint("The actor token was issued to another client.")
+		}
+	} else if form.Get("actor_token_type") != "" {
+		return fosite.ErrInvalidRequest.WithHint("The actor_token_type parameter requires an actor_token.")
+	} else if !policy.Impersonation {
+		return fosite.ErrInvalidRequest.WithHint("The OAuth 2.0 Client is not allowed to impersonate; an actor_token is required.")
+	}
+
+	audiences := append(slices.Clone(request.GetRequestedAudience()), fosite.RemoveEmpty(form["resource"])...)
+	if len(audiences) == 0 {
+		return fosite.ErrInvalidRequest.WithHint("The audience or resource parameter is required.")
+	}
+	for _, aud := range audiences {
+		if !slices.Contains(policy.Audiences, aud) {
+			return ErrInvalidTarget.WithHintf("The OAuth 2.0 Client is not allowed to exchange tokens for audience %q.", aud)
+		}
+	}
+	scopes := request.GetRequestedScopes()
+	if len(scopes) == 0 {
+		// Without a scope parameter, the issued token keeps what the policy allows of the
+		// subject token's scopes.
+		for _, scope := range subject.GetGrantedScopes() {
+			if fosite.HierarchicScopeStrategy(policy.Scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	for _, scope := range scopes {
+		if !fosite.HierarchicScopeStrategy(policy.Scopes, scope) {
+			return fosite.ErrInvalidScope.WithHintf("The OAuth 2.0 Client is not allowed to exchange tokens for scope %q.", scope)
+		}
+		if !fosite.HierarchicScopeStrategy(subject.GetGrantedScopes(), scope) {
+			return fosite.ErrInvalidScope.WithHintf("The subject token was not granted scope %q.", scope)
+		}
+	}
+
+	session, ok := subject.GetSession().Clone().(*openid.DefaultSession)
+	if !ok {
+		return fosite.ErrServerError.WithDebug("The subject token has no OpenID Connect session.")
+	}
+	if session.Claims.Extra == nil {
+		session.Claims.Extra = map[string]interface{}{}
+	}
+	if actor != nil {
+		act := map[string]interface{}{"sub": actorSubject(actor), "client_id": actor.GetClient().GetID()}
+		if prev, ok := session.Claims.Extra[ClaimActor]; ok {
+			act[ClaimActor] = prev
+		}
+		session.Claims.Extra[ClaimActor] = act
+	}
+	// The issued token never outlives the subject token.
+	now := time.Now().UTC()
+	expiresAt := now.Add(fosite.GetEffectiveLifespan(client, GrantTypeTokenExchange, fosite.AccessToken, h.Config.GetAccessTokenLifespan(ctx))).Round(time.Second)
+	if exp := subject.GetSession().GetExpiresAt(fosite.AccessToken); !exp.IsZero() && exp.Before(expiresAt) {
+		expiresAt = exp
+	}
+	session.ExpiresAt = map[fosite.TokenType]time.Time{fosite.AccessToken: expiresAt}
+	request.SetSession(session)
+	request.SetRequestedScopes(scopes)
+	request.SetRequestedAudience(audiences)
+	for _, scope := range scopes {
+		request.GrantScope(scope)
+	}
+	for _, aud := range audiences {
+		request.GrantAudience(aud)
+	}
+	return nil
+}
+
+// PopulateTokenEndpointResponse issues the access token.
+func (h *TokenExchangeHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return fosite.ErrUnknownRequest
+	}
+	access, signature, err := h.Strategy.GenerateAccessToken(ctx, requester)
+	if err != nil {
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	if err := h.Storage.CreateAccessTokenSession(ctx, signature, requester.Sanitize([]string{})); err != nil {
+		return fosite.ErrServerError.WithWrap(err)
+	}
+	responder.SetAccessToken(access)
+	responder.SetTokenType("bearer")
+	responder.SetExpiresIn(time.Until(requester.GetSession().GetExpiresAt(fosite.AccessToken)).Round(time.Second))
+	responder.SetScopes(requester.GetGrantedScopes())
+	responder.SetExtra("issued_token_type", TokenTypeAccessToken)
+	return nil
+}
+
+// CanSkipClientAuth reports false: only authenticated clients have a policy to check.
+func (h *TokenExchangeHandler) CanSkipClientAuth(context.Context, fosite.AccessRequester) bool {
+	return false
+}
+
+// CanHandleTokenEndpointRequest reports whether the request uses the token exchange grant.
+func (h *TokenExchangeHandler) CanHandleTokenEndpointRequest(_ context.Context, requester fosite.AccessRequester) bool {
+	return requester.GetGrantTypes().ExactOne(GrantTypeTokenExchange)
+}
+
+// validateToken returns the request an active access token was issued for. Invalid tokens
+// are reported as invalid_request, as RFC 8693 section 2.2.2 asks.
+func (h *TokenExchangeHandler) validateToken(ctx context.Context, param, token, tokenType string) (fosite.Requester, error) {
+	if token == "" {
+		return nil, fosite.ErrInvalidRequest.WithHintf("The %s parameter is missing.", param)
+	}
+	if tokenType != TokenTypeAccessToken {
+		return nil, fosite.ErrInvalidRequest.WithHintf("The %s_type %q is not supported; only access tokens can be exchanged.", param, tokenType)
+	}
+	req, err := h.Storage.GetAccessTokenSession(ctx, h.Strategy.AccessTokenSignature(ctx, token), openid.NewDefaultSession())
+	if errors.Is(err, fosite.ErrNotFound) {
+		return nil, fosite.ErrInvalidRequest.WithHintf("The %s is invalid, expired or revoked.", param)
+	} else if err != nil {
+		return nil, fosite.ErrServerError.WithWrap(err)
+	}
+	if err := h.Strategy.ValidateAccessToken(ctx, req, token); err != nil {
+		return nil, fosite.ErrInvalidRequest.WithHintf("The %s is invalid, expired or revoked.", param).WithWrap(err)
+	}
+	return req, nil
+}
+
+// actorSubject names the actor of a delegated exchange: the subject of the actor token, or
+// its client when the token has no subject.
+func actorSubject(actor fosite.Requester) string {
+	if sub := actor.GetSession().GetSubject(); sub != "" {
+		return sub
+	}
+	return actor.GetClient().GetID()
+}
@@ -0,0 +1,74 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+)
+
+func TestTokenExchange_NeverOutlivesSubjectToken(t *testing.T) {
+	ctx := context.Background()
+	client := enttest.Open(t, "sqlite3", "file:token_exchange?mode=memory&_fk=1")
+	defer client.Close()
+	hash, err := password.Hash("secret")
+	require.NoError(t, err)
+	client.OAuth2Client.Create().SetClientID("gateway").SetClientSecret(hash).SetRedirectUris([]string{}).ExecX(ctx)
+	client.OAuth2Client.Create().SetClientID("web").SetClientSecret(hash).SetRedirectUris([]string{}).ExecX(ctx)
+
+	cfg, err := DefaultOIDCConfig("http://localhost:8888")
+	require.NoError(t, err)
+	store := NewFositeStorage(client)
+	store.SetTokenExchangePolicies([]TokenExchangePolicy{{ClientID: "gateway", Audiences: []string{"orders-api"}, Impersonation: true}})
+	provider := NewOAuth2Provider(cfg, store)
+
+	gateway, err := store.GetClient(ctx, "gateway")
+	require.NoError(t, err)
+	require.Contains(t, gateway.GetGrantTypes(), GrantTypeTokenExchange)
+	web, err := store.GetClient(ctx, "web")
+	require.NoError(t, err)
+	require.NotContains(t, web.GetGrantTypes(), GrantTypeTokenExchange, "clients without a policy cannot exchange tokens")
+
+	// A subject token issued to web that expires well before the configured lifespan.
+	subject := fosite.NewAccessRequest(openid.NewDefaultSession())
+	subject.Client = web
+	subject.GetSession().(*openid.DefaultSession).SetSubject("alice")
+	expiresAt := time.Now().UTC().Add(time.Minute).Round(time.Second)
+	subject.GetSession().SetExpiresAt(fosite.AccessToken, expiresAt)
+	token, signature, err := compose.NewOAuth2HMACStrategy(cfg.NewFositeConfig()).GenerateAccessToken(ctx, subject)
+	require.NoError(t, err)
+	require.NoError(t, store.CreateAccessTokenSession(ctx, signature, subject))
+
+	form := url.Values{
+		"grant_type":         {GrantTypeTokenExchange},
+		"subject_token":      {token},
+		"subject_token_type": {TokenTypeAccessToken},
+		"audience":           {"orders-api"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("gateway", "secret")
+	ar, err := provider.NewAccessRequest(ctx, req, openid.NewDefaultSession())
+	require.NoError(t, err)
+	require.Equal(t, "alice", ar.GetSession().GetSubject())
+	require.Equal(t, expiresAt, ar.GetSession().GetExpiresAt(fosite.AccessToken))
+	require.Equal(t, fosite.Arguments{"orders-api"}, ar.GetGrantedAudience())
+	resp, err := provider.NewAccessResponse(ctx, ar)
+	require.NoError(t, err)
+	require.LessOrEqual(t, resp.GetExtra("expires_in"), int64(60))
+}
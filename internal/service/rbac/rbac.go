@@ -0,0 +1,437 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// AdminRole is the role required for the admin API.
+const AdminRole = "admin"
+
+const maxNameLength = 128
+
+var (
+	// ErrGroupNotFound is returned when the group does not exist.
+	ErrGroupNotFound = errors.New("group not found")
+	// ErrRoleNotFound is returned when the role does not exist.
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrUserNotFound is returned when the user referenced by a membership change does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrGroupExists is returned when creating a group whose name is taken.
+	ErrGroupExists = errors.New("group already exists")
+	// ErrRoleExists is returned when creating a role whose name is taken.
+	ErrRoleExists = errors.New("role already exists")
+	// ErrInvalidName is returned for empty, overlong or non-printable group and role names.
+	ErrInvalidName = errors.New("invalid name")
+	// ErrSyncedGroup is returned when changing the membership of a group owned by an upstream IdP.
+	ErrSyncedGroup = errors.New("group is managed by an upstream connector")
+)
+
+// Service manages groups, roles and membership.
+type Service struct {
+	repo  Repository
+	users UserFinder
+}
+
+// NewService creates a Service with the given repositories.
+func NewService(repo Repository, users UserFinder) *Service {
+	return &Service{repo: repo, users: users}
+}
+
+// CreateGroup creates a locally managed group.
+func (s *Service) CreateGroup(ctx context.Context, name, description string) (*domain.Group, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GroupByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("create group: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrGroupExists
+	}
+	g := &domain.Group{Name: name, Description: description, CreatedAt: time.Now()}
+	if err := s.repo.CreateGroup(ctx, g); err != nil {
+		return nil, fmt.Errorf("create group: %w", err)
+	}
+	return g, nil
+}
+
+// GetGroup returns the group with the given ID.
+func (s *Service) GetGroup(ctx context.Context, id string) (*domain.Group, error) {
+	g, err := s.repo.GroupByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+	if g == nil {
+		return nil, ErrGroupNotFound
+	}
+	return g, nil
+}
+
+// ListGroups returns all groups.
+func (s *Service) ListGroups(ctx context.Context) ([]*domain.Group, error) {
+	groups, err := s.repo.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	return groups, nil
+}
+
+// RenameGroup changes the name of a locally managed group.
+func (s *Service) RenameGroup(ctx context.Context, id, name string) (*domain.Group, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.localGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if g.Name == name {
+		return g, nil
+	}
+	existing, err := s.repo.GroupByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("rename group: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrGroupExists
+	}
+	if err := s.repo.RenameGroup(ctx, id, name); err != nil {
+		return nil, fmt.Errorf("rename group: %w", err)
+	}
+	g.Name = name
+	return g, nil
+}
+
+// DeleteGroup removes the group and its memberships.
+func (s *Service) DeleteGroup(ctx context.Context, id string) error {
+	ok, err := s.repo.DeleteGroup(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete group: %w", err)
+	}
+	if !ok {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+// AddGroupMember adds the user to a locally managed group. Adding an existing member is a no-op.
+func (s *Service) AddGroupMember(ctx context.Context, groupID, userID string) error {
+	if _, err := s.localGroup(ctx, groupID); err != nil {
+		return err
+	}
+	if err := s.requireUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.AddGroupMember(ctx, groupID, userID); err != nil {
+		return fmt.Errorf("add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupMember removes the user from a locally managed group.
+func (s *Service) RemoveGroupMember(ctx context.Context, groupID, userID string) error {
+	if _, err := s.localGroup(ctx, groupID); err != nil {
+		return err
+	}
+	if err := s.requireUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveGroupMember(ctx, groupID, userID); err != nil {
+		return fmt.Errorf("remove group member: %w", err)
+	}
+	return nil
+}
+
+// ListGroupMembers returns the users in the group.
+func (s *Service) ListGroupMembers(ctx context.Context, groupID string) ([]*domain.User, error) {
+	if _, err := s.GetGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+	users, err := s.repo.ListGroupMembers(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("list group members: %w", err)
+	}
+	return users, nil
+}
+
+// ListGroupRoles returns the roles granted to the group.
+func (s *Service) ListGroupRoles(ctx context.Context, groupID string) ([]*domain.Role, error) {
+	if _, err := s.GetGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+	roles, err := s.repo.ListGroupRoles(ctx, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("list group roles: %w", err)
+	}
+	return roles, nil
+}
+
+// CreateRole creates a role.
+func (s *Service) CreateRole(ctx context.Context, name, description string) (*domain.Role, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.RoleByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("create role: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrRoleExists
+	}
+	r := &domain.Role{Name: name, Description: description, CreatedAt: time.Now()}
+	if err := s.repo.CreateRole(ctx, r); err != nil {
+		return nil, fmt.Errorf("create role: %w", err)
+	}
+	return r, nil
+}
+
+// ListRoles returns all roles.
+func (s *Service) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	roles, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// DeleteRole removes the role and all its assignments.
+func (s *Service) DeleteRole(ctx context.Context, id string) error {
+	ok, err := s.repo.DeleteRole(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete role: %w", err)
+	}
+	if !ok {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+// AssignUserRole grants the role directly to the user.
+func (s *Service) AssignUserRole(ctx context.Context, roleID, userID string) error {
+	if err := s.requireRole(ctx, roleID); err != nil {
+		return err
+	}
+	if err := s.requireUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.AddUserRole(ctx, roleID, userID); err != nil {
+		return fmt.Errorf("assign user role: %w", err)
+	}
+	return nil
+}
+
+// UnassignUserRole removes a directly granted role from the user. Roles held through groups remain.
+func (s *Service) UnassignUserRole(ctx context.Context, roleID, userID string) error {
+	if err := s.requireRole(ctx, roleID); err != nil {
+		return err
+	}
+	if err := s.requireUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveUserRole(ctx, roleID, userID); err != nil {
+		return fmt.Errorf("unassign user role: %w", err)
+	}
+	return nil
+}
+
+// AssignGroupRole grants the role to every member of the group.
+func (s *Service) AssignGroupRole(ctx context.Context, roleID, groupID string) error {
+	if err := s.requireRole(ctx, roleID); err != nil {
+		return err
+	}
+	if _, err := s.GetGroup(ctx, groupID); err != nil {
+		return err
+	}
+	if err := s.repo.AddGroupRole(ctx, roleID, groupID); err != nil {
+		return fmt.Errorf("assign group role: %w", err)
+	}
+	return nil
+}
+
+// UnassignGroupRole removes the role from the group.
+func (s *Service) UnassignGroupRole(ctx context.Context, roleID, groupID string) error {
+	if err := s.requireRole(ctx, roleID); err != nil {
+		return err
+	}
+	if _, err := s.GetGroup(ctx, groupID); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveGroupRole(ctx, roleID, groupID); err != nil {
+		return fmt.Errorf("unassign group role: %w", err)
+	}
+	return nil
+}
+
+// Memberships returns the names of the user's groups and effective roles, each sorted.
+func (s *Service) Memberships(ctx context.Context, userID string) (groups, roles []string, err error) {
+	gs, err := s.repo.UserGroups(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user groups: %w", err)
+	}
+	rs, err := s.repo.UserRoles(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user roles: %w", err)
+	}
+	groups = make([]string, len(gs))
+	for i, g := range gs {
+		groups[i] = g.Name
+	}
+	roles = make([]string, len(rs))
+	for i, r := range rs {
+		roles[i] = r.Name
+	}
+	return groups, roles, nil
+}
+
+// HasRole reports whether the user holds the named role directly or through a group.
+func (s *Service) HasRole(ctx context.Context, userID, role string) (bool, error) {
+	rs, err := s.repo.UserRoles(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("user roles: %w", err)
+	}
+	for _, r := range rs {
+		if r.Name == role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SyncConnectorGroups makes the user's membership in groups owned by connectorID match names.
+// Missing groups are created and owned by the connector. Names that collide with a local group
+// or a group of another connector are skipped, so an upstream IdP can never grant access to
+// groups it does not own. Locally managed memberships are left untouched.
+func (s *Service) SyncConnectorGroups(ctx context.Context, connectorID, userID string, names []string) error {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n, err := normalizeName(n); err == nil {
+			want[n] = true
+		}
+	}
+	current, err := s.repo.UserGroups(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("sync groups: %w", err)
+	}
+	have := make(map[string]bool, len(current))
+	for _, g := range current {
+		if g.ConnectorID != connectorID {
+			continue
+		}
+		if want[g.Name] {
+			have[g.Name] = true
+			continue
+		}
+		if err := s.repo.RemoveGroupMember(ctx, g.ID, userID); err != nil {
+			return fmt.Errorf("sync groups: %w", err)
+		}
+	}
+	for name := range want {
+		if have[name] {
+			continue
+		}
+		g, err := s.repo.GroupByName(ctx, name)
+		if err != nil {
+			return fmt.Errorf("sync groups: %w", err)
+		}
+		if g == nil {
+			g = &domain.Group{Name: name, ConnectorID: connectorID, CreatedAt: time.Now()}
+			if err := s.repo.CreateGroup(ctx, g); err != nil {
+				return fmt.Errorf("sync groups: %w", err)
+			}
+		} else if g.ConnectorID != connectorID {
+			continue
+		}
+		if err := s.repo.AddGroupMember(ctx, g.ID, userID); err != nil {
+			return fmt.Errorf("sync groups: %w", err)
+		}
+	}
+	return nil
+}
+
+// BootstrapAdmins ensures the admin role exists and is granted to each listed username.
+// Unknown usernames are returned so the caller can report them.
+func (s *Service) BootstrapAdmins(ctx context.Context, usernames []string) (missing []string, err error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	role, err := s.repo.RoleByName(ctx, AdminRole)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap admins: %w", err)
+	}
+	if role == nil {
+		if role, err = s.CreateRole(ctx, AdminRole, "Administrators"); err != nil {
+			return nil, fmt.Errorf("bootstrap admins: %w", err)
+		}
+	}
+	for _, name := range usernames {
+		u, err := s.users.ByUsername(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("bootstrap admins: %w", err)
+		}
+		if u == nil {
+			missing = append(missing, name)
+			continue
+		}
+		if err := s.repo.AddUserRole(ctx, role.ID, u.ID); err != nil {
+			return nil, fmt.Errorf("bootstrap admins: %w", err)
+		}
+	}
+	return missing, nil
+}
+
+func (s *Service) localGroup(ctx context.Context, groupID string) (*domain.Group, error) {
+	g, err := s.GetGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if g.ConnectorID != "" {
+		return nil, ErrSyncedGroup
+	}
+	return g, nil
+}
+
+func (s *Service) requireRole(ctx context.Context, roleID string) error {
+	r, err := s.repo.RoleByID(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("get role: %w", err)
+	}
+	if r == nil {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+func (s *Service) requireUser(ctx context.Context, userID string) error {
+	u, err := s.users.ByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return "", ErrInvalidName
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,143 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func newTestService(t *testing.T) (*Service, *storage.UserRepository) {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	userRepo := storage.NewUserRepository(client)
+	return NewService(storage.NewRBACRepository(client), userRepo), userRepo
+}
+
+func createUser(t *testing.T, repo *storage.UserRepository, username string) *domain.User {
+	t.Helper()
+	u := &domain.User{Username: username, Email: username + "@example.com", PasswordHash: "x", CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(context.Background(), u))
+	return u
+}
+
+func TestService_EffectiveRoles(t *testing.T) {
+	svc, users := newTestService(t)
+	ctx := context.Background()
+	u := createUser(t, users, "nina")
+
+	g, err := svc.CreateGroup(ctx, "engineering", "")
+	require.NoError(t, err)
+	_, err = svc.CreateGroup(ctx, "engineering", "")
+	require.ErrorIs(t, err, ErrGroupExists)
+
+	deployer, err := svc.CreateRole(ctx, "deployer", "")
+	require.NoError(t, err)
+	viewer, err := svc.CreateRole(ctx, "viewer", "")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.AddGroupMember(ctx, g.ID, u.ID))
+	require.NoError(t, svc.AddGroupMember(ctx, g.ID, u.ID), "adding twice is a no-op")
+	require.NoError(t, svc.AssignGroupRole(ctx, deployer.ID, g.ID))
+	require.NoError(t, svc.AssignUserRole(ctx, viewer.ID, u.ID))
+
+	groups, roles, err := svc.Memberships(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"engineering"}, groups)
+	require.Equal(t, []string{"deployer", "viewer"}, roles)
+
+	ok, err := svc.HasRole(ctx, u.ID, "deployer")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, svc.RemoveGroupMember(ctx, g.ID, u.ID))
+	_, roles, err = svc.Memberships(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"viewer"}, roles)
+
+	require.NoError(t, svc.DeleteRole(ctx, viewer.ID))
+	_, roles, err = svc.Memberships(ctx, u.ID)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+}
+
+func TestService_MembershipErrors(t *testing.T) {
+	svc, users := newTestService(t)
+	ctx := context.Background()
+	u := createUser(t, users, "omar")
+	g, err := svc.CreateGroup(ctx, "ops", "")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.AddGroupMember(ctx, "999", u.ID), ErrGroupNotFound)
+	require.ErrorIs(t, svc.AddGroupMember(ctx, g.ID, "999"), ErrUserNotFound)
+	require.ErrorIs(t, svc.AssignUserRole(ctx, "999", u.ID), ErrRoleNotFound)
+	require.ErrorIs(t, svc.DeleteGroup(ctx, "999"), ErrGroupNotFound)
+
+	_, err = svc.CreateGroup(ctx, "  ", "")
+	require.ErrorIs(t, err, ErrInvalidName)
+
+	require.NoError(t, svc.SyncConnectorGroups(ctx, "7", u.ID, []string{"upstream"}))
+	synced, err := svc.ListGroups(ctx)
+	require.NoError(t, err)
+	var upstreamID string
+	for _, g := range synced {
+		if g.Name == "upstream" {
+			upstreamID = g.ID
+			require.Equal(t, "7", g.ConnectorID)
+		}
+	}
+	require.NotEmpty(t, upstreamID)
+	require.ErrorIs(t, svc.RemoveGroupMember(ctx, upstreamID, u.ID), ErrSyncedGroup)
+}
+
+func TestService_SyncConnectorGroups(t *testing.T) {
+	svc, users := newTestService(t)
+	ctx := context.Background()
+	u := createUser(t, users, "pia")
+
+	local, err := svc.CreateGroup(ctx, "local-team", "")
+	require.NoError(t, err)
+	require.NoError(t, svc.AddGroupMember(ctx, local.ID, u.ID))
+
+	require.NoError(t, svc.SyncConnectorGroups(ctx, "1", u.ID, []string{"a", "b", "local-team"}))
+	groups, _, err := svc.Memberships(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "local-team"}, groups)
+
+	// Another connector cannot take over connector 1's groups.
+	require.NoError(t, svc.SyncConnectorGroups(ctx, "2", u.ID, nil))
+	groups, _, err = svc.Memberships(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "local-team"}, groups)
+
+	// Dropping upstream groups leaves local membership intact.
+	require.NoError(t, svc.SyncConnectorGroups(ctx, "1", u.ID, []string{"b"}))
+	groups, _, err = svc.Memberships(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "local-team"}, groups)
+}
+
+func TestService_BootstrapAdmins(t *testing.T) {
+	svc, users := newTestService(t)
+	ctx := context.Background()
+	u := createUser(t, users, "quinn")
+
+	missing, err := svc.BootstrapAdmins(ctx, []string{"quinn", "ghost"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghost"}, missing)
+
+	ok, err := svc.HasRole(ctx, u.ID, AdminRole)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Idempotent on restart.
+	_, err = svc.BootstrapAdmins(ctx, []string{"quinn"})
+	require.NoError(t, err)
+}
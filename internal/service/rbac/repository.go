@@ -0,0 +1,48 @@
+// Package rbac manages groups, roles and user membership.
+package rbac
+
+import (
+	"context"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// Repository defines persistence operations for groups, roles and their assignments.
+// Interface is defined in the consuming (service) layer per project architecture.
+// Lookups return nil, nil when the entity does not exist.
+type Repository interface {
+	CreateGroup(ctx context.Context, g *domain.Group) error
+	GroupByID(ctx context.Context, id string) (*domain.Group, error)
+	GroupByName(ctx context.Context, name string) (*domain.Group, error)
+	ListGroups(ctx context.Context) ([]*domain.Group, error)
+	RenameGroup(ctx context.Context, id, name string) error
+	// DeleteGroup removes the group and its memberships. Returns false if it did not exist.
+	DeleteGroup(ctx context.Context, id string) (bool, error)
+	AddGroupMember(ctx context.Context, groupID, userID string) error
+	RemoveGroupMember(ctx context.Context, groupID, userID string) error
+	ListGroupMembers(ctx context.Context, groupID string) ([]*domain.User, error)
+	// ListGroupRoles returns the roles granted to the group.
+	ListGroupRoles(ctx context.Context, groupID string) ([]*domain.Role, error)
+
+	CreateRole(ctx context.Context, r *domain.Role) error
+	RoleByID(ctx context.Context, id string) (*domain.Role, error)
+	RoleByName(ctx context.Context, name string) (*domain.Role, error)
+	ListRoles(ctx context.Context) ([]*domain.Role, error)
+	// DeleteRole removes the role and its assignments. Returns false if it did not exist.
+	DeleteRole(ctx context.Context, id string) (bool, error)
+	AddUserRole(ctx context.Context, roleID, userID string) error
+	RemoveUserRole(ctx context.Context, roleID, userID string) error
+	AddGroupRole(ctx context.Context, roleID, groupID string) error
+	RemoveGroupRole(ctx context.Context, roleID, groupID string) error
+
+	// UserGroups returns the groups the user belongs to, ordered by name.
+	UserGroups(ctx context.Context, userID string) ([]*domain.Group, error)
+	// UserRoles returns the user's effective roles (direct and through groups), ordered by name.
+	UserRoles(ctx context.Context, userID string) ([]*domain.Role, error)
+}
+
+// UserFinder looks up users referenced by membership operations.
+type UserFinder interface {
+	ByID(ctx context.Context, userID string) (*domain.User, error)
+	ByUsername(ctx context.Context, username string) (*domain.User, error)
+}
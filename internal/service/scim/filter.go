@@ -0,0 +1,40 @@
+package scim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filterPattern matches the only filter form supported: `attribute eq "value"`.
+var filterPattern = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9_.$-]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// Filter is a parsed SCIM equality filter (RFC 7644 section 3.4.2.2).
+type Filter struct {
+	// Attribute is the attribute path as written by the client; compare it case-insensitively.
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses expr. An empty expr yields a nil Filter. Only `attr eq "value"` is
+// supported; anything else returns ErrInvalidFilter.
+func ParseFilter(expr string) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	m := filterPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, fmt.Errorf("%w: only `attribute eq \"value\"` is supported", ErrInvalidFilter)
+	}
+	value, err := strconv.Unquote(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	return &Filter{Attribute: m[1], Value: value}, nil
+}
+
+// Is reports whether the filter targets attr (case-insensitive, per RFC 7643 section 2.1).
+func (f *Filter) Is(attr string) bool {
+	return strings.EqualFold(f.Attribute, attr)
+}
@@ -0,0 +1,405 @@
+// Package scim provisions users and groups for SCIM 2.0 clients (RFC 7643, RFC 7644).
+package scim
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/user"
+)
+
+var (
+	// ErrNotFound is returned when the user or group does not exist.
+	ErrNotFound = errors.New("resource not found")
+	// ErrUniqueness is returned when a userName or group displayName is already taken.
+	ErrUniqueness = errors.New("value already in use")
+	// ErrInvalidValue is returned for missing or malformed attributes.
+	ErrInvalidValue = errors.New("invalid attribute value")
+	// ErrInvalidFilter is returned for filters other than `attribute eq "value"`.
+	ErrInvalidFilter = errors.New("unsupported filter")
+	// ErrMutability is returned when changing a group that is managed by an upstream connector.
+	ErrMutability = errors.New("resource is read-only")
+)
+
+const minPasswordLen = 8
+
+// UserAttrs holds the provisioned attributes of a user.
+type UserAttrs struct {
+	UserName    string
+	ExternalID  string
+	DisplayName string
+	Email       string
+	// Password, when non-empty, replaces the user's password.
+	Password string
+	// Active, when set, activates or suspends the user; nil leaves the status as it is, and
+	// means active for new users.
+	Active *bool
+}
+
+// Service maps SCIM provisioning operations onto users and groups.
+type Service struct {
+	users  user.UserRepository
+	auth   *auth.AuthService
+	groups *rbac.Service
+	hasher *password.Hasher
+	events user.EventPublisher
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithPasswordHasher sets the hasher for provisioned passwords. Defaults to password.Default().
+func WithPasswordHasher(h *password.Hasher) Option {
+	return func(s *Service) {
+		if h != nil {
+			s.hasher = h
+		}
+	}
+}
+
+// WithEventPublisher sets the EventPublisher that receives user.created for provisioned users
+// and user.deleted for deprovisioned ones.
+func WithEventPublisher(p user.EventPublisher) Option {
+	return func(s *Service) {
+		s.events = p
+	}
+}
+
+// NewService creates a Service. authSvc revokes the sessions and tokens of deprovisioned users.
+func NewService(users user.UserRepository, authSvc *auth.AuthService, groups *rbac.Service, opts ...Option) *Service {
+	s := &Service{users: users, auth: authSvc, groups: groups, hasher: password.Default()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListUsers returns the users matching f (nil for all) starting at the 1-based startIndex, at most
+// count of them, and the total number of matches. Supported filters: userName, externalId.
+func (s *Service) ListUsers(ctx context.Context, f *Filter, startIndex, count int) ([]*domain.User, int, error) {
+	if f == nil {
+		users, total, err := s.users.Search(ctx, "", startIndex-1, count)
+		if err != nil {
+			return nil, 0, fmt.Errorf("list users: %w", err)
+		}
+		return users, total, nil
+	}
+	var u *domain.User
+	var err error
+	switch {
+	case f.Is("userName"):
+		u, err = s.users.ByUsername(ctx, f.Value)
+	case f.Is("externalId"):
+		u, err = s.users.ByExternalID(ctx, f.Value)
+	default:
+		return nil, 0, fmt.Errorf("%w: cannot filter users by %s", ErrInvalidFilter, f.Attribute)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("list users: %w", err)
+	}
+	if u == nil {
+		return nil, 0, nil
+	}
+	return page([]*domain.User{u}, startIndex, count), 1, nil
+}
+
+// GetUser returns the user with the given ID, or ErrNotFound.
+func (s *Service) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	u, err := s.users.ByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, ErrNotFound
+	}
+	return u, nil
+}
+
+// CreateUser provisions a new user. Without a password the user can only sign in through an
+// upstream IdP until one is set.
+func (s *Service) CreateUser(ctx context.Context, a UserAttrs) (*domain.User, error) {
+	if err := validateUser(&a); err != nil {
+		return nil, err
+	}
+	existing, err := s.users.ByUsername(ctx, a.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: userName %q", ErrUniqueness, a.UserName)
+	}
+	hash, err := s.passwordHash(a.Password)
+	if err != nil {
+		return nil, err
+	}
+	u := &domain.User{
+		Username:     a.UserName,
+		Email:        a.Email,
+		DisplayName:  a.DisplayName,
+		ExternalID:   a.ExternalID,
+		PasswordHash: hash,
+		Status:       domain.UserStatusActive,
+		CreatedAt:    time.Now(),
+	}
+	if a.Active != nil && !*a.Active {
+		u.Status = domain.UserStatusSuspended
+	}
+	if err := s.users.Create(ctx, u); err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+	s.publish(ctx, domain.WebhookUserCreated, map[string]string{
+		"user_id":  u.ID,
+		"username": u.Username,
+		"email":    u.Email,
+		"source":   "scim",
+	})
+	return u, nil
+}
+
+// ReplaceUser replaces every provisioned attribute of the user. Setting Active to false
+// deprovisions the user (see DeactivateUser); leaving it nil keeps the user's status.
+func (s *Service) ReplaceUser(ctx context.Context, id string, a UserAttrs) (*domain.User, error) {
+	if err := validateUser(&a); err != nil {
+		return nil, err
+	}
+	u, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.UserName != u.Username {
+		other, err := s.users.ByUsername(ctx, a.UserName)
+		if err != nil {
+			return nil, fmt.Errorf("replace user: %w", err)
+		}
+		if other != nil {
+			return nil, fmt.Errorf("%w: userName %q", ErrUniqueness, a.UserName)
+		}
+	}
+	u.Username = a.UserName
+	u.Email = a.Email
+	u.DisplayName = a.DisplayName
+	u.ExternalID = a.ExternalID
+	if err := s.users.Update(ctx, u); err != nil {
+		return nil, fmt.Errorf("replace user: %w", err)
+	}
+	if a.Password != "" {
+		hash, err := s.passwordHash(a.Password)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.users.UpdatePasswordHash(ctx, id, hash); err != nil {
+			return nil, fmt.Errorf("replace user: %w", err)
+		}
+	}
+	// A client that does not send active leaves the status alone, so that locked and unverified
+	// users stay that way; active=false only suspends active users, and active=true is an
+	// explicit activation from any status.
+	switch {
+	case a.Active == nil:
+	case !*a.Active && u.Active():
+		if err := s.deactivate(ctx, id); err != nil {
+			return nil, err
+		}
+		u.Status = domain.UserStatusSuspended
+	case *a.Active && !u.Active():
+		if err := s.auth.SetUserStatus(ctx, id, domain.UserStatusActive); err != nil {
+			return nil, fmt.Errorf("activate user: %w", err)
+		}
+		u.Status = domain.UserStatusActive
+	}
+	return u, nil
+}
+
+// DeactivateUser deprovisions the user: it is suspended rather than deleted, and its sessions
+// and OAuth2 tokens are revoked. Subscribers still get user.deleted, since the provisioning
+// client deleted the user.
+func (s *Service) DeactivateUser(ctx context.Context, id string) error {
+	if _, err := s.GetUser(ctx, id); err != nil {
+		return err
+	}
+	if err := s.deactivate(ctx, id); err != nil {
+		return err
+	}
+	s.publish(ctx, domain.WebhookUserDeleted, map[string]string{"user_id": id, "reason": "deprovisioned"})
+	return nil
+}
+
+func (s *Service) publish(ctx context.Context, t domain.WebhookEventType, data map[string]string) {
+	if s.events != nil {
+		s.events.Publish(ctx, t, data)
+	}
+}
+
+func (s *Service) deactivate(ctx context.Context, id string) error {
+	if err := s.auth.SetUserStatus(ctx, id, domain.UserStatusSuspended); err != nil {
+		return fmt.Errorf("deactivate user: %w", err)
+	}
+	return nil
+}
+
+// ListGroups returns the groups matching f (nil for all; displayName is the only supported
+// filter) starting at the 1-based startIndex, at most count of them, and the total matches.
+func (s *Service) ListGroups(ctx context.Context, f *Filter, startIndex, count int) ([]*domain.Group, int, error) {
+	if f != nil && !f.Is("displayName") {
+		return nil, 0, fmt.Errorf("%w: cannot filter groups by %s", ErrInvalidFilter, f.Attribute)
+	}
+	all, err := s.groups.ListGroups(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list groups: %w", err)
+	}
+	matched := all
+	if f != nil {
+		matched = nil
+		for _, g := range all {
+			if g.Name == f.Value {
+				matched = append(matched, g)
+			}
+		}
+	}
+	return page(matched, startIndex, count), len(matched), nil
+}
+
+// GetGroup returns the group with the given ID and its members, or ErrNotFound.
+func (s *Service) GetGroup(ctx context.Context, id string) (*domain.Group, []*domain.User, error) {
+	g, err := s.groups.GetGroup(ctx, id)
+	if err != nil {
+		return nil, nil, groupErr(err)
+	}
+	members, err := s.groups.ListGroupMembers(ctx, id)
+	if err != nil {
+		return nil, nil, groupErr(err)
+	}
+	return g, members, nil
+}
+
+// CreateGroup creates a local group with the given members.
+func (s *Service) CreateGroup(ctx context.Context, name string, memberIDs []string) (*domain.Group, error) {
+	g, err := s.groups.CreateGroup(ctx, name, "")
+	if err != nil {
+		return nil, groupErr(err)
+	}
+	for _, id := range memberIDs {
+		if err := s.groups.AddGroupMember(ctx, g.ID, id); err != nil {
+			// Do not leave a half-provisioned group behind.
+			_ = s.groups.DeleteGroup(ctx, g.ID)
+			return nil, groupErr(err)
+		}
+	}
+	return g, nil
+}
+
+// ReplaceGroup renames the group and sets its members to exactly memberIDs.
+// Groups synced from an upstream connector cannot be changed.
+func (s *Service) ReplaceGroup(ctx context.Context, id, name string, memberIDs []string) (*domain.Group, error) {
+	g, err := s.groups.RenameGroup(ctx, id, name)
+	if err != nil {
+		return nil, groupErr(err)
+	}
+	current, err := s.groups.ListGroupMembers(ctx, id)
+	if err != nil {
+		return nil, groupErr(err)
+	}
+	want := make(map[string]bool, len(memberIDs))
+	for _, m := range memberIDs {
+		want[m] = true
+	}
+	for _, u := range current {
+		if want[u.ID] {
+			delete(want, u.ID)
+			continue
+		}
+		if err := s.groups.RemoveGroupMember(ctx, id, u.ID); err != nil {
+			return nil, groupErr(err)
+		}
+	}
+	for _, m := range memberIDs {
+		if !want[m] {
+			continue
+		}
+		if err := s.groups.AddGroupMember(ctx, id, m); err != nil {
+			return nil, groupErr(err)
+		}
+		delete(want, m)
+	}
+	return g, nil
+}
+
+// DeleteGroup removes the group. Its members are not affected.
+func (s *Service) DeleteGroup(ctx context.Context, id string) error {
+	if err := s.groups.DeleteGroup(ctx, id); err != nil {
+		return groupErr(err)
+	}
+	return nil
+}
+
+func (s *Service) passwordHash(pwd string) (string, error) {
+	if pwd == "" {
+		// Unusable random password, as for users created through federation.
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return "", fmt.Errorf("generate password: %w", err)
+		}
+		pwd = hex.EncodeToString(b)
+	} else if len(pwd) < minPasswordLen {
+		return "", fmt.Errorf("%w: password must be at least %d characters", ErrInvalidValue, minPasswordLen)
+	}
+	hash, err := s.hasher.Hash(pwd)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return hash, nil
+}
+
+func validateUser(a *UserAttrs) error {
+	a.UserName = strings.TrimSpace(a.UserName)
+	a.Email = strings.TrimSpace(a.Email)
+	a.DisplayName = strings.TrimSpace(a.DisplayName)
+	a.ExternalID = strings.TrimSpace(a.ExternalID)
+	if a.UserName == "" {
+		return fmt.Errorf("%w: userName is required", ErrInvalidValue)
+	}
+	if a.Email == "" {
+		return fmt.Errorf("%w: an email address is required", ErrInvalidValue)
+	}
+	return nil
+}
+
+// groupErr translates rbac errors into SCIM errors.
+func groupErr(err error) error {
+	switch {
+	case errors.Is(err, rbac.ErrGroupNotFound):
+		return ErrNotFound
+	case errors.Is(err, rbac.ErrGroupExists):
+		return fmt.Errorf("%w: displayName", ErrUniqueness)
+	case errors.Is(err, rbac.ErrInvalidName):
+		return fmt.Errorf("%w: displayName", ErrInvalidValue)
+	case errors.Is(err, rbac.ErrUserNotFound):
+		return fmt.Errorf("%w: unknown member", ErrInvalidValue)
+	case errors.Is(err, rbac.ErrSyncedGroup):
+		return fmt.Errorf("%w: %v", ErrMutability, err)
+	default:
+		return err
+	}
+}
+
+// page returns the items from the 1-based startIndex, at most count of them.
+func page[T any](items []T, startIndex, count int) []T {
+	start := startIndex - 1
+	if start >= len(items) || count <= 0 {
+		return nil
+	}
+	end := start + count
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
@@ -0,0 +1,155 @@
+package scim
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/service/auth"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func newTestService(t *testing.T, opts ...Option) (*Service, *auth.AuthService, *rbac.Service) {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	userRepo := storage.NewUserRepository(client)
+	authSvc := auth.NewAuthService(userRepo, storage.NewSessionRepository(client))
+	rbacSvc := rbac.NewService(storage.NewRBACRepository(client), userRepo)
+	return NewService(userRepo, authSvc, rbacSvc, opts...), authSvc, rbacSvc
+}
+
+// recordingPublisher captures published lifecycle events.
+type recordingPublisher struct {
+	types []domain.WebhookEventType
+	data  []map[string]string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, t domain.WebhookEventType, data map[string]string) {
+	p.types = append(p.types, t)
+	p.data = append(p.data, data)
+}
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter(`userName eq "bjensen@example.com"`)
+	require.NoError(t, err)
+	require.True(t, f.Is("username"))
+	require.Equal(t, "bjensen@example.com", f.Value)
+
+	f, err = ParseFilter(`externalId EQ "a \"quoted\" id"`)
+	require.NoError(t, err)
+	require.Equal(t, `a "quoted" id`, f.Value)
+
+	f, err = ParseFilter("  ")
+	require.NoError(t, err)
+	require.Nil(t, f)
+
+	for _, expr := range []string{`userName co "b"`, `userName eq "a" and active eq "true"`, `userName eq bob`} {
+		_, err = ParseFilter(expr)
+		require.ErrorIs(t, err, ErrInvalidFilter, expr)
+	}
+}
+
+func TestService_UserLifecycle(t *testing.T) {
+	svc, authSvc, _ := newTestService(t)
+	ctx := context.Background()
+
+	u, err := svc.CreateUser(ctx, UserAttrs{UserName: "bjensen", ExternalID: "hr-1", Email: "bjensen@example.com", Password: "password1"})
+	require.NoError(t, err)
+	_, err = svc.CreateUser(ctx, UserAttrs{UserName: "bjensen", Email: "other@example.com"})
+	require.ErrorIs(t, err, ErrUniqueness)
+	_, err = svc.CreateUser(ctx, UserAttrs{UserName: "short", Email: "short@example.com", Password: "x"})
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	users, total, err := svc.ListUsers(ctx, &Filter{Attribute: "externalId", Value: "hr-1"}, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, u.ID, users[0].ID)
+	_, err = authSvc.ValidateCredentials(ctx, "bjensen", "password1")
+	require.NoError(t, err)
+
+	sess, err := authSvc.CreateSession(ctx, u.ID, false)
+	require.NoError(t, err)
+	inactive, active := false, true
+	u, err = svc.ReplaceUser(ctx, u.ID, UserAttrs{UserName: "barbara", Email: "bjensen@example.com", Active: &inactive})
+	require.NoError(t, err)
+	require.Equal(t, "barbara", u.Username)
+	require.False(t, u.Active())
+	got, err := authSvc.GetSession(ctx, sess.Token)
+	require.NoError(t, err)
+	require.Nil(t, got, "deactivation revokes sessions")
+
+	u, err = svc.ReplaceUser(ctx, u.ID, UserAttrs{UserName: "barbara", Email: "bjensen@example.com", Active: &active})
+	require.NoError(t, err)
+	require.True(t, u.Active())
+
+	// Without active, a replace keeps statuses other than suspended, such as locked.
+	require.NoError(t, authSvc.SetUserStatus(ctx, u.ID, domain.UserStatusLocked))
+	u, err = svc.ReplaceUser(ctx, u.ID, UserAttrs{UserName: "barbara", Email: "barbara@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, domain.UserStatusLocked, u.Status)
+	require.Equal(t, "barbara@example.com", u.Email)
+
+	require.NoError(t, svc.DeactivateUser(ctx, u.ID))
+	u, err = svc.GetUser(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.UserStatusSuspended, u.Status, "deprovisioned users are kept")
+	require.ErrorIs(t, svc.DeactivateUser(ctx, "999"), ErrNotFound)
+}
+
+func TestService_PublishesLifecycleEvents(t *testing.T) {
+	events := &recordingPublisher{}
+	svc, _, _ := newTestService(t, WithEventPublisher(events))
+	ctx := context.Background()
+
+	u, err := svc.CreateUser(ctx, UserAttrs{UserName: "cdavis", Email: "cdavis@example.com"})
+	require.NoError(t, err)
+	inactive := false
+	_, err = svc.ReplaceUser(ctx, u.ID, UserAttrs{UserName: "cdavis", Email: "cdavis@example.com", Active: &inactive})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeactivateUser(ctx, u.ID))
+
+	// Suspending through a replace is not a deletion; DELETE is.
+	require.Equal(t, []domain.WebhookEventType{domain.WebhookUserCreated, domain.WebhookUserDeleted}, events.types)
+	require.Equal(t, "scim", events.data[0]["source"])
+	require.Equal(t, u.ID, events.data[0]["user_id"])
+	require.Equal(t, map[string]string{"user_id": u.ID, "reason": "deprovisioned"}, events.data[1])
+}
+
+func TestService_ReplaceGroupMembers(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	ctx := context.Background()
+	var ids []string
+	for _, name := range []string{"ann", "ben", "cat"} {
+		u, err := svc.CreateUser(ctx, UserAttrs{UserName: name, Email: name + "@example.com"})
+		require.NoError(t, err)
+		ids = append(ids, u.ID)
+	}
+
+	g, err := svc.CreateGroup(ctx, "engineering", ids[:2])
+	require.NoError(t, err)
+	_, err = svc.CreateGroup(ctx, "engineering", nil)
+	require.ErrorIs(t, err, ErrUniqueness)
+
+	_, err = svc.ReplaceGroup(ctx, g.ID, "platform", ids[1:])
+	require.NoError(t, err)
+	g, members, err := svc.GetGroup(ctx, g.ID)
+	require.NoError(t, err)
+	require.Equal(t, "platform", g.Name)
+	require.Len(t, members, 2)
+	require.ElementsMatch(t, ids[1:], []string{members[0].ID, members[1].ID})
+
+	groups, total, err := svc.ListGroups(ctx, &Filter{Attribute: "displayName", Value: "platform"}, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, g.ID, groups[0].ID)
+
+	require.NoError(t, svc.DeleteGroup(ctx, g.ID))
+	_, _, err = svc.GetGroup(ctx, g.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// DefaultPageSize is the page size used by Search when pageSize is not positive.
+const DefaultPageSize = 20
+
+// UserPage is one page of a user search.
+type UserPage struct {
+	Users    []*domain.User
+	Query    string
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// HasPrev reports whether a page precedes this one.
+func (p *UserPage) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext reports whether more matches follow this page.
+func (p *UserPage) HasNext() bool {
+	return p.Page*p.PageSize < p.Total
+}
+
+// Search returns the 1-based page of users whose username, email or display name contains query.
+func (s *UserService) Search(ctx context.Context, query string, page, pageSize int) (*UserPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	query = strings.TrimSpace(query)
+	users, total, err := s.repo.Search(ctx, query, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("search users: %w", err)
+	}
+	return &UserPage{Users: users, Query: query, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// GetByUsername returns the user with the given username, or ErrUserNotFound.
+func (s *UserService) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	u, err := s.repo.ByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// CreateByAdmin creates a user on behalf of an administrator (CLI or bootstrap file). The rules
+// of Register apply, but the email address is trusted as verified.
+func (s *UserService) CreateByAdmin(ctx context.Context, username, email, pwd string) (*domain.User, error) {
+	return s.createWithPassword(ctx, username, email, pwd, "admin", true)
+}
+
+// ResetPassword sets a new password without checking the current one (administrative reset).
+func (s *UserService) ResetPassword(ctx context.Context, userID, newPwd string) error {
+	if _, err := s.Get(ctx, userID); err != nil {
+		return err
+	}
+	if len(newPwd) < minPasswordLen {
+		return ErrWeakPassword
+	}
+	hash, err := s.hasher.Hash(newPwd)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	if err := s.repo.UpdatePasswordHash(ctx, userID, hash); err != nil {
+		return fmt.Errorf("reset password: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditPasswordReset, UserID: userID})
+	return nil
+}
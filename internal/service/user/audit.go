@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// Auditor records security events. Recording is best effort and never fails the operation.
+type Auditor interface {
+	Record(ctx context.Context, e domain.AuditEvent)
+}
+
+// WithAuditor sets the Auditor that receives registration, credential and deletion events.
+func WithAuditor(a Auditor) Option {
+	return func(s *UserService) {
+		s.auditor = a
+	}
+}
+
+func (s *UserService) audit(ctx context.Context, e domain.AuditEvent) {
+	if s.auditor != nil {
+		s.auditor.Record(ctx, e)
+	}
+}
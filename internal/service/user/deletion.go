@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// DefaultDeletionGracePeriod is how long a requested account deletion can be cancelled.
+const DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+// RequestDeletion schedules the user's account for deletion once the grace period has passed and
+// returns when that is. Signing in again before then cancels it; the cleanup reaper performs the
+// deletion. Callers are responsible for signing the user out.
+func (s *UserService) RequestDeletion(ctx context.Context, userID string) (time.Time, error) {
+	if _, err := s.Get(ctx, userID); err != nil {
+		return time.Time{}, err
+	}
+	at := time.Now().Add(s.deletionGrace)
+	if err := s.repo.ScheduleDeletion(ctx, userID, at); err != nil {
+		return time.Time{}, fmt.Errorf("request deletion: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{
+		Type:    domain.AuditAccountDeletionRequested,
+		UserID:  userID,
+		Details: map[string]string{"scheduled_for": at.UTC().Format(time.RFC3339)},
+	})
+	return at, nil
+}
+
+// PurgeExpired deletes up to limit users whose scheduled deletion time is before now, together
+// with their sessions. It completes account deletions once the grace period has passed and is
+// registered with the cleanup reaper.
+func (s *UserService) PurgeExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	ids, err := s.repo.DueForDeletion(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted users: %w", err)
+	}
+	for i, id := range ids {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return i, fmt.Errorf("purge deleted users: %w", err)
+		}
+		s.audit(ctx, domain.AuditEvent{Type: domain.AuditAccountDeleted, UserID: id, Details: map[string]string{"reason": "scheduled"}})
+		s.publish(ctx, domain.WebhookUserDeleted, map[string]string{"user_id": id, "reason": "scheduled"})
+	}
+	return len(ids), nil
+}
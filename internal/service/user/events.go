@@ -0,0 +1,26 @@
+package user
+
+import (
+	"context"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// EventPublisher announces identity lifecycle events to external subscribers (webhooks).
+// Publishing is best effort and never fails the operation.
+type EventPublisher interface {
+	Publish(ctx context.Context, t domain.WebhookEventType, data map[string]string)
+}
+
+// WithEventPublisher sets the EventPublisher that receives user.created and user.deleted.
+func WithEventPublisher(p EventPublisher) Option {
+	return func(s *UserService) {
+		s.events = p
+	}
+}
+
+func (s *UserService) publish(ctx context.Context, t domain.WebhookEventType, data map[string]string) {
+	if s.events != nil {
+		s.events.Publish(ctx, t, data)
+	}
+}
@@ -0,0 +1,221 @@
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+)
+
+// ImportRecord is one user in a file exported by another system. PasswordHash is kept as is and
+// must be in one of the formats password.Import accepts, unless PasswordSalt or
+// PasswordAlgorithm is set: then it is a raw salted SHA digest (hex or base64) of the password
+// and PasswordSalt, and PasswordAlgorithm (sha1, sha256 or sha512) defaults to the one matching
+// the digest's length.
+type ImportRecord struct {
+	Username          string `json:"username"`
+	Email             string `json:"email"`
+	DisplayName       string `json:"display_name"`
+	EmailVerified     bool   `json:"email_verified"`
+	PasswordHash      string `json:"password_hash"`
+	PasswordSalt      string `json:"password_salt"`
+	PasswordAlgorithm string `json:"password_algorithm"`
+}
+
+// ImportOptions controls Import.
+type ImportOptions struct {
+	// SaltFirst selects sha(salt + password) for raw salted digests; the default is
+	// sha(password + salt).
+	SaltFirst bool
+	// DryRun validates the records and reports what would be created without writing anything.
+	DryRun bool
+}
+
+// ImportError is a record that could not be imported. Record is 1-based.
+type ImportError struct {
+	Record   int
+	Username string
+	Err      error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("record %d (%s): %v", e.Record, e.Username, e.Err)
+}
+
+// ImportResult lists the usernames created and skipped because they already exist, and the
+// records that failed.
+type ImportResult struct {
+	Created []string
+	Skipped []string
+	Errors  []ImportError
+}
+
+// ReadImportCSV reads records from CSV with a header row naming the columns, using the JSON
+// names of ImportRecord's fields. username and password_hash are required; unknown columns are
+// rejected so that a misspelt salt column is not silently ignored.
+func ReadImportCSV(r io.Reader) ([]ImportRecord, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "username", "email", "display_name", "email_verified", "password_hash", "password_salt", "password_algorithm":
+			cols[name] = i
+		default:
+			return nil, fmt.Errorf("read csv header: unknown column %q", name)
+		}
+	}
+	for _, required := range []string{"username", "password_hash"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("read csv header: missing column %q", required)
+		}
+	}
+
+	var records []ImportRecord
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		rec := ImportRecord{
+			Username:          get("username"),
+			Email:             get("email"),
+			DisplayName:       get("display_name"),
+			PasswordHash:      get("password_hash"),
+			PasswordSalt:      get("password_salt"),
+			PasswordAlgorithm: get("password_algorithm"),
+		}
+		if v := get("email_verified"); v != "" {
+			if rec.EmailVerified, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("read csv line %d: email_verified: %w", line, err)
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+// ReadImportJSON reads records from a JSON array of objects with ImportRecord's fields.
+func ReadImportJSON(r io.Reader) ([]ImportRecord, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var records []ImportRecord
+	if err := dec.Decode(&records); err != nil {
+		return nil, fmt.Errorf("read json: %w", err)
+	}
+	return records, nil
+}
+
+// Import creates a user for each record with its password hash converted by password.Import,
+// so the user signs in with their existing password, which is rehashed with the configured
+// algorithm on the first successful sign-in. Records whose username exists are skipped, and
+// invalid records, including those whose email another user has, are reported in the result
+// without stopping the import. Created users are
+// audited and announced like users created by an administrator, with source "import".
+func (s *UserService) Import(ctx context.Context, records []ImportRecord, opts ImportOptions) (*ImportResult, error) {
+	res := &ImportResult{}
+	seen, seenEmail := map[string]bool{}, map[string]bool{}
+	for i, rec := range records {
+		fail := func(err error) {
+			res.Errors = append(res.Errors, ImportError{Record: i + 1, Username: rec.Username, Err: err})
+		}
+		if rec.Username == "" || rec.Email == "" {
+			fail(errors.New("username and email are required"))
+			continue
+		}
+		if seen[rec.Username] {
+			fail(errors.New("duplicate username in file"))
+			continue
+		}
+		seen[rec.Username] = true
+		if seenEmail[rec.Email] {
+			fail(errors.New("duplicate email in file"))
+			continue
+		}
+		seenEmail[rec.Email] = true
+		hash, err := importedPasswordHash(rec, opts.SaltFirst)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		existing, err := s.repo.ByUsername(ctx, rec.Username)
+		if err != nil {
+			return res, fmt.Errorf("check username: %w", err)
+		}
+		if existing != nil {
+			res.Skipped = append(res.Skipped, rec.Username)
+			continue
+		}
+		if existing, err = s.repo.ByEmail(ctx, rec.Email); err != nil {
+			return res, fmt.Errorf("check email: %w", err)
+		}
+		if existing != nil {
+			fail(fmt.Errorf("%w by user %s", ErrEmailTaken, existing.Username))
+			continue
+		}
+		if opts.DryRun {
+			res.Created = append(res.Created, rec.Username)
+			continue
+		}
+		u := &domain.User{
+			Username:      rec.Username,
+			Email:         rec.Email,
+			DisplayName:   rec.DisplayName,
+			EmailVerified: rec.EmailVerified,
+			PasswordHash:  hash,
+			CreatedAt:     time.Now(),
+		}
+		if err := s.Create(ctx, u); err != nil {
+			fail(err)
+			continue
+		}
+		res.Created = append(res.Created, u.Username)
+		s.audit(ctx, domain.AuditEvent{Type: domain.AuditUserRegistered, UserID: u.ID, Details: map[string]string{"username": u.Username, "source": "import"}})
+		s.publish(ctx, domain.WebhookUserCreated, map[string]string{
+			"user_id":  u.ID,
+			"username": u.Username,
+			"email":    u.Email,
+			"source":   "import",
+		})
+	}
+	return res, nil
+}
+
+func importedPasswordHash(rec ImportRecord, saltFirst bool) (string, error) {
+	if rec.PasswordHash == "" {
+		return "", errors.New("password_hash is required")
+	}
+	if rec.PasswordSalt == "" && rec.PasswordAlgorithm == "" {
+		return password.Import(rec.PasswordHash)
+	}
+	digest, err := password.DecodeDigest(rec.PasswordHash)
+	if err != nil {
+		return "", fmt.Errorf("password_hash: not a hex or base64 digest")
+	}
+	alg := rec.PasswordAlgorithm
+	if alg == "" {
+		alg = map[int]string{20: "sha1", 32: "sha256", 64: "sha512"}[len(digest)]
+	}
+	return password.SaltedSHA(alg, digest, []byte(rec.PasswordSalt), saltFirst)
+}
@@ -0,0 +1,83 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestUserService_Import(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	repo := storage.NewUserRepository(client)
+	svc := NewUserService(repo)
+	ctx := context.Background()
+	_, err := svc.Register(ctx, "carol", "carol@example.com", "password123")
+	require.NoError(t, err)
+	_, err = svc.Register(ctx, "henry", "henry@example.com", "password123")
+	require.NoError(t, err)
+
+	// All passwords are "secret".
+	records, err := ReadImportCSV(strings.NewReader(`username,email,display_name,email_verified,password_hash,password_salt
+alice,alice@example.com,Alice,true,pbkdf2_sha256$1000$NaCl4django$QMoXf5FsTxfErS6+0q9pa7q5ZU5EvYh3yWcLaJ89l7U=,
+bob,bob@example.com,,,744a9101f7182a6ae0d978121ff74e33cac8d2832579c0637c1c37e9bbb6c065,pepper
+carol,carol@example.com,,,{SSHA}D4kSuvPIF4rZzl+dW9Km/1788fBwZXBwZXI=,
+dave,,,,{SSHA}D4kSuvPIF4rZzl+dW9Km/1788fBwZXBwZXI=,
+erin,erin@example.com,,,$1$md5crypt$hash,
+frank,henry@example.com,,,{SSHA}D4kSuvPIF4rZzl+dW9Km/1788fBwZXBwZXI=,
+gina,alice@example.com,,,{SSHA}D4kSuvPIF4rZzl+dW9Km/1788fBwZXBwZXI=,
+`))
+	require.NoError(t, err)
+	require.Len(t, records, 7)
+
+	dry, err := svc.Import(ctx, records, ImportOptions{SaltFirst: true, DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, dry.Created)
+	alice, err := repo.ByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.Nil(t, alice, "a dry run writes nothing")
+
+	res, err := svc.Import(ctx, records, ImportOptions{SaltFirst: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob"}, res.Created)
+	require.Equal(t, []string{"carol"}, res.Skipped)
+	require.Len(t, res.Errors, 4)
+	require.Equal(t, 4, res.Errors[0].Record)
+	require.ErrorIs(t, res.Errors[1].Err, password.ErrUnknownAlgorithm)
+	require.ErrorIs(t, res.Errors[2].Err, ErrEmailTaken, "henry's address belongs to an existing user")
+	require.ErrorContains(t, res.Errors[3].Err, "duplicate email in file")
+	henry, err := repo.ByEmail(ctx, "henry@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "henry", henry.Username, "the address still resolves to a single user")
+
+	for _, name := range []string{"alice", "bob"} {
+		u, err := repo.ByUsername(ctx, name)
+		require.NoError(t, err)
+		ok, needsRehash := password.Verify("secret", u.PasswordHash)
+		require.True(t, ok, name)
+		require.True(t, needsRehash, "imported hashes are replaced on the first sign-in")
+	}
+	alice, err = repo.ByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", alice.DisplayName)
+	require.True(t, alice.EmailVerified)
+}
+
+func TestReadImportJSON(t *testing.T) {
+	records, err := ReadImportJSON(strings.NewReader(`[{"username":"alice","email":"alice@example.com","password_hash":"x"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []ImportRecord{{Username: "alice", Email: "alice@example.com", PasswordHash: "x"}}, records)
+
+	_, err = ReadImportJSON(strings.NewReader(`[{"username":"alice","salt":"x"}]`))
+	require.Error(t, err)
+	_, err = ReadImportCSV(strings.NewReader("username,salt\nalice,x\n"))
+	require.ErrorContains(t, err, `unknown column "salt"`)
+}
@@ -0,0 +1,167 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// ErrUserNotFound is returned when the user does not exist.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrEmailTaken is returned when the email is already used by another account.
+var ErrEmailTaken = errors.New("email already in use")
+
+// ErrWrongPassword is returned when the supplied current password does not match.
+var ErrWrongPassword = errors.New("current password is incorrect")
+
+// ErrInvalidVerificationToken is returned when an email verification token is unknown or expired.
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+const emailVerifyTokenBytes = 32
+const emailVerifyTTL = 24 * time.Hour
+
+// Notifier delivers out-of-band messages to users.
+// Interface is defined in the consuming (service) layer per project architecture.
+type Notifier interface {
+	// SendEmailVerification delivers the verification token for the new address.
+	SendEmailVerification(ctx context.Context, u *domain.User, email, token string) error
+}
+
+// Get returns the user with the given ID, or ErrUserNotFound.
+func (s *UserService) Get(ctx context.Context, userID string) (*domain.User, error) {
+	u, err := s.repo.ByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// UpdateProfile updates the display name and, when email differs from the current address,
+// starts re-verification: the new address is kept as pending and only replaces Email once
+// VerifyEmail is called with the token delivered through the Notifier. Submitting the current
+// address again cancels a pending change.
+// Returns the updated user and whether a verification was started.
+func (s *UserService) UpdateProfile(ctx context.Context, userID, displayName, email string) (*domain.User, bool, error) {
+	u, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	u.DisplayName = strings.TrimSpace(displayName)
+	email = strings.TrimSpace(email)
+
+	var token string
+	if email != "" && !strings.EqualFold(email, u.Email) {
+		other, err := s.repo.ByEmail(ctx, email)
+		if err != nil {
+			return nil, false, fmt.Errorf("check email: %w", err)
+		}
+		if other != nil && other.ID != u.ID {
+			return nil, false, ErrEmailTaken
+		}
+		token, err = generateVerifyToken()
+		if err != nil {
+			return nil, false, fmt.Errorf("update profile: %w", err)
+		}
+		u.PendingEmail = email
+		u.EmailVerifyTokenHash = hashVerifyToken(token)
+		u.EmailVerifyExpiresAt = time.Now().Add(emailVerifyTTL)
+	} else if email != "" && u.PendingEmail != "" {
+		u.PendingEmail = ""
+		u.EmailVerifyTokenHash = ""
+		u.EmailVerifyExpiresAt = time.Time{}
+	}
+
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, false, fmt.Errorf("update profile: %w", err)
+	}
+	if token == "" {
+		return u, false, nil
+	}
+	if s.notifier != nil {
+		if err := s.notifier.SendEmailVerification(ctx, u, u.PendingEmail, token); err != nil {
+			return nil, false, fmt.Errorf("send email verification: %w", err)
+		}
+	}
+	return u, true, nil
+}
+
+// VerifyEmail completes a pending email change for the holder of token. It returns
+// ErrEmailTaken, leaving the change pending, if another account has taken the address since the
+// change was requested.
+func (s *UserService) VerifyEmail(ctx context.Context, token string) (*domain.User, error) {
+	if token == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+	u, err := s.repo.ByEmailVerifyTokenHash(ctx, hashVerifyToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("verify email: %w", err)
+	}
+	if u == nil || u.PendingEmail == "" || time.Now().After(u.EmailVerifyExpiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+	other, err := s.repo.ByEmail(ctx, u.PendingEmail)
+	if err != nil {
+		return nil, fmt.Errorf("check email: %w", err)
+	}
+	if other != nil && other.ID != u.ID {
+		return nil, ErrEmailTaken
+	}
+	previous := u.Email
+	u.Email = u.PendingEmail
+	u.EmailVerified = true
+	u.PendingEmail = ""
+	u.EmailVerifyTokenHash = ""
+	u.EmailVerifyExpiresAt = time.Time{}
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, fmt.Errorf("verify email: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditEmailChanged, UserID: u.ID, Details: map[string]string{"from": previous, "to": u.Email}})
+	return u, nil
+}
+
+// ChangePassword replaces the user's password after checking the current one.
+func (s *UserService) ChangePassword(ctx context.Context, userID, current, newPwd string) error {
+	u, err := s.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if ok, _ := s.hasher.Verify(current, u.PasswordHash); !ok {
+		return ErrWrongPassword
+	}
+	if len(newPwd) < minPasswordLen {
+		return ErrWeakPassword
+	}
+	hash, err := s.hasher.Hash(newPwd)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	if err := s.repo.UpdatePasswordHash(ctx, u.ID, hash); err != nil {
+		return fmt.Errorf("change password: %w", err)
+	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditPasswordChanged, UserID: u.ID})
+	return nil
+}
+
+func generateVerifyToken() (string, error) {
+	b := make([]byte, emailVerifyTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashVerifyToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
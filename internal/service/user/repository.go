@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/qinzj/superpowers-demo/internal/domain"
 )
@@ -12,5 +13,28 @@ type UserRepository interface {
 	Create(ctx context.Context, u *domain.User) error
 	ByUsername(ctx context.Context, username string) (*domain.User, error)
 	ByEmail(ctx context.Context, email string) (*domain.User, error)
+	// ByID returns the user with the given ID, or nil if not found.
+	ByID(ctx context.Context, userID string) (*domain.User, error)
+	// ByEmailVerifyTokenHash returns the user with the given outstanding verification token hash, or nil.
+	ByEmailVerifyTokenHash(ctx context.Context, tokenHash string) (*domain.User, error)
+	// ByExternalID returns the user with the given provisioning client ID, or nil if not found.
+	ByExternalID(ctx context.Context, externalID string) (*domain.User, error)
+	// Update persists the mutable profile fields (username, email, display name, external ID,
+	// verification state).
+	Update(ctx context.Context, u *domain.User) error
 	Delete(ctx context.Context, userID string) error
+	// UpdatePasswordHash replaces the stored password hash for the user.
+	UpdatePasswordHash(ctx context.Context, userID, hash string) error
+	// Search returns one page of users matching query (substring of username, email or display
+	// name; empty matches all) and the total number of matches.
+	Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int, error)
+	// SetStatus changes the user's lifecycle status.
+	SetStatus(ctx context.Context, userID string, status domain.UserStatus) error
+	// ScheduleDeletion marks the user for deletion at the given time.
+	ScheduleDeletion(ctx context.Context, userID string, at time.Time) error
+	// CancelDeletion clears a scheduled deletion. Returns false if none was scheduled.
+	CancelDeletion(ctx context.Context, userID string) (bool, error)
+	// DueForDeletion returns the IDs of up to limit users whose scheduled deletion time is
+	// before now.
+	DueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error)
 }
@@ -20,12 +20,51 @@ const minPasswordLen = 8
 
 // UserService provides user business operations.
 type UserService struct {
-	repo UserRepository
+	repo          UserRepository
+	hasher        *password.Hasher
+	notifier      Notifier
+	deletionGrace time.Duration
+	auditor       Auditor
+	events        EventPublisher
+}
+
+// Option configures optional UserService dependencies.
+type Option func(*UserService)
+
+// WithPasswordHasher sets the hasher used for new password hashes.
+// Defaults to password.Default().
+func WithPasswordHasher(h *password.Hasher) Option {
+	return func(s *UserService) {
+		if h != nil {
+			s.hasher = h
+		}
+	}
+}
+
+// WithNotifier sets the Notifier used to deliver email verification tokens.
+func WithNotifier(n Notifier) Option {
+	return func(s *UserService) {
+		s.notifier = n
+	}
+}
+
+// WithDeletionGracePeriod sets how long a requested account deletion can be cancelled.
+// Non-positive values fall back to DefaultDeletionGracePeriod.
+func WithDeletionGracePeriod(d time.Duration) Option {
+	return func(s *UserService) {
+		if d > 0 {
+			s.deletionGrace = d
+		}
+	}
 }
 
 // NewUserService creates a UserService with the given repository.
-func NewUserService(repo UserRepository) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(repo UserRepository, opts ...Option) *UserService {
+	s := &UserService{repo: repo, hasher: password.Default(), deletionGrace: DefaultDeletionGracePeriod}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Create persists a new user. The user's ID is populated after creation.
@@ -33,16 +72,23 @@ func (s *UserService) Create(ctx context.Context, u *domain.User) error {
 	return s.repo.Create(ctx, u)
 }
 
-// Delete removes the user and all their sessions. Idempotent: returns nil if user already deleted.
+// Delete removes the user and all their sessions immediately. Idempotent: returns nil if user
+// already deleted. Self-service deletion goes through RequestDeletion instead.
 func (s *UserService) Delete(ctx context.Context, userID string) error {
 	if err := s.repo.Delete(ctx, userID); err != nil {
 		return fmt.Errorf("delete user: %w", err)
 	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditAccountDeleted, UserID: userID})
+	s.publish(ctx, domain.WebhookUserDeleted, map[string]string{"user_id": userID, "reason": "immediate"})
 	return nil
 }
 
 // Register creates a new user after validating username uniqueness and password strength.
 func (s *UserService) Register(ctx context.Context, username, email, pwd string) (*domain.User, error) {
+	return s.createWithPassword(ctx, username, email, pwd, "registration", false)
+}
+
+func (s *UserService) createWithPassword(ctx context.Context, username, email, pwd, source string, verified bool) (*domain.User, error) {
 	existing, err := s.repo.ByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("check username: %w", err)
@@ -50,21 +96,35 @@ func (s *UserService) Register(ctx context.Context, username, email, pwd string)
 	if existing != nil {
 		return nil, ErrUsernameTaken
 	}
+	if existing, err = s.repo.ByEmail(ctx, email); err != nil {
+		return nil, fmt.Errorf("check email: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrEmailTaken
+	}
 	if len(pwd) < minPasswordLen {
 		return nil, ErrWeakPassword
 	}
-	hash, err := password.Hash(pwd)
+	hash, err := s.hasher.Hash(pwd)
 	if err != nil {
 		return nil, fmt.Errorf("hash password: %w", err)
 	}
 	u := &domain.User{
-		Username:     username,
-		Email:        email,
-		PasswordHash: hash,
-		CreatedAt:    time.Now(),
+		Username:      username,
+		Email:         email,
+		EmailVerified: verified,
+		PasswordHash:  hash,
+		CreatedAt:     time.Now(),
 	}
 	if err := s.Create(ctx, u); err != nil {
 		return nil, fmt.Errorf("create user: %w", err)
 	}
+	s.audit(ctx, domain.AuditEvent{Type: domain.AuditUserRegistered, UserID: u.ID, Details: map[string]string{"username": username, "source": source}})
+	s.publish(ctx, domain.WebhookUserCreated, map[string]string{
+		"user_id":  u.ID,
+		"username": u.Username,
+		"email":    u.Email,
+		"source":   source,
+	})
 	return u, nil
 }
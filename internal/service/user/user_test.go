@@ -63,6 +63,12 @@ func TestUserService_Register(t *testing.T) {
 	_, err = svc.Register(ctx, "bob", "other@example.com", "otherpass1")
 	require.ErrorIs(t, err, ErrUsernameTaken)
 
+	// Duplicate email, from registration or an administrator
+	_, err = svc.Register(ctx, "robert", "bob@example.com", "otherpass1")
+	require.ErrorIs(t, err, ErrEmailTaken)
+	_, err = svc.CreateByAdmin(ctx, "robert", "bob@example.com", "otherpass1")
+	require.ErrorIs(t, err, ErrEmailTaken)
+
 	// Weak password
 	_, err = svc.Register(ctx, "carol", "carol@example.com", "short")
 	require.ErrorIs(t, err, ErrWeakPassword)
@@ -71,5 +77,209 @@ func TestUserService_Register(t *testing.T) {
 	found, err := repo.ByUsername(ctx, "bob")
 	require.NoError(t, err)
 	require.NotNil(t, found)
-	require.True(t, password.Verify("password123", found.PasswordHash))
+	ok, _ := password.Verify("password123", found.PasswordHash)
+	require.True(t, ok)
+}
+
+// recordingNotifier captures verification tokens instead of delivering them.
+type recordingNotifier struct {
+	email string
+	token string
+}
+
+func (n *recordingNotifier) SendEmailVerification(_ context.Context, _ *domain.User, email, token string) error {
+	n.email = email
+	n.token = token
+	return nil
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	repo := storage.NewUserRepository(client)
+	notifier := &recordingNotifier{}
+	svc := NewUserService(repo, WithNotifier(notifier))
+	ctx := context.Background()
+
+	u, err := svc.Register(ctx, "dave", "dave@example.com", "password123")
+	require.NoError(t, err)
+	_, err = svc.Register(ctx, "erin", "erin@example.com", "password123")
+	require.NoError(t, err)
+
+	// Display name only: no verification
+	got, verifying, err := svc.UpdateProfile(ctx, u.ID, "Dave D", "dave@example.com")
+	require.NoError(t, err)
+	require.False(t, verifying)
+	require.Equal(t, "Dave D", got.DisplayName)
+
+	// Email used by someone else
+	_, _, err = svc.UpdateProfile(ctx, u.ID, "Dave D", "erin@example.com")
+	require.ErrorIs(t, err, ErrEmailTaken)
+
+	// Email change stays pending until verified
+	got, verifying, err = svc.UpdateProfile(ctx, u.ID, "Dave D", "dave@new.example.com")
+	require.NoError(t, err)
+	require.True(t, verifying)
+	require.Equal(t, "dave@example.com", got.Email)
+	require.Equal(t, "dave@new.example.com", got.PendingEmail)
+	require.Equal(t, "dave@new.example.com", notifier.email)
+	require.NotEmpty(t, notifier.token)
+
+	_, err = svc.VerifyEmail(ctx, "bogus")
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+
+	verified, err := svc.VerifyEmail(ctx, notifier.token)
+	require.NoError(t, err)
+	require.Equal(t, "dave@new.example.com", verified.Email)
+	require.True(t, verified.EmailVerified)
+	require.Empty(t, verified.PendingEmail)
+
+	// Token is single-use
+	_, err = svc.VerifyEmail(ctx, notifier.token)
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+
+	// Changing back to the current address cancels the pending change and its token.
+	_, _, err = svc.UpdateProfile(ctx, u.ID, "Dave D", "dave@other.example.com")
+	require.NoError(t, err)
+	got, verifying, err = svc.UpdateProfile(ctx, u.ID, "Dave D", "dave@new.example.com")
+	require.NoError(t, err)
+	require.False(t, verifying)
+	require.Empty(t, got.PendingEmail)
+	_, err = svc.VerifyEmail(ctx, notifier.token)
+	require.ErrorIs(t, err, ErrInvalidVerificationToken)
+
+	// An address taken by another account after the change was requested is not applied.
+	_, _, err = svc.UpdateProfile(ctx, u.ID, "Dave D", "frank@example.com")
+	require.NoError(t, err)
+	_, err = svc.Register(ctx, "frank", "frank@example.com", "password123")
+	require.NoError(t, err)
+	_, err = svc.VerifyEmail(ctx, notifier.token)
+	require.ErrorIs(t, err, ErrEmailTaken)
+	got, err = svc.Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, "dave@new.example.com", got.Email)
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	repo := storage.NewUserRepository(client)
+	svc := NewUserService(repo)
+	ctx := context.Background()
+
+	u, err := svc.Register(ctx, "frank", "frank@example.com", "password123")
+	require.NoError(t, err)
+
+	err = svc.ChangePassword(ctx, u.ID, "wrong-password", "newpassword1")
+	require.ErrorIs(t, err, ErrWrongPassword)
+
+	err = svc.ChangePassword(ctx, u.ID, "password123", "short")
+	require.ErrorIs(t, err, ErrWeakPassword)
+
+	require.NoError(t, svc.ChangePassword(ctx, u.ID, "password123", "newpassword1"))
+
+	found, err := repo.ByID(ctx, u.ID)
+	require.NoError(t, err)
+	ok, _ := password.Verify("newpassword1", found.PasswordHash)
+	require.True(t, ok)
+	ok, _ = password.Verify("password123", found.PasswordHash)
+	require.False(t, ok)
+}
+
+func TestUserService_SearchAndAdminActions(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	repo := storage.NewUserRepository(client)
+	svc := NewUserService(repo)
+	ctx := context.Background()
+
+	var ids []string
+	for _, name := range []string{"alice", "bob", "carol", "alina"} {
+		u, err := svc.Register(ctx, name, name+"@example.com", "password123")
+		require.NoError(t, err)
+		ids = append(ids, u.ID)
+	}
+
+	t.Run("search_filters_and_paginates", func(t *testing.T) {
+		page, err := svc.Search(ctx, "AL", 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, 2, page.Total)
+		require.Len(t, page.Users, 1)
+		require.Equal(t, "alice", page.Users[0].Username)
+		require.False(t, page.HasPrev())
+		require.True(t, page.HasNext())
+
+		page, err = svc.Search(ctx, "al", 2, 1)
+		require.NoError(t, err)
+		require.Equal(t, "alina", page.Users[0].Username)
+		require.True(t, page.HasPrev())
+		require.False(t, page.HasNext())
+
+		all, err := svc.Search(ctx, "", 0, 0)
+		require.NoError(t, err)
+		require.Equal(t, 4, all.Total)
+		require.Equal(t, 1, all.Page)
+		require.Equal(t, DefaultPageSize, all.PageSize)
+	})
+
+	t.Run("reset_password", func(t *testing.T) {
+		require.ErrorIs(t, svc.ResetPassword(ctx, ids[2], "short"), ErrWeakPassword)
+		require.NoError(t, svc.ResetPassword(ctx, ids[2], "brand-new-pass"))
+		u, err := svc.Get(ctx, ids[2])
+		require.NoError(t, err)
+		ok, _ := password.Verify("brand-new-pass", u.PasswordHash)
+		require.True(t, ok)
+		require.ErrorIs(t, svc.ResetPassword(ctx, "nope", "brand-new-pass"), ErrUserNotFound)
+	})
+}
+
+func TestUserService_RequestDeletion(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	defer client.Close()
+
+	repo := storage.NewUserRepository(client)
+	svc := NewUserService(repo, WithDeletionGracePeriod(48*time.Hour))
+	ctx := context.Background()
+
+	leaving, err := svc.Register(ctx, "leaving", "leaving@example.com", "password123")
+	require.NoError(t, err)
+	staying, err := svc.Register(ctx, "staying", "staying@example.com", "password123")
+	require.NoError(t, err)
+
+	at, err := svc.RequestDeletion(ctx, leaving.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(48*time.Hour), at, time.Minute)
+	u, err := svc.Get(ctx, leaving.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, at, u.DeletionScheduledAt, time.Second)
+	_, err = svc.RequestDeletion(ctx, "999")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	n, err := svc.PurgeExpired(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Zero(t, n, "nothing is deleted during the grace period")
+
+	n, err = svc.PurgeExpired(ctx, at.Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	_, err = svc.Get(ctx, leaving.ID)
+	require.ErrorIs(t, err, ErrUserNotFound)
+	_, err = svc.Get(ctx, staying.ID)
+	require.NoError(t, err)
+
+	// A cancelled request is not purged.
+	_, err = svc.RequestDeletion(ctx, staying.ID)
+	require.NoError(t, err)
+	cancelled, err := repo.CancelDeletion(ctx, staying.ID)
+	require.NoError(t, err)
+	require.True(t, cancelled)
+	cancelled, err = repo.CancelDeletion(ctx, staying.ID)
+	require.NoError(t, err)
+	require.False(t, cancelled)
+	n, err = svc.PurgeExpired(ctx, time.Now().Add(365*24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Zero(t, n)
 }
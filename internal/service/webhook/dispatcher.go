@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// Delivery request headers.
+const (
+	HeaderEventID    = "X-Webhook-Id"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderDeliveryID = "X-Webhook-Delivery"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+// maxErrorLen caps the response excerpt kept in the delivery log.
+const maxErrorLen = 512
+
+// Config holds delivery settings matching the "webhooks" section of settings.yaml.
+type Config struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // time between outbox sweeps
+	BatchSize      int           `mapstructure:"batch_size"`      // max deliveries attempted per sweep
+	Timeout        time.Duration `mapstructure:"timeout"`         // per-request timeout
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // attempts before a delivery is marked failed
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"` // delay after the first failed attempt
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`     // cap on the doubling delay
+}
+
+const (
+	defaultPollInterval   = 5 * time.Second
+	defaultBatchSize      = 50
+	defaultTimeout        = 10 * time.Second
+	defaultMaxAttempts    = 8
+	defaultInitialBackoff = 30 * time.Second
+	defaultMaxBackoff     = 6 * time.Hour
+)
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = defaultInitialBackoff
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		c.MaxBackoff = max(defaultMaxBackoff, c.InitialBackoff)
+	}
+	return c
+}
+
+// Backoff returns the delay before the attempt following the given number of failed attempts:
+// InitialBackoff doubled per extra failure, capped at MaxBackoff.
+func (c Config) Backoff(failures int) time.Duration {
+	d := c.InitialBackoff
+	for i := 1; i < failures && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, c.MaxBackoff)
+}
+
+// Sign returns the X-Webhook-Signature value for body sent at timestamp (Unix seconds):
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run delivers due events immediately and then every PollInterval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.DeliverDue(ctx, time.Now()); err != nil && s.logger != nil && ctx.Err() == nil {
+			s.logger.Warn("webhook delivery sweep failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue attempts up to BatchSize deliveries that are due at now and reports how many it
+// attempted. Delivery is at least once: receivers should deduplicate on X-Webhook-Id.
+func (s *Service) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.DueDeliveries(ctx, now, s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("load due webhook deliveries: %w", err)
+	}
+	attempted := 0
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return attempted, ctx.Err()
+		}
+		// The lease keeps other workers off the delivery while the request is in flight and
+		// makes it due again should this process die before recording the outcome.
+		ok, err := s.repo.ClaimDelivery(ctx, d, now, now.Add(2*s.cfg.Timeout))
+		if err != nil {
+			return attempted, err
+		}
+		if !ok {
+			continue
+		}
+		sub, err := s.repo.SubscriptionByID(ctx, d.SubscriptionID)
+		if err != nil {
+			return attempted, fmt.Errorf("load webhook subscription: %w", err)
+		}
+		if sub == nil {
+			continue
+		}
+		attempted++
+		s.settle(d, now, s.send(ctx, sub, d))
+		if err := s.repo.UpdateDelivery(ctx, d); err != nil {
+			return attempted, err
+		}
+	}
+	return attempted, nil
+}
+
+// attemptResult is the outcome of one HTTP attempt.
+type attemptResult struct {
+	status int
+	err    string
+}
+
+// settle records an attempt's outcome on d: success, another try after the backoff delay, or
+// failure once MaxAttempts is used up.
+func (s *Service) settle(d *domain.WebhookDelivery, now time.Time, res attemptResult) {
+	d.ResponseStatus = res.status
+	d.LastError = res.err
+	switch {
+	case res.err == "":
+		d.Status = domain.WebhookDeliverySucceeded
+	case d.Attempts >= s.cfg.MaxAttempts:
+		d.Status = domain.WebhookDeliveryFailed
+	default:
+		d.Status = domain.WebhookDeliveryPending
+		d.NextAttemptAt = now.Add(s.cfg.Backoff(d.Attempts))
+	}
+}
+
+func (s *Service) send(ctx context.Context, sub *domain.WebhookSubscription, d *domain.WebhookDelivery) attemptResult {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		return attemptResult{err: err.Error()}
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, d.EventID)
+	req.Header.Set(HeaderEventType, string(d.EventType))
+	req.Header.Set(HeaderDeliveryID, d.ID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, ts, body))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return attemptResult{err: err.Error()}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorLen))
+		return attemptResult{status: resp.StatusCode}
+	}
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLen))
+	msg := resp.Status
+	if len(excerpt) > 0 {
+		msg += ": " + strings.TrimSpace(string(excerpt))
+	}
+	return attemptResult{status: resp.StatusCode, err: msg}
+}
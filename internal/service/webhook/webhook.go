@@ -0,0 +1,368 @@
+// Package webhook delivers identity lifecycle events to subscribed HTTP endpoints through a
+// persistent outbox with retries.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/pkg/log"
+)
+
+var (
+	// ErrSubscriptionNotFound is returned when the webhook subscription does not exist.
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrDeliveryNotFound is returned when the webhook delivery does not exist.
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrInvalidSubscription is returned for a subscription with a bad URL or event list.
+	ErrInvalidSubscription = errors.New("invalid webhook subscription")
+)
+
+const (
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+	eventIDBytes = 16
+
+	// DefaultPageSize is the page size used by ListDeliveries when pageSize is not positive.
+	DefaultPageSize = 50
+	// MaxPageSize caps the page size accepted by ListDeliveries.
+	MaxPageSize = 200
+)
+
+// Repository defines persistence operations for webhook subscriptions and deliveries.
+// Interface is defined in the consuming (service) layer per project architecture.
+type Repository interface {
+	// CreateSubscription persists s and populates s.ID.
+	CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	// SubscriptionByID returns the subscription, or nil if not found.
+	SubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	// UpdateSubscription persists every mutable field of s.
+	UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	// DeleteSubscription removes the subscription and its deliveries. Returns false if it did
+	// not exist.
+	DeleteSubscription(ctx context.Context, id string) (bool, error)
+	// CreateDelivery persists d and populates d.ID.
+	CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error
+	// DeliveryByID returns the delivery, or nil if not found.
+	DeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	// ListDeliveries returns one page of the subscription's deliveries, newest first, and the
+	// total number.
+	ListDeliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]*domain.WebhookDelivery, int, error)
+	// DueDeliveries returns up to limit pending deliveries of enabled subscriptions whose next
+	// attempt is due at now, earliest first.
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	// ClaimDelivery starts an attempt on d unless another worker already has. Returns false
+	// when the claim was lost.
+	ClaimDelivery(ctx context.Context, d *domain.WebhookDelivery, now, leaseUntil time.Time) (bool, error)
+	// UpdateDelivery records the outcome of an attempt.
+	UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery) error
+}
+
+// Service manages webhook subscriptions, queues events and delivers them.
+type Service struct {
+	repo   Repository
+	cfg    Config
+	client *http.Client
+	logger log.Logger
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithConfig sets delivery settings. Zero values fall back to defaults.
+func WithConfig(cfg Config) Option {
+	return func(s *Service) {
+		s.cfg = cfg.withDefaults()
+	}
+}
+
+// WithHTTPClient sets the client used for deliveries. Its timeout is replaced by Config.Timeout.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Service) {
+		if c != nil {
+			s.client = c
+		}
+	}
+}
+
+// WithLogger sets the logger used for events that could not be queued and failed sweeps.
+func WithLogger(l log.Logger) Option {
+	return func(s *Service) {
+		s.logger = l
+	}
+}
+
+// NewService creates a Service with the given repository.
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{repo: repo, cfg: Config{}.withDefaults(), client: &http.Client{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	client := *s.client
+	client.Timeout = s.cfg.Timeout
+	s.client = &client
+	return s
+}
+
+// SubscriptionAttrs holds the administrator-editable fields of a subscription.
+type SubscriptionAttrs struct {
+	URL         string
+	Events      []string
+	Description string
+	Enabled     bool
+}
+
+// CreateSubscription validates attrs and stores a new subscription with a freshly generated
+// signing secret. The returned subscription carries the secret.
+func (s *Service) CreateSubscription(ctx context.Context, attrs SubscriptionAttrs) (*domain.WebhookSubscription, error) {
+	if err := normalizeAttrs(&attrs); err != nil {
+		return nil, err
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("create webhook subscription: %w", err)
+	}
+	sub := &domain.WebhookSubscription{
+		URL:         attrs.URL,
+		Events:      attrs.Events,
+		Secret:      secret,
+		Description: attrs.Description,
+		Enabled:     attrs.Enabled,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("create webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// GetSubscription returns the subscription or ErrSubscriptionNotFound.
+func (s *Service) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.repo.SubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get webhook subscription: %w", err)
+	}
+	if sub == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns every subscription.
+func (s *Service) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// UpdateSubscription replaces the subscription's URL, events, description and enabled flag.
+// Pending deliveries of a disabled subscription wait until it is enabled again.
+func (s *Service) UpdateSubscription(ctx context.Context, id string, attrs SubscriptionAttrs) (*domain.WebhookSubscription, error) {
+	if err := normalizeAttrs(&attrs); err != nil {
+		return nil, err
+	}
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.URL, sub.Events, sub.Description, sub.Enabled = attrs.URL, attrs.Events, attrs.Description, attrs.Enabled
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("update webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+// RotateSecret replaces the subscription's signing secret and returns the subscription with
+// the new secret. Deliveries attempted afterwards are signed with it.
+func (s *Service) RotateSecret(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Secret, err = generateSecret(); err != nil {
+		return nil, fmt.Errorf("rotate webhook secret: %w", err)
+	}
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("rotate webhook secret: %w", err)
+	}
+	return sub, nil
+}
+
+// DeleteSubscription removes the subscription together with its delivery log.
+func (s *Service) DeleteSubscription(ctx context.Context, id string) error {
+	ok, err := s.repo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription: %w", err)
+	}
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// DeliveryPage is one page of a subscription's delivery log, newest first.
+type DeliveryPage struct {
+	Deliveries []*domain.WebhookDelivery
+	Page       int
+	PageSize   int
+	Total      int
+}
+
+// ListDeliveries returns the 1-based page of the subscription's delivery log.
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID string, page, pageSize int) (*DeliveryPage, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	deliveries, total, err := s.repo.ListDeliveries(ctx, subscriptionID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return &DeliveryPage{Deliveries: deliveries, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// Redeliver queues a new delivery of the same event and payload as deliveryID, which must
+// belong to subscriptionID. The original entry stays in the log unchanged.
+func (s *Service) Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	orig, err := s.repo.DeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("redeliver webhook: %w", err)
+	}
+	if orig == nil || orig.SubscriptionID != subscriptionID {
+		return nil, ErrDeliveryNotFound
+	}
+	now := time.Now()
+	d := &domain.WebhookDelivery{
+		SubscriptionID: orig.SubscriptionID,
+		EventID:        orig.EventID,
+		EventType:      orig.EventType,
+		Payload:        orig.Payload,
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+	if err := s.repo.CreateDelivery(ctx, d); err != nil {
+		return nil, fmt.Errorf("redeliver webhook: %w", err)
+	}
+	return d, nil
+}
+
+// eventPayload is the JSON body of every delivery.
+type eventPayload struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	CreatedAt time.Time         `json:"created_at"`
+	Data      map[string]string `json:"data"`
+}
+
+// Publish queues event t with data for every enabled subscription that selects it. Queueing is
+// best effort: a failure is logged rather than returned so that webhooks never block the
+// operation that raised the event.
+func (s *Service) Publish(ctx context.Context, t domain.WebhookEventType, data map[string]string) {
+	if err := s.publish(ctx, t, data); err != nil && s.logger != nil {
+		s.logger.Error("queue webhook event", zap.String("type", string(t)), zap.Error(err))
+	}
+}
+
+func (s *Service) publish(ctx context.Context, t domain.WebhookEventType, data map[string]string) error {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	var targets []*domain.WebhookSubscription
+	for _, sub := range subs {
+		if sub.Wants(t) {
+			targets = append(targets, sub)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	eventID, err := generateEventID()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	body, err := json.Marshal(eventPayload{ID: eventID, Type: string(t), CreatedAt: now.UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+	for _, sub := range targets {
+		d := &domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      t,
+			Payload:        string(body),
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		if err := s.repo.CreateDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeAttrs(a *SubscriptionAttrs) error {
+	a.URL = strings.TrimSpace(a.URL)
+	u, err := url.Parse(a.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+	events := make([]string, 0, len(a.Events))
+	for _, e := range a.Events {
+		e = strings.TrimSpace(e)
+		if e != domain.WebhookAllEvents && !domain.WebhookEventType(e).Valid() {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidSubscription, e)
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidSubscription)
+	}
+	a.Events = events
+	a.Description = strings.TrimSpace(a.Description)
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+func generateEventID() (string, error) {
+	b := make([]byte, eventIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// receiver records webhook requests and answers with a configurable status.
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte("nope"))
+}
+
+func newTestService(t *testing.T, cfg Config) *Service {
+	t.Helper()
+	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	return NewService(storage.NewWebhookRepository(client), WithConfig(cfg))
+}
+
+func TestConfig_Backoff(t *testing.T) {
+	cfg := Config{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}.withDefaults()
+	require.Equal(t, time.Second, cfg.Backoff(1))
+	require.Equal(t, 2*time.Second, cfg.Backoff(2))
+	require.Equal(t, 4*time.Second, cfg.Backoff(3))
+	require.Equal(t, 5*time.Second, cfg.Backoff(4))
+	require.Equal(t, 5*time.Second, cfg.Backoff(40))
+}
+
+func TestService_Subscriptions(t *testing.T) {
+	svc := newTestService(t, Config{})
+	ctx := context.Background()
+
+	_, err := svc.CreateSubscription(ctx, SubscriptionAttrs{URL: "ftp://example.com", Events: []string{"user.created"}})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+	_, err = svc.CreateSubscription(ctx, SubscriptionAttrs{URL: "https://example.com/hook", Events: []string{"user.exploded"}})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+	_, err = svc.CreateSubscription(ctx, SubscriptionAttrs{URL: "https://example.com/hook"})
+	require.ErrorIs(t, err, ErrInvalidSubscription)
+
+	sub, err := svc.CreateSubscription(ctx, SubscriptionAttrs{URL: " https://example.com/hook ", Events: []string{"user.created", "user.created"}, Enabled: true})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/hook", sub.URL)
+	require.Equal(t, []string{"user.created"}, sub.Events)
+	require.Contains(t, sub.Secret, secretPrefix)
+
+	rotated, err := svc.RotateSecret(ctx, sub.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, sub.Secret, rotated.Secret)
+
+	updated, err := svc.UpdateSubscription(ctx, sub.ID, SubscriptionAttrs{URL: "https://example.com/v2", Events: []string{"*"}, Enabled: false})
+	require.NoError(t, err)
+	require.False(t, updated.Enabled)
+	got, err := svc.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/v2", got.URL)
+	require.Equal(t, rotated.Secret, got.Secret)
+
+	require.NoError(t, svc.DeleteSubscription(ctx, sub.ID))
+	require.ErrorIs(t, svc.DeleteSubscription(ctx, sub.ID), ErrSubscriptionNotFound)
+	_, err = svc.GetSubscription(ctx, "999")
+	require.ErrorIs(t, err, ErrSubscriptionNotFound)
+}
+
+func TestService_DeliverSigned(t *testing.T) {
+	recv := &receiver{status: http.StatusNoContent}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	svc := newTestService(t, Config{})
+	ctx := context.Background()
+
+	all, err := svc.CreateSubscription(ctx, SubscriptionAttrs{URL: srv.URL, Events: []string{"*"}, Enabled: true})
+	require.NoError(t, err)
+	_, err = svc.CreateSubscription(ctx, SubscriptionAttrs{URL: srv.URL, Events: []string{"user.deleted"}, Enabled: true})
+	require.NoError(t, err)
+	_, err = svc.CreateSubscription(ctx, SubscriptionAttrs{URL: srv.URL, Events: []string{"*"}, Enabled: false})
+	require.NoError(t, err)
+
+	svc.Publish(ctx, domain.WebhookUserCreated, map[string]string{"user_id": "7"})
+	n, err := svc.DeliverDue(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "only enabled subscriptions selecting the event receive it")
+
+	req, body := recv.requests[0], recv.bodies[0]
+	require.Equal(t, "user.created", req.Header.Get(HeaderEventType))
+	require.Equal(t, Sign(all.Secret, req.Header.Get(HeaderTimestamp), body), req.Header.Get(HeaderSignature))
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Equal(t, req.Header.Get(HeaderEventID), payload["id"])
+	require.Equal(t, "7", payload["data"].(map[string]interface{})["user_id"])
+
+	page, err := svc.ListDeliveries(ctx, all.ID, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, page.Total)
+	require.Equal(t, domain.WebhookDeliverySucceeded, page.Deliveries[0].Status)
+	require.Equal(t, http.StatusNoContent, page.Deliveries[0].ResponseStatus)
+	require.Equal(t, 1, page.Deliveries[0].Attempts)
+}
+
+func TestService_RetryAndRedeliver(t *testing.T) {
+	recv := &receiver{status: http.StatusInternalServerError}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	svc := newTestService(t, Config{MaxAttempts: 2, InitialBackoff: time.Minute})
+	ctx := context.Background()
+
+	sub, err := svc.CreateSubscription(ctx, SubscriptionAttrs{URL: srv.URL, Events: []string{"user.login"}, Enabled: true})
+	require.NoError(t, err)
+	svc.Publish(ctx, domain.WebhookUserLogin, map[string]string{"user_id": "1"})
+
+	now := time.Now()
+	n, err := svc.DeliverDue(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	page, err := svc.ListDeliveries(ctx, sub.ID, 1, 0)
+	require.NoError(t, err)
+	d := page.Deliveries[0]
+	require.Equal(t, domain.WebhookDeliveryPending, d.Status)
+	require.Equal(t, http.StatusInternalServerError, d.ResponseStatus)
+	require.Contains(t, d.LastError, "nope")
+	require.WithinDuration(t, now.Add(time.Minute), d.NextAttemptAt, time.Second)
+
+	n, err = svc.DeliverDue(ctx, now.Add(30*time.Second))
+	require.NoError(t, err)
+	require.Zero(t, n, "not due during the backoff")
+	n, err = svc.DeliverDue(ctx, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	page, err = svc.ListDeliveries(ctx, sub.ID, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, domain.WebhookDeliveryFailed, page.Deliveries[0].Status)
+	require.Equal(t, 2, page.Deliveries[0].Attempts)
+
+	recv.mu.Lock()
+	recv.status = http.StatusOK
+	recv.mu.Unlock()
+	again, err := svc.Redeliver(ctx, sub.ID, d.ID)
+	require.NoError(t, err)
+	require.Equal(t, d.EventID, again.EventID)
+	_, err = svc.Redeliver(ctx, "999", d.ID)
+	require.ErrorIs(t, err, ErrDeliveryNotFound)
+	n, err = svc.DeliverDue(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	page, err = svc.ListDeliveries(ctx, sub.ID, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, page.Total)
+	require.Equal(t, domain.WebhookDeliverySucceeded, page.Deliveries[0].Status)
+	require.Equal(t, domain.WebhookDeliveryFailed, page.Deliveries[1].Status, "the original entry is kept")
+}
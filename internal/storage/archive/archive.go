@@ -0,0 +1,537 @@
+// Package archive exports the users, groups, roles, OAuth2 clients, upstream connectors and the
+// upstream identities linked to users of a database to a driver-independent archive and imports
+// them again, for moving an instance between environments or database drivers.
+//
+// Archives hold credentials as stored: password and client secret hashes, and connector client
+// secrets sealed with the instance's master key. Sessions, tokens, consents, pending email
+// changes, audit events and webhooks are not exported.
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/group"
+	"github.com/qinzj/superpowers-demo/ent/idpconnector"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/oauth2client"
+	"github.com/qinzj/superpowers-demo/ent/role"
+	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/internal/infra/envelope"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// Version is the archive format version written by Export. Import reads this and older versions.
+// Version 2 added linked identities.
+const Version = 2
+
+// Archive is the content of an export.
+type Archive struct {
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Source     string       `json:"source_driver,omitempty"`
+	Connectors []*Connector `json:"connectors"`
+	Clients    []*Client    `json:"clients"`
+	Roles      []*Role      `json:"roles"`
+	Groups     []*Group     `json:"groups"`
+	Users      []*User      `json:"users"`
+	Identities []*Identity  `json:"linked_identities,omitempty"`
+}
+
+// Connector is an upstream connector, identified by issuer and client ID.
+type Connector struct {
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"` // sealed unless the source had no master key
+	GroupsClaim  string `json:"groups_claim,omitempty"`
+}
+
+// Client is an OAuth2 client, identified by client ID.
+type Client struct {
+	ClientID     string   `json:"client_id"`
+	SecretHash   string   `json:"secret_hash"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
+
+// Role is identified by name.
+type Role struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ConnectorRef names the connector a synced group came from.
+type ConnectorRef struct {
+	Issuer   string `json:"issuer"`
+	ClientID string `json:"client_id"`
+}
+
+// Group is identified by name.
+type Group struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Connector   *ConnectorRef `json:"connector,omitempty"`
+	Roles       []string      `json:"roles,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// User is identified by username. Federated users are exported like local ones, with their
+// upstream accounts in Archive.Identities.
+type User struct {
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	DisplayName         string     `json:"display_name,omitempty"`
+	EmailVerified       bool       `json:"email_verified"`
+	PasswordHash        string     `json:"password_hash"`
+	ExternalID          string     `json:"external_id,omitempty"`
+	Status              string     `json:"status"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	Groups              []string   `json:"groups,omitempty"`
+	Roles               []string   `json:"roles,omitempty"`
+}
+
+// Identity is an upstream account linked to a user, identified by its connector and subject.
+type Identity struct {
+	Connector ConnectorRef `json:"connector"`
+	Subject   string       `json:"subject"`
+	Email     string       `json:"email,omitempty"`
+	Username  string       `json:"username"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Export reads the archive content from client, which must not have storage.EncryptSecrets
+// installed so that sealed values are read as stored. Connector secrets stored before encryption
+// was enabled are sealed with sealer; with a nil sealer they are exported as stored, and
+// plaintext counts them.
+func Export(ctx context.Context, client *ent.Client, sealer storage.Sealer) (a *Archive, plaintext int, err error) {
+	a = &Archive{Version: Version, ExportedAt: time.Now().UTC()}
+	connectors, err := client.IdPConnector.Query().Order(ent.Asc(idpconnector.FieldID)).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export connectors: %w", err)
+	}
+	refs := make(map[string]*ConnectorRef, len(connectors))
+	for _, c := range connectors {
+		secret := c.ClientSecret
+		if !envelope.IsSealed(secret) {
+			if sealer != nil {
+				if secret, err = sealer.Seal(secret, storage.ConnectorSecretBinding(c.Issuer, c.ClientID)); err != nil {
+					return nil, 0, fmt.Errorf("seal connector secret: %w", err)
+				}
+			} else {
+				plaintext++
+			}
+		}
+		a.Connectors = append(a.Connectors, &Connector{Issuer: c.Issuer, ClientID: c.ClientID, ClientSecret: secret, GroupsClaim: c.GroupsClaim})
+		refs[strconv.Itoa(c.ID)] = &ConnectorRef{Issuer: c.Issuer, ClientID: c.ClientID}
+	}
+
+	clients, err := client.OAuth2Client.Query().Order(ent.Asc(oauth2client.FieldClientID)).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export clients: %w", err)
+	}
+	for _, c := range clients {
+		a.Clients = append(a.Clients, &Client{ClientID: c.ClientID, SecretHash: c.ClientSecret, RedirectURIs: c.RedirectUris})
+	}
+
+	roles, err := client.Role.Query().Order(ent.Asc(role.FieldName)).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export roles: %w", err)
+	}
+	for _, r := range roles {
+		a.Roles = append(a.Roles, &Role{Name: r.Name, Description: r.Description, CreatedAt: r.CreatedAt.UTC()})
+	}
+
+	groups, err := client.Group.Query().WithRoles().Order(ent.Asc(group.FieldName)).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export groups: %w", err)
+	}
+	for _, g := range groups {
+		a.Groups = append(a.Groups, &Group{
+			Name:        g.Name,
+			Description: g.Description,
+			Connector:   refs[g.ConnectorID],
+			Roles:       roleNames(g.Edges.Roles),
+			CreatedAt:   g.CreatedAt.UTC(),
+		})
+	}
+
+	users, err := client.User.Query().WithGroups().WithRoles().Order(ent.Asc(user.FieldUsername)).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export users: %w", err)
+	}
+	for _, u := range users {
+		eu := &User{
+			Username:      u.Username,
+			Email:         u.Email,
+			DisplayName:   u.DisplayName,
+			EmailVerified: u.EmailVerified,
+			PasswordHash:  u.PasswordHash,
+			ExternalID:    u.ExternalID,
+			Status:        string(u.Status),
+			CreatedAt:     u.CreatedAt.UTC(),
+			Roles:         roleNames(u.Edges.Roles),
+		}
+		if u.DeletionScheduledAt != nil {
+			t := u.DeletionScheduledAt.UTC()
+			eu.DeletionScheduledAt = &t
+		}
+		for _, g := range u.Edges.Groups {
+			eu.Groups = append(eu.Groups, g.Name)
+		}
+		a.Users = append(a.Users, eu)
+	}
+
+	identities, err := client.LinkedIdentity.Query().WithUser().Order(ent.Asc(linkedidentity.FieldID)).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("export linked identities: %w", err)
+	}
+	for _, li := range identities {
+		ref := refs[li.ConnectorID]
+		if ref == nil {
+			continue // the connector was deleted; the identity can no longer sign in
+		}
+		a.Identities = append(a.Identities, &Identity{
+			Connector: *ref,
+			Subject:   li.Subject,
+			Email:     li.Email,
+			Username:  li.Edges.User.Username,
+			CreatedAt: li.CreatedAt.UTC(),
+		})
+	}
+	return a, plaintext, nil
+}
+
+func roleNames(roles []*ent.Role) []string {
+	var names []string
+	for _, r := range roles {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// ImportOptions controls Import.
+type ImportOptions struct {
+	// SkipExisting keeps entries that already exist unchanged; by default they are overwritten
+	// with the archive's values.
+	SkipExisting bool
+	// Sealer opens and seals connector secrets; nil if the target has no master key.
+	Sealer storage.Sealer
+}
+
+// Counts reports what Import did per kind ("users", "clients", "identities", ...).
+type Counts struct {
+	Created map[string]int
+	Updated map[string]int
+	Skipped map[string]int
+}
+
+// ErrNewerArchive is returned for archives written by a newer release.
+var ErrNewerArchive = errors.New("archive was written by a newer release")
+
+// Import writes a into client in a single transaction, matching existing entries by their keys
+// (username, client ID, issuer and client ID, group and role name, connector and subject), so
+// importing the same archive twice changes nothing. client must not have storage.EncryptSecrets
+// installed.
+//
+// Sealed connector secrets are stored as they are and must open with opts.Sealer, so that the
+// target can use them; plaintext ones are sealed when opts.Sealer is set.
+func Import(ctx context.Context, client *ent.Client, a *Archive, opts ImportOptions) (*Counts, error) {
+	if a.Version > Version {
+		return nil, fmt.Errorf("%w: version %d, this release reads up to %d", ErrNewerArchive, a.Version, Version)
+	}
+	if err := checkSecrets(a, opts.Sealer); err != nil {
+		return nil, err
+	}
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	im := &importer{tx: tx, opts: opts, counts: &Counts{Created: map[string]int{}, Updated: map[string]int{}, Skipped: map[string]int{}}}
+	steps := []func(context.Context, *Archive) error{im.connectors, im.clients, im.roles, im.groups, im.users, im.identities}
+	for _, step := range steps {
+		if err := step(ctx, a); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return im.counts, nil
+}
+
+// checkSecrets fails before anything is written when a sealed secret cannot be opened here.
+func checkSecrets(a *Archive, sealer storage.Sealer) error {
+	for _, c := range a.Connectors {
+		if !envelope.IsSealed(c.ClientSecret) {
+			continue
+		}
+		if sealer == nil {
+			return fmt.Errorf("connector %s (%s) has an encrypted secret but secrets.master_key is not set", c.Issuer, c.ClientID)
+		}
+		if _, err := sealer.Open(c.ClientSecret, storage.ConnectorSecretBinding(c.Issuer, c.ClientID)); err != nil {
+			return fmt.Errorf("connector %s (%s): %w; add the source instance's master key to secrets.previous_keys", c.Issuer, c.ClientID, err)
+		}
+	}
+	return nil
+}
+
+type importer struct {
+	tx     *ent.Tx
+	opts   ImportOptions
+	counts *Counts
+	// connectorIDs maps connectorKey to the ID of the connector in the target.
+	connectorIDs map[string]string
+}
+
+// note records the outcome for one entry and reports whether it should be written.
+func (im *importer) note(kind string, exists bool) bool {
+	switch {
+	case !exists:
+		im.counts.Created[kind]++
+	case im.opts.SkipExisting:
+		im.counts.Skipped[kind]++
+		return false
+	default:
+		im.counts.Updated[kind]++
+	}
+	return true
+}
+
+func connectorKey(issuer, clientID string) string {
+	return strings.TrimSuffix(issuer, "/") + " " + clientID
+}
+
+func (im *importer) connectors(ctx context.Context, a *Archive) error {
+	existing, err := im.tx.IdPConnector.Query().All(ctx)
+	if err != nil {
+		return fmt.Errorf("import connectors: %w", err)
+	}
+	im.connectorIDs = make(map[string]string, len(existing)+len(a.Connectors))
+	byKey := make(map[string]*ent.IdPConnector, len(existing))
+	for _, c := range existing {
+		key := connectorKey(c.Issuer, c.ClientID)
+		byKey[key] = c
+		im.connectorIDs[key] = strconv.Itoa(c.ID)
+	}
+	for _, c := range a.Connectors {
+		secret := c.ClientSecret
+		if !envelope.IsSealed(secret) && im.opts.Sealer != nil {
+			if secret, err = im.opts.Sealer.Seal(secret, storage.ConnectorSecretBinding(c.Issuer, c.ClientID)); err != nil {
+				return fmt.Errorf("seal connector secret: %w", err)
+			}
+		}
+		key := connectorKey(c.Issuer, c.ClientID)
+		have := byKey[key]
+		if !im.note("connectors", have != nil) {
+			continue
+		}
+		if have != nil {
+			// The issuer may differ in a trailing slash; take the archive's, which the secret is bound to.
+			err = im.tx.IdPConnector.UpdateOne(have).SetIssuer(c.Issuer).SetClientSecret(secret).SetGroupsClaim(c.GroupsClaim).Exec(ctx)
+		} else {
+			var e *ent.IdPConnector
+			e, err = im.tx.IdPConnector.Create().SetIssuer(c.Issuer).SetClientID(c.ClientID).
+				SetClientSecret(secret).SetGroupsClaim(c.GroupsClaim).Save(ctx)
+			if err == nil {
+				im.connectorIDs[key] = strconv.Itoa(e.ID)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("import connector %s (%s): %w", c.Issuer, c.ClientID, err)
+		}
+	}
+	return nil
+}
+
+func (im *importer) clients(ctx context.Context, a *Archive) error {
+	for _, c := range a.Clients {
+		have, err := im.tx.OAuth2Client.Query().Where(oauth2client.ClientID(c.ClientID)).Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return fmt.Errorf("import client %s: %w", c.ClientID, err)
+		}
+		if !im.note("clients", have != nil) {
+			continue
+		}
+		if have != nil {
+			err = im.tx.OAuth2Client.UpdateOne(have).SetClientSecret(c.SecretHash).SetRedirectUris(c.RedirectURIs).Exec(ctx)
+		} else {
+			err = im.tx.OAuth2Client.Create().SetClientID(c.ClientID).SetClientSecret(c.SecretHash).SetRedirectUris(c.RedirectURIs).Exec(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("import client %s: %w", c.ClientID, err)
+		}
+	}
+	return nil
+}
+
+func (im *importer) roles(ctx context.Context, a *Archive) error {
+	for _, r := range a.Roles {
+		have, err := im.tx.Role.Query().Where(role.Name(r.Name)).Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return fmt.Errorf("import role %s: %w", r.Name, err)
+		}
+		if !im.note("roles", have != nil) {
+			continue
+		}
+		if have != nil {
+			err = im.tx.Role.UpdateOne(have).SetDescription(r.Description).Exec(ctx)
+		} else {
+			err = im.tx.Role.Create().SetName(r.Name).SetDescription(r.Description).SetCreatedAt(r.CreatedAt).Exec(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("import role %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// roleIDs resolves role names; every role must be in the target, from the archive or already.
+func (im *importer) roleIDs(ctx context.Context, names []string) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ids, err := im.tx.Role.Query().Where(role.NameIn(names...)).IDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(names) {
+		return nil, fmt.Errorf("unknown role among %s", strings.Join(names, ", "))
+	}
+	return ids, nil
+}
+
+func (im *importer) groups(ctx context.Context, a *Archive) error {
+	for _, g := range a.Groups {
+		connectorID := ""
+		if g.Connector != nil {
+			id, ok := im.connectorIDs[connectorKey(g.Connector.Issuer, g.Connector.ClientID)]
+			if !ok {
+				return fmt.Errorf("import group %s: unknown connector %s (%s)", g.Name, g.Connector.Issuer, g.Connector.ClientID)
+			}
+			connectorID = id
+		}
+		have, err := im.tx.Group.Query().Where(group.Name(g.Name)).Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return fmt.Errorf("import group %s: %w", g.Name, err)
+		}
+		if !im.note("groups", have != nil) {
+			continue
+		}
+		roleIDs, err := im.roleIDs(ctx, g.Roles)
+		if err != nil {
+			return fmt.Errorf("import group %s: %w", g.Name, err)
+		}
+		if have != nil {
+			err = im.tx.Group.UpdateOne(have).SetDescription(g.Description).SetConnectorID(connectorID).
+				ClearRoles().AddRoleIDs(roleIDs...).Exec(ctx)
+		} else {
+			err = im.tx.Group.Create().SetName(g.Name).SetDescription(g.Description).SetConnectorID(connectorID).
+				SetCreatedAt(g.CreatedAt).AddRoleIDs(roleIDs...).Exec(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("import group %s: %w", g.Name, err)
+		}
+	}
+	return nil
+}
+
+func (im *importer) users(ctx context.Context, a *Archive) error {
+	for _, u := range a.Users {
+		status := user.Status(u.Status)
+		if err := user.StatusValidator(status); err != nil {
+			return fmt.Errorf("import user %s: %w", u.Username, err)
+		}
+		have, err := im.tx.User.Query().Where(user.Username(u.Username)).Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return fmt.Errorf("import user %s: %w", u.Username, err)
+		}
+		if !im.note("users", have != nil) {
+			continue
+		}
+		roleIDs, err := im.roleIDs(ctx, u.Roles)
+		if err != nil {
+			return fmt.Errorf("import user %s: %w", u.Username, err)
+		}
+		var groupIDs []int
+		if len(u.Groups) > 0 {
+			if groupIDs, err = im.tx.Group.Query().Where(group.NameIn(u.Groups...)).IDs(ctx); err != nil {
+				return fmt.Errorf("import user %s: %w", u.Username, err)
+			}
+			if len(groupIDs) != len(u.Groups) {
+				return fmt.Errorf("import user %s: unknown group among %s", u.Username, strings.Join(u.Groups, ", "))
+			}
+		}
+		if have != nil {
+			err = im.tx.User.UpdateOne(have).
+				SetEmail(u.Email).
+				SetDisplayName(u.DisplayName).
+				SetEmailVerified(u.EmailVerified).
+				SetPasswordHash(u.PasswordHash).
+				SetExternalID(u.ExternalID).
+				SetStatus(status).
+				SetNillableDeletionScheduledAt(u.DeletionScheduledAt).
+				ClearGroups().AddGroupIDs(groupIDs...).
+				ClearRoles().AddRoleIDs(roleIDs...).
+				Exec(ctx)
+			if err == nil && u.DeletionScheduledAt == nil {
+				err = im.tx.User.UpdateOne(have).ClearDeletionScheduledAt().Exec(ctx)
+			}
+		} else {
+			err = im.tx.User.Create().
+				SetUsername(u.Username).
+				SetEmail(u.Email).
+				SetDisplayName(u.DisplayName).
+				SetEmailVerified(u.EmailVerified).
+				SetPasswordHash(u.PasswordHash).
+				SetExternalID(u.ExternalID).
+				SetStatus(status).
+				SetNillableDeletionScheduledAt(u.DeletionScheduledAt).
+				SetCreatedAt(u.CreatedAt).
+				AddGroupIDs(groupIDs...).
+				AddRoleIDs(roleIDs...).
+				Exec(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("import user %s: %w", u.Username, err)
+		}
+	}
+	return nil
+}
+
+func (im *importer) identities(ctx context.Context, a *Archive) error {
+	for _, li := range a.Identities {
+		connectorID, ok := im.connectorIDs[connectorKey(li.Connector.Issuer, li.Connector.ClientID)]
+		if !ok {
+			return fmt.Errorf("import identity %s: unknown connector %s (%s)", li.Subject, li.Connector.Issuer, li.Connector.ClientID)
+		}
+		userID, err := im.tx.User.Query().Where(user.Username(li.Username)).OnlyID(ctx)
+		if err != nil {
+			return fmt.Errorf("import identity %s: user %s: %w", li.Subject, li.Username, err)
+		}
+		have, err := im.tx.LinkedIdentity.Query().
+			Where(linkedidentity.ConnectorID(connectorID), linkedidentity.Subject(li.Subject)).Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return fmt.Errorf("import identity %s: %w", li.Subject, err)
+		}
+		if !im.note("identities", have != nil) {
+			continue
+		}
+		if have != nil {
+			err = im.tx.LinkedIdentity.UpdateOne(have).SetUserID(userID).SetEmail(li.Email).Exec(ctx)
+		} else {
+			err = im.tx.LinkedIdentity.Create().SetConnectorID(connectorID).SetSubject(li.Subject).
+				SetEmail(li.Email).SetUserID(userID).SetCreatedAt(li.CreatedAt).Exec(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("import identity %s: %w", li.Subject, err)
+		}
+	}
+	return nil
+}
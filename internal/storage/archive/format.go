@@ -0,0 +1,152 @@
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Format is the encoding of an archive.
+type Format string
+
+const (
+	// FormatJSON is a single JSON document.
+	FormatJSON Format = "json"
+	// FormatNDJSON is one JSON record per line: a header with the version, then one line per
+	// entry, in dependency order, so large archives can be inspected and diffed line by line.
+	FormatNDJSON Format = "ndjson"
+)
+
+// record is one NDJSON line. The header line has Kind "header" and the archive metadata in Data.
+type record struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+type header struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Source     string    `json:"source_driver,omitempty"`
+}
+
+// Write encodes a to w.
+func Write(w io.Writer, a *Archive, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(a)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		put := func(kind string, v any) error {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			return enc.Encode(record{Kind: kind, Data: b})
+		}
+		h := header{Version: a.Version, ExportedAt: a.ExportedAt, Source: a.Source}
+		if err := put("header", h); err != nil {
+			return err
+		}
+		for _, c := range a.Connectors {
+			if err := put("connector", c); err != nil {
+				return err
+			}
+		}
+		for _, c := range a.Clients {
+			if err := put("client", c); err != nil {
+				return err
+			}
+		}
+		for _, r := range a.Roles {
+			if err := put("role", r); err != nil {
+				return err
+			}
+		}
+		for _, g := range a.Groups {
+			if err := put("group", g); err != nil {
+				return err
+			}
+		}
+		for _, u := range a.Users {
+			if err := put("user", u); err != nil {
+				return err
+			}
+		}
+		for _, li := range a.Identities {
+			if err := put("identity", li); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown archive format %q (use json or ndjson)", format)
+}
+
+// Read decodes an archive in either format, telling them apart by the first record.
+func Read(r io.Reader) (*Archive, error) {
+	dec := json.NewDecoder(r)
+	var first json.RawMessage
+	if err := dec.Decode(&first); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	var rec record
+	if err := json.Unmarshal(first, &rec); err == nil && rec.Kind == "header" {
+		return readNDJSON(rec, dec)
+	}
+	var a Archive
+	if err := json.Unmarshal(first, &a); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	if a.Version == 0 {
+		return nil, errors.New("read archive: no version; not an archive written by export")
+	}
+	return &a, nil
+}
+
+func readNDJSON(h record, dec *json.Decoder) (*Archive, error) {
+	var hdr header
+	if err := json.Unmarshal(h.Data, &hdr); err != nil {
+		return nil, fmt.Errorf("read archive header: %w", err)
+	}
+	a := &Archive{Version: hdr.Version, ExportedAt: hdr.ExportedAt, Source: hdr.Source}
+	for line := 2; ; line++ {
+		var rec record
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return a, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive record %d: %w", line, err)
+		}
+		var target any
+		switch rec.Kind {
+		case "connector":
+			c := &Connector{}
+			a.Connectors, target = append(a.Connectors, c), c
+		case "client":
+			c := &Client{}
+			a.Clients, target = append(a.Clients, c), c
+		case "role":
+			r := &Role{}
+			a.Roles, target = append(a.Roles, r), r
+		case "group":
+			g := &Group{}
+			a.Groups, target = append(a.Groups, g), g
+		case "user":
+			u := &User{}
+			a.Users, target = append(a.Users, u), u
+		case "identity":
+			li := &Identity{}
+			a.Identities, target = append(a.Identities, li), li
+		default:
+			return nil, fmt.Errorf("read archive record %d: unknown kind %q", line, rec.Kind)
+		}
+		if err := json.Unmarshal(rec.Data, target); err != nil {
+			return nil, fmt.Errorf("read archive record %d (%s): %w", line, rec.Kind, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/auditevent"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// AuditRepository implements audit.Repository using ent.
+type AuditRepository struct {
+	client *ent.Client
+}
+
+// NewAuditRepository creates an AuditRepository backed by the given ent client.
+func NewAuditRepository(client *ent.Client) *AuditRepository {
+	return &AuditRepository{client: client}
+}
+
+// appendAttempts bounds how often Append re-reads the chain head after losing a race for it.
+const appendAttempts = 5
+
+// Append links e to the most recent event, seals it with its hash and persists it, populating
+// e.ID, e.PrevHash and e.Hash. CreatedAt defaults to now and is stored at microsecond precision.
+// A concurrent writer extending the same event, such as another replica, makes one of the two
+// fail on the unique prev_hash index instead of forking the chain; the loser links to the new
+// head and tries again, up to appendAttempts times.
+func (r *AuditRepository) Append(ctx context.Context, e *domain.AuditEvent) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Microsecond)
+	for attempt := 1; ; attempt++ {
+		err := r.appendOnce(ctx, e)
+		if err == nil || !ent.IsConstraintError(err) || attempt == appendAttempts {
+			return err
+		}
+	}
+}
+
+func (r *AuditRepository) appendOnce(ctx context.Context, e *domain.AuditEvent) error {
+	tx, err := r.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+	last, err := tx.AuditEvent.Query().Order(ent.Desc(auditevent.FieldID)).First(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return rollback(tx, fmt.Errorf("load last audit event: %w", err))
+	}
+	e.PrevHash = ""
+	if last != nil {
+		e.PrevHash = last.Hash
+	}
+	e.Hash = e.ComputeHash()
+	create := tx.AuditEvent.Create().
+		SetType(string(e.Type)).
+		SetUserID(e.UserID).
+		SetClientID(e.ClientID).
+		SetIP(e.IP).
+		SetUserAgent(e.UserAgent).
+		SetCreatedAt(e.CreatedAt).
+		SetPrevHash(e.PrevHash).
+		SetHash(e.Hash)
+	if len(e.Details) > 0 {
+		create = create.SetDetails(e.Details)
+	}
+	saved, err := create.Save(ctx)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("append audit event: %w", err))
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+	e.ID = strconv.Itoa(saved.ID)
+	return nil
+}
+
+// List returns one page of events matching f, newest first, and the total number of matches.
+func (r *AuditRepository) List(ctx context.Context, f domain.AuditFilter, offset, limit int) ([]*domain.AuditEvent, int, error) {
+	q := r.client.AuditEvent.Query()
+	if f.UserID != "" {
+		q = q.Where(auditevent.UserIDEQ(f.UserID))
+	}
+	if f.ClientID != "" {
+		q = q.Where(auditevent.ClientIDEQ(f.ClientID))
+	}
+	if f.Type != "" {
+		q = q.Where(auditevent.TypeEQ(string(f.Type)))
+	}
+	if !f.Since.IsZero() {
+		q = q.Where(auditevent.CreatedAtGTE(f.Since))
+	}
+	if !f.Until.IsZero() {
+		q = q.Where(auditevent.CreatedAtLT(f.Until))
+	}
+	total, err := q.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count audit events: %w", err)
+	}
+	ents, err := q.Order(ent.Desc(auditevent.FieldID)).Offset(offset).Limit(limit).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit events: %w", err)
+	}
+	return entAuditEventsToDomain(ents), total, nil
+}
+
+// ListAfter returns up to limit events recorded after the event with ID afterID (from the
+// start when empty), oldest first.
+func (r *AuditRepository) ListAfter(ctx context.Context, afterID string, limit int) ([]*domain.AuditEvent, error) {
+	q := r.client.AuditEvent.Query()
+	if afterID != "" {
+		id, err := strconv.Atoi(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit event id: %w", err)
+		}
+		q = q.Where(auditevent.IDGT(id))
+	}
+	ents, err := q.Order(ent.Asc(auditevent.FieldID)).Limit(limit).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	return entAuditEventsToDomain(ents), nil
+}
+
+func entAuditEventsToDomain(ents []*ent.AuditEvent) []*domain.AuditEvent {
+	out := make([]*domain.AuditEvent, len(ents))
+	for i, e := range ents {
+		out[i] = &domain.AuditEvent{
+			ID:        strconv.Itoa(e.ID),
+			Type:      domain.AuditEventType(e.Type),
+			UserID:    e.UserID,
+			ClientID:  e.ClientID,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt,
+			PrevHash:  e.PrevHash,
+			Hash:      e.Hash,
+		}
+	}
+	return out
+}
+
+// rollback aborts tx and returns err, annotated with any rollback failure.
+func rollback(tx *ent.Tx, err error) error {
+	if rerr := tx.Rollback(); rerr != nil {
+		return fmt.Errorf("%w: rollback: %v", err, rerr)
+	}
+	return err
+}
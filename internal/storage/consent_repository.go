@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/consent"
+	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// ConsentRepository implements auth.ConsentRepository using ent.
+type ConsentRepository struct {
+	client *ent.Client
+}
+
+// NewConsentRepository creates a ConsentRepository backed by the given ent client.
+func NewConsentRepository(client *ent.Client) *ConsentRepository {
+	return &ConsentRepository{client: client}
+}
+
+// Get returns the user's consent for clientID, or nil if there is none.
+func (r *ConsentRepository) Get(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	e, err := r.client.Consent.Query().
+		Where(consent.ClientIDEQ(clientID), consent.HasUserWith(user.IDEQ(uid))).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get consent: %w", err)
+	}
+	return entConsentToDomain(e, userID), nil
+}
+
+// Save stores scopes as the user's consent for clientID, replacing any earlier consent.
+func (r *ConsentRepository) Save(ctx context.Context, userID, clientID string, scopes []string) error {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	n, err := r.client.Consent.Update().
+		Where(consent.ClientIDEQ(clientID), consent.HasUserWith(user.IDEQ(uid))).
+		SetScopes(scopes).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("update consent: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+	err = r.client.Consent.Create().
+		SetUserID(uid).
+		SetClientID(clientID).
+		SetScopes(scopes).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("create consent: %w", err)
+	}
+	return nil
+}
+
+// ListByUser returns the user's consents ordered by client ID.
+func (r *ConsentRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Consent, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	ents, err := r.client.Consent.Query().
+		Where(consent.HasUserWith(user.IDEQ(uid))).
+		Order(ent.Asc(consent.FieldClientID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	out := make([]*domain.Consent, len(ents))
+	for i, e := range ents {
+		out[i] = entConsentToDomain(e, userID)
+	}
+	return out, nil
+}
+
+// Delete removes the user's consent for clientID, if any.
+func (r *ConsentRepository) Delete(ctx context.Context, userID, clientID string) error {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	_, err = r.client.Consent.Delete().
+		Where(consent.ClientIDEQ(clientID), consent.HasUserWith(user.IDEQ(uid))).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("delete consent: %w", err)
+	}
+	return nil
+}
+
+func entConsentToDomain(e *ent.Consent, userID string) *domain.Consent {
+	return &domain.Consent{
+		UserID:    userID,
+		ClientID:  e.ClientID,
+		Scopes:    e.Scopes,
+		GrantedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
 	"github.com/qinzj/superpowers-demo/internal/domain"
 )
 
@@ -49,11 +50,66 @@ func (r *IdPConnectorRepository) List(ctx context.Context) ([]*domain.IdPConnect
 	return out, nil
 }
 
+// Create persists c and populates c.ID.
+func (r *IdPConnectorRepository) Create(ctx context.Context, c *domain.IdPConnector) error {
+	e, err := r.client.IdPConnector.Create().
+		SetIssuer(c.Issuer).
+		SetClientID(c.ClientID).
+		SetClientSecret(c.ClientSecret).
+		SetGroupsClaim(c.GroupsClaim).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create idp connector: %w", err)
+	}
+	c.ID = strconv.Itoa(e.ID)
+	return nil
+}
+
+// Update persists every field of c.
+func (r *IdPConnectorRepository) Update(ctx context.Context, c *domain.IdPConnector) error {
+	numericID, err := strconv.Atoi(c.ID)
+	if err != nil {
+		return fmt.Errorf("invalid connector id: %w", err)
+	}
+	err = r.client.IdPConnector.UpdateOneID(numericID).
+		SetIssuer(c.Issuer).
+		SetClientID(c.ClientID).
+		SetClientSecret(c.ClientSecret).
+		SetGroupsClaim(c.GroupsClaim).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("update idp connector: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the connector and the identities linked through it. Returns false if it did
+// not exist.
+func (r *IdPConnectorRepository) Delete(ctx context.Context, id string) (bool, error) {
+	numericID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, nil
+	}
+	err = r.client.IdPConnector.DeleteOneID(numericID).Exec(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("delete idp connector: %w", err)
+	}
+	_, err = r.client.LinkedIdentity.Delete().Where(linkedidentity.ConnectorIDEQ(id)).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete idp connector identities: %w", err)
+	}
+	return true, nil
+}
+
 func entIdPConnectorToDomain(e *ent.IdPConnector) *domain.IdPConnector {
 	return &domain.IdPConnector{
 		ID:           strconv.Itoa(e.ID),
 		Issuer:       e.Issuer,
 		ClientID:     e.ClientID,
 		ClientSecret: e.ClientSecret,
+		GroupsClaim:  e.GroupsClaim,
 	}
 }
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
+	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// LinkedIdentityRepository implements federation.IdentityRepository using ent.
+type LinkedIdentityRepository struct {
+	client *ent.Client
+}
+
+// NewLinkedIdentityRepository creates a LinkedIdentityRepository backed by the given ent client.
+func NewLinkedIdentityRepository(client *ent.Client) *LinkedIdentityRepository {
+	return &LinkedIdentityRepository{client: client}
+}
+
+// UserID returns the ID of the user that subject at connectorID is linked to, or "" if none.
+func (r *LinkedIdentityRepository) UserID(ctx context.Context, connectorID, subject string) (string, error) {
+	id, err := r.client.LinkedIdentity.Query().
+		Where(linkedidentity.ConnectorIDEQ(connectorID), linkedidentity.SubjectEQ(subject)).
+		QueryUser().
+		OnlyID(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get linked identity: %w", err)
+	}
+	return strconv.Itoa(id), nil
+}
+
+// Create links i.Subject at i.ConnectorID to i.UserID and populates i.LinkedAt.
+func (r *LinkedIdentityRepository) Create(ctx context.Context, i *domain.LinkedIdentity) error {
+	uid, err := strconv.Atoi(i.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	e, err := r.client.LinkedIdentity.Create().
+		SetUserID(uid).
+		SetConnectorID(i.ConnectorID).
+		SetSubject(i.Subject).
+		SetEmail(i.Email).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create linked identity: %w", err)
+	}
+	i.LinkedAt = e.CreatedAt
+	return nil
+}
+
+// ListByUser returns the identities linked to the user, oldest first.
+func (r *LinkedIdentityRepository) ListByUser(ctx context.Context, userID string) ([]*domain.LinkedIdentity, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	ents, err := r.client.LinkedIdentity.Query().
+		Where(linkedidentity.HasUserWith(user.IDEQ(uid))).
+		Order(ent.Asc(linkedidentity.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list linked identities: %w", err)
+	}
+	out := make([]*domain.LinkedIdentity, len(ents))
+	for i, e := range ents {
+		out[i] = &domain.LinkedIdentity{
+			UserID:      userID,
+			ConnectorID: e.ConnectorID,
+			Subject:     e.Subject,
+			Email:       e.Email,
+			LinkedAt:    e.CreatedAt,
+		}
+	}
+	return out, nil
+}
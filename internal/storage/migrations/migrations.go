@@ -0,0 +1,104 @@
+// Package migrations holds the versioned schema migrations for each supported database and
+// applies them.
+//
+// Each dialect directory (sqlite3, postgres, mysql) holds pairs of <version>_<name>.up.sql and
+// <version>_<name>.down.sql files, generated from the ent schema by Diff, and an atlas.sum file
+// that guards them against manual edits. Applied versions are recorded in the schema_revisions
+// table.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqltool"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql/schema"
+
+	entmigrate "github.com/qinzj/superpowers-demo/ent/migrate"
+)
+
+//go:embed sqlite3 postgres mysql
+var files embed.FS
+
+// ErrNoChanges is returned by Diff when the migrations already match the ent schema.
+var ErrNoChanges = errors.New("migrations are up to date with the ent schema")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version     string
+	Description string
+	up, down    string
+}
+
+// Available returns the migrations for a dialect in the order they apply.
+func Available(dialectName string) ([]Migration, error) {
+	dir, err := fs.Sub(files, dialectName)
+	if err != nil {
+		return nil, err
+	}
+	ups, err := fs.Glob(dir, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(ups) == 0 {
+		return nil, fmt.Errorf("no migrations for database driver %q", dialectName)
+	}
+	sort.Strings(ups)
+	out := make([]Migration, 0, len(ups))
+	for _, up := range ups {
+		base := strings.TrimSuffix(up, ".up.sql")
+		version, desc, _ := strings.Cut(base, "_")
+		m := Migration{Version: version, Description: desc, up: up}
+		if _, err := fs.Stat(dir, base+".down.sql"); err == nil {
+			m.down = base + ".down.sql"
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// statements reads the statements of one migration file.
+func statements(dialectName, name string) ([]string, error) {
+	b, err := files.ReadFile(dialectName + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewLocalFile(name, b).Stmts()
+}
+
+// Diff writes a migration named name to dir with the changes needed to bring the migrations
+// already in dir up to the current ent schema, and updates dir's atlas.sum. drv must be
+// connected to an empty scratch database of the target dialect: the existing migrations are
+// replayed on it to work out the current state, and it is emptied again afterwards. Diff returns
+// ErrNoChanges when there is nothing to write.
+func Diff(ctx context.Context, drv dialect.Driver, dir, name string) error {
+	d, err := sqltool.NewGolangMigrateDir(dir)
+	if err != nil {
+		return err
+	}
+	m, err := schema.NewMigrate(drv,
+		schema.WithDir(d),
+		schema.WithFormatter(sqltool.GolangMigrateFormatter),
+		schema.WithMigrationMode(schema.ModeReplay),
+		schema.WithDropColumn(true),
+		schema.WithDropIndex(true),
+		schema.WithErrNoPlan(true),
+	)
+	if err != nil {
+		return err
+	}
+	if err := m.NamedDiff(ctx, name, entmigrate.Tables...); err != nil {
+		if errors.Is(err, migrate.ErrNoPlan) {
+			return ErrNoChanges
+		}
+		return err
+	}
+	return nil
+}
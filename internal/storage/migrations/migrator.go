@@ -0,0 +1,217 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
+)
+
+// revisionsTable records the applied migrations.
+const revisionsTable = "schema_revisions"
+
+// Status is a migration and whether it has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the migrations of one dialect to a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// New returns a migrator for db, which must be a database of the given ent dialect.
+func New(db *sql.DB, dialectName string) (*Migrator, error) {
+	ms, err := Available(dialectName)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialectName, migrations: ms}, nil
+}
+
+// Status lists every migration with the time it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Status, len(m.migrations))
+	for i, mg := range m.migrations {
+		at, ok := applied[mg.Version]
+		out[i] = Status{Migration: mg, Applied: ok, AppliedAt: at}
+	}
+	return out, nil
+}
+
+// Pending returns the migrations that have not been applied.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Migration
+	for _, s := range st {
+		if !s.Applied {
+			out = append(out, s.Migration)
+		}
+	}
+	return out, nil
+}
+
+// Up applies up to n pending migrations, or all of them when n <= 0, and returns the ones it
+// applied. Each migration runs in a transaction, so on PostgreSQL and SQLite a failed migration
+// leaves no trace; MySQL commits each DDL statement on its own and may need manual cleanup.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+	for i, mg := range pending {
+		if err := m.run(ctx, mg, mg.up, true); err != nil {
+			return pending[:i], err
+		}
+	}
+	return pending, nil
+}
+
+// Down reverts the n most recently applied migrations, or one when n <= 0, and returns the ones
+// it reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		n = 1
+	}
+	var reverted []Migration
+	for i := len(st) - 1; i >= 0 && len(reverted) < n; i-- {
+		if !st[i].Applied {
+			continue
+		}
+		mg := st[i].Migration
+		if mg.down == "" {
+			return reverted, fmt.Errorf("migration %s_%s cannot be reverted: it has no down file", mg.Version, mg.Description)
+		}
+		if err := m.run(ctx, mg, mg.down, false); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, mg)
+	}
+	return reverted, nil
+}
+
+// Baseline records every migration up to and including version as applied without running it,
+// for databases whose schema was created before versioned migrations were introduced.
+func (m *Migrator) Baseline(ctx context.Context, version string) ([]Migration, error) {
+	found := false
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown migration version %q", version)
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var recorded []Migration
+	for _, mg := range pending {
+		if mg.Version > version {
+			break
+		}
+		if err := m.record(ctx, m.db, mg); err != nil {
+			return recorded, err
+		}
+		recorded = append(recorded, mg)
+	}
+	return recorded, nil
+}
+
+// run executes one migration file and updates the revisions table in the same transaction.
+func (m *Migrator) run(ctx context.Context, mg Migration, file string, up bool) error {
+	stmts, err := statements(m.dialect, file)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", file, err)
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("%s: %w\n\t%s", file, err, stmt)
+		}
+	}
+	if up {
+		err = m.record(ctx, tx, mg)
+	} else {
+		query, args := entsql.Dialect(m.dialect).Delete(revisionsTable).Where(entsql.EQ("version", mg.Version)).Query()
+		_, err = tx.ExecContext(ctx, query, args...)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("%s: update %s: %w", file, revisionsTable, err)
+	}
+	return tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (m *Migrator) record(ctx context.Context, e execer, mg Migration) error {
+	query, args := entsql.Dialect(m.dialect).Insert(revisionsTable).
+		Columns("version", "description", "applied_at").
+		Values(mg.Version, mg.Description, time.Now().UTC().Format(time.RFC3339)).
+		Query()
+	_, err := e.ExecContext(ctx, query, args...)
+	return err
+}
+
+// applied creates the revisions table if needed and returns the applied versions. It fails when
+// the database has a version this binary does not know, which means a newer release migrated it.
+func (m *Migrator) applied(ctx context.Context) (map[string]time.Time, error) {
+	// applied_at is RFC 3339 text: DATETIME and TIMESTAMP scan differently on every driver.
+	if _, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+revisionsTable+` (
+	version VARCHAR(32) NOT NULL PRIMARY KEY,
+	description VARCHAR(255) NOT NULL,
+	applied_at VARCHAR(64) NOT NULL
+)`); err != nil {
+		return nil, fmt.Errorf("create %s: %w", revisionsTable, err)
+	}
+	query, args := entsql.Dialect(m.dialect).Select("version", "applied_at").From(entsql.Table(revisionsTable)).Query()
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	known := make(map[string]bool, len(m.migrations))
+	for _, mg := range m.migrations {
+		known[mg.Version] = true
+	}
+	out := make(map[string]time.Time)
+	for rows.Next() {
+		var version, at string
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		if !known[version] {
+			return nil, fmt.Errorf("database is at migration %s, which this release does not know; it was migrated by a newer release", version)
+		}
+		out[version], _ = time.Parse(time.RFC3339, at)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,24 @@
+-- reverse: create "webhook_deliveries" table
+DROP TABLE `webhook_deliveries`;
+-- reverse: create "webhook_subscriptions" table
+DROP TABLE `webhook_subscriptions`;
+-- reverse: create "sessions" table
+DROP TABLE `sessions`;
+-- reverse: create "role_users" table
+DROP TABLE `role_users`;
+-- reverse: create "group_users" table
+DROP TABLE `group_users`;
+-- reverse: create "users" table
+DROP TABLE `users`;
+-- reverse: create "group_roles" table
+DROP TABLE `group_roles`;
+-- reverse: create "roles" table
+DROP TABLE `roles`;
+-- reverse: create "groups" table
+DROP TABLE `groups`;
+-- reverse: create "oauth2clients" table
+DROP TABLE `oauth2clients`;
+-- reverse: create "id_pconnectors" table
+DROP TABLE `id_pconnectors`;
+-- reverse: create "audit_events" table
+DROP TABLE `audit_events`;
@@ -0,0 +1,24 @@
+-- create "audit_events" table
+CREATE TABLE `audit_events` (`id` bigint NOT NULL AUTO_INCREMENT, `type` varchar(255) NOT NULL, `user_id` varchar(255) NOT NULL DEFAULT '', `client_id` varchar(255) NOT NULL DEFAULT '', `ip` varchar(255) NOT NULL DEFAULT '', `user_agent` varchar(255) NOT NULL DEFAULT '', `details` json NULL, `created_at` timestamp NOT NULL, `prev_hash` varchar(255) NOT NULL DEFAULT '', `hash` varchar(255) NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `auditevent_hash` (`hash`), UNIQUE INDEX `auditevent_prev_hash` (`prev_hash`), INDEX `auditevent_user_id` (`user_id`), INDEX `auditevent_client_id` (`client_id`), INDEX `auditevent_type` (`type`), INDEX `auditevent_created_at` (`created_at`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "id_pconnectors" table
+CREATE TABLE `id_pconnectors` (`id` bigint NOT NULL AUTO_INCREMENT, `issuer` varchar(255) NOT NULL, `client_id` varchar(255) NOT NULL, `client_secret` varchar(255) NOT NULL, `groups_claim` varchar(255) NOT NULL DEFAULT '', PRIMARY KEY (`id`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "oauth2clients" table
+CREATE TABLE `oauth2clients` (`id` bigint NOT NULL AUTO_INCREMENT, `client_id` varchar(255) NOT NULL, `client_secret` varchar(255) NOT NULL, `redirect_uris` json NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `client_id` (`client_id`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "groups" table
+CREATE TABLE `groups` (`id` bigint NOT NULL AUTO_INCREMENT, `name` varchar(255) NOT NULL, `description` varchar(255) NOT NULL DEFAULT '', `connector_id` varchar(255) NOT NULL DEFAULT '', `created_at` timestamp NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `name` (`name`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "roles" table
+CREATE TABLE `roles` (`id` bigint NOT NULL AUTO_INCREMENT, `name` varchar(255) NOT NULL, `description` varchar(255) NOT NULL DEFAULT '', `created_at` timestamp NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `name` (`name`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "group_roles" table
+CREATE TABLE `group_roles` (`group_id` bigint NOT NULL, `role_id` bigint NOT NULL, PRIMARY KEY (`group_id`, `role_id`), CONSTRAINT `group_roles_group_id` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`) ON DELETE CASCADE, CONSTRAINT `group_roles_role_id` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "users" table
+CREATE TABLE `users` (`id` bigint NOT NULL AUTO_INCREMENT, `username` varchar(255) NOT NULL, `email` varchar(255) NOT NULL, `password_hash` varchar(255) NOT NULL, `display_name` varchar(255) NOT NULL DEFAULT '', `email_verified` bool NOT NULL DEFAULT false, `pending_email` varchar(255) NOT NULL DEFAULT '', `email_verify_token_hash` varchar(255) NOT NULL DEFAULT '', `email_verify_expires_at` timestamp NULL, `external_id` varchar(255) NOT NULL DEFAULT '', `status` enum('active','suspended','locked','pending_verification') NOT NULL DEFAULT 'active', `deletion_scheduled_at` timestamp NULL, `created_at` timestamp NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `username` (`username`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "group_users" table
+CREATE TABLE `group_users` (`group_id` bigint NOT NULL, `user_id` bigint NOT NULL, PRIMARY KEY (`group_id`, `user_id`), CONSTRAINT `group_users_group_id` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`) ON DELETE CASCADE, CONSTRAINT `group_users_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "role_users" table
+CREATE TABLE `role_users` (`role_id` bigint NOT NULL, `user_id` bigint NOT NULL, PRIMARY KEY (`role_id`, `user_id`), CONSTRAINT `role_users_role_id` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE, CONSTRAINT `role_users_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "sessions" table
+CREATE TABLE `sessions` (`id` bigint NOT NULL AUTO_INCREMENT, `token` varchar(255) NOT NULL, `expires_at` timestamp NOT NULL, `absolute_expires_at` timestamp NULL, `remember` bool NOT NULL DEFAULT false, `created_at` timestamp NOT NULL, `last_seen_at` timestamp NOT NULL, `ip` varchar(255) NOT NULL DEFAULT '', `user_agent` varchar(255) NOT NULL DEFAULT '', `user_sessions` bigint NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `session_token` (`token`), INDEX `session_expires_at` (`expires_at`), CONSTRAINT `sessions_users_sessions` FOREIGN KEY (`user_sessions`) REFERENCES `users` (`id`) ON DELETE NO ACTION) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "webhook_subscriptions" table
+CREATE TABLE `webhook_subscriptions` (`id` bigint NOT NULL AUTO_INCREMENT, `url` varchar(255) NOT NULL, `events` json NOT NULL, `secret` varchar(255) NOT NULL, `description` varchar(255) NOT NULL DEFAULT '', `enabled` bool NOT NULL DEFAULT true, `created_at` timestamp NOT NULL, PRIMARY KEY (`id`)) CHARSET utf8mb4 COLLATE utf8mb4_bin;
+-- create "webhook_deliveries" table
+CREATE TABLE `webhook_deliveries` (`id` bigint NOT NULL AUTO_INCREMENT, `event_id` varchar(255) NOT NULL, `event_type` varchar(255) NOT NULL, `payload` longtext NOT NULL, `status` enum('pending','succeeded','failed') NOT NULL DEFAULT 'pending', `attempts` bigint NOT NULL DEFAULT 0, `next_attempt_at` timestamp NOT NULL, `last_attempt_at` timestamp NULL, `response_status` bigint NOT NULL DEFAULT 0, `last_error` varchar(255) NOT NULL DEFAULT '', `created_at` timestamp NOT NULL, `webhook_subscription_deliveries` bigint NOT NULL, PRIMARY KEY (`id`), INDEX `webhookdelivery_status_next_attempt_at` (`status`, `next_attempt_at`), INDEX `webhookdelivery_event_id` (`event_id`), CONSTRAINT `webhook_deliveries_webhook_subscriptions_deliveries` FOREIGN KEY (`webhook_subscription_deliveries`) REFERENCES `webhook_subscriptions` (`id`) ON DELETE NO ACTION) CHARSET utf8mb4 COLLATE utf8mb4_bin;
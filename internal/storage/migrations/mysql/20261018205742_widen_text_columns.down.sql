@@ -0,0 +1,10 @@
+-- reverse: modify "webhook_deliveries" table
+ALTER TABLE `webhook_deliveries` MODIFY COLUMN `last_error` varchar(255) NOT NULL DEFAULT '';
+-- reverse: modify "webhook_subscriptions" table
+ALTER TABLE `webhook_subscriptions` MODIFY COLUMN `secret` varchar(255) NOT NULL;
+-- reverse: modify "sessions" table
+ALTER TABLE `sessions` MODIFY COLUMN `user_agent` varchar(255) NOT NULL DEFAULT '';
+-- reverse: modify "id_pconnectors" table
+ALTER TABLE `id_pconnectors` MODIFY COLUMN `client_secret` varchar(255) NOT NULL;
+-- reverse: modify "audit_events" table
+ALTER TABLE `audit_events` MODIFY COLUMN `user_agent` varchar(255) NOT NULL DEFAULT '', MODIFY COLUMN `created_at` timestamp NOT NULL;
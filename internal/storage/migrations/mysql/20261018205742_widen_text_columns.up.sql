@@ -0,0 +1,10 @@
+-- modify "audit_events" table
+ALTER TABLE `audit_events` MODIFY COLUMN `user_agent` longtext NOT NULL, MODIFY COLUMN `created_at` timestamp(6) NOT NULL;
+-- modify "id_pconnectors" table
+ALTER TABLE `id_pconnectors` MODIFY COLUMN `client_secret` longtext NOT NULL;
+-- modify "sessions" table
+ALTER TABLE `sessions` MODIFY COLUMN `user_agent` longtext NOT NULL;
+-- modify "webhook_subscriptions" table
+ALTER TABLE `webhook_subscriptions` MODIFY COLUMN `secret` longtext NOT NULL;
+-- modify "webhook_deliveries" table
+ALTER TABLE `webhook_deliveries` MODIFY COLUMN `last_error` longtext NOT NULL;
@@ -0,0 +1,2 @@
+-- reverse: create "consents" table
+DROP TABLE `consents`;
@@ -0,0 +1,2 @@
+-- create "consents" table
+CREATE TABLE `consents` (`id` bigint NOT NULL AUTO_INCREMENT, `client_id` varchar(255) NOT NULL, `scopes` json NOT NULL, `created_at` timestamp NOT NULL, `updated_at` timestamp NOT NULL, `user_consents` bigint NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `consent_client_id_user_consents` (`client_id`, `user_consents`), CONSTRAINT `consents_users_consents` FOREIGN KEY (`user_consents`) REFERENCES `users` (`id`) ON DELETE NO ACTION) CHARSET utf8mb4 COLLATE utf8mb4_bin;
@@ -0,0 +1,2 @@
+-- reverse: create "linked_identities" table
+DROP TABLE `linked_identities`;
@@ -0,0 +1,2 @@
+-- create "linked_identities" table
+CREATE TABLE `linked_identities` (`id` bigint NOT NULL AUTO_INCREMENT, `connector_id` varchar(255) NOT NULL, `subject` varchar(255) NOT NULL, `email` varchar(255) NOT NULL DEFAULT '', `created_at` timestamp NOT NULL, `user_identities` bigint NOT NULL, PRIMARY KEY (`id`), UNIQUE INDEX `linkedidentity_connector_id_subject` (`connector_id`, `subject`), CONSTRAINT `linked_identities_users_identities` FOREIGN KEY (`user_identities`) REFERENCES `users` (`id`) ON DELETE NO ACTION) CHARSET utf8mb4 COLLATE utf8mb4_bin;
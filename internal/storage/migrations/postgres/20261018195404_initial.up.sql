@@ -0,0 +1,52 @@
+-- create "audit_events" table
+CREATE TABLE "audit_events" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "type" character varying NOT NULL, "user_id" character varying NOT NULL DEFAULT '', "client_id" character varying NOT NULL DEFAULT '', "ip" character varying NOT NULL DEFAULT '', "user_agent" character varying NOT NULL DEFAULT '', "details" jsonb NULL, "created_at" timestamptz NOT NULL, "prev_hash" character varying NOT NULL DEFAULT '', "hash" character varying NOT NULL, PRIMARY KEY ("id"));
+-- create index "auditevent_hash" to table: "audit_events"
+CREATE UNIQUE INDEX "auditevent_hash" ON "audit_events" ("hash");
+-- create index "auditevent_prev_hash" to table: "audit_events"
+CREATE UNIQUE INDEX "auditevent_prev_hash" ON "audit_events" ("prev_hash");
+-- create index "auditevent_user_id" to table: "audit_events"
+CREATE INDEX "auditevent_user_id" ON "audit_events" ("user_id");
+-- create index "auditevent_client_id" to table: "audit_events"
+CREATE INDEX "auditevent_client_id" ON "audit_events" ("client_id");
+-- create index "auditevent_type" to table: "audit_events"
+CREATE INDEX "auditevent_type" ON "audit_events" ("type");
+-- create index "auditevent_created_at" to table: "audit_events"
+CREATE INDEX "auditevent_created_at" ON "audit_events" ("created_at");
+-- create "id_pconnectors" table
+CREATE TABLE "id_pconnectors" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "issuer" character varying NOT NULL, "client_id" character varying NOT NULL, "client_secret" character varying NOT NULL, "groups_claim" character varying NOT NULL DEFAULT '', PRIMARY KEY ("id"));
+-- create "oauth2clients" table
+CREATE TABLE "oauth2clients" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "client_id" character varying NOT NULL, "client_secret" character varying NOT NULL, "redirect_uris" jsonb NOT NULL, PRIMARY KEY ("id"));
+-- create index "oauth2clients_client_id_key" to table: "oauth2clients"
+CREATE UNIQUE INDEX "oauth2clients_client_id_key" ON "oauth2clients" ("client_id");
+-- create "groups" table
+CREATE TABLE "groups" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "name" character varying NOT NULL, "description" character varying NOT NULL DEFAULT '', "connector_id" character varying NOT NULL DEFAULT '', "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "groups_name_key" to table: "groups"
+CREATE UNIQUE INDEX "groups_name_key" ON "groups" ("name");
+-- create "roles" table
+CREATE TABLE "roles" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "name" character varying NOT NULL, "description" character varying NOT NULL DEFAULT '', "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "roles_name_key" to table: "roles"
+CREATE UNIQUE INDEX "roles_name_key" ON "roles" ("name");
+-- create "group_roles" table
+CREATE TABLE "group_roles" ("group_id" bigint NOT NULL, "role_id" bigint NOT NULL, PRIMARY KEY ("group_id", "role_id"), CONSTRAINT "group_roles_group_id" FOREIGN KEY ("group_id") REFERENCES "groups" ("id") ON DELETE CASCADE, CONSTRAINT "group_roles_role_id" FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE CASCADE);
+-- create "users" table
+CREATE TABLE "users" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "username" character varying NOT NULL, "email" character varying NOT NULL, "password_hash" character varying NOT NULL, "display_name" character varying NOT NULL DEFAULT '', "email_verified" boolean NOT NULL DEFAULT false, "pending_email" character varying NOT NULL DEFAULT '', "email_verify_token_hash" character varying NOT NULL DEFAULT '', "email_verify_expires_at" timestamptz NULL, "external_id" character varying NOT NULL DEFAULT '', "status" character varying NOT NULL DEFAULT 'active', "deletion_scheduled_at" timestamptz NULL, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "users_username_key" to table: "users"
+CREATE UNIQUE INDEX "users_username_key" ON "users" ("username");
+-- create "group_users" table
+CREATE TABLE "group_users" ("group_id" bigint NOT NULL, "user_id" bigint NOT NULL, PRIMARY KEY ("group_id", "user_id"), CONSTRAINT "group_users_group_id" FOREIGN KEY ("group_id") REFERENCES "groups" ("id") ON DELETE CASCADE, CONSTRAINT "group_users_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE);
+-- create "role_users" table
+CREATE TABLE "role_users" ("role_id" bigint NOT NULL, "user_id" bigint NOT NULL, PRIMARY KEY ("role_id", "user_id"), CONSTRAINT "role_users_role_id" FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE CASCADE, CONSTRAINT "role_users_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE);
+-- create "sessions" table
+CREATE TABLE "sessions" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "token" character varying NOT NULL, "expires_at" timestamptz NOT NULL, "absolute_expires_at" timestamptz NULL, "remember" boolean NOT NULL DEFAULT false, "created_at" timestamptz NOT NULL, "last_seen_at" timestamptz NOT NULL, "ip" character varying NOT NULL DEFAULT '', "user_agent" character varying NOT NULL DEFAULT '', "user_sessions" bigint NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "sessions_users_sessions" FOREIGN KEY ("user_sessions") REFERENCES "users" ("id") ON DELETE NO ACTION);
+-- create index "session_token" to table: "sessions"
+CREATE UNIQUE INDEX "session_token" ON "sessions" ("token");
+-- create index "session_expires_at" to table: "sessions"
+CREATE INDEX "session_expires_at" ON "sessions" ("expires_at");
+-- create "webhook_subscriptions" table
+CREATE TABLE "webhook_subscriptions" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "url" character varying NOT NULL, "events" jsonb NOT NULL, "secret" character varying NOT NULL, "description" character varying NOT NULL DEFAULT '', "enabled" boolean NOT NULL DEFAULT true, "created_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create "webhook_deliveries" table
+CREATE TABLE "webhook_deliveries" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "event_id" character varying NOT NULL, "event_type" character varying NOT NULL, "payload" text NOT NULL, "status" character varying NOT NULL DEFAULT 'pending', "attempts" bigint NOT NULL DEFAULT 0, "next_attempt_at" timestamptz NOT NULL, "last_attempt_at" timestamptz NULL, "response_status" bigint NOT NULL DEFAULT 0, "last_error" character varying NOT NULL DEFAULT '', "created_at" timestamptz NOT NULL, "webhook_subscription_deliveries" bigint NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "webhook_deliveries_webhook_subscriptions_deliveries" FOREIGN KEY ("webhook_subscription_deliveries") REFERENCES "webhook_subscriptions" ("id") ON DELETE NO ACTION);
+-- create index "webhookdelivery_status_next_attempt_at" to table: "webhook_deliveries"
+CREATE INDEX "webhookdelivery_status_next_attempt_at" ON "webhook_deliveries" ("status", "next_attempt_at");
+-- create index "webhookdelivery_event_id" to table: "webhook_deliveries"
+CREATE INDEX "webhookdelivery_event_id" ON "webhook_deliveries" ("event_id");
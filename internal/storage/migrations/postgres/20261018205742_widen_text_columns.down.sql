@@ -0,0 +1,10 @@
+-- reverse: modify "webhook_deliveries" table
+ALTER TABLE "webhook_deliveries" ALTER COLUMN "last_error" TYPE character varying;
+-- reverse: modify "webhook_subscriptions" table
+ALTER TABLE "webhook_subscriptions" ALTER COLUMN "secret" TYPE character varying;
+-- reverse: modify "sessions" table
+ALTER TABLE "sessions" ALTER COLUMN "user_agent" TYPE character varying;
+-- reverse: modify "id_pconnectors" table
+ALTER TABLE "id_pconnectors" ALTER COLUMN "client_secret" TYPE character varying;
+-- reverse: modify "audit_events" table
+ALTER TABLE "audit_events" ALTER COLUMN "user_agent" TYPE character varying;
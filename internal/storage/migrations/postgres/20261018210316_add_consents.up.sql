@@ -0,0 +1,4 @@
+-- create "consents" table
+CREATE TABLE "consents" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "client_id" character varying NOT NULL, "scopes" jsonb NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, "user_consents" bigint NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "consents_users_consents" FOREIGN KEY ("user_consents") REFERENCES "users" ("id") ON DELETE NO ACTION);
+-- create index "consent_client_id_user_consents" to table: "consents"
+CREATE UNIQUE INDEX "consent_client_id_user_consents" ON "consents" ("client_id", "user_consents");
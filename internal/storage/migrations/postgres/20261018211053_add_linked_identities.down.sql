@@ -0,0 +1,4 @@
+-- reverse: create index "linkedidentity_connector_id_subject" to table: "linked_identities"
+DROP INDEX "linkedidentity_connector_id_subject";
+-- reverse: create "linked_identities" table
+DROP TABLE "linked_identities";
@@ -0,0 +1,4 @@
+-- create "linked_identities" table
+CREATE TABLE "linked_identities" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "connector_id" character varying NOT NULL, "subject" character varying NOT NULL, "email" character varying NOT NULL DEFAULT '', "created_at" timestamptz NOT NULL, "user_identities" bigint NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "linked_identities_users_identities" FOREIGN KEY ("user_identities") REFERENCES "users" ("id") ON DELETE NO ACTION);
+-- create index "linkedidentity_connector_id_subject" to table: "linked_identities"
+CREATE UNIQUE INDEX "linkedidentity_connector_id_subject" ON "linked_identities" ("connector_id", "subject");
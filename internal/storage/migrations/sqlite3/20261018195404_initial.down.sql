@@ -0,0 +1,52 @@
+-- reverse: create "role_users" table
+DROP TABLE `role_users`;
+-- reverse: create "group_roles" table
+DROP TABLE `group_roles`;
+-- reverse: create "group_users" table
+DROP TABLE `group_users`;
+-- reverse: create "webhook_subscriptions" table
+DROP TABLE `webhook_subscriptions`;
+-- reverse: create index "webhookdelivery_event_id" to table: "webhook_deliveries"
+DROP INDEX `webhookdelivery_event_id`;
+-- reverse: create index "webhookdelivery_status_next_attempt_at" to table: "webhook_deliveries"
+DROP INDEX `webhookdelivery_status_next_attempt_at`;
+-- reverse: create "webhook_deliveries" table
+DROP TABLE `webhook_deliveries`;
+-- reverse: create index "users_username_key" to table: "users"
+DROP INDEX `users_username_key`;
+-- reverse: create "users" table
+DROP TABLE `users`;
+-- reverse: create index "session_expires_at" to table: "sessions"
+DROP INDEX `session_expires_at`;
+-- reverse: create index "session_token" to table: "sessions"
+DROP INDEX `session_token`;
+-- reverse: create "sessions" table
+DROP TABLE `sessions`;
+-- reverse: create index "roles_name_key" to table: "roles"
+DROP INDEX `roles_name_key`;
+-- reverse: create "roles" table
+DROP TABLE `roles`;
+-- reverse: create index "oauth2clients_client_id_key" to table: "oauth2clients"
+DROP INDEX `oauth2clients_client_id_key`;
+-- reverse: create "oauth2clients" table
+DROP TABLE `oauth2clients`;
+-- reverse: create "id_pconnectors" table
+DROP TABLE `id_pconnectors`;
+-- reverse: create index "groups_name_key" to table: "groups"
+DROP INDEX `groups_name_key`;
+-- reverse: create "groups" table
+DROP TABLE `groups`;
+-- reverse: create index "auditevent_created_at" to table: "audit_events"
+DROP INDEX `auditevent_created_at`;
+-- reverse: create index "auditevent_type" to table: "audit_events"
+DROP INDEX `auditevent_type`;
+-- reverse: create index "auditevent_client_id" to table: "audit_events"
+DROP INDEX `auditevent_client_id`;
+-- reverse: create index "auditevent_user_id" to table: "audit_events"
+DROP INDEX `auditevent_user_id`;
+-- reverse: create index "auditevent_prev_hash" to table: "audit_events"
+DROP INDEX `auditevent_prev_hash`;
+-- reverse: create index "auditevent_hash" to table: "audit_events"
+DROP INDEX `auditevent_hash`;
+-- reverse: create "audit_events" table
+DROP TABLE `audit_events`;
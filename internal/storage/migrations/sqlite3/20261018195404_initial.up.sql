@@ -0,0 +1,52 @@
+-- create "audit_events" table
+CREATE TABLE `audit_events` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `type` text NOT NULL, `user_id` text NOT NULL DEFAULT '', `client_id` text NOT NULL DEFAULT '', `ip` text NOT NULL DEFAULT '', `user_agent` text NOT NULL DEFAULT '', `details` json NULL, `created_at` datetime NOT NULL, `prev_hash` text NOT NULL DEFAULT '', `hash` text NOT NULL);
+-- create index "auditevent_hash" to table: "audit_events"
+CREATE UNIQUE INDEX `auditevent_hash` ON `audit_events` (`hash`);
+-- create index "auditevent_prev_hash" to table: "audit_events"
+CREATE UNIQUE INDEX `auditevent_prev_hash` ON `audit_events` (`prev_hash`);
+-- create index "auditevent_user_id" to table: "audit_events"
+CREATE INDEX `auditevent_user_id` ON `audit_events` (`user_id`);
+-- create index "auditevent_client_id" to table: "audit_events"
+CREATE INDEX `auditevent_client_id` ON `audit_events` (`client_id`);
+-- create index "auditevent_type" to table: "audit_events"
+CREATE INDEX `auditevent_type` ON `audit_events` (`type`);
+-- create index "auditevent_created_at" to table: "audit_events"
+CREATE INDEX `auditevent_created_at` ON `audit_events` (`created_at`);
+-- create "groups" table
+CREATE TABLE `groups` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` text NOT NULL, `description` text NOT NULL DEFAULT '', `connector_id` text NOT NULL DEFAULT '', `created_at` datetime NOT NULL);
+-- create index "groups_name_key" to table: "groups"
+CREATE UNIQUE INDEX `groups_name_key` ON `groups` (`name`);
+-- create "id_pconnectors" table
+CREATE TABLE `id_pconnectors` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `issuer` text NOT NULL, `client_id` text NOT NULL, `client_secret` text NOT NULL, `groups_claim` text NOT NULL DEFAULT '');
+-- create "oauth2clients" table
+CREATE TABLE `oauth2clients` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `client_id` text NOT NULL, `client_secret` text NOT NULL, `redirect_uris` json NOT NULL);
+-- create index "oauth2clients_client_id_key" to table: "oauth2clients"
+CREATE UNIQUE INDEX `oauth2clients_client_id_key` ON `oauth2clients` (`client_id`);
+-- create "roles" table
+CREATE TABLE `roles` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `name` text NOT NULL, `description` text NOT NULL DEFAULT '', `created_at` datetime NOT NULL);
+-- create index "roles_name_key" to table: "roles"
+CREATE UNIQUE INDEX `roles_name_key` ON `roles` (`name`);
+-- create "sessions" table
+CREATE TABLE `sessions` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `token` text NOT NULL, `expires_at` datetime NOT NULL, `absolute_expires_at` datetime NULL, `remember` bool NOT NULL DEFAULT false, `created_at` datetime NOT NULL, `last_seen_at` datetime NOT NULL, `ip` text NOT NULL DEFAULT '', `user_agent` text NOT NULL DEFAULT '', `user_sessions` integer NOT NULL, CONSTRAINT `sessions_users_sessions` FOREIGN KEY (`user_sessions`) REFERENCES `users` (`id`) ON DELETE NO ACTION);
+-- create index "session_token" to table: "sessions"
+CREATE UNIQUE INDEX `session_token` ON `sessions` (`token`);
+-- create index "session_expires_at" to table: "sessions"
+CREATE INDEX `session_expires_at` ON `sessions` (`expires_at`);
+-- create "users" table
+CREATE TABLE `users` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `username` text NOT NULL, `email` text NOT NULL, `password_hash` text NOT NULL, `display_name` text NOT NULL DEFAULT '', `email_verified` bool NOT NULL DEFAULT false, `pending_email` text NOT NULL DEFAULT '', `email_verify_token_hash` text NOT NULL DEFAULT '', `email_verify_expires_at` datetime NULL, `external_id` text NOT NULL DEFAULT '', `status` text NOT NULL DEFAULT 'active', `deletion_scheduled_at` datetime NULL, `created_at` datetime NOT NULL);
+-- create index "users_username_key" to table: "users"
+CREATE UNIQUE INDEX `users_username_key` ON `users` (`username`);
+-- create "webhook_deliveries" table
+CREATE TABLE `webhook_deliveries` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `event_id` text NOT NULL, `event_type` text NOT NULL, `payload` text NOT NULL, `status` text NOT NULL DEFAULT 'pending', `attempts` integer NOT NULL DEFAULT 0, `next_attempt_at` datetime NOT NULL, `last_attempt_at` datetime NULL, `response_status` integer NOT NULL DEFAULT 0, `last_error` text NOT NULL DEFAULT '', `created_at` datetime NOT NULL, `webhook_subscription_deliveries` integer NOT NULL, CONSTRAINT `webhook_deliveries_webhook_subscriptions_deliveries` FOREIGN KEY (`webhook_subscription_deliveries`) REFERENCES `webhook_subscriptions` (`id`) ON DELETE NO ACTION);
+-- create index "webhookdelivery_status_next_attempt_at" to table: "webhook_deliveries"
+CREATE INDEX `webhookdelivery_status_next_attempt_at` ON `webhook_deliveries` (`status`, `next_attempt_at`);
+-- create index "webhookdelivery_event_id" to table: "webhook_deliveries"
+CREATE INDEX `webhookdelivery_event_id` ON `webhook_deliveries` (`event_id`);
+-- create "webhook_subscriptions" table
+CREATE TABLE `webhook_subscriptions` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `url` text NOT NULL, `events` json NOT NULL, `secret` text NOT NULL, `description` text NOT NULL DEFAULT '', `enabled` bool NOT NULL DEFAULT true, `created_at` datetime NOT NULL);
+-- create "group_users" table
+CREATE TABLE `group_users` (`group_id` integer NOT NULL, `user_id` integer NOT NULL, PRIMARY KEY (`group_id`, `user_id`), CONSTRAINT `group_users_group_id` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`) ON DELETE CASCADE, CONSTRAINT `group_users_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE);
+-- create "group_roles" table
+CREATE TABLE `group_roles` (`group_id` integer NOT NULL, `role_id` integer NOT NULL, PRIMARY KEY (`group_id`, `role_id`), CONSTRAINT `group_roles_group_id` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`) ON DELETE CASCADE, CONSTRAINT `group_roles_role_id` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE);
+-- create "role_users" table
+CREATE TABLE `role_users` (`role_id` integer NOT NULL, `user_id` integer NOT NULL, PRIMARY KEY (`role_id`, `user_id`), CONSTRAINT `role_users_role_id` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE, CONSTRAINT `role_users_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE);
@@ -0,0 +1,4 @@
+-- reverse: create index "consent_client_id_user_consents" to table: "consents"
+DROP INDEX `consent_client_id_user_consents`;
+-- reverse: create "consents" table
+DROP TABLE `consents`;
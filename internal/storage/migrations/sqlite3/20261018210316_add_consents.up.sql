@@ -0,0 +1,4 @@
+-- create "consents" table
+CREATE TABLE `consents` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `client_id` text NOT NULL, `scopes` json NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, `user_consents` integer NOT NULL, CONSTRAINT `consents_users_consents` FOREIGN KEY (`user_consents`) REFERENCES `users` (`id`) ON DELETE NO ACTION);
+-- create index "consent_client_id_user_consents" to table: "consents"
+CREATE UNIQUE INDEX `consent_client_id_user_consents` ON `consents` (`client_id`, `user_consents`);
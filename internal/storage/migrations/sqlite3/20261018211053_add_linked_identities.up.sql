@@ -0,0 +1,4 @@
+-- create "linked_identities" table
+CREATE TABLE `linked_identities` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `connector_id` text NOT NULL, `subject` text NOT NULL, `email` text NOT NULL DEFAULT '', `created_at` datetime NOT NULL, `user_identities` integer NOT NULL, CONSTRAINT `linked_identities_users_identities` FOREIGN KEY (`user_identities`) REFERENCES `users` (`id`) ON DELETE NO ACTION);
+-- create index "linkedidentity_connector_id_subject" to table: "linked_identities"
+CREATE UNIQUE INDEX `linkedidentity_connector_id_subject` ON `linked_identities` (`connector_id`, `subject`);
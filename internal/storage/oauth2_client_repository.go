@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/oauth2client"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// OAuth2ClientRepository implements oauthclient.Repository using ent.
+type OAuth2ClientRepository struct {
+	client *ent.Client
+}
+
+// NewOAuth2ClientRepository creates an OAuth2ClientRepository backed by the given ent client.
+func NewOAuth2ClientRepository(client *ent.Client) *OAuth2ClientRepository {
+	return &OAuth2ClientRepository{client: client}
+}
+
+// Create persists c and populates c.ID.
+func (r *OAuth2ClientRepository) Create(ctx context.Context, c *domain.OAuth2Client) error {
+	e, err := r.client.OAuth2Client.Create().
+		SetClientID(c.ClientID).
+		SetClientSecret(c.SecretHash).
+		SetRedirectUris(c.RedirectURIs).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create oauth2 client: %w", err)
+	}
+	c.ID = strconv.Itoa(e.ID)
+	return nil
+}
+
+// ByClientID returns the client with the given client_id, or nil if not found.
+func (r *OAuth2ClientRepository) ByClientID(ctx context.Context, clientID string) (*domain.OAuth2Client, error) {
+	e, err := r.client.OAuth2Client.Query().
+		Where(oauth2client.ClientIDEQ(clientID)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query oauth2 client: %w", err)
+	}
+	return entOAuth2ClientToDomain(e), nil
+}
+
+// List returns all clients ordered by client_id.
+func (r *OAuth2ClientRepository) List(ctx context.Context) ([]*domain.OAuth2Client, error) {
+	ents, err := r.client.OAuth2Client.Query().
+		Order(ent.Asc(oauth2client.FieldClientID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list oauth2 clients: %w", err)
+	}
+	out := make([]*domain.OAuth2Client, len(ents))
+	for i, e := range ents {
+		out[i] = entOAuth2ClientToDomain(e)
+	}
+	return out, nil
+}
+
+// Update persists the secret hash and redirect URIs of c.
+func (r *OAuth2ClientRepository) Update(ctx context.Context, c *domain.OAuth2Client) error {
+	n, err := r.client.OAuth2Client.Update().
+		Where(oauth2client.ClientIDEQ(c.ClientID)).
+		SetClientSecret(c.SecretHash).
+		SetRedirectUris(c.RedirectURIs).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("update oauth2 client: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("update oauth2 client: %s not found", c.ClientID)
+	}
+	return nil
+}
+
+// Delete removes the client. Returns false if it did not exist.
+func (r *OAuth2ClientRepository) Delete(ctx context.Context, clientID string) (bool, error) {
+	n, err := r.client.OAuth2Client.Delete().
+		Where(oauth2client.ClientIDEQ(clientID)).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete oauth2 client: %w", err)
+	}
+	return n > 0, nil
+}
+
+func entOAuth2ClientToDomain(e *ent.OAuth2Client) *domain.OAuth2Client {
+	return &domain.OAuth2Client{
+		ID:           strconv.Itoa(e.ID),
+		ClientID:     e.ClientID,
+		SecretHash:   e.ClientSecret,
+		RedirectURIs: e.RedirectUris,
+	}
+}
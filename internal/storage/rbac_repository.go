@@ -0,0 +1,382 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/group"
+	"github.com/qinzj/superpowers-demo/ent/role"
+	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// RBACRepository implements rbac.Repository using ent.
+type RBACRepository struct {
+	client *ent.Client
+}
+
+// NewRBACRepository creates an RBACRepository backed by the given ent client.
+func NewRBACRepository(client *ent.Client) *RBACRepository {
+	return &RBACRepository{client: client}
+}
+
+// CreateGroup persists the group and populates g.ID.
+func (r *RBACRepository) CreateGroup(ctx context.Context, g *domain.Group) error {
+	e, err := r.client.Group.Create().
+		SetName(g.Name).
+		SetDescription(g.Description).
+		SetConnectorID(g.ConnectorID).
+		SetCreatedAt(g.CreatedAt).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create group: %w", err)
+	}
+	g.ID = strconv.Itoa(e.ID)
+	return nil
+}
+
+// GroupByID returns the group with the given ID, or nil if not found.
+func (r *RBACRepository) GroupByID(ctx context.Context, id string) (*domain.Group, error) {
+	gid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	e, err := r.client.Group.Get(ctx, gid)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+	return entGroupToDomain(e), nil
+}
+
+// GroupByName returns the group with the given name, or nil if not found.
+func (r *RBACRepository) GroupByName(ctx context.Context, name string) (*domain.Group, error) {
+	e, err := r.client.Group.Query().Where(group.NameEQ(name)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query group by name: %w", err)
+	}
+	return entGroupToDomain(e), nil
+}
+
+// ListGroups returns all groups ordered by name.
+func (r *RBACRepository) ListGroups(ctx context.Context) ([]*domain.Group, error) {
+	ents, err := r.client.Group.Query().Order(ent.Asc(group.FieldName)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	return entGroupsToDomain(ents), nil
+}
+
+// RenameGroup sets the group's name.
+func (r *RBACRepository) RenameGroup(ctx context.Context, id, name string) error {
+	gid, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid group id: %w", err)
+	}
+	if err := r.client.Group.UpdateOneID(gid).SetName(name).Exec(ctx); err != nil {
+		return fmt.Errorf("rename group: %w", err)
+	}
+	return nil
+}
+
+// DeleteGroup removes the group. Membership and role edges are removed with it.
+func (r *RBACRepository) DeleteGroup(ctx context.Context, id string) (bool, error) {
+	gid, err := strconv.Atoi(id)
+	if err != nil {
+		return false, nil
+	}
+	n, err := r.client.Group.Delete().Where(group.IDEQ(gid)).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete group: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AddGroupMember adds the user to the group.
+func (r *RBACRepository) AddGroupMember(ctx context.Context, groupID, userID string) error {
+	gid, uid, err := parseIDPair(groupID, userID)
+	if err != nil {
+		return err
+	}
+	exists, err := r.client.Group.Query().
+		Where(group.IDEQ(gid), group.HasUsersWith(user.IDEQ(uid))).
+		Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("query group member: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if err := r.client.Group.UpdateOneID(gid).AddUserIDs(uid).Exec(ctx); err != nil {
+		return fmt.Errorf("add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupMember removes the user from the group.
+func (r *RBACRepository) RemoveGroupMember(ctx context.Context, groupID, userID string) error {
+	gid, uid, err := parseIDPair(groupID, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Group.UpdateOneID(gid).RemoveUserIDs(uid).Exec(ctx); err != nil {
+		return fmt.Errorf("remove group member: %w", err)
+	}
+	return nil
+}
+
+// ListGroupMembers returns the group's users ordered by username.
+func (r *RBACRepository) ListGroupMembers(ctx context.Context, groupID string) ([]*domain.User, error) {
+	gid, err := strconv.Atoi(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group id: %w", err)
+	}
+	ents, err := r.client.User.Query().
+		Where(user.HasGroupsWith(group.IDEQ(gid))).
+		Order(ent.Asc(user.FieldUsername)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list group members: %w", err)
+	}
+	out := make([]*domain.User, len(ents))
+	for i, e := range ents {
+		out[i] = entUserToDomain(e)
+	}
+	return out, nil
+}
+
+// ListGroupRoles returns the roles granted to the group ordered by name.
+func (r *RBACRepository) ListGroupRoles(ctx context.Context, groupID string) ([]*domain.Role, error) {
+	gid, err := strconv.Atoi(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group id: %w", err)
+	}
+	ents, err := r.client.Role.Query().
+		Where(role.HasGroupsWith(group.IDEQ(gid))).
+		Order(ent.Asc(role.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list group roles: %w", err)
+	}
+	return entRolesToDomain(ents), nil
+}
+
+// CreateRole persists the role and populates ro.ID.
+func (r *RBACRepository) CreateRole(ctx context.Context, ro *domain.Role) error {
+	e, err := r.client.Role.Create().
+		SetName(ro.Name).
+		SetDescription(ro.Description).
+		SetCreatedAt(ro.CreatedAt).
+		Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create role: %w", err)
+	}
+	ro.ID = strconv.Itoa(e.ID)
+	return nil
+}
+
+// RoleByID returns the role with the given ID, or nil if not found.
+func (r *RBACRepository) RoleByID(ctx context.Context, id string) (*domain.Role, error) {
+	rid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	e, err := r.client.Role.Get(ctx, rid)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get role: %w", err)
+	}
+	return entRoleToDomain(e), nil
+}
+
+// RoleByName returns the role with the given name, or nil if not found.
+func (r *RBACRepository) RoleByName(ctx context.Context, name string) (*domain.Role, error) {
+	e, err := r.client.Role.Query().Where(role.NameEQ(name)).Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query role by name: %w", err)
+	}
+	return entRoleToDomain(e), nil
+}
+
+// ListRoles returns all roles ordered by name.
+func (r *RBACRepository) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	ents, err := r.client.Role.Query().Order(ent.Asc(role.FieldName)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+	return entRolesToDomain(ents), nil
+}
+
+// DeleteRole removes the role. User and group assignments are removed with it.
+func (r *RBACRepository) DeleteRole(ctx context.Context, id string) (bool, error) {
+	rid, err := strconv.Atoi(id)
+	if err != nil {
+		return false, nil
+	}
+	n, err := r.client.Role.Delete().Where(role.IDEQ(rid)).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete role: %w", err)
+	}
+	return n > 0, nil
+}
+
+// AddUserRole grants the role directly to the user.
+func (r *RBACRepository) AddUserRole(ctx context.Context, roleID, userID string) error {
+	rid, uid, err := parseIDPair(roleID, userID)
+	if err != nil {
+		return err
+	}
+	exists, err := r.client.Role.Query().
+		Where(role.IDEQ(rid), role.HasUsersWith(user.IDEQ(uid))).
+		Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("query user role: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if err := r.client.Role.UpdateOneID(rid).AddUserIDs(uid).Exec(ctx); err != nil {
+		return fmt.Errorf("add user role: %w", err)
+	}
+	return nil
+}
+
+// RemoveUserRole removes a directly granted role from the user.
+func (r *RBACRepository) RemoveUserRole(ctx context.Context, roleID, userID string) error {
+	rid, uid, err := parseIDPair(roleID, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Role.UpdateOneID(rid).RemoveUserIDs(uid).Exec(ctx); err != nil {
+		return fmt.Errorf("remove user role: %w", err)
+	}
+	return nil
+}
+
+// AddGroupRole grants the role to the group.
+func (r *RBACRepository) AddGroupRole(ctx context.Context, roleID, groupID string) error {
+	rid, gid, err := parseIDPair(roleID, groupID)
+	if err != nil {
+		return err
+	}
+	exists, err := r.client.Role.Query().
+		Where(role.IDEQ(rid), role.HasGroupsWith(group.IDEQ(gid))).
+		Exist(ctx)
+	if err != nil {
+		return fmt.Errorf("query group role: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if err := r.client.Role.UpdateOneID(rid).AddGroupIDs(gid).Exec(ctx); err != nil {
+		return fmt.Errorf("add group role: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupRole removes the role from the group.
+func (r *RBACRepository) RemoveGroupRole(ctx context.Context, roleID, groupID string) error {
+	rid, gid, err := parseIDPair(roleID, groupID)
+	if err != nil {
+		return err
+	}
+	if err := r.client.Role.UpdateOneID(rid).RemoveGroupIDs(gid).Exec(ctx); err != nil {
+		return fmt.Errorf("remove group role: %w", err)
+	}
+	return nil
+}
+
+// UserGroups returns the groups the user belongs to, ordered by name. Unknown users have none.
+func (r *RBACRepository) UserGroups(ctx context.Context, userID string) ([]*domain.Group, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, nil
+	}
+	ents, err := r.client.Group.Query().
+		Where(group.HasUsersWith(user.IDEQ(uid))).
+		Order(ent.Asc(group.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query user groups: %w", err)
+	}
+	return entGroupsToDomain(ents), nil
+}
+
+// UserRoles returns the roles granted to the user directly or through a group, ordered by name.
+// Unknown users have none.
+func (r *RBACRepository) UserRoles(ctx context.Context, userID string) ([]*domain.Role, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, nil
+	}
+	ents, err := r.client.Role.Query().
+		Where(role.Or(
+			role.HasUsersWith(user.IDEQ(uid)),
+			role.HasGroupsWith(group.HasUsersWith(user.IDEQ(uid))),
+		)).
+		Order(ent.Asc(role.FieldName)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query user roles: %w", err)
+	}
+	return entRolesToDomain(ents), nil
+}
+
+func parseIDPair(a, b string) (int, int, error) {
+	x, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid id: %w", err)
+	}
+	y, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid id: %w", err)
+	}
+	return x, y, nil
+}
+
+func entGroupToDomain(e *ent.Group) *domain.Group {
+	return &domain.Group{
+		ID:          strconv.Itoa(e.ID),
+		Name:        e.Name,
+		Description: e.Description,
+		ConnectorID: e.ConnectorID,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func entGroupsToDomain(ents []*ent.Group) []*domain.Group {
+	out := make([]*domain.Group, len(ents))
+	for i, e := range ents {
+		out[i] = entGroupToDomain(e)
+	}
+	return out
+}
+
+func entRoleToDomain(e *ent.Role) *domain.Role {
+	return &domain.Role{
+		ID:          strconv.Itoa(e.ID),
+		Name:        e.Name,
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func entRolesToDomain(ents []*ent.Role) []*domain.Role {
+	out := make([]*domain.Role, len(ents))
+	for i, e := range ents {
+		out[i] = entRoleToDomain(e)
+	}
+	return out
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/hook"
+)
+
+// Sealer encrypts sensitive values before they are stored; satisfied by *envelope.Keyring.
+// binding names where a value is stored (see ConnectorSecretBinding), so that a sealed value
+// moved to another row or column does not open.
+type Sealer interface {
+	Seal(plaintext, binding string) (string, error)
+	// Open decrypts a sealed value and returns plaintext values unchanged.
+	Open(value, binding string) (string, error)
+}
+
+// ConnectorSecretBinding is the binding of an IdP connector's client secret. It uses the
+// connector's issuer and client ID rather than its row ID, so that sealed secrets stay valid
+// in archives moved to another database.
+func ConnectorSecretBinding(issuer, clientID string) string {
+	return "idp_connector.client_secret:" + issuer + " " + clientID
+}
+
+// webhookSecretBinding is the binding of a webhook subscription's signing secret.
+func webhookSecretBinding(url string) string {
+	return "webhook_subscription.secret:" + url
+}
+
+// errBulkSecretUpdate is returned for bulk updates of the fields a sealed secret is bound to,
+// which the hooks cannot re-seal row by row.
+var errBulkSecretUpdate = errors.New("bulk updates of encrypted secrets or the fields they are bound to are not supported")
+
+// EncryptSecrets keeps the sensitive fields of client encrypted at rest: IdP connector client
+// secrets and webhook signing secrets are sealed on every write and opened on every read, so
+// repositories and callers only ever see plaintext. Each value is bound to its row's natural
+// key, and re-sealed when that key changes. Call it before the client is used.
+func EncryptSecrets(client *ent.Client, s Sealer) {
+	client.IdPConnector.Use(func(next ent.Mutator) ent.Mutator {
+		return hook.IdPConnectorFunc(func(ctx context.Context, m *ent.IdPConnectorMutation) (ent.Value, error) {
+			plain, ok := m.ClientSecret()
+			issuer, issuerSet := m.Issuer()
+			clientID, clientIDSet := m.ClientID()
+			if !ok && !issuerSet && !clientIDSet {
+				return next.Mutate(ctx, m)
+			}
+			if !m.Op().Is(ent.OpCreate | ent.OpUpdateOne) {
+				return nil, errBulkSecretUpdate
+			}
+			var err error
+			if !issuerSet {
+				if issuer, err = m.OldIssuer(ctx); err != nil {
+					return nil, err
+				}
+			}
+			if !clientIDSet {
+				if clientID, err = m.OldClientID(ctx); err != nil {
+					return nil, err
+				}
+			}
+			if !ok {
+				// The secret is bound to the old issuer or client ID; re-seal it.
+				if plain, err = m.OldClientSecret(ctx); err != nil {
+					return nil, err
+				}
+			}
+			sealed, err := s.Seal(plain, ConnectorSecretBinding(issuer, clientID))
+			if err != nil {
+				return nil, fmt.Errorf("seal idp connector secret: %w", err)
+			}
+			m.SetClientSecret(sealed)
+			v, err := next.Mutate(ctx, m)
+			if e, ok := v.(*ent.IdPConnector); ok {
+				e.ClientSecret = plain
+			}
+			return v, err
+		})
+	})
+	client.IdPConnector.Intercept(ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			v, err := next.Query(ctx, q)
+			if err != nil {
+				return v, err
+			}
+			if ents, ok := v.([]*ent.IdPConnector); ok {
+				for _, e := range ents {
+					if e.ClientSecret, err = s.Open(e.ClientSecret, ConnectorSecretBinding(e.Issuer, e.ClientID)); err != nil {
+						return nil, fmt.Errorf("open idp connector %d secret: %w", e.ID, err)
+					}
+				}
+			}
+			return v, nil
+		})
+	}))
+
+	client.WebhookSubscription.Use(func(next ent.Mutator) ent.Mutator {
+		return hook.WebhookSubscriptionFunc(func(ctx context.Context, m *ent.WebhookSubscriptionMutation) (ent.Value, error) {
+			plain, ok := m.Secret()
+			url, urlSet := m.URL()
+			if !ok && !urlSet {
+				return next.Mutate(ctx, m)
+			}
+			if !m.Op().Is(ent.OpCreate | ent.OpUpdateOne) {
+				return nil, errBulkSecretUpdate
+			}
+			var err error
+			if !urlSet {
+				if url, err = m.OldURL(ctx); err != nil {
+					return nil, err
+				}
+			}
+			if !ok {
+				// The secret is bound to the old URL; re-seal it.
+				if plain, err = m.OldSecret(ctx); err != nil {
+					return nil, err
+				}
+			}
+			sealed, err := s.Seal(plain, webhookSecretBinding(url))
+			if err != nil {
+				return nil, fmt.Errorf("seal webhook secret: %w", err)
+			}
+			m.SetSecret(sealed)
+			v, err := next.Mutate(ctx, m)
+			if e, ok := v.(*ent.WebhookSubscription); ok {
+				e.Secret = plain
+			}
+			return v, err
+		})
+	})
+	client.WebhookSubscription.Intercept(ent.InterceptFunc(func(next ent.Querier) ent.Querier {
+		return ent.QuerierFunc(func(ctx context.Context, q ent.Query) (ent.Value, error) {
+			v, err := next.Query(ctx, q)
+			if err != nil {
+				return v, err
+			}
+			if ents, ok := v.([]*ent.WebhookSubscription); ok {
+				for _, e := range ents {
+					if e.Secret, err = s.Open(e.Secret, webhookSecretBinding(e.URL)); err != nil {
+						return nil, fmt.Errorf("open webhook subscription %d secret: %w", e.ID, err)
+					}
+				}
+			}
+			return v, nil
+		})
+	}))
+}
+
+// RekeySecrets rewrites every encrypted field so that it is sealed with the current primary
+// key and bound to its row, including values stored before encryption was enabled. It returns the number of rows
+// rewritten. The client must have been passed to EncryptSecrets.
+func RekeySecrets(ctx context.Context, client *ent.Client) (int, error) {
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin rekey: %w", err)
+	}
+	n, err := rekey(ctx, tx.Client())
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit rekey: %w", err)
+	}
+	return n, nil
+}
+
+func rekey(ctx context.Context, client *ent.Client) (int, error) {
+	n := 0
+	conns, err := client.IdPConnector.Query().All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list idp connectors: %w", err)
+	}
+	for _, c := range conns {
+		if err := client.IdPConnector.UpdateOne(c).SetClientSecret(c.ClientSecret).Exec(ctx); err != nil {
+			return 0, fmt.Errorf("rekey idp connector %d: %w", c.ID, err)
+		}
+		n++
+	}
+	subs, err := client.WebhookSubscription.Query().All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	for _, s := range subs {
+		if err := client.WebhookSubscription.UpdateOne(s).SetSecret(s.Secret).Exec(ctx); err != nil {
+			return 0, fmt.Errorf("rekey webhook subscription %d: %w", s.ID, err)
+		}
+		n++
+	}
+	return n, nil
+}
@@ -2,12 +2,14 @@ package storage
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/qinzj/superpowers-demo/ent"
 	"github.com/qinzj/superpowers-demo/ent/session"
+	"github.com/qinzj/superpowers-demo/ent/user"
 	"github.com/qinzj/superpowers-demo/internal/domain"
 )
 
@@ -21,27 +23,54 @@ func NewSessionRepository(client *ent.Client) *SessionRepository {
 	return &SessionRepository{client: client}
 }
 
-// Create persists the session. Session.ID and Token must be set; UserID is required.
+// Create persists the session and populates s.ID. TokenHash and UserID are required; the raw
+// token is never written.
 func (r *SessionRepository) Create(ctx context.Context, s *domain.Session) error {
 	userID, err := strconv.Atoi(s.UserID)
 	if err != nil {
 		return fmt.Errorf("invalid user id: %w", err)
 	}
-	_, err = r.client.Session.Create().
-		SetToken(s.Token).
+	create := r.client.Session.Create().
+		SetTokenHash(s.TokenHash).
 		SetExpiresAt(s.ExpiresAt).
-		SetUserID(userID).
-		Save(ctx)
+		SetRemember(s.Remember).
+		SetIP(s.IP).
+		SetUserAgent(s.UserAgent).
+		SetUserID(userID)
+	if !s.AbsoluteExpiresAt.IsZero() {
+		create = create.SetAbsoluteExpiresAt(s.AbsoluteExpiresAt)
+	}
+	if !s.CreatedAt.IsZero() {
+		create = create.SetCreatedAt(s.CreatedAt)
+	}
+	if !s.LastSeenAt.IsZero() {
+		create = create.SetLastSeenAt(s.LastSeenAt)
+	}
+	entSession, err := create.Save(ctx)
 	if err != nil {
 		return fmt.Errorf("create session: %w", err)
 	}
+	s.ID = strconv.Itoa(entSession.ID)
 	return nil
 }
 
-// GetByToken returns the session with the given token if it exists and is not expired.
-func (r *SessionRepository) GetByToken(ctx context.Context, token string) (*domain.Session, error) {
+// GetByTokenHash returns the session with the given token hash if it exists and is not expired.
+func (r *SessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	entSession, err := r.queryByTokenHash(ctx, tokenHash)
+	if err != nil || entSession == nil {
+		return nil, err
+	}
+	return entSessionToDomain(entSession), nil
+}
+
+// queryByTokenHash looks the session up through the unique token index and re-checks the stored
+// digest in constant time, so the match itself does not depend on how the database compares strings.
+func (r *SessionRepository) queryByTokenHash(ctx context.Context, tokenHash string) (*ent.Session, error) {
+	if tokenHash == "" {
+		return nil, nil
+	}
 	entSession, err := r.client.Session.Query().
-		Where(session.TokenEQ(token)).
+		Where(session.TokenHashEQ(tokenHash)).
 		Where(session.ExpiresAtGT(time.Now())).
 		WithUser().
 		Only(ctx)
@@ -51,14 +80,23 @@ func (r *SessionRepository) GetByToken(ctx context.Context, token string) (*doma
 		}
 		return nil, fmt.Errorf("query session by token: %w", err)
 	}
-	return entSessionToDomain(entSession), nil
+	if subtle.ConstantTimeCompare([]byte(entSession.TokenHash), []byte(tokenHash)) != 1 {
+		return nil, nil
+	}
+	return entSession, nil
 }
 
 func entSessionToDomain(e *ent.Session) *domain.Session {
 	s := &domain.Session{
-		ID:        strconv.Itoa(e.ID),
-		Token:     e.Token,
-		ExpiresAt: e.ExpiresAt,
+		ID:                strconv.Itoa(e.ID),
+		TokenHash:         e.TokenHash,
+		ExpiresAt:         e.ExpiresAt,
+		AbsoluteExpiresAt: e.AbsoluteExpiresAt,
+		Remember:          e.Remember,
+		CreatedAt:         e.CreatedAt,
+		LastSeenAt:        e.LastSeenAt,
+		IP:                e.IP,
+		UserAgent:         e.UserAgent,
 	}
 	if e.Edges.User != nil {
 		s.UserID = strconv.Itoa(e.Edges.User.ID)
@@ -66,18 +104,11 @@ func entSessionToDomain(e *ent.Session) *domain.Session {
 	return s
 }
 
-// GetByTokenWithUser returns the session and its user if valid and not expired.
-func (r *SessionRepository) GetByTokenWithUser(ctx context.Context, token string) (*domain.Session, *domain.User, error) {
-	entSession, err := r.client.Session.Query().
-		Where(session.TokenEQ(token)).
-		Where(session.ExpiresAtGT(time.Now())).
-		WithUser().
-		Only(ctx)
-	if err != nil {
-		if ent.IsNotFound(err) {
-			return nil, nil, nil
-		}
-		return nil, nil, fmt.Errorf("query session by token: %w", err)
+// GetByTokenHashWithUser returns the session and its user if valid and not expired.
+func (r *SessionRepository) GetByTokenHashWithUser(ctx context.Context, tokenHash string) (*domain.Session, *domain.User, error) {
+	entSession, err := r.queryByTokenHash(ctx, tokenHash)
+	if err != nil || entSession == nil {
+		return nil, nil, err
 	}
 	s := entSessionToDomain(entSession)
 	var u *domain.User
@@ -86,7 +117,122 @@ func (r *SessionRepository) GetByTokenWithUser(ctx context.Context, token string
 			ID:       strconv.Itoa(entSession.Edges.User.ID),
 			Username: entSession.Edges.User.Username,
 			Email:    entSession.Edges.User.Email,
+			Status:   domain.UserStatus(entSession.Edges.User.Status),
 		}
 	}
 	return s, u, nil
 }
+
+// DeleteByUser removes all sessions of the user except the one with exceptTokenHash (may be empty).
+func (r *SessionRepository) DeleteByUser(ctx context.Context, userID, exceptTokenHash string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	q := r.client.Session.Delete().Where(session.HasUserWith(user.IDEQ(id)))
+	if exceptTokenHash != "" {
+		q = q.Where(session.TokenHashNEQ(exceptTokenHash))
+	}
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("delete user sessions: %w", err)
+	}
+	return nil
+}
+
+// ListByUser returns the user's unexpired sessions, most recently seen first.
+func (r *SessionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	ents, err := r.client.Session.Query().
+		Where(session.HasUserWith(user.IDEQ(id))).
+		Where(session.ExpiresAtGT(time.Now())).
+		Order(ent.Desc(session.FieldLastSeenAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list user sessions: %w", err)
+	}
+	out := make([]*domain.Session, len(ents))
+	for i, e := range ents {
+		out[i] = entSessionToDomain(e)
+		out[i].UserID = userID
+	}
+	return out, nil
+}
+
+// DeleteByID removes the session if it belongs to the user. Returns false if nothing was deleted.
+func (r *SessionRepository) DeleteByID(ctx context.Context, userID, sessionID string) (bool, error) {
+	uid, err := strconv.Atoi(userID)
+	if err != nil {
+		return false, fmt.Errorf("invalid user id: %w", err)
+	}
+	sid, err := strconv.Atoi(sessionID)
+	if err != nil {
+		return false, nil
+	}
+	n, err := r.client.Session.Delete().
+		Where(session.IDEQ(sid)).
+		Where(session.HasUserWith(user.IDEQ(uid))).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete session: %w", err)
+	}
+	return n > 0, nil
+}
+
+// Touch records activity on the session and moves its idle deadline to expiresAt.
+func (r *SessionRepository) Touch(ctx context.Context, sessionID string, lastSeen, expiresAt time.Time) error {
+	id, err := strconv.Atoi(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session id: %w", err)
+	}
+	err = r.client.Session.UpdateOneID(id).
+		SetLastSeenAt(lastSeen).
+		SetExpiresAt(expiresAt).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+	return nil
+}
+
+// UpdateDevice replaces the IP and user agent recorded for the session.
+func (r *SessionRepository) UpdateDevice(ctx context.Context, sessionID, ip, userAgent string) error {
+	id, err := strconv.Atoi(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session id: %w", err)
+	}
+	if err := r.client.Session.UpdateOneID(id).SetIP(ip).SetUserAgent(userAgent).Exec(ctx); err != nil {
+		return fmt.Errorf("update session device: %w", err)
+	}
+	return nil
+}
+
+// CountActive returns the number of sessions that have not expired at now.
+func (r *SessionRepository) CountActive(ctx context.Context, now time.Time) (int, error) {
+	n, err := r.client.Session.Query().Where(session.ExpiresAtGT(now)).Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count active sessions: %w", err)
+	}
+	return n, nil
+}
+
+// PurgeExpired deletes up to limit sessions that expired before now.
+func (r *SessionRepository) PurgeExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	ids, err := r.client.Session.Query().
+		Where(session.ExpiresAtLT(now)).
+		Limit(limit).
+		IDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query expired sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	n, err := r.client.Session.Delete().Where(session.IDIn(ids...)).Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired sessions: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// backupStepPages is how many pages a backup copies before letting writers in again.
+	backupStepPages = 256
+	backupStepPause = 10 * time.Millisecond
+)
+
+// BackupSQLite copies the SQLite database behind src to a new file at dest with SQLite's online
+// backup API, so the server can keep serving, and writing, meanwhile: pages are copied in small
+// steps, and the copy restarts on its own if another process writes in between. The result is a
+// consistent snapshot. It is written next to dest and renamed into place once complete, and dest
+// must not exist.
+func BackupSQLite(ctx context.Context, src *sql.DB, dest string) (pages int, err error) {
+	if _, err := os.Stat(dest); err == nil {
+		return 0, fmt.Errorf("backup: %s already exists", dest)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer func() {
+		if err != nil {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	destDB, err := sql.Open("sqlite3", "file:"+tmpPath)
+	if err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	defer destDB.Close()
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("backup: open %s: %w", dest, err)
+	}
+	defer destConn.Close()
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	defer srcConn.Close()
+
+	err = destConn.Raw(func(d any) error {
+		return srcConn.Raw(func(s any) error {
+			dc, ok1 := d.(*sqlite3.SQLiteConn)
+			sc, ok2 := s.(*sqlite3.SQLiteConn)
+			if !ok1 || !ok2 {
+				return errors.New("not a sqlite3 database")
+			}
+			b, err := dc.Backup("main", sc, "main")
+			if err != nil {
+				return err
+			}
+			for {
+				done, err := b.Step(backupStepPages)
+				if err != nil {
+					b.Finish()
+					return err
+				}
+				if done {
+					pages = b.PageCount()
+					return b.Finish()
+				}
+				select {
+				case <-ctx.Done():
+					b.Finish()
+					return ctx.Err()
+				case <-time.After(backupStepPause):
+				}
+			}
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	if err := destConn.Close(); err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	if err := destDB.Close(); err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return 0, fmt.Errorf("backup: %w", err)
+	}
+	return pages, nil
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/consent"
+	"github.com/qinzj/superpowers-demo/ent/linkedidentity"
 	"github.com/qinzj/superpowers-demo/ent/session"
 	"github.com/qinzj/superpowers-demo/ent/user"
 	"github.com/qinzj/superpowers-demo/internal/domain"
@@ -21,12 +24,20 @@ func NewUserRepository(client *ent.Client) *UserRepository {
 	return &UserRepository{client: client}
 }
 
-// Create persists the user and populates u.ID with the generated ID.
+// Create persists the user and populates u.ID with the generated ID. An empty u.Status is stored
+// (and set on u) as active.
 func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
+	if u.Status == "" {
+		u.Status = domain.UserStatusActive
+	}
 	entUser, err := r.client.User.Create().
 		SetUsername(u.Username).
 		SetEmail(u.Email).
 		SetPasswordHash(u.PasswordHash).
+		SetDisplayName(u.DisplayName).
+		SetEmailVerified(u.EmailVerified).
+		SetExternalID(u.ExternalID).
+		SetStatus(user.Status(u.Status)).
 		SetCreatedAt(u.CreatedAt).
 		Save(ctx)
 	if err != nil {
@@ -64,7 +75,84 @@ func (r *UserRepository) ByEmail(ctx context.Context, email string) (*domain.Use
 	return entUserToDomain(entUser), nil
 }
 
-// Delete removes the user and all their sessions. Returns nil if user not found.
+// ByID returns the user with the given ID, or nil if not found (including non-numeric IDs).
+func (r *UserRepository) ByID(ctx context.Context, userID string) (*domain.User, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return nil, nil
+	}
+	entUser, err := r.client.User.Get(ctx, id)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query user by id: %w", err)
+	}
+	return entUserToDomain(entUser), nil
+}
+
+// ByExternalID returns the user with the given provisioning client ID, or nil if not found.
+func (r *UserRepository) ByExternalID(ctx context.Context, externalID string) (*domain.User, error) {
+	if externalID == "" {
+		return nil, nil
+	}
+	entUser, err := r.client.User.Query().
+		Where(user.ExternalIDEQ(externalID)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query user by external id: %w", err)
+	}
+	return entUserToDomain(entUser), nil
+}
+
+// ByEmailVerifyTokenHash returns the user with the given outstanding verification token hash, or nil.
+func (r *UserRepository) ByEmailVerifyTokenHash(ctx context.Context, tokenHash string) (*domain.User, error) {
+	if tokenHash == "" {
+		return nil, nil
+	}
+	entUser, err := r.client.User.Query().
+		Where(user.EmailVerifyTokenHashEQ(tokenHash)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query user by verify token: %w", err)
+	}
+	return entUserToDomain(entUser), nil
+}
+
+// Update persists the mutable profile fields (username, email, display name, external ID,
+// verification state).
+func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
+	id, err := strconv.Atoi(u.ID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	upd := r.client.User.UpdateOneID(id).
+		SetUsername(u.Username).
+		SetEmail(u.Email).
+		SetExternalID(u.ExternalID).
+		SetDisplayName(u.DisplayName).
+		SetEmailVerified(u.EmailVerified).
+		SetPendingEmail(u.PendingEmail).
+		SetEmailVerifyTokenHash(u.EmailVerifyTokenHash)
+	if u.EmailVerifyExpiresAt.IsZero() {
+		upd = upd.ClearEmailVerifyExpiresAt()
+	} else {
+		upd = upd.SetEmailVerifyExpiresAt(u.EmailVerifyExpiresAt)
+	}
+	if err := upd.Exec(ctx); err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the user and all their sessions, consents and linked identities. Returns nil if
+// user not found.
 func (r *UserRepository) Delete(ctx context.Context, userID string) error {
 	id, err := strconv.Atoi(userID)
 	if err != nil {
@@ -74,6 +162,14 @@ func (r *UserRepository) Delete(ctx context.Context, userID string) error {
 	if err != nil {
 		return fmt.Errorf("delete user sessions: %w", err)
 	}
+	_, err = r.client.Consent.Delete().Where(consent.HasUserWith(user.IDEQ(id))).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("delete user consents: %w", err)
+	}
+	_, err = r.client.LinkedIdentity.Delete().Where(linkedidentity.HasUserWith(user.IDEQ(id))).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("delete user linked identities: %w", err)
+	}
 	err = r.client.User.DeleteOneID(id).Exec(ctx)
 	if err != nil {
 		if ent.IsNotFound(err) {
@@ -84,12 +180,120 @@ func (r *UserRepository) Delete(ctx context.Context, userID string) error {
 	return nil
 }
 
+// UpdatePasswordHash replaces the stored password hash for the user.
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, userID, hash string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	if err := r.client.User.UpdateOneID(id).SetPasswordHash(hash).Exec(ctx); err != nil {
+		return fmt.Errorf("update password hash: %w", err)
+	}
+	return nil
+}
+
+// Search returns one page of users whose username, email or display name contains query
+// (case-insensitive; empty matches all), ordered by ID, and the total number of matches.
+func (r *UserRepository) Search(ctx context.Context, query string, offset, limit int) ([]*domain.User, int, error) {
+	q := r.client.User.Query()
+	if query != "" {
+		q = q.Where(user.Or(
+			user.UsernameContainsFold(query),
+			user.EmailContainsFold(query),
+			user.DisplayNameContainsFold(query),
+		))
+	}
+	total, err := q.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count users: %w", err)
+	}
+	ents, err := q.Order(ent.Asc(user.FieldID)).Offset(offset).Limit(limit).All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search users: %w", err)
+	}
+	out := make([]*domain.User, len(ents))
+	for i, e := range ents {
+		out[i] = entUserToDomain(e)
+	}
+	return out, total, nil
+}
+
+// SetStatus changes the user's lifecycle status.
+func (r *UserRepository) SetStatus(ctx context.Context, userID string, status domain.UserStatus) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	if err := r.client.User.UpdateOneID(id).SetStatus(user.Status(status)).Exec(ctx); err != nil {
+		return fmt.Errorf("set user status: %w", err)
+	}
+	return nil
+}
+
+// ScheduleDeletion marks the user for deletion at the given time.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userID string, at time.Time) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	if err := r.client.User.UpdateOneID(id).SetDeletionScheduledAt(at).Exec(ctx); err != nil {
+		return fmt.Errorf("schedule user deletion: %w", err)
+	}
+	return nil
+}
+
+// CancelDeletion clears a scheduled deletion. Returns false if none was scheduled.
+func (r *UserRepository) CancelDeletion(ctx context.Context, userID string) (bool, error) {
+	id, err := strconv.Atoi(userID)
+	if err != nil {
+		return false, fmt.Errorf("invalid user id: %w", err)
+	}
+	n, err := r.client.User.Update().
+		Where(user.IDEQ(id), user.DeletionScheduledAtNotNil()).
+		ClearDeletionScheduledAt().
+		Save(ctx)
+	if err != nil {
+		return false, fmt.Errorf("cancel user deletion: %w", err)
+	}
+	return n > 0, nil
+}
+
+// DueForDeletion returns the IDs of up to limit users whose scheduled deletion time is before now.
+func (r *UserRepository) DueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	ids, err := r.client.User.Query().
+		Where(user.DeletionScheduledAtLT(now)).
+		Order(ent.Asc(user.FieldID)).
+		Limit(limit).
+		IDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query users due for deletion: %w", err)
+	}
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = strconv.Itoa(id)
+	}
+	return out, nil
+}
+
 func entUserToDomain(e *ent.User) *domain.User {
-	return &domain.User{
-		ID:           strconv.Itoa(e.ID),
-		Username:     e.Username,
-		Email:        e.Email,
-		PasswordHash: e.PasswordHash,
-		CreatedAt:    e.CreatedAt,
+	u := &domain.User{
+		ID:                   strconv.Itoa(e.ID),
+		Username:             e.Username,
+		Email:                e.Email,
+		DisplayName:          e.DisplayName,
+		EmailVerified:        e.EmailVerified,
+		PendingEmail:         e.PendingEmail,
+		EmailVerifyTokenHash: e.EmailVerifyTokenHash,
+		PasswordHash:         e.PasswordHash,
+		ExternalID:           e.ExternalID,
+		Status:               domain.UserStatus(e.Status),
+		CreatedAt:            e.CreatedAt,
+	}
+	if e.EmailVerifyExpiresAt != nil {
+		u.EmailVerifyExpiresAt = *e.EmailVerifyExpiresAt
+	}
+	if e.DeletionScheduledAt != nil {
+		u.DeletionScheduledAt = *e.DeletionScheduledAt
 	}
+	return u
 }
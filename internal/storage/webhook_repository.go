@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/webhookdelivery"
+	"github.com/qinzj/superpowers-demo/ent/webhooksubscription"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+)
+
+// WebhookRepository implements webhook.Repository using ent.
+type WebhookRepository struct {
+	client *ent.Client
+}
+
+// NewWebhookRepository creates a WebhookRepository backed by the given ent client.
+func NewWebhookRepository(client *ent.Client) *WebhookRepository {
+	return &WebhookRepository{client: client}
+}
+
+// CreateSubscription persists the subscription and populates s.ID.
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	create := r.client.WebhookSubscription.Create().
+		SetURL(s.URL).
+		SetEvents(s.Events).
+		SetSecret(s.Secret).
+		SetDescription(s.Description).
+		SetEnabled(s.Enabled)
+	if !s.CreatedAt.IsZero() {
+		create = create.SetCreatedAt(s.CreatedAt)
+	}
+	e, err := create.Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create webhook subscription: %w", err)
+	}
+	s.ID = strconv.Itoa(e.ID)
+	return nil
+}
+
+// SubscriptionByID returns the subscription with the given ID, or nil if not found.
+func (r *WebhookRepository) SubscriptionByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	e, err := r.client.WebhookSubscription.Get(ctx, sid)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get webhook subscription: %w", err)
+	}
+	return entWebhookSubscriptionToDomain(e), nil
+}
+
+// ListSubscriptions returns all subscriptions ordered by ID.
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	ents, err := r.client.WebhookSubscription.Query().Order(ent.Asc(webhooksubscription.FieldID)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	out := make([]*domain.WebhookSubscription, len(ents))
+	for i, e := range ents {
+		out[i] = entWebhookSubscriptionToDomain(e)
+	}
+	return out, nil
+}
+
+// UpdateSubscription persists every mutable field of s.
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	id, err := strconv.Atoi(s.ID)
+	if err != nil {
+		return fmt.Errorf("invalid webhook subscription id: %w", err)
+	}
+	err = r.client.WebhookSubscription.UpdateOneID(id).
+		SetURL(s.URL).
+		SetEvents(s.Events).
+		SetSecret(s.Secret).
+		SetDescription(s.Description).
+		SetEnabled(s.Enabled).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription removes the subscription and its delivery log. Returns false if it did
+// not exist.
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string) (bool, error) {
+	sid, err := strconv.Atoi(id)
+	if err != nil {
+		return false, nil
+	}
+	_, err = r.client.WebhookDelivery.Delete().
+		Where(webhookdelivery.HasSubscriptionWith(webhooksubscription.IDEQ(sid))).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	n, err := r.client.WebhookSubscription.Delete().Where(webhooksubscription.IDEQ(sid)).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("delete webhook subscription: %w", err)
+	}
+	return n > 0, nil
+}
+
+// CreateDelivery persists the delivery and populates d.ID.
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	sid, err := strconv.Atoi(d.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("invalid webhook subscription id: %w", err)
+	}
+	create := r.client.WebhookDelivery.Create().
+		SetSubscriptionID(sid).
+		SetEventID(d.EventID).
+		SetEventType(string(d.EventType)).
+		SetPayload(d.Payload).
+		SetStatus(webhookdelivery.Status(d.Status)).
+		SetAttempts(d.Attempts).
+		SetNextAttemptAt(d.NextAttemptAt)
+	if !d.CreatedAt.IsZero() {
+		create = create.SetCreatedAt(d.CreatedAt)
+	}
+	e, err := create.Save(ctx)
+	if err != nil {
+		return fmt.Errorf("create webhook delivery: %w", err)
+	}
+	d.ID = strconv.Itoa(e.ID)
+	return nil
+}
+
+// DeliveryByID returns the delivery with the given ID, or nil if not found.
+func (r *WebhookRepository) DeliveryByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	did, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	e, err := r.client.WebhookDelivery.Query().
+		Where(webhookdelivery.IDEQ(did)).
+		WithSubscription().
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get webhook delivery: %w", err)
+	}
+	return entWebhookDeliveryToDomain(e), nil
+}
+
+// ListDeliveries returns one page of the subscription's deliveries, newest first, and the total.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, offset, limit int) ([]*domain.WebhookDelivery, int, error) {
+	sid, err := strconv.Atoi(subscriptionID)
+	if err != nil {
+		return nil, 0, nil
+	}
+	q := r.client.WebhookDelivery.Query().
+		Where(webhookdelivery.HasSubscriptionWith(webhooksubscription.IDEQ(sid)))
+	total, err := q.Clone().Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count webhook deliveries: %w", err)
+	}
+	ents, err := q.WithSubscription().
+		Order(ent.Desc(webhookdelivery.FieldID)).
+		Offset(offset).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return entWebhookDeliveriesToDomain(ents), total, nil
+}
+
+// DueDeliveries returns up to limit pending deliveries of enabled subscriptions whose next
+// attempt is due at now, earliest first.
+func (r *WebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	ents, err := r.client.WebhookDelivery.Query().
+		Where(
+			webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+			webhookdelivery.NextAttemptAtLTE(now),
+			webhookdelivery.HasSubscriptionWith(webhooksubscription.EnabledEQ(true)),
+		).
+		WithSubscription().
+		Order(ent.Asc(webhookdelivery.FieldNextAttemptAt), ent.Asc(webhookdelivery.FieldID)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query due webhook deliveries: %w", err)
+	}
+	return entWebhookDeliveriesToDomain(ents), nil
+}
+
+// ClaimDelivery starts an attempt on d: it increments the attempt count and moves the next
+// attempt to leaseUntil, provided no other worker has claimed d since it was loaded. Returns
+// false when the claim was lost. On success d reflects the new values.
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, d *domain.WebhookDelivery, now, leaseUntil time.Time) (bool, error) {
+	id, err := strconv.Atoi(d.ID)
+	if err != nil {
+		return false, fmt.Errorf("invalid webhook delivery id: %w", err)
+	}
+	n, err := r.client.WebhookDelivery.Update().
+		Where(
+			webhookdelivery.IDEQ(id),
+			webhookdelivery.StatusEQ(webhookdelivery.StatusPending),
+			webhookdelivery.AttemptsEQ(d.Attempts),
+		).
+		AddAttempts(1).
+		SetLastAttemptAt(now).
+		SetNextAttemptAt(leaseUntil).
+		Save(ctx)
+	if err != nil {
+		return false, fmt.Errorf("claim webhook delivery: %w", err)
+	}
+	if n == 0 {
+		return false, nil
+	}
+	d.Attempts++
+	d.LastAttemptAt = now
+	d.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+// UpdateDelivery records the outcome of an attempt: status, next attempt, response status and
+// error.
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	id, err := strconv.Atoi(d.ID)
+	if err != nil {
+		return fmt.Errorf("invalid webhook delivery id: %w", err)
+	}
+	err = r.client.WebhookDelivery.UpdateOneID(id).
+		SetStatus(webhookdelivery.Status(d.Status)).
+		SetNextAttemptAt(d.NextAttemptAt).
+		SetResponseStatus(d.ResponseStatus).
+		SetLastError(d.LastError).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func entWebhookSubscriptionToDomain(e *ent.WebhookSubscription) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:          strconv.Itoa(e.ID),
+		URL:         e.URL,
+		Events:      e.Events,
+		Secret:      e.Secret,
+		Description: e.Description,
+		Enabled:     e.Enabled,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func entWebhookDeliveriesToDomain(ents []*ent.WebhookDelivery) []*domain.WebhookDelivery {
+	out := make([]*domain.WebhookDelivery, len(ents))
+	for i, e := range ents {
+		out[i] = entWebhookDeliveryToDomain(e)
+	}
+	return out
+}
+
+// entWebhookDeliveryToDomain converts a delivery loaded with its subscription edge.
+func entWebhookDeliveryToDomain(e *ent.WebhookDelivery) *domain.WebhookDelivery {
+	d := &domain.WebhookDelivery{
+		ID:             strconv.Itoa(e.ID),
+		EventID:        e.EventID,
+		EventType:      domain.WebhookEventType(e.EventType),
+		Payload:        e.Payload,
+		Status:         domain.WebhookDeliveryStatus(e.Status),
+		Attempts:       e.Attempts,
+		NextAttemptAt:  e.NextAttemptAt,
+		ResponseStatus: e.ResponseStatus,
+		LastError:      e.LastError,
+		CreatedAt:      e.CreatedAt,
+	}
+	if e.Edges.Subscription != nil {
+		d.SubscriptionID = strconv.Itoa(e.Edges.Subscription.ID)
+	}
+	if e.LastAttemptAt != nil {
+		d.LastAttemptAt = *e.LastAttemptAt
+	}
+	return d
+}
@@ -9,6 +9,9 @@ Config fields align with configs/settings.yaml:
   - max_size: max size in MB before rotation
   - max_age: days to retain rotated logs
   - max_backups: number of rotated files to keep
+
+The level can be changed while the logger is in use through the zap.AtomicLevel returned by
+NewWithLevel.
 */
 package log
 
@@ -44,15 +47,38 @@ type Logger interface {
 // ensure *zap.Logger implements Logger
 var _ Logger = (*zap.Logger)(nil)
 
+// Validate reports an unknown level or output, or a file output without a path.
+func (c *Config) Validate() error {
+	if _, err := ParseLevel(c.Level); err != nil {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Output)) {
+	case "", "stdout":
+	case "file", "both":
+		if c.File == "" {
+			return fmt.Errorf("log: output requires file but file path is empty")
+		}
+	default:
+		return fmt.Errorf("log: unknown output %q (use stdout, file, or both)", c.Output)
+	}
+	return nil
+}
+
 // New builds a zap.Logger from config.
 // Output: "stdout" (console only), "file" (file only), "both" (console and file).
 // When output includes file, File must be non-empty; rotation uses max_size, max_age, max_backups.
 func New(cfg *Config) (*zap.Logger, error) {
+	logger, _, err := NewWithLevel(cfg)
+	return logger, err
+}
+
+// NewWithLevel is New but also returns the logger's level, which can be changed at runtime.
+func NewWithLevel(cfg *Config) (*zap.Logger, zap.AtomicLevel, error) {
 	if cfg == nil {
 		cfg = &Config{Level: "info", Output: "stdout"}
 	}
 
-	level := parseLevel(cfg.Level)
+	level := zap.NewAtomicLevelAt(parseLevel(cfg.Level))
 	encCfg := zap.NewProductionEncoderConfig()
 	enc := zapcore.NewJSONEncoder(encCfg)
 
@@ -71,7 +97,7 @@ func New(cfg *Config) (*zap.Logger, error) {
 	// File output
 	if out == "file" || out == "both" {
 		if cfg.File == "" {
-			return nil, fmt.Errorf("log: output requires file but file path is empty")
+			return nil, level, fmt.Errorf("log: output requires file but file path is empty")
 		}
 		lj := &lumberjack.Logger{
 			Filename:   cfg.File,
@@ -83,25 +109,32 @@ func New(cfg *Config) (*zap.Logger, error) {
 	}
 
 	if len(writers) == 0 {
-		return nil, fmt.Errorf("log: unknown output %q (use stdout, file, or both)", cfg.Output)
+		return nil, level, fmt.Errorf("log: unknown output %q (use stdout, file, or both)", cfg.Output)
 	}
 
 	ws := zapcore.NewMultiWriteSyncer(writers...)
 	core := zapcore.NewCore(enc, ws, level)
-	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), nil
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)), level, nil
 }
 
-func parseLevel(s string) zapcore.Level {
+// ParseLevel converts a configured level name; empty means info.
+func ParseLevel(s string) (zapcore.Level, error) {
 	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "debug":
-		return zapcore.DebugLevel
+		return zapcore.DebugLevel, nil
 	case "info", "":
-		return zapcore.InfoLevel
+		return zapcore.InfoLevel, nil
 	case "warn":
-		return zapcore.WarnLevel
+		return zapcore.WarnLevel, nil
 	case "error":
-		return zapcore.ErrorLevel
+		return zapcore.ErrorLevel, nil
 	default:
-		return zapcore.InfoLevel
+		return zapcore.InfoLevel, fmt.Errorf("log: unknown level %q (use debug, info, warn, or error)", s)
 	}
 }
+
+// parseLevel is ParseLevel with unknown names falling back to info.
+func parseLevel(s string) zapcore.Level {
+	l, _ := ParseLevel(s)
+	return l
+}
@@ -0,0 +1,374 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// noRedirectClient returns an HTTP client that does not follow redirects.
+func noRedirectClient() *http.Client {
+	return &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+}
+
+// createTestUser inserts a user with the given username and password.
+func createTestUser(t *testing.T, ctx context.Context, repo *storage.UserRepository, username, pwd string) *domain.User {
+	t.Helper()
+	hash, err := password.Hash(pwd)
+	require.NoError(t, err)
+	u := &domain.User{
+		Username:     username,
+		Email:        username + "@example.com",
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, u))
+	return u
+}
+
+// loginSession posts the login form and returns a jar holding the session cookie.
+func loginSession(t *testing.T, srvURL, username, pwd string) *testCookieJar {
+	t.Helper()
+	jar := &testCookieJar{}
+	form := url.Values{}
+	form.Set("username", username)
+	form.Set("password", pwd)
+	resp := postForm(t, srvURL, "/login", jar, fetchCSRFToken(t, srvURL, "/login", jar), form)
+	require.Equal(t, http.StatusFound, resp.StatusCode, "login should redirect")
+	return jar
+}
+
+// postForm POSTs form to path with the jar's cookies and csrfToken (omitted when empty),
+// capturing any cookies set in the response.
+func postForm(t *testing.T, srvURL, path string, jar *testCookieJar, csrfToken string, form url.Values) *http.Response {
+	t.Helper()
+	if form == nil {
+		form = url.Values{}
+	}
+	if csrfToken != "" {
+		form.Set("csrf_token", csrfToken)
+	}
+	req, err := http.NewRequest(http.MethodPost, srvURL+path, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	jar.Capture(resp)
+	return resp
+}
+
+func TestAccount_ProfileRequiresLogin(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	resp, err := noRedirectClient().Get(srv.URL + "/account")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Location"), "/login")
+}
+
+func TestLogin_NextStaysOnServer(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "nextuser", "password123")
+
+	for _, tc := range []struct {
+		next  string
+		local bool
+	}{
+		{"/account", true},
+		{"/device?user_code=BCDF-GHJK", true},
+		{"https://evil.com", false},
+		{"//evil.com", false},
+		{"/\\evil.com", false},
+		{"/\t/evil.com", false},
+		{"/\n/evil.com", false},
+		{"/%5Cevil.com", false},
+		{"/%09/evil.com", false},
+	} {
+		jar := &testCookieJar{}
+		resp := postForm(t, srv.URL, "/login", jar, fetchCSRFToken(t, srv.URL, "/login", jar),
+			url.Values{"username": {"nextuser"}, "password": {"password123"}, "next": {tc.next}})
+		require.Equal(t, http.StatusFound, resp.StatusCode, "%q", tc.next)
+		if tc.local {
+			require.Equal(t, tc.next, resp.Header.Get("Location"))
+		} else {
+			require.True(t, strings.HasPrefix(resp.Header.Get("Location"), "/authorize?"), "%q redirected to %q", tc.next, resp.Header.Get("Location"))
+		}
+	}
+}
+
+func TestAccount_ChangePasswordRevokesOtherSessions(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	createTestUser(t, ctx, userRepo, "acctuser", "oldpassword1")
+
+	current := loginSession(t, srv.URL, "acctuser", "oldpassword1")
+	other := loginSession(t, srv.URL, "acctuser", "oldpassword1")
+
+	// Profile page shows the user
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account", nil)
+	require.NoError(t, err)
+	current.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, string(body), "acctuser")
+
+	// Change password and sign out elsewhere
+	form := url.Values{}
+	form.Set("current_password", "oldpassword1")
+	form.Set("new_password", "newpassword1")
+	form.Set("revoke_sessions", "yes")
+	resp = postForm(t, srv.URL, "/account/password", current, fetchCSRFToken(t, srv.URL, "/account", current), form)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Equal(t, "/account?updated=password", resp.Header.Get("Location"))
+
+	// Current session survives; the other session is gone
+	for _, tc := range []struct {
+		jar    *testCookieJar
+		status int
+	}{{current, http.StatusOK}, {other, http.StatusFound}} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/account", nil)
+		require.NoError(t, err)
+		tc.jar.Inject(req)
+		resp, err := noRedirectClient().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, tc.status, resp.StatusCode)
+	}
+
+	// New password works for login
+	loginSession(t, srv.URL, "acctuser", "newpassword1")
+}
+
+// authorizeAndExchange runs the authorization code flow for an existing session with the sso-demo
+// client and returns the access token.
+func authorizeAndExchange(t *testing.T, srvURL string, jar *testCookieJar) string {
+	t.Helper()
+	body := exchangeTokens(t, srvURL, jar, "sso-demo", "openid")
+	token, _ := body["access_token"].(string)
+	require.NotEmpty(t, token)
+	return token
+}
+
+// exchangeTokens runs the authorization code flow for clientID (secret "secret") with the given
+// space-separated scope, allowing the client if asked, and returns the token response.
+func exchangeTokens(t *testing.T, srvURL string, jar *testCookieJar, clientID, scope string) map[string]interface{} {
+	t.Helper()
+	authURL := srvURL + "/authorize?" + url.Values{
+		"client_id":     []string{clientID},
+		"redirect_uri":  []string{"http://localhost:3000/callback"},
+		"response_type": []string{"code"},
+		"scope":         []string{scope},
+		"state":         []string{"apps-state"},
+	}.Encode()
+	req, err := http.NewRequest(http.MethodGet, authURL, nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		resp = approveConsent(t, srvURL, strings.TrimPrefix(authURL, srvURL), jar)
+	}
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	code := loc.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", "http://localhost:3000/callback")
+	req, err = http.NewRequest(http.MethodPost, srvURL+"/token", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, "secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, http.StatusOK, resp.StatusCode, "token exchange failed: %+v", body)
+	return body
+}
+
+func TestAccount_SessionsListAndRevoke(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	createTestUser(t, ctx, storage.NewUserRepository(db), "sessuser", "password123")
+	jar := loginSession(t, srv.URL, "sessuser", "password123")
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/sessions", nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, string(body), "This device")
+	require.Contains(t, string(body), "Go-http-client")
+
+	sessions, err := db.Session.Query().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	// Revoking the current session logs the user out
+	csrf := fetchCSRFToken(t, srv.URL, "/account/sessions", jar)
+	resp = postForm(t, srv.URL, "/account/sessions/"+strconv.Itoa(sessions[0].ID)+"/revoke", jar, csrf, nil)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Equal(t, "/login", resp.Header.Get("Location"))
+
+	n, err := db.Session.Query().Count(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestAccount_AppsListAndRevoke(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	createTestUser(t, ctx, storage.NewUserRepository(db), "appuser", "password123")
+	jar := loginSession(t, srv.URL, "appuser", "password123")
+	accessToken := authorizeAndExchange(t, srv.URL, jar)
+	require.Equal(t, http.StatusOK, userInfoStatus(t, srv.URL, accessToken))
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/apps", nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, string(body), "sso-demo")
+
+	resp = postForm(t, srv.URL, "/account/apps/sso-demo/revoke", jar, fetchCSRFToken(t, srv.URL, "/account/apps", jar), nil)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	// The revoked access token no longer works
+	require.Equal(t, http.StatusUnauthorized, userInfoStatus(t, srv.URL, accessToken))
+}
+
+// userInfoStatus calls /userinfo with the access token and returns the status code.
+func userInfoStatus(t *testing.T, srvURL, accessToken string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srvURL+"/userinfo", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAccount_DeletionCancelledBySigningIn(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	u := createTestUser(t, ctx, userRepo, "dana", "password123")
+	jar := loginSession(t, srv.URL, "dana", "password123")
+	other := loginSession(t, srv.URL, "dana", "password123")
+
+	status, body := postFormBody(t, srv.URL, "/account/delete", jar, fetchCSRFToken(t, srv.URL, "/account/delete", jar),
+		url.Values{"confirm": {"yes"}})
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "will be permanently deleted")
+	status, _ = getPage(t, srv.URL, "/account", other)
+	require.Equal(t, http.StatusFound, status, "requesting deletion signs out every session")
+
+	got, err := userRepo.ByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.True(t, got.DeletionScheduledAt.After(time.Now()))
+
+	loginSession(t, srv.URL, "dana", "password123")
+	got, err = userRepo.ByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.True(t, got.DeletionScheduledAt.IsZero(), "signing in cancels the deletion")
+}
+
+func TestAccount_Export(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	erin := createTestUser(t, ctx, storage.NewUserRepository(db), "erin", "password123")
+	require.NoError(t, storage.NewLinkedIdentityRepository(db).Create(ctx, &domain.LinkedIdentity{
+		UserID: erin.ID, ConnectorID: "corp", Subject: "erin-sub", Email: "erin@corp.example.com",
+	}))
+	status, _ := getPage(t, srv.URL, "/account/export", &testCookieJar{})
+	require.Equal(t, http.StatusFound, status)
+
+	jar := loginSession(t, srv.URL, "erin", "password123")
+	exchangeTokens(t, srv.URL, jar, "sso-demo", "openid")
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account/export", nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Disposition"), "attachment")
+
+	var export map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&export))
+	profile := export["profile"].(map[string]interface{})
+	require.Equal(t, "erin", profile["username"])
+	require.Equal(t, "active", profile["status"])
+	require.NotContains(t, profile, "password_hash")
+	require.Len(t, export["sessions"], 1)
+	apps := export["authorized_apps"].([]interface{})
+	require.Len(t, apps, 1)
+	require.Equal(t, "sso-demo", apps[0].(map[string]interface{})["client_id"])
+	consents := export["consents"].([]interface{})
+	require.Len(t, consents, 1)
+	require.Equal(t, "sso-demo", consents[0].(map[string]interface{})["client_id"])
+	require.Equal(t, []interface{}{"openid"}, consents[0].(map[string]interface{})["scopes"])
+	identities := export["linked_identities"].([]interface{})
+	require.Len(t, identities, 1)
+	require.Equal(t, "corp", identities[0].(map[string]interface{})["connector_id"])
+	require.Equal(t, "erin-sub", identities[0].(map[string]interface{})["subject"])
+	events := export["audit_events"].([]interface{})
+	require.NotEmpty(t, events)
+	require.Equal(t, "token.issued", events[0].(map[string]interface{})["type"], "newest first")
+}
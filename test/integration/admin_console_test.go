@@ -0,0 +1,172 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// getPage GETs path with the jar's cookies and returns the status and body.
+func getPage(t *testing.T, srvURL, path string, jar *testCookieJar) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srvURL+path, nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// postFormBody is postForm for responses whose body the test inspects.
+func postFormBody(t *testing.T, srvURL, path string, jar *testCookieJar, csrfToken string, form url.Values) (int, string) {
+	t.Helper()
+	form.Set("csrf_token", csrfToken)
+	req, err := http.NewRequest(http.MethodPost, srvURL+path, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestAdminConsole_RequiresAdmin(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "mallory", "password123")
+
+	resp, err := noRedirectClient().Get(srv.URL + "/admin/users")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Location"), "/login")
+
+	jar := loginSession(t, srv.URL, "mallory", "password123")
+	for _, path := range []string{"/admin", "/admin/users", "/admin/clients", "/admin/connectors"} {
+		status, _ := getPage(t, srv.URL, path, jar)
+		require.Equal(t, http.StatusForbidden, status, path)
+	}
+	csrf := fetchCSRFToken(t, srv.URL, "/account", jar)
+	resp = postForm(t, srv.URL, "/admin/clients", jar, csrf, url.Values{"client_id": {"evil"}, "redirect_uris": {"https://evil.example.com/cb"}})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestAdminConsole_UserManagement(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	admin := createTestUser(t, ctx, userRepo, "root", "password123")
+	victim := createTestUser(t, ctx, userRepo, "victor", "password123")
+	grantAdmin(t, db, "root")
+
+	adminJar := loginSession(t, srv.URL, "root", "password123")
+	csrf := fetchCSRFToken(t, srv.URL, "/account", adminJar)
+	victimJar := loginSession(t, srv.URL, "victor", "password123")
+
+	status, body := getPage(t, srv.URL, "/admin/users?q=VIC", adminJar)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "victor")
+	require.NotContains(t, body, ">root<")
+
+	status, body = getPage(t, srv.URL, "/admin/users/"+victim.ID, adminJar)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "/admin/users/"+victim.ID+"/sessions/", "the victim's session is listed")
+	status, _ = getPage(t, srv.URL, "/admin/users/999", adminJar)
+	require.Equal(t, http.StatusNotFound, status)
+
+	// Without a CSRF token the action is rejected.
+	suspend := url.Values{"status": {"suspended"}}
+	resp := postForm(t, srv.URL, "/admin/users/"+victim.ID+"/status", adminJar, "", suspend)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = postForm(t, srv.URL, "/admin/users/"+victim.ID+"/status", adminJar, csrf, suspend)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	status, _ = getPage(t, srv.URL, "/account", victimJar)
+	require.Equal(t, http.StatusFound, status, "suspending signs the user out")
+	jar := &testCookieJar{}
+	status, body = postFormBody(t, srv.URL, "/login", jar, fetchCSRFToken(t, srv.URL, "/login", jar),
+		url.Values{"username": {"victor"}, "password": {"password123"}})
+	require.Equal(t, http.StatusForbidden, status)
+	require.Contains(t, body, "not active")
+
+	status, _ = postFormBody(t, srv.URL, "/admin/users/"+admin.ID+"/status", adminJar, csrf, url.Values{"status": {"locked"}})
+	require.Equal(t, http.StatusBadRequest, status, "admins cannot deactivate themselves")
+	status, _ = postFormBody(t, srv.URL, "/admin/users/"+victim.ID+"/status", adminJar, csrf, url.Values{"status": {"deleted"}})
+	require.Equal(t, http.StatusBadRequest, status)
+
+	resp = postForm(t, srv.URL, "/admin/users/"+victim.ID+"/status", adminJar, csrf, url.Values{"status": {"active"}})
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	status, _ = postFormBody(t, srv.URL, "/admin/users/"+victim.ID+"/password", adminJar, csrf, url.Values{"password": {"short"}})
+	require.Equal(t, http.StatusBadRequest, status)
+	resp = postForm(t, srv.URL, "/admin/users/"+victim.ID+"/password", adminJar, csrf, url.Values{"password": {"reset-by-admin"}})
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	loginSession(t, srv.URL, "victor", "reset-by-admin")
+
+	// Revoking everything for their own account keeps the admin signed in.
+	resp = postForm(t, srv.URL, "/admin/users/"+admin.ID+"/revoke", adminJar, csrf, nil)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	status, _ = getPage(t, srv.URL, "/admin/users", adminJar)
+	require.Equal(t, http.StatusOK, status)
+}
+
+func TestAdminConsole_ClientsAndConnectors(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "root", "password123")
+	grantAdmin(t, db, "root")
+	jar := loginSession(t, srv.URL, "root", "password123")
+	csrf := fetchCSRFToken(t, srv.URL, "/admin/clients", jar)
+
+	status, body := postFormBody(t, srv.URL, "/admin/clients", jar, csrf, url.Values{
+		"client_id":     {"new-app"},
+		"redirect_uris": {"https://new.example.com/cb\nhttp://localhost:4000/cb"},
+	})
+	require.Equal(t, http.StatusCreated, status)
+	require.Contains(t, body, "Copy it now")
+
+	status, _ = postFormBody(t, srv.URL, "/admin/clients", jar, csrf, url.Values{
+		"client_id": {"new-app"}, "redirect_uris": {"https://new.example.com/cb"},
+	})
+	require.Equal(t, http.StatusConflict, status)
+	status, _ = postFormBody(t, srv.URL, "/admin/clients/new-app", jar, csrf, url.Values{"redirect_uris": {"not-absolute"}})
+	require.Equal(t, http.StatusBadRequest, status)
+
+	resp := postForm(t, srv.URL, "/admin/clients/new-app/delete", jar, csrf, nil)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	_, body = getPage(t, srv.URL, "/admin/clients", jar)
+	require.NotContains(t, body, "new-app")
+	require.Contains(t, body, "sso-demo")
+
+	resp = postForm(t, srv.URL, "/admin/connectors", jar, csrf, url.Values{
+		"issuer": {"https://idp.example.com"}, "client_id": {"up"}, "client_secret": {"up-secret"},
+	})
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	_, body = getPage(t, srv.URL, "/admin/connectors", jar)
+	require.Contains(t, body, "https://idp.example.com")
+	require.NotContains(t, body, "up-secret", "connector secrets are never rendered")
+	status, _ = postFormBody(t, srv.URL, "/admin/connectors", jar, csrf, url.Values{
+		"issuer": {"ftp://idp.example.com"}, "client_id": {"up"}, "client_secret": {"s"},
+	})
+	require.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,142 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/ent/group"
+	"github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/internal/infra/envelope"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+	"github.com/qinzj/superpowers-demo/internal/storage/archive"
+)
+
+func TestArchive_ExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	keyring, key := newTestKeyring(t)
+
+	src := enttest.Open(t, "sqlite3", "file:archive-src?mode=memory&_fk=1")
+	defer src.Close()
+	conn := src.IdPConnector.Create().SetIssuer("https://idp.example.com").SetClientID("sso").
+		SetClientSecret("stored-before-encryption").SetGroupsClaim("groups").SaveX(ctx)
+	src.OAuth2Client.Create().SetClientID("portal").SetClientSecret("$argon2id$hash").
+		SetRedirectUris([]string{"https://portal.example.com/cb"}).ExecX(ctx)
+	admin := src.Role.Create().SetName("admin").SetDescription("Administrators").SaveX(ctx)
+	eng := src.Group.Create().SetName("eng").SetConnectorID(strconv.Itoa(conn.ID)).AddRoles(admin).SaveX(ctx)
+	srcAlice := src.User.Create().SetUsername("alice").SetEmail("alice@example.com").SetPasswordHash("$argon2id$alice").
+		SetEmailVerified(true).SetStatus(user.StatusSuspended).AddGroups(eng).AddRoles(admin).SaveX(ctx)
+	src.LinkedIdentity.Create().SetConnectorID(strconv.Itoa(conn.ID)).SetSubject("upstream-alice").
+		SetEmail("alice@idp.example.com").SetUser(srcAlice).ExecX(ctx)
+
+	a, plaintext, err := archive.Export(ctx, src, keyring)
+	require.NoError(t, err)
+	require.Zero(t, plaintext)
+	require.True(t, envelope.IsSealed(a.Connectors[0].ClientSecret), "secrets stored in plaintext are sealed on export")
+
+	for _, format := range []archive.Format{archive.FormatJSON, archive.FormatNDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, archive.Write(&buf, a, format))
+			read, err := archive.Read(&buf)
+			require.NoError(t, err)
+
+			// The target is another instance with the same master key, which may have been rotated.
+			targetKeyring, _ := newTestKeyring(t, key)
+			dst := enttest.Open(t, "sqlite3", "file:archive-dst-"+string(format)+"?mode=memory&_fk=1")
+			defer dst.Close()
+			dst.User.Create().SetUsername("alice").SetEmail("old@example.com").SetPasswordHash("old").ExecX(ctx)
+
+			opts := archive.ImportOptions{Sealer: targetKeyring}
+			counts, err := archive.Import(ctx, dst, read, opts)
+			require.NoError(t, err)
+			require.Equal(t, 1, counts.Updated["users"])
+			require.Equal(t, 1, counts.Created["connectors"])
+			require.Equal(t, 1, counts.Created["identities"])
+
+			alice := dst.User.Query().Where(user.Username("alice")).WithGroups().WithRoles().OnlyX(ctx)
+			require.Equal(t, "alice@example.com", alice.Email)
+			require.Equal(t, "$argon2id$alice", alice.PasswordHash)
+			require.Equal(t, user.StatusSuspended, alice.Status)
+			require.Len(t, alice.Edges.Groups, 1)
+			require.Len(t, alice.Edges.Roles, 1)
+			g := dst.Group.Query().Where(group.Name("eng")).WithRoles().OnlyX(ctx)
+			require.Len(t, g.Edges.Roles, 1)
+			imported := dst.IdPConnector.Query().OnlyX(ctx)
+			require.Equal(t, strconv.Itoa(imported.ID), g.ConnectorID)
+
+			// The stored secret stays sealed and opens with the target's keyring.
+			require.True(t, envelope.IsSealed(imported.ClientSecret))
+			secret, err := targetKeyring.Open(imported.ClientSecret, storage.ConnectorSecretBinding(imported.Issuer, imported.ClientID))
+			require.NoError(t, err)
+			require.Equal(t, "stored-before-encryption", secret)
+
+			// The linked identity points at the imported connector and user.
+			li := dst.LinkedIdentity.Query().WithUser().OnlyX(ctx)
+			require.Equal(t, strconv.Itoa(imported.ID), li.ConnectorID)
+			require.Equal(t, "upstream-alice", li.Subject)
+			require.Equal(t, "alice@idp.example.com", li.Email)
+			require.Equal(t, alice.ID, li.Edges.User.ID)
+
+			// Importing again changes nothing.
+			_, err = archive.Import(ctx, dst, read, opts)
+			require.NoError(t, err)
+			require.Equal(t, 1, dst.User.Query().CountX(ctx))
+			require.Equal(t, 1, dst.IdPConnector.Query().CountX(ctx))
+			require.Equal(t, 1, dst.Group.Query().CountX(ctx))
+			require.Equal(t, 1, dst.LinkedIdentity.Query().CountX(ctx))
+		})
+	}
+
+	t.Run("unknown master key", func(t *testing.T) {
+		other, _ := newTestKeyring(t)
+		dst := enttest.Open(t, "sqlite3", "file:archive-otherkey?mode=memory&_fk=1")
+		defer dst.Close()
+		_, err := archive.Import(ctx, dst, a, archive.ImportOptions{Sealer: other})
+		require.ErrorIs(t, err, envelope.ErrUnknownKey)
+		require.Zero(t, dst.User.Query().CountX(ctx), "nothing is imported")
+	})
+
+	t.Run("newer archive", func(t *testing.T) {
+		_, err := archive.Import(ctx, src, &archive.Archive{Version: archive.Version + 1}, archive.ImportOptions{})
+		require.ErrorIs(t, err, archive.ErrNewerArchive)
+	})
+}
+
+func TestBackupSQLite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "sso.db")+"?_fk=1")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "CREATE TABLE t (v TEXT)")
+	require.NoError(t, err)
+	for i := 0; i < 2000; i++ {
+		_, err = db.ExecContext(ctx, "INSERT INTO t (v) VALUES (?)", strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+
+	dest := filepath.Join(dir, "backup.db")
+	pages, err := storage.BackupSQLite(ctx, db, dest)
+	require.NoError(t, err)
+	require.Positive(t, pages)
+
+	copied, err := sql.Open("sqlite3", "file:"+dest)
+	require.NoError(t, err)
+	defer copied.Close()
+	var n int
+	require.NoError(t, copied.QueryRowContext(ctx, "SELECT count(*) FROM t").Scan(&n))
+	require.Equal(t, 2000, n)
+
+	_, err = storage.BackupSQLite(ctx, db, dest)
+	require.ErrorContains(t, err, "already exists")
+}
@@ -0,0 +1,86 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+type auditListResp struct {
+	Total  int `json:"total"`
+	Events []struct {
+		Type     string            `json:"type"`
+		UserID   string            `json:"user_id"`
+		ClientID string            `json:"client_id"`
+		IP       string            `json:"ip"`
+		Details  map[string]string `json:"details"`
+	} `json:"events"`
+}
+
+func TestAudit_RecordsSignInAndTokens(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	createTestUser(t, ctx, userRepo, "root", "password123")
+	alice := createTestUser(t, ctx, userRepo, "alice", "password123")
+	grantAdmin(t, db, "root")
+
+	lj := &testCookieJar{}
+	status, _ := postFormBody(t, srv.URL, "/login", lj, fetchCSRFToken(t, srv.URL, "/login", lj),
+		url.Values{"username": {"alice"}, "password": {"wrong-password"}})
+	require.Equal(t, http.StatusUnauthorized, status)
+	jar := loginSession(t, srv.URL, "alice", "password123")
+	exchangeTokens(t, srv.URL, jar, "sso-demo", "openid")
+
+	status, _ = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/audit", jar, "", "", nil)
+	require.Equal(t, http.StatusForbidden, status)
+
+	adminJar := loginSession(t, srv.URL, "root", "password123")
+	var list auditListResp
+	status, raw := adminAPI(t, srv.URL, http.MethodGet, "/admin/api/audit?user_id="+alice.ID, adminJar, "", "", nil)
+	require.Equal(t, http.StatusOK, status, string(raw))
+	require.NoError(t, json.Unmarshal(raw, &list))
+	require.Equal(t, 4, list.Total)
+	require.Equal(t, "token.issued", list.Events[0].Type)
+	require.Equal(t, "sso-demo", list.Events[0].ClientID)
+	require.Equal(t, "authorization_code", list.Events[0].Details["grant_type"])
+	require.Equal(t, "consent.granted", list.Events[1].Type)
+	require.Equal(t, "openid", list.Events[1].Details["scope"])
+	require.Equal(t, "login.succeeded", list.Events[2].Type)
+	require.Equal(t, "login.failed", list.Events[3].Type)
+	require.Equal(t, "invalid_password", list.Events[3].Details["reason"])
+	require.NotEmpty(t, list.Events[3].IP)
+
+	status, raw = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/audit?type=token.issued&client_id=sso-demo", adminJar, "", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(raw, &list))
+	require.Equal(t, 1, list.Total)
+	status, raw = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/audit?until=2000-01-01T00:00:00Z", adminJar, "", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(raw, &list))
+	require.Zero(t, list.Total)
+	status, _ = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/audit?since=yesterday", adminJar, "", "", nil)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	var verify struct {
+		Valid   bool `json:"valid"`
+		Checked int  `json:"checked"`
+	}
+	status, raw = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/audit/verify", adminJar, "", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(raw, &verify))
+	require.True(t, verify.Valid)
+	require.GreaterOrEqual(t, verify.Checked, 4)
+}
@@ -0,0 +1,83 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestOIDC_Consent(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "carol", "password123")
+	jar := loginSession(t, srv.URL, "carol", "password123")
+
+	authorize := func(scope string, extra url.Values) string {
+		q := url.Values{
+			"client_id":     {"sso-demo"},
+			"redirect_uri":  {"http://localhost:3000/callback"},
+			"response_type": {"code"},
+			"scope":         {scope},
+			"state":         {"consent-state"},
+		}
+		for k, v := range extra {
+			q[k] = v
+		}
+		return "/authorize?" + q.Encode()
+	}
+	redirect := func(resp *http.Response) url.Values {
+		t.Helper()
+		require.Contains(t, []int{http.StatusFound, http.StatusSeeOther}, resp.StatusCode)
+		loc, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "localhost:3000", loc.Host)
+		return loc.Query()
+	}
+
+	// The first request asks, and denying returns access_denied to the client.
+	code, html := getPage(t, srv.URL, authorize("openid", nil), jar)
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, html, "<strong>sso-demo</strong> asks for access")
+	csrf := fetchCSRFToken(t, srv.URL, authorize("openid", nil), jar)
+	resp := postForm(t, srv.URL, authorize("openid", nil), jar, csrf, url.Values{"consent": {"deny"}})
+	require.Equal(t, "access_denied", redirect(resp).Get("error"))
+
+	// The consent form is CSRF protected.
+	resp = postForm(t, srv.URL, authorize("openid", nil), jar, "", url.Values{"consent": {"approve"}})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// Once allowed, the same scopes are granted without asking.
+	require.NotEmpty(t, redirect(approveConsent(t, srv.URL, authorize("openid", nil), jar)).Get("code"))
+	code, _ = getPage(t, srv.URL, authorize("openid", nil), jar)
+	require.Equal(t, http.StatusSeeOther, code)
+
+	// A new scope asks again, and prompt=none cannot.
+	code, _ = getPage(t, srv.URL, authorize("openid profile", nil), jar)
+	require.Equal(t, http.StatusOK, code)
+	req, err := http.NewRequest(http.MethodGet, srv.URL+authorize("openid profile", url.Values{"prompt": {"none"}}), nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err = noRedirectClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "consent_required", redirect(resp).Get("error"))
+
+	// prompt=consent asks even for allowed scopes.
+	code, _ = getPage(t, srv.URL, authorize("openid", url.Values{"prompt": {"consent"}}), jar)
+	require.Equal(t, http.StatusOK, code)
+
+	// Revoking the app forgets the consent.
+	resp = postForm(t, srv.URL, "/account/apps/sso-demo/revoke", jar, fetchCSRFToken(t, srv.URL, "/account/apps", jar), nil)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	code, _ = getPage(t, srv.URL, authorize("openid", nil), jar)
+	require.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestCSRF_LoginWithoutTokenRejected(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "csrflogin", "password123")
+
+	form := url.Values{}
+	form.Set("username", "csrflogin")
+	form.Set("password", "password123")
+	resp := postForm(t, srv.URL, "/login", &testCookieJar{}, "", form)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	for _, c := range resp.Cookies() {
+		require.NotEqual(t, "sso_session", c.Name, "no session may be created without a CSRF token")
+	}
+}
+
+func TestCSRF_CrossSitePostRejected(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	victim := createTestUser(t, ctx, userRepo, "victim", "password123")
+	jar := loginSession(t, srv.URL, "victim", "password123")
+
+	attacker := loginSession(t, srv.URL, createTestUser(t, ctx, userRepo, "attacker", "password123").Username, "password123")
+	attackerToken := fetchCSRFToken(t, srv.URL, "/account/delete", attacker)
+
+	form := url.Values{}
+	form.Set("confirm", "yes")
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"missing token", ""},
+		{"forged token", "not-a-valid-token"},
+		{"token from another session", attackerToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postForm(t, srv.URL, "/account/delete", jar, tc.token, form)
+			require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+			u, err := userRepo.ByID(ctx, victim.ID)
+			require.NoError(t, err)
+			require.NotNil(t, u, "account must survive a cross-site POST")
+		})
+	}
+
+	// The same request with the page's own token succeeds.
+	resp := postForm(t, srv.URL, "/account/delete", jar, fetchCSRFToken(t, srv.URL, "/account/delete", jar), form)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	u, err := userRepo.ByID(ctx, victim.ID)
+	require.NoError(t, err)
+	require.False(t, u.DeletionScheduledAt.IsZero(), "deletion is scheduled")
+}
+
+func TestCSRF_HeaderTokenAccepted(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "hdruser", "password123")
+	jar := loginSession(t, srv.URL, "hdruser", "password123")
+	token := fetchCSRFToken(t, srv.URL, "/account", jar)
+
+	form := url.Values{}
+	form.Set("display_name", "Header User")
+	form.Set("email", "hdruser@example.com")
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/account/profile", nil)
+	require.NoError(t, err)
+	req.URL.RawQuery = form.Encode()
+	req.Header.Set("X-CSRF-Token", token)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestCSRF_CookieOnlyOnHTMLRoutes(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	hasCSRFCookie := func(resp *http.Response) bool {
+		for _, c := range resp.Cookies() {
+			if c.Name == "sso_csrf" {
+				return true
+			}
+		}
+		return false
+	}
+	for _, path := range []string{"/login", "/register"} {
+		resp, err := noRedirectClient().Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.True(t, hasCSRFCookie(resp), path)
+	}
+
+	resp, err := noRedirectClient().PostForm(srv.URL+"/token", url.Values{"grant_type": {"client_credentials"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.False(t, hasCSRFCookie(resp), "/token")
+	for _, path := range []string{"/userinfo", "/healthz", "/.well-known/openid-configuration", "/admin/api/users"} {
+		resp, err := noRedirectClient().Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.False(t, hasCSRFCookie(resp), path)
+	}
+}
+
+func TestCookie_SessionCookieAttributes(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "cookieuser", "password123")
+	jar := loginSession(t, srv.URL, "cookieuser", "password123")
+
+	var found bool
+	for _, c := range jar.cookies {
+		if c.Name != "sso_session" {
+			continue
+		}
+		found = true
+		require.True(t, c.HttpOnly)
+		require.Equal(t, http.SameSiteLaxMode, c.SameSite)
+		require.Equal(t, "/", c.Path)
+	}
+	require.True(t, found, "session cookie not set")
+}
@@ -0,0 +1,158 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// clientPost POSTs form to path authenticated as the sso-demo client and returns the status
+// code and decoded JSON body.
+func clientPost(t *testing.T, srvURL, path string, form url.Values) (int, map[string]interface{}) {
+	t.Helper()
+	return clientPostAs(t, srvURL, "sso-demo", "secret", path, form)
+}
+
+// clientPostAs is clientPost authenticated as another client.
+func clientPostAs(t *testing.T, srvURL, clientID, secret, path string, form url.Values) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srvURL+path, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, secret)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func pollDeviceToken(t *testing.T, srvURL, deviceCode string) (int, map[string]interface{}) {
+	t.Helper()
+	return clientPost(t, srvURL, "/token", url.Values{"grant_type": {oidc.GrantTypeDeviceCode}, "device_code": {deviceCode}})
+}
+
+func TestDevice_AuthorizationGrant(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "tv", "password123")
+
+	status, doc := clientPost(t, srv.URL, "/device/code", url.Values{"scope": {"openid offline"}})
+	require.Equal(t, http.StatusOK, status, "%v", doc)
+	require.Equal(t, "http://localhost:8888/device", doc["verification_uri"])
+	require.EqualValues(t, 5, doc["interval"])
+	deviceCode, _ := doc["device_code"].(string)
+	userCode, _ := doc["user_code"].(string)
+	require.NotEmpty(t, deviceCode)
+	require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, userCode)
+
+	// The device polls before the user has acted, and then too fast.
+	status, body := pollDeviceToken(t, srv.URL, deviceCode)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "authorization_pending", body["error"])
+	_, body = pollDeviceToken(t, srv.URL, deviceCode)
+	require.Equal(t, "slow_down", body["error"])
+
+	// The verification page sends anonymous users to sign in and back.
+	page := "/device?" + url.Values{"user_code": {userCode}}.Encode()
+	jar := &testCookieJar{}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+page, nil)
+	require.NoError(t, err)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Equal(t, "/login?"+url.Values{"next": {page}}.Encode(), resp.Header.Get("Location"))
+
+	resp = postForm(t, srv.URL, "/login", jar, fetchCSRFToken(t, srv.URL, "/login", jar),
+		url.Values{"username": {"tv"}, "password": {"password123"}, "next": {page}})
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	require.Equal(t, page, resp.Header.Get("Location"))
+
+	// The code is accepted as typed, and the page names the client.
+	typed := "/device?user_code=" + strings.ToLower(strings.ReplaceAll(userCode, "-", ""))
+	code, html := getPage(t, srv.URL, typed, jar)
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, html, "sso-demo")
+	csrf := fetchCSRFToken(t, srv.URL, typed, jar)
+	code, html = postFormBody(t, srv.URL, "/device", jar, csrf, url.Values{"user_code": {userCode}, "action": {"approve"}})
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, html, "The device is connected")
+
+	status, body = pollDeviceToken(t, srv.URL, deviceCode)
+	require.Equal(t, http.StatusOK, status, "%v", body)
+	require.NotEmpty(t, body["access_token"])
+	require.NotEmpty(t, body["refresh_token"])
+	require.NotEmpty(t, body["id_token"])
+
+	// A device code is redeemed once, and a used user code is no longer accepted.
+	_, body = pollDeviceToken(t, srv.URL, deviceCode)
+	require.Equal(t, "invalid_grant", body["error"])
+	code, _ = postFormBody(t, srv.URL, "/device", jar, csrf, url.Values{"user_code": {userCode}, "action": {"approve"}})
+	require.Equal(t, http.StatusBadRequest, code)
+
+	t.Run("denied", func(t *testing.T) {
+		_, doc := clientPost(t, srv.URL, "/device/code", url.Values{"scope": {"openid"}})
+		code, _ := postFormBody(t, srv.URL, "/device", jar, csrf, url.Values{"user_code": {doc["user_code"].(string)}, "action": {"deny"}})
+		require.Equal(t, http.StatusOK, code)
+		_, body := pollDeviceToken(t, srv.URL, doc["device_code"].(string))
+		require.Equal(t, "access_denied", body["error"])
+	})
+
+	t.Run("shares consent with the browser flow", func(t *testing.T) {
+		_, doc := clientPost(t, srv.URL, "/device/code", url.Values{"scope": {"openid"}})
+		code, html := getPage(t, srv.URL, "/device?user_code="+doc["user_code"].(string), jar)
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, html, "You have allowed <strong>sso-demo</strong> this access before")
+		code, _ = getPage(t, srv.URL, "/authorize?"+url.Values{
+			"client_id": {"sso-demo"}, "redirect_uri": {"http://localhost:3000/callback"},
+			"response_type": {"code"}, "scope": {"openid offline"}, "state": {"device-state"},
+		}.Encode(), jar)
+		require.Equal(t, http.StatusSeeOther, code, "approving the device should count as consent")
+	})
+
+	t.Run("unknown code and scope", func(t *testing.T) {
+		_, body := pollDeviceToken(t, srv.URL, "not-a-device-code")
+		require.Equal(t, "invalid_grant", body["error"])
+		status, body := clientPost(t, srv.URL, "/device/code", url.Values{"scope": {"admin"}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_scope", body["error"])
+	})
+
+	t.Run("clients not allowed the grant", func(t *testing.T) {
+		hash, err := password.Hash("kiosk-secret")
+		require.NoError(t, err)
+		db.OAuth2Client.Create().SetClientID("kiosk").SetClientSecret(hash).
+			SetRedirectUris([]string{"http://localhost:3001/callback"}).ExecX(context.Background())
+		status, body := clientPostAs(t, srv.URL, "kiosk", "kiosk-secret", "/device/code", url.Values{"scope": {"openid"}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "unauthorized_client", body["error"])
+		_, body = clientPostAs(t, srv.URL, "kiosk", "kiosk-secret", "/token",
+			url.Values{"grant_type": {oidc.GrantTypeDeviceCode}, "device_code": {"any"}})
+		require.Equal(t, "unauthorized_client", body["error"])
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/.well-known/openid-configuration")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var doc map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+		require.Equal(t, "http://localhost:8888/device/code", doc["device_authorization_endpoint"])
+		require.Contains(t, doc["grant_types_supported"], oidc.GrantTypeDeviceCode)
+	})
+}
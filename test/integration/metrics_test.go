@@ -0,0 +1,48 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestMetrics_ExposesSignInAndTokenActivity(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	createTestUser(t, ctx, storage.NewUserRepository(db), "alice", "password123")
+
+	lj := &testCookieJar{}
+	status, _ := postFormBody(t, srv.URL, "/login", lj, fetchCSRFToken(t, srv.URL, "/login", lj),
+		url.Values{"username": {"alice"}, "password": {"wrong-password"}})
+	require.Equal(t, http.StatusUnauthorized, status)
+	jar := loginSession(t, srv.URL, "alice", "password123")
+	exchangeTokens(t, srv.URL, jar, "sso-demo", "openid")
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := string(raw)
+
+	require.Contains(t, body, `sso_login_attempts_total{outcome="invalid_password"} 1`)
+	require.Contains(t, body, `sso_login_attempts_total{outcome="success"} 1`)
+	require.Contains(t, body, `sso_tokens_issued_total{client_id="sso-demo",grant_type="authorization_code"} 1`)
+	require.Contains(t, body, `sso_active_sessions 1`)
+	require.Contains(t, body, `sso_oauth2_store_entries{store="access_tokens"} 1`)
+	require.Contains(t, body, `sso_http_request_duration_seconds_count{method="POST",route="/login",status="401"} 1`)
+	require.Contains(t, body, `sso_http_request_duration_seconds_count{method="POST",route="/token",status="200"} 1`)
+}
@@ -7,44 +7,54 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"os"
-	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/gin-gonic/gin"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 
 	"github.com/qinzj/superpowers-demo/ent"
 	"github.com/qinzj/superpowers-demo/ent/enttest"
 	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/metrics"
 	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
 	"github.com/qinzj/superpowers-demo/internal/router"
 	"github.com/qinzj/superpowers-demo/internal/server/http/handler"
+	"github.com/qinzj/superpowers-demo/internal/service/audit"
 	"github.com/qinzj/superpowers-demo/internal/service/auth"
 	"github.com/qinzj/superpowers-demo/internal/service/federation"
+	"github.com/qinzj/superpowers-demo/internal/service/oauthclient"
 	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/service/scim"
 	"github.com/qinzj/superpowers-demo/internal/service/user"
+	"github.com/qinzj/superpowers-demo/internal/service/webhook"
 	"github.com/qinzj/superpowers-demo/internal/storage"
 )
 
+// adminAPIClientID is the only client whose access tokens testServer accepts on the admin API.
+const adminAPIClientID = "admin-cli"
+
+// scimTestToken is the bearer token testServer accepts on the SCIM API.
+const scimTestToken = "scim-test-token"
+
 // testServer sets up an httptest server with full OIDC stack for integration tests.
 // Uses in-memory SQLite, seeded OAuth2 client (sso-demo/secret), and OIDC routes.
 func testServer(t *testing.T) (*httptest.Server, *ent.Client) {
 	t.Helper()
-	// NewEngine loads templates relative to cwd; ensure we run from module root.
-	modRoot := findModuleRoot(t)
-	orig, _ := os.Getwd()
-	if err := os.Chdir(modRoot); err != nil {
-		t.Fatalf("chdir to module root: %v", err)
-	}
-	defer func() { _ = os.Chdir(orig) }()
-
-	client := enttest.Open(t, "sqlite3", "file:ent?mode=memory&_fk=1")
+	drv, err := entsql.Open(dialect.SQLite, "file:ent?mode=memory&_fk=1")
+	require.NoError(t, err)
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(tracing.WrapDriver(drv))))
 
 	ctx := context.Background()
 	if err := seedOAuth2Client(ctx, client); err != nil {
@@ -56,27 +66,50 @@ func testServer(t *testing.T) (*httptest.Server, *ent.Client) {
 	require.NoError(t, err)
 
 	oidcStorage := oidc.NewFositeStorage(client)
+	oidcStorage.SetTokenExchangePolicies([]oidc.TokenExchangePolicy{
+		{ClientID: "sso-demo", Audiences: []string{"orders-api"}, Scopes: []string{"openid", "profile"}, Impersonation: true},
+		{ClientID: "api-gateway", Audiences: []string{"orders-api", "billing-api"}, Scopes: []string{"openid"}, Delegation: true},
+	})
+	oidcStorage.SetDeviceClients([]string{"sso-demo"})
 	provider := oidc.NewOAuth2Provider(oidcCfg, oidcStorage)
 
 	userRepo := storage.NewUserRepository(client)
 	sessionRepo := storage.NewSessionRepository(client)
 	idpConnRepo := storage.NewIdPConnectorRepository(client)
-	userSvc := user.NewUserService(userRepo)
-	authSvc := auth.NewAuthService(userRepo, sessionRepo)
+	auditSvc := audit.NewService(storage.NewAuditRepository(client))
+	webhookSvc := webhook.NewService(storage.NewWebhookRepository(client))
+	m := metrics.New()
+	m.WatchSessions(sessionRepo)
+	m.WatchTokenStore(oidcStorage)
+	userSvc := user.NewUserService(userRepo, user.WithAuditor(auditSvc), user.WithEventPublisher(webhookSvc))
+	authSvc := auth.NewAuthService(userRepo, sessionRepo, auth.WithTokenStore(oidcStorage),
+		auth.WithConsentRepository(storage.NewConsentRepository(client)), auth.WithAuditor(auditSvc), auth.WithEventPublisher(webhookSvc), auth.WithMetrics(m))
+	clientSvc := oauthclient.NewService(storage.NewOAuth2ClientRepository(client),
+		oauthclient.WithTokenRevoker(oidcStorage))
 	oidcAdapter := federation.NewOIDCClientAdapter()
-	fedSvc := federation.NewFederationService(idpConnRepo, oidcAdapter, userRepo, authSvc)
+	rbacSvc := rbac.NewService(storage.NewRBACRepository(client), userRepo)
+	fedSvc := federation.NewFederationService(idpConnRepo, oidcAdapter, userRepo, authSvc,
+		federation.WithIdentityRepository(storage.NewLinkedIdentityRepository(client)),
+		federation.WithGroupSync(rbacSvc), federation.WithAuditor(auditSvc), federation.WithEventPublisher(webhookSvc),
+		federation.WithMetrics(m))
 
 	fedCfg := handler.FederationRouteConfig{
 		Service: fedSvc,
 		Issuer:  issuer,
 	}
 
+	gin.SetMode(gin.TestMode)
 	engine := handler.NewEngine(nil)
-	router.Setup(engine, &router.Config{
+	err = router.Setup(engine, &router.Config{
+		Metrics: &handler.MetricsRouteConfig{Metrics: m},
 		OIDC: &handler.OIDCRouteConfig{
 			Provider: provider,
 			Issuer:   issuer,
 			Auth:     authSvc,
+			RBAC:     rbacSvc,
+			Audit:    auditSvc,
+			Metrics:  m,
+			Device:   oidc.NewDeviceFlow(oidcCfg, oidcStorage),
 		},
 		Login: &handler.LoginRouteConfig{
 			Auth:       authSvc,
@@ -85,31 +118,37 @@ func testServer(t *testing.T) (*httptest.Server, *ent.Client) {
 		Register: &handler.RegisterRouteConfig{
 			UserService: userSvc,
 		},
+		Account: &handler.AccountRouteConfig{
+			UserService: userSvc,
+			Auth:        authSvc,
+			RBAC:        rbacSvc,
+			Audit:       auditSvc,
+			Federation:  fedSvc,
+		},
 		Federation: &fedCfg,
+		Admin: &handler.AdminRouteConfig{
+			Provider:   provider,
+			Auth:       authSvc,
+			RBAC:       rbacSvc,
+			Users:      userSvc,
+			Clients:    clientSvc,
+			Federation: fedSvc,
+			Audit:      auditSvc,
+			Webhooks:   webhookSvc,
+			APIClients: []string{adminAPIClientID},
+		},
+		SCIM: &handler.SCIMRouteConfig{
+			Service: scim.NewService(userRepo, authSvc, rbacSvc, scim.WithEventPublisher(webhookSvc)),
+			Tokens:  []string{scimTestToken},
+			BaseURL: issuer,
+		},
 	})
+	require.NoError(t, err)
 
 	srv := httptest.NewServer(engine)
 	return srv, client
 }
 
-func findModuleRoot(t *testing.T) string {
-	t.Helper()
-	dir, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("getwd: %v", err)
-	}
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
-			return dir
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			t.Fatal("go.mod not found")
-		}
-		dir = parent
-	}
-}
-
 func seedOAuth2Client(ctx context.Context, client *ent.Client) error {
 	count, err := client.OAuth2Client.Query().Count(ctx)
 	if err != nil {
@@ -118,7 +157,7 @@ func seedOAuth2Client(ctx context.Context, client *ent.Client) error {
 	if count > 0 {
 		return nil
 	}
-	// Fosite expects bcrypt-hashed client secret for DefaultClient
+	// Fosite verifies client secrets through oidc.SecretsHasher (PHC-encoded hashes)
 	secretHash, err := password.Hash("secret")
 	if err != nil {
 		return err
@@ -248,6 +287,7 @@ func TestOIDC_FullFlow_AuthorizationCode(t *testing.T) {
 
 	// Step 1: POST /login to create session
 	loginForm := url.Values{}
+	loginForm.Set("csrf_token", fetchCSRFToken(t, srv.URL, "/login", jar))
 	loginForm.Set("username", "oidctest")
 	loginForm.Set("password", "testpass123")
 	loginForm.Set("client_id", "sso-demo")
@@ -282,6 +322,10 @@ func TestOIDC_FullFlow_AuthorizationCode(t *testing.T) {
 	authResp, err := client.Do(authReq)
 	require.NoError(t, err)
 	authResp.Body.Close()
+	require.Equal(t, http.StatusOK, authResp.StatusCode, "the first authorization should ask for consent")
+
+	// Step 2b: allow the client on the consent page
+	authResp = approveConsent(t, srv.URL, strings.TrimPrefix(authURL, srv.URL), jar)
 
 	require.True(t, authResp.StatusCode == http.StatusFound || authResp.StatusCode == http.StatusSeeOther,
 		"expected 302 or 303 redirect, got %d", authResp.StatusCode)
@@ -321,6 +365,7 @@ func TestOIDC_FullFlow_AuthorizationCode(t *testing.T) {
 }
 
 // testCookieJar captures Set-Cookie from responses and injects into requests.
+// Cookies are merged by name; a cookie with MaxAge < 0 is removed.
 type testCookieJar struct {
 	cookies []*http.Cookie
 	host    string
@@ -330,7 +375,7 @@ func (j *testCookieJar) Capture(resp *http.Response) {
 	if u := resp.Request.URL; u != nil {
 		j.host = u.Host
 	}
-	j.cookies = resp.Cookies()
+	j.merge(resp.Cookies())
 }
 
 func (j *testCookieJar) Inject(req *http.Request) {
@@ -341,7 +386,7 @@ func (j *testCookieJar) Inject(req *http.Request) {
 
 func (j *testCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
 	j.host = u.Host
-	j.cookies = cookies
+	j.merge(cookies)
 }
 
 func (j *testCookieJar) Cookies(u *url.URL) []*http.Cookie {
@@ -350,3 +395,44 @@ func (j *testCookieJar) Cookies(u *url.URL) []*http.Cookie {
 	}
 	return j.cookies
 }
+
+func (j *testCookieJar) merge(cookies []*http.Cookie) {
+	for _, c := range cookies {
+		kept := j.cookies[:0]
+		for _, old := range j.cookies {
+			if old.Name != c.Name {
+				kept = append(kept, old)
+			}
+		}
+		j.cookies = kept
+		if c.MaxAge >= 0 {
+			j.cookies = append(j.cookies, c)
+		}
+	}
+}
+
+var csrfFieldRe = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+// fetchCSRFToken GETs an HTML page with the jar's cookies and returns its csrf_token field.
+// approveConsent allows the client on the consent page of the authorize request at path and
+// returns the response, a redirect to the client.
+func approveConsent(t *testing.T, srvURL, path string, jar *testCookieJar) *http.Response {
+	t.Helper()
+	return postForm(t, srvURL, path, jar, fetchCSRFToken(t, srvURL, path, jar), url.Values{"consent": {"approve"}})
+}
+
+func fetchCSRFToken(t *testing.T, srvURL, path string, jar *testCookieJar) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srvURL+path, nil)
+	require.NoError(t, err)
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "GET %s", path)
+	jar.Capture(resp)
+	m := csrfFieldRe.FindStringSubmatch(string(body))
+	require.Len(t, m, 2, "csrf_token field not found on %s", path)
+	return m[1]
+}
@@ -0,0 +1,189 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/rbac"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// adminAPI calls the admin API with the jar's session cookie and CSRF token, or with bearer when set.
+func adminAPI(t *testing.T, srvURL, method, path string, jar *testCookieJar, csrf, bearer string, body interface{}) (int, []byte) {
+	t.Helper()
+	var r *strings.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		r = strings.NewReader(string(b))
+	} else {
+		r = strings.NewReader("")
+	}
+	req, err := http.NewRequest(method, srvURL+path, r)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	} else {
+		jar.Inject(req)
+		if csrf != "" {
+			req.Header.Set("X-CSRF-Token", csrf)
+		}
+	}
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var buf strings.Builder
+	_, _ = io.Copy(&buf, resp.Body)
+	return resp.StatusCode, []byte(buf.String())
+}
+
+// jwtClaims decodes the payload of a JWT without verifying it.
+func jwtClaims(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	return claims
+}
+
+func grantAdmin(t *testing.T, db *ent.Client, username string) {
+	t.Helper()
+	userRepo := storage.NewUserRepository(db)
+	svc := rbac.NewService(storage.NewRBACRepository(db), userRepo)
+	missing, err := svc.BootstrapAdmins(context.Background(), []string{username})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestRBAC_AdminAPIRequiresAdminRole(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	createTestUser(t, ctx, userRepo, "plainuser", "password123")
+	jar := loginSession(t, srv.URL, "plainuser", "password123")
+	csrf := fetchCSRFToken(t, srv.URL, "/account", jar)
+
+	status, _ := adminAPI(t, srv.URL, http.MethodGet, "/admin/api/groups", &testCookieJar{}, "", "", nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/groups", jar, "", "", nil)
+	require.Equal(t, http.StatusForbidden, status)
+
+	grantAdmin(t, db, "plainuser")
+	status, _ = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/groups", jar, "", "", nil)
+	require.Equal(t, http.StatusOK, status)
+
+	// Cookie-authenticated writes need the CSRF token.
+	status, _ = adminAPI(t, srv.URL, http.MethodPost, "/admin/api/groups", jar, "", "", map[string]string{"name": "ops"})
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodPost, "/admin/api/groups", jar, csrf, "", map[string]string{"name": "ops"})
+	require.Equal(t, http.StatusCreated, status)
+}
+
+func TestRBAC_AdminAPIBearerLimitedToAPIClients(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	secretHash, err := password.Hash("secret")
+	require.NoError(t, err)
+	_, err = db.OAuth2Client.Create().
+		SetClientID(adminAPIClientID).
+		SetClientSecret(secretHash).
+		SetRedirectUris([]string{"http://localhost:3000/callback"}).
+		Save(ctx)
+	require.NoError(t, err)
+
+	createTestUser(t, ctx, storage.NewUserRepository(db), "boss", "password123")
+	grantAdmin(t, db, "boss")
+	jar := loginSession(t, srv.URL, "boss", "password123")
+
+	appToken, _ := exchangeTokens(t, srv.URL, jar, "sso-demo", "openid")["access_token"].(string)
+	status, _ := adminAPI(t, srv.URL, http.MethodGet, "/admin/api/roles", nil, "", appToken, nil)
+	require.Equal(t, http.StatusUnauthorized, status, "tokens of ordinary apps must not reach the admin API")
+
+	cliToken, _ := exchangeTokens(t, srv.URL, jar, adminAPIClientID, "openid")["access_token"].(string)
+	status, body := adminAPI(t, srv.URL, http.MethodGet, "/admin/api/roles", nil, "", cliToken, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, string(body), `"admin"`)
+}
+
+func TestRBAC_GroupsClaims(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	u := createTestUser(t, ctx, userRepo, "member", "password123")
+	createTestUser(t, ctx, userRepo, "root", "password123")
+	grantAdmin(t, db, "root")
+
+	adminJar := loginSession(t, srv.URL, "root", "password123")
+	csrf := fetchCSRFToken(t, srv.URL, "/account", adminJar)
+
+	var group domain.Group
+	status, body := adminAPI(t, srv.URL, http.MethodPost, "/admin/api/groups", adminJar, csrf, "", map[string]string{"name": "engineering"})
+	require.Equal(t, http.StatusCreated, status)
+	require.NoError(t, json.Unmarshal(body, &struct{ ID *string }{&group.ID}))
+	var role domain.Role
+	status, body = adminAPI(t, srv.URL, http.MethodPost, "/admin/api/roles", adminJar, csrf, "", map[string]string{"name": "deployer"})
+	require.Equal(t, http.StatusCreated, status)
+	require.NoError(t, json.Unmarshal(body, &struct{ ID *string }{&role.ID}))
+
+	status, _ = adminAPI(t, srv.URL, http.MethodPut, "/admin/api/groups/"+group.ID+"/members/"+u.ID, adminJar, csrf, "", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodPut, "/admin/api/groups/"+group.ID+"/roles/"+role.ID, adminJar, csrf, "", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodPut, "/admin/api/groups/999/members/"+u.ID, adminJar, csrf, "", nil)
+	require.Equal(t, http.StatusNotFound, status)
+
+	jar := loginSession(t, srv.URL, "member", "password123")
+
+	// Without the groups scope no membership claims are emitted.
+	plain := exchangeTokens(t, srv.URL, jar, "sso-demo", "openid")
+	idToken, _ := plain["id_token"].(string)
+	require.NotEmpty(t, idToken)
+	require.NotContains(t, jwtClaims(t, idToken), "groups")
+
+	withGroups := exchangeTokens(t, srv.URL, jar, "sso-demo", "openid groups")
+	idToken, _ = withGroups["id_token"].(string)
+	claims := jwtClaims(t, idToken)
+	require.Equal(t, u.ID, claims["sub"])
+	require.Equal(t, []interface{}{"engineering"}, claims["groups"])
+	require.Equal(t, []interface{}{"deployer"}, claims["roles"])
+
+	accessToken, _ := withGroups["access_token"].(string)
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/userinfo", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var info map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Equal(t, []interface{}{"engineering"}, info["groups"])
+	require.Equal(t, []interface{}{"deployer"}, info["roles"])
+}
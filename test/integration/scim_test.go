@@ -0,0 +1,175 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// scimCall calls the SCIM API with the test bearer token and decodes a JSON response into out.
+func scimCall(t *testing.T, srvURL, method, path string, body, out interface{}) int {
+	t.Helper()
+	status, raw := adminAPI(t, srvURL, method, path, nil, "", scimTestToken, body)
+	if out != nil && len(raw) > 0 {
+		require.NoError(t, json.Unmarshal(raw, out), string(raw))
+	}
+	return status
+}
+
+func TestSCIM_RequiresBearerToken(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	status, _ := adminAPI(t, srv.URL, http.MethodGet, "/scim/v2/Users", nil, "", "wrong-token", nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodGet, "/scim/v2/Users", &testCookieJar{}, "", "", nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestSCIM_UserProvisioning(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	var created map[string]interface{}
+	status := scimCall(t, srv.URL, http.MethodPost, "/scim/v2/Users", map[string]interface{}{
+		"schemas":    []string{"urn:ietf:params:scim:schemas:core:2.0:User"},
+		"userName":   "bjensen",
+		"externalId": "hr-42",
+		"name":       map[string]string{"givenName": "Barbara", "familyName": "Jensen"},
+		"emails":     []map[string]interface{}{{"value": "bjensen@example.com", "primary": true}},
+		"password":   "password123",
+	}, &created)
+	require.Equal(t, http.StatusCreated, status)
+	id := created["id"].(string)
+	require.Equal(t, "Barbara Jensen", created["displayName"])
+	require.Equal(t, true, created["active"])
+	require.NotContains(t, created, "password")
+
+	status = scimCall(t, srv.URL, http.MethodPost, "/scim/v2/Users", map[string]interface{}{"userName": "bjensen", "emails": []map[string]string{{"value": "b2@example.com"}}}, nil)
+	require.Equal(t, http.StatusConflict, status)
+
+	var list struct {
+		TotalResults int                      `json:"totalResults"`
+		Resources    []map[string]interface{} `json:"Resources"`
+	}
+	status = scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Users?filter="+url.QueryEscape(`userName eq "bjensen"`), nil, &list)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, list.TotalResults)
+	require.Equal(t, id, list.Resources[0]["id"])
+	status = scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Users?filter="+url.QueryEscape(`userName sw "b"`), nil, nil)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	jar := loginSession(t, srv.URL, "bjensen", "password123")
+
+	// Okta and Azure AD deprovision through PATCH active=false.
+	var patched map[string]interface{}
+	status = scimCall(t, srv.URL, http.MethodPatch, "/scim/v2/Users/"+id, map[string]interface{}{
+		"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []map[string]interface{}{
+			{"op": "Replace", "path": "active", "value": "False"},
+			{"op": "replace", "value": map[string]string{"displayName": "Babs"}},
+		},
+	}, &patched)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, false, patched["active"])
+	require.Equal(t, "Babs", patched["displayName"])
+	require.Equal(t, "hr-42", patched["externalId"], "attributes outside the patch are kept")
+
+	status, _ = getPage(t, srv.URL, "/account", jar)
+	require.Equal(t, http.StatusFound, status, "deactivation signs the user out")
+	lj := &testCookieJar{}
+	status, _ = postFormBody(t, srv.URL, "/login", lj, fetchCSRFToken(t, srv.URL, "/login", lj),
+		url.Values{"username": {"bjensen"}, "password": {"password123"}})
+	require.Equal(t, http.StatusForbidden, status)
+
+	status = scimCall(t, srv.URL, http.MethodPatch, "/scim/v2/Users/"+id, map[string]interface{}{
+		"Operations": []map[string]interface{}{{"op": "replace", "path": "active", "value": true}},
+	}, nil)
+	require.Equal(t, http.StatusOK, status)
+	jar = loginSession(t, srv.URL, "bjensen", "password123")
+
+	status = scimCall(t, srv.URL, http.MethodDelete, "/scim/v2/Users/"+id, nil, nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, _ = getPage(t, srv.URL, "/account", jar)
+	require.Equal(t, http.StatusFound, status)
+	var got map[string]interface{}
+	status = scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Users/"+id, nil, &got)
+	require.Equal(t, http.StatusOK, status, "deprovisioned users are deactivated, not deleted")
+	require.Equal(t, false, got["active"])
+
+	status = scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Users/999", nil, nil)
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+func TestSCIM_GroupMembers(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	ann := createTestUser(t, ctx, userRepo, "ann", "password123")
+	ben := createTestUser(t, ctx, userRepo, "ben", "password123")
+
+	var group map[string]interface{}
+	status := scimCall(t, srv.URL, http.MethodPost, "/scim/v2/Groups", map[string]interface{}{
+		"displayName": "engineering",
+		"members":     []map[string]string{{"value": ann.ID}},
+	}, &group)
+	require.Equal(t, http.StatusCreated, status)
+	id := group["id"].(string)
+
+	status = scimCall(t, srv.URL, http.MethodPatch, "/scim/v2/Groups/"+id, map[string]interface{}{
+		"Operations": []map[string]interface{}{
+			{"op": "add", "path": "members", "value": []map[string]string{{"value": ben.ID}}},
+			{"op": "remove", "path": `members[value eq "` + ann.ID + `"]`},
+		},
+	}, &group)
+	require.Equal(t, http.StatusOK, status)
+	members := group["members"].([]interface{})
+	require.Len(t, members, 1)
+	require.Equal(t, ben.ID, members[0].(map[string]interface{})["value"])
+
+	var list struct {
+		TotalResults int `json:"totalResults"`
+	}
+	status = scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Groups?filter="+url.QueryEscape(`displayName eq "engineering"`), nil, &list)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, list.TotalResults)
+
+	status = scimCall(t, srv.URL, http.MethodDelete, "/scim/v2/Groups/"+id, nil, nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status = scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Groups/"+id, nil, nil)
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+func TestSCIM_Discovery(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	var spc map[string]interface{}
+	require.Equal(t, http.StatusOK, scimCall(t, srv.URL, http.MethodGet, "/scim/v2/ServiceProviderConfig", nil, &spc))
+	require.Equal(t, true, spc["patch"].(map[string]interface{})["supported"])
+
+	var list struct {
+		TotalResults int `json:"totalResults"`
+	}
+	require.Equal(t, http.StatusOK, scimCall(t, srv.URL, http.MethodGet, "/scim/v2/ResourceTypes", nil, &list))
+	require.Equal(t, 2, list.TotalResults)
+	require.Equal(t, http.StatusOK, scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Schemas", nil, &list))
+	require.Equal(t, 2, list.TotalResults)
+	require.Equal(t, http.StatusOK, scimCall(t, srv.URL, http.MethodGet, "/scim/v2/Schemas/urn:ietf:params:scim:schemas:core:2.0:Group", nil, nil))
+	require.Equal(t, http.StatusNotFound, scimCall(t, srv.URL, http.MethodGet, "/scim/v2/ResourceTypes/Device", nil, nil))
+}
@@ -0,0 +1,133 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/ent"
+	"github.com/qinzj/superpowers-demo/ent/enttest"
+	"github.com/qinzj/superpowers-demo/internal/domain"
+	"github.com/qinzj/superpowers-demo/internal/infra/envelope"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func newTestKeyring(t *testing.T, previous ...[]byte) (*envelope.Keyring, []byte) {
+	t.Helper()
+	s, err := envelope.GenerateKey()
+	require.NoError(t, err)
+	key, err := envelope.ParseKey(s)
+	require.NoError(t, err)
+	k, err := envelope.NewKeyring(key, previous...)
+	require.NoError(t, err)
+	return k, key
+}
+
+func entID(t *testing.T, id string) int {
+	t.Helper()
+	n, err := strconv.Atoi(id)
+	require.NoError(t, err)
+	return n
+}
+
+func TestSecrets_EncryptedAtRestAndRekeyed(t *testing.T) {
+	ctx := context.Background()
+	drv, err := entsql.Open(dialect.SQLite, "file:secrets?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	// raw shares the database but not the hooks, so it sees what is actually stored.
+	raw := enttest.NewClient(t, enttest.WithOptions(ent.Driver(drv)))
+	defer raw.Close()
+
+	// A connector stored before encryption was enabled.
+	legacy, err := raw.IdPConnector.Create().
+		SetIssuer("https://legacy.example.com").SetClientID("legacy").SetClientSecret("legacy-secret").
+		Save(ctx)
+	require.NoError(t, err)
+
+	oldKeyring, oldKey := newTestKeyring(t)
+	client := ent.NewClient(ent.Driver(drv))
+	storage.EncryptSecrets(client, oldKeyring)
+
+	conns := storage.NewIdPConnectorRepository(client)
+	conn := &domain.IdPConnector{Issuer: "https://idp.example.com", ClientID: "sso", ClientSecret: "upstream-secret"}
+	require.NoError(t, conns.Create(ctx, conn))
+	hooks := storage.NewWebhookRepository(client)
+	sub := &domain.WebhookSubscription{URL: "https://hooks.example.com", Events: []string{"user.created"}, Secret: "signing-secret", Enabled: true}
+	require.NoError(t, hooks.CreateSubscription(ctx, sub))
+
+	stored, err := raw.IdPConnector.Get(ctx, entID(t, conn.ID))
+	require.NoError(t, err)
+	require.True(t, envelope.IsSealed(stored.ClientSecret))
+	require.NotContains(t, stored.ClientSecret, "upstream-secret")
+	storedSub, err := raw.WebhookSubscription.Get(ctx, entID(t, sub.ID))
+	require.NoError(t, err)
+	require.True(t, envelope.IsSealed(storedSub.Secret))
+
+	got, err := conns.GetByID(ctx, conn.ID)
+	require.NoError(t, err)
+	require.Equal(t, "upstream-secret", got.ClientSecret)
+	all, err := conns.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, "legacy-secret", all[0].ClientSecret, "plaintext rows stay readable")
+	gotSub, err := hooks.SubscriptionByID(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, "signing-secret", gotSub.Secret)
+
+	// A sealed secret is bound to its connector: copied into another row, it does not open.
+	other := &domain.IdPConnector{Issuer: "https://other.example.com", ClientID: "sso", ClientSecret: "other-secret"}
+	require.NoError(t, conns.Create(ctx, other))
+	require.NoError(t, raw.IdPConnector.UpdateOneID(entID(t, other.ID)).SetClientSecret(stored.ClientSecret).Exec(ctx))
+	_, err = conns.GetByID(ctx, other.ID)
+	require.Error(t, err)
+	require.NoError(t, raw.IdPConnector.DeleteOneID(entID(t, other.ID)).Exec(ctx))
+
+	// Changing what the secret is bound to re-seals it.
+	require.NoError(t, client.IdPConnector.UpdateOneID(entID(t, conn.ID)).SetIssuer("https://idp2.example.com").Exec(ctx))
+	got, err = conns.GetByID(ctx, conn.ID)
+	require.NoError(t, err)
+	require.Equal(t, "upstream-secret", got.ClientSecret)
+	require.NoError(t, client.WebhookSubscription.UpdateOneID(entID(t, sub.ID)).SetURL("https://hooks2.example.com").Exec(ctx))
+	gotSub, err = hooks.SubscriptionByID(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, "signing-secret", gotSub.Secret)
+
+	// Rotate: the new keyring still opens values sealed with the old key, and rekeying
+	// rewrites every value, including the legacy plaintext one, under the new key.
+	newKeyring, newKey := newTestKeyring(t, oldKey)
+	rotated := ent.NewClient(ent.Driver(drv))
+	storage.EncryptSecrets(rotated, newKeyring)
+	n, err := storage.RekeySecrets(ctx, rotated)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	for _, id := range []int{legacy.ID, entID(t, conn.ID)} {
+		e, err := raw.IdPConnector.Get(ctx, id)
+		require.NoError(t, err)
+		require.True(t, newKeyring.Current(e.ClientSecret))
+	}
+	storedSub, err = raw.WebhookSubscription.Get(ctx, entID(t, sub.ID))
+	require.NoError(t, err)
+	require.True(t, newKeyring.Current(storedSub.Secret))
+
+	got, err = storage.NewIdPConnectorRepository(rotated).GetByID(ctx, conn.ID)
+	require.NoError(t, err)
+	require.Equal(t, "upstream-secret", got.ClientSecret)
+
+	// The retired key is no longer needed.
+	onlyNew, err := envelope.NewKeyring(newKey)
+	require.NoError(t, err)
+	final := ent.NewClient(ent.Driver(drv))
+	storage.EncryptSecrets(final, onlyNew)
+	all, err = storage.NewIdPConnectorRepository(final).List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "legacy-secret", all[0].ClientSecret)
+	require.Equal(t, "upstream-secret", all[1].ClientSecret)
+}
@@ -0,0 +1,183 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/infra/password"
+	"github.com/qinzj/superpowers-demo/internal/service/oidc"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// exchangeToken sends a token exchange request for subjectToken as clientID (secret "secret").
+// Extra parameters, such as audience, scope and actor_token, come from form.
+func exchangeToken(t *testing.T, srvURL, clientID, subjectToken string, form url.Values) (int, map[string]interface{}) {
+	t.Helper()
+	form.Set("grant_type", oidc.GrantTypeTokenExchange)
+	form.Set("subject_token", subjectToken)
+	form.Set("subject_token_type", oidc.TokenTypeAccessToken)
+	if form.Get("actor_token") != "" {
+		form.Set("actor_token_type", oidc.TokenTypeAccessToken)
+	}
+	req, err := http.NewRequest(http.MethodPost, srvURL+"/token", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, "secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+// introspect asks /introspect about token as clientID with the given secret.
+func introspect(t *testing.T, srvURL, clientID, secret, token string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srvURL+"/introspect", strings.NewReader(url.Values{"token": {token}}.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, secret)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func userInfo(t *testing.T, srvURL, accessToken string) map[string]interface{} {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srvURL+"/userinfo", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var claims map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&claims))
+	return claims
+}
+
+func TestTokenExchange_ImpersonationAndDelegation(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+	ctx := context.Background()
+	users := storage.NewUserRepository(db)
+	alice := createTestUser(t, ctx, users, "alice", "password123")
+	gatewaySvc := createTestUser(t, ctx, users, "gateway-svc", "password123")
+	hash, err := password.Hash("secret")
+	require.NoError(t, err)
+	db.OAuth2Client.Create().SetClientID("api-gateway").SetClientSecret(hash).
+		SetRedirectUris([]string{"http://localhost:3000/callback"}).ExecX(ctx)
+
+	aliceJar := loginSession(t, srv.URL, "alice", "password123")
+	subjectToken, _ := exchangeTokens(t, srv.URL, aliceJar, "sso-demo", "openid profile email")["access_token"].(string)
+	require.NotEmpty(t, subjectToken)
+
+	// sso-demo may impersonate alice towards orders-api, with a narrower scope.
+	status, body := exchangeToken(t, srv.URL, "sso-demo", subjectToken, url.Values{"audience": {"orders-api"}, "scope": {"openid"}})
+	require.Equal(t, http.StatusOK, status, "%v", body)
+	require.Equal(t, oidc.TokenTypeAccessToken, body["issued_token_type"])
+	require.Equal(t, "bearer", strings.ToLower(body["token_type"].(string)))
+	require.Equal(t, "openid", body["scope"])
+	require.Nil(t, body["refresh_token"])
+	claims := userInfo(t, srv.URL, body["access_token"].(string))
+	require.Equal(t, alice.ID, claims["sub"])
+	require.Nil(t, claims[oidc.ClaimActor])
+
+	// Without a scope parameter the token keeps the subject's scopes the policy allows.
+	_, body = exchangeToken(t, srv.URL, "sso-demo", subjectToken, url.Values{"resource": {"orders-api"}})
+	require.Equal(t, "openid profile", body["scope"])
+
+	t.Run("policy denials", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			clientID string
+			token    string
+			form     url.Values
+			want     string
+		}{
+			{"audience not allowed", "sso-demo", subjectToken, url.Values{"audience": {"billing-api"}}, "invalid_target"},
+			{"scope not allowed", "sso-demo", subjectToken, url.Values{"audience": {"orders-api"}, "scope": {"email"}}, "invalid_scope"},
+			{"audience missing", "sso-demo", subjectToken, url.Values{}, "invalid_request"},
+			{"unknown subject token", "sso-demo", "not-a-token", url.Values{"audience": {"orders-api"}}, "invalid_request"},
+			{"delegation not allowed", "sso-demo", subjectToken, url.Values{"audience": {"orders-api"}, "actor_token": {subjectToken}}, "invalid_request"},
+			{"impersonation not allowed", "api-gateway", subjectToken, url.Values{"audience": {"orders-api"}}, "invalid_request"},
+		} {
+			status, body := exchangeToken(t, srv.URL, tc.clientID, tc.token, tc.form)
+			require.Equal(t, http.StatusBadRequest, status, tc.name)
+			require.Equal(t, tc.want, body["error"], tc.name)
+		}
+	})
+
+	t.Run("delegation", func(t *testing.T) {
+		actorToken, _ := exchangeTokens(t, srv.URL, loginSession(t, srv.URL, "gateway-svc", "password123"), "api-gateway", "openid")["access_token"].(string)
+		require.NotEmpty(t, actorToken)
+
+		status, body := exchangeToken(t, srv.URL, "api-gateway", subjectToken, url.Values{"audience": {"orders-api"}, "actor_token": {actorToken}})
+		require.Equal(t, http.StatusOK, status, "%v", body)
+		delegated := body["access_token"].(string)
+		claims := userInfo(t, srv.URL, delegated)
+		require.Equal(t, alice.ID, claims["sub"])
+		require.Equal(t, map[string]interface{}{"sub": gatewaySvc.ID, "client_id": "api-gateway"}, claims[oidc.ClaimActor])
+
+		// A resource server learns the audience, actor and scope of the token by introspection.
+		status, info := introspect(t, srv.URL, "sso-demo", "secret", delegated)
+		require.Equal(t, http.StatusOK, status, "%v", info)
+		require.Equal(t, true, info["active"])
+		require.Equal(t, "api-gateway", info["client_id"])
+		require.Equal(t, alice.ID, info["sub"])
+		require.Equal(t, "openid", info["scope"])
+		require.Equal(t, []interface{}{"orders-api"}, info["aud"])
+		require.Equal(t, claims[oidc.ClaimActor], info[oidc.ClaimActor])
+		require.NotContains(t, info, "preferred_username", "ID token claims are not disclosed")
+
+		// Exchanging the delegated token again nests the previous actor.
+		_, body = exchangeToken(t, srv.URL, "api-gateway", delegated, url.Values{"audience": {"billing-api"}, "actor_token": {actorToken}})
+		act, _ := userInfo(t, srv.URL, body["access_token"].(string))[oidc.ClaimActor].(map[string]interface{})
+		require.Equal(t, gatewaySvc.ID, act["sub"])
+		require.Equal(t, claims[oidc.ClaimActor], act[oidc.ClaimActor])
+
+		// The actor token must belong to the client that presents it.
+		status, body = exchangeToken(t, srv.URL, "api-gateway", subjectToken, url.Values{"audience": {"orders-api"}, "actor_token": {subjectToken}})
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, "invalid_request", body["error"])
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/.well-known/openid-configuration")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var doc map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+		require.Contains(t, doc["grant_types_supported"], oidc.GrantTypeTokenExchange)
+		require.Equal(t, doc["issuer"].(string)+"/introspect", doc["introspection_endpoint"])
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		status, info := introspect(t, srv.URL, "sso-demo", "secret", subjectToken)
+		require.Equal(t, http.StatusOK, status, "%v", info)
+		require.Equal(t, true, info["active"])
+		require.Equal(t, "sso-demo", info["client_id"])
+		require.Nil(t, info[oidc.ClaimActor])
+
+		status, info = introspect(t, srv.URL, "sso-demo", "secret", "not-a-token")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, map[string]interface{}{"active": false}, info)
+
+		status, _ = introspect(t, srv.URL, "sso-demo", "wrong", subjectToken)
+		require.Equal(t, http.StatusUnauthorized, status, "callers must authenticate")
+	})
+}
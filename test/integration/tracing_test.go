@@ -0,0 +1,73 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/qinzj/superpowers-demo/internal/infra/tracing"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// TestTracing_FollowsIncomingTraceContext installs a recording tracer provider and restores the
+// previous global provider and propagator when it ends, so later tests are not traced.
+func TestTracing_FollowsIncomingTraceContext(t *testing.T) {
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	_, err := tracing.Setup(context.Background(), tracing.Config{})
+	require.NoError(t, err)
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+		require.NoError(t, tp.Shutdown(context.Background()))
+	})
+
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+	createTestUser(t, context.Background(), storage.NewUserRepository(db), "alice", "password123")
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	jar := &testCookieJar{}
+	form := url.Values{"username": {"alice"}, "password": {"password123"}, "csrf_token": {fetchCSRFToken(t, srv.URL, "/login", jar)}}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/login", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	jar.Inject(req)
+	resp, err := noRedirectClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		if s.SpanContext().TraceID().String() == traceID {
+			spans[s.Name()] = s
+		}
+	}
+	server, ok := spans["POST /login"]
+	require.True(t, ok, "server span continues the incoming trace")
+	require.Contains(t, server.Attributes(), attribute.String("request_id", resp.Header.Get("X-Request-Id")))
+
+	validate, ok := spans["AuthService.ValidateCredentials"]
+	require.True(t, ok)
+	require.Equal(t, server.SpanContext().SpanID(), validate.Parent().SpanID())
+	_, ok = spans["AuthService.CreateSession"]
+	require.True(t, ok)
+	_, ok = spans["db.Query"]
+	require.True(t, ok, "ent queries are traced")
+}
@@ -0,0 +1,91 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	entuser "github.com/qinzj/superpowers-demo/ent/user"
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+// refreshTokens redeems refreshToken at /token and returns the status code and decoded body.
+func refreshTokens(t *testing.T, srvURL, refreshToken string) (int, map[string]interface{}) {
+	t.Helper()
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refreshToken}}
+	req, err := http.NewRequest(http.MethodPost, srvURL+"/token", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("sso-demo", "secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestUserStatus_RefreshRefusedForInactiveUser(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	u := createTestUser(t, ctx, storage.NewUserRepository(db), "rita", "password123")
+	jar := loginSession(t, srv.URL, "rita", "password123")
+	tokens := exchangeTokens(t, srv.URL, jar, "sso-demo", "openid offline")
+	refresh, _ := tokens["refresh_token"].(string)
+	require.NotEmpty(t, refresh)
+
+	// Change the status behind the service's back so the tokens are not revoked: the token
+	// endpoint must still refuse the grant.
+	id, err := strconv.Atoi(u.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.User.UpdateOneID(id).SetStatus(entuser.StatusLocked).Exec(ctx))
+	status, body := refreshTokens(t, srv.URL, refresh)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "invalid_grant", body["error"])
+
+	require.NoError(t, db.User.UpdateOneID(id).SetStatus(entuser.StatusActive).Exec(ctx))
+	status, body = refreshTokens(t, srv.URL, refresh)
+	require.Equal(t, http.StatusOK, status, "%v", body)
+}
+
+func TestUserStatus_SuspendRevokesTokens(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := storage.NewUserRepository(db)
+	createTestUser(t, ctx, userRepo, "root", "password123")
+	victim := createTestUser(t, ctx, userRepo, "sam", "password123")
+	grantAdmin(t, db, "root")
+
+	jar := loginSession(t, srv.URL, "sam", "password123")
+	tokens := exchangeTokens(t, srv.URL, jar, "sso-demo", "openid offline")
+	accessToken := tokens["access_token"].(string)
+	require.Equal(t, http.StatusOK, userInfoStatus(t, srv.URL, accessToken))
+
+	adminJar := loginSession(t, srv.URL, "root", "password123")
+	csrf := fetchCSRFToken(t, srv.URL, "/account", adminJar)
+	resp := postForm(t, srv.URL, "/admin/users/"+victim.ID+"/status", adminJar, csrf, url.Values{"status": {"suspended"}})
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	require.Equal(t, http.StatusUnauthorized, userInfoStatus(t, srv.URL, accessToken))
+	status, _ := refreshTokens(t, srv.URL, tokens["refresh_token"].(string))
+	require.Equal(t, http.StatusBadRequest, status)
+
+	resp = postForm(t, srv.URL, "/admin/users/"+victim.ID+"/status", adminJar, csrf, url.Values{"status": {"active"}})
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	loginSession(t, srv.URL, "sam", "password123")
+}
@@ -0,0 +1,84 @@
+// Copyright © 2026 qinzj
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/qinzj/superpowers-demo/internal/storage"
+)
+
+func TestWebhooks_AdminAPIAndOutbox(t *testing.T) {
+	srv, db := testServer(t)
+	defer srv.Close()
+	defer db.Close()
+
+	ctx := context.Background()
+	createTestUser(t, ctx, storage.NewUserRepository(db), "root", "password123")
+	grantAdmin(t, db, "root")
+	adminJar := loginSession(t, srv.URL, "root", "password123")
+	csrf := fetchCSRFToken(t, srv.URL, "/account", adminJar)
+
+	status, _ := adminAPI(t, srv.URL, http.MethodPost, "/admin/api/webhooks", adminJar, csrf, "",
+		map[string]interface{}{"url": "https://hooks.example.com/sso", "events": []string{"user.teleported"}})
+	require.Equal(t, http.StatusBadRequest, status)
+
+	var created map[string]interface{}
+	status, raw := adminAPI(t, srv.URL, http.MethodPost, "/admin/api/webhooks", adminJar, csrf, "",
+		map[string]interface{}{"url": "https://hooks.example.com/sso", "events": []string{"user.created", "user.login"}})
+	require.Equal(t, http.StatusCreated, status, string(raw))
+	require.NoError(t, json.Unmarshal(raw, &created))
+	id := created["id"].(string)
+	require.NotEmpty(t, created["secret"])
+	require.Equal(t, true, created["enabled"])
+
+	status, raw = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/webhooks/"+id, adminJar, "", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NotContains(t, string(raw), "secret", "the secret is only shown once")
+
+	// Registration and sign-in queue deliveries in the outbox.
+	rj := &testCookieJar{}
+	status, _ = postFormBody(t, srv.URL, "/register", rj, fetchCSRFToken(t, srv.URL, "/register", rj), url.Values{
+		"username": {"newbie"}, "email": {"newbie@example.com"}, "password": {"password123"},
+	})
+	require.Less(t, status, http.StatusBadRequest)
+	loginSession(t, srv.URL, "newbie", "password123")
+
+	var log struct {
+		Total      int `json:"total"`
+		Deliveries []struct {
+			ID        string `json:"id"`
+			EventType string `json:"event_type"`
+			Status    string `json:"status"`
+			Payload   string `json:"payload"`
+		} `json:"deliveries"`
+	}
+	status, raw = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/webhooks/"+id+"/deliveries", adminJar, "", "", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(raw, &log))
+	require.Equal(t, 2, log.Total)
+	require.Equal(t, "user.login", log.Deliveries[0].EventType)
+	require.Equal(t, "user.created", log.Deliveries[1].EventType)
+	require.Equal(t, "pending", log.Deliveries[1].Status)
+	require.Contains(t, log.Deliveries[1].Payload, `"username":"newbie"`)
+
+	status, _ = adminAPI(t, srv.URL, http.MethodPost, "/admin/api/webhooks/"+id+"/deliveries/"+log.Deliveries[1].ID+"/redeliver", adminJar, csrf, "", nil)
+	require.Equal(t, http.StatusAccepted, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodPost, "/admin/api/webhooks/"+id+"/deliveries/999/redeliver", adminJar, csrf, "", nil)
+	require.Equal(t, http.StatusNotFound, status)
+
+	status, _ = adminAPI(t, srv.URL, http.MethodPut, "/admin/api/webhooks/"+id, adminJar, csrf, "",
+		map[string]interface{}{"url": "https://hooks.example.com/sso", "events": []string{"*"}, "enabled": false})
+	require.Equal(t, http.StatusOK, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodDelete, "/admin/api/webhooks/"+id, adminJar, csrf, "", nil)
+	require.Equal(t, http.StatusNoContent, status)
+	status, _ = adminAPI(t, srv.URL, http.MethodGet, "/admin/api/webhooks/"+id+"/deliveries", adminJar, "", "", nil)
+	require.Equal(t, http.StatusNotFound, status)
+}